@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fscnet"
+)
+
+// The main command describes the service and
+// defaults to printing the help message.
+var mainCmd = &cobra.Command{
+	Use:   "fscnet",
+	Short: "Generates, runs and inspects networks described by topology files.",
+}
+
+func main() {
+	mainCmd.AddCommand(fscnet.Cmds()...)
+
+	// On failure Cobra prints the usage message and error string, so we only
+	// need to exit with a non-0 status
+	if mainCmd.Execute() != nil {
+		os.Exit(1)
+	}
+}
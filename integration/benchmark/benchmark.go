@@ -0,0 +1,336 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package benchmark drives a workload of view calls against FSC nodes, either the nodes of an nwo network
+// or an in-process stack, and reports latency percentiles, throughput, errors and the time spent in the
+// phases of the view protocols as measured by the nodes themselves.
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+)
+
+// Caller calls views, the client of an nwo network and the view service client are Callers
+type Caller interface {
+	CallView(fid string, input []byte) (interface{}, error)
+}
+
+// InProcess calls the views registered in an in-process stack
+type InProcess struct {
+	sp view2.ServiceProvider
+}
+
+// NewInProcess returns a Caller initiating views with the view manager of the passed service provider
+func NewInProcess(sp view2.ServiceProvider) *InProcess {
+	return &InProcess{sp: sp}
+}
+
+func (i *InProcess) CallView(fid string, input []byte) (interface{}, error) {
+	manager := view2.GetManager(i.sp)
+	v, err := manager.NewView(fid, input)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed creating view [%s]", fid)
+	}
+	return manager.InitiateView(v)
+}
+
+// Operation is an entry of the workload mix
+type Operation struct {
+	// Name identifies the operation in the report
+	Name string
+	// Caller calls the view
+	Caller Caller
+	// View is the identifier of the view to call
+	View string
+	// Input is the input of the view, used if NewInput is nil
+	Input []byte
+	// NewInput, if set, returns the input of each call
+	NewInput func() ([]byte, error)
+	// Weight is the relative frequency of the operation in the mix, one if not positive
+	Weight int
+}
+
+func (o *Operation) weight() int {
+	if o.Weight <= 0 {
+		return 1
+	}
+	return o.Weight
+}
+
+func (o *Operation) call() error {
+	input := o.Input
+	if o.NewInput != nil {
+		var err error
+		input, err = o.NewInput()
+		if err != nil {
+			return errors.WithMessage(err, "failed creating input")
+		}
+	}
+	_, err := o.Caller.CallView(o.View, input)
+	return err
+}
+
+// Config describes a benchmark run
+type Config struct {
+	// Operations is the workload mix
+	Operations []*Operation
+	// Concurrency is the number of workers calling views in parallel, one if not positive
+	Concurrency int
+	// WarmUp is how long the workers run before the measurements start
+	WarmUp time.Duration
+	// Duration is how long the measurements last
+	Duration time.Duration
+	// Sources are the nodes whose metrics give the time spent in each phase, if any
+	Sources []Source
+	// Phases are the phases to report, DefaultPhases if empty
+	Phases []Phase
+}
+
+// Report is the outcome of a benchmark run, meant to be stored as JSON for regression tracking
+type Report struct {
+	Start       time.Time
+	Concurrency int
+	// Duration is the measured duration in seconds
+	Duration float64
+	// Total aggregates all the operations
+	Total *OperationReport
+	// Operations are the reports by operation name
+	Operations map[string]*OperationReport
+	// Phases are the reports by phase name
+	Phases map[string]*PhaseReport `json:",omitempty"`
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// OperationReport summarizes the calls of an operation
+type OperationReport struct {
+	Calls  int
+	Errors int
+	// Throughput is the number of successful calls per second
+	Throughput float64
+	// Latency of the successful calls
+	Latency *Latency
+	// ErrorBreakdown counts the failed calls by error message
+	ErrorBreakdown map[string]int `json:",omitempty"`
+}
+
+// Run runs the benchmark described by the passed configuration.
+// The run stops early, with an error, if the passed context is done.
+func Run(ctx context.Context, config *Config) (*Report, error) {
+	if len(config.Operations) == 0 {
+		return nil, errors.New("no operations to run")
+	}
+	if config.Duration <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	phases := config.Phases
+	if len(phases) == 0 {
+		phases = DefaultPhases
+	}
+	pick := picker(config.Operations)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rec := newRecorder(config.Operations)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for ctx.Err() == nil {
+				op := pick(r)
+				start := time.Now()
+				err := op.call()
+				rec.record(op.Name, start, time.Since(start), err)
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+
+	stop := func() {
+		cancel()
+		wg.Wait()
+	}
+
+	if err := sleep(ctx, config.WarmUp); err != nil {
+		stop()
+		return nil, err
+	}
+	before, err := scrape(config.Sources)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	start := time.Now()
+	rec.start(start)
+	err = sleep(ctx, config.Duration)
+	end := time.Now()
+	rec.stop(end)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	after, err := scrape(config.Sources)
+	stop()
+	if err != nil {
+		return nil, err
+	}
+
+	report := rec.report(end.Sub(start))
+	report.Start = start
+	report.Concurrency = concurrency
+	if len(config.Sources) != 0 {
+		report.Phases = phaseReports(phases, before, after)
+	}
+	return report, nil
+}
+
+// sleep waits for the passed duration, it fails if the passed context is done before
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "benchmark interrupted")
+	}
+}
+
+// picker returns a function picking operations at random according to their weights
+func picker(operations []*Operation) func(r *rand.Rand) *Operation {
+	total := 0
+	for _, op := range operations {
+		total += op.weight()
+	}
+	return func(r *rand.Rand) *Operation {
+		n := r.Intn(total)
+		for _, op := range operations {
+			n -= op.weight()
+			if n < 0 {
+				return op
+			}
+		}
+		return operations[len(operations)-1]
+	}
+}
+
+// recorder records the outcome of the calls completed within the measurement window
+type recorder struct {
+	mutex    sync.Mutex
+	from, to time.Time
+	calls    map[string][]time.Duration
+	errors   map[string]map[string]int
+	names    []string
+}
+
+func newRecorder(operations []*Operation) *recorder {
+	r := &recorder{calls: map[string][]time.Duration{}, errors: map[string]map[string]int{}}
+	for _, op := range operations {
+		if _, ok := r.errors[op.Name]; !ok {
+			r.names = append(r.names, op.Name)
+			r.errors[op.Name] = map[string]int{}
+		}
+	}
+	return r
+}
+
+func (r *recorder) start(t time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.from = t
+}
+
+func (r *recorder) stop(t time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.to = t
+}
+
+func (r *recorder) record(name string, start time.Time, latency time.Duration, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// only the calls started, and completed, within the window count
+	if r.from.IsZero() || start.Before(r.from) || (!r.to.IsZero() && start.Add(latency).After(r.to)) {
+		return
+	}
+	if err != nil {
+		r.errors[name][errorKey(err)]++
+		return
+	}
+	r.calls[name] = append(r.calls[name], latency)
+}
+
+func (r *recorder) report(window time.Duration) *Report {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	report := &Report{
+		Duration:   window.Seconds(),
+		Operations: map[string]*OperationReport{},
+	}
+	var all []time.Duration
+	allErrors := map[string]int{}
+	for _, name := range r.names {
+		report.Operations[name] = operationReport(r.calls[name], r.errors[name], window)
+		all = append(all, r.calls[name]...)
+		for k, v := range r.errors[name] {
+			allErrors[k] += v
+		}
+	}
+	report.Total = operationReport(all, allErrors, window)
+	return report
+}
+
+func operationReport(latencies []time.Duration, errs map[string]int, window time.Duration) *OperationReport {
+	res := &OperationReport{
+		Calls:   len(latencies),
+		Latency: newLatency(latencies),
+	}
+	for _, v := range errs {
+		res.Errors += v
+	}
+	res.Calls += res.Errors
+	if len(errs) != 0 {
+		res.ErrorBreakdown = errs
+	}
+	if window > 0 {
+		res.Throughput = float64(len(latencies)) / window.Seconds()
+	}
+	return res
+}
+
+// errorKey returns the key under which the passed error is counted, the first line of its message truncated
+func errorKey(err error) string {
+	msg := err.Error()
+	for i, c := range msg {
+		if c == '\n' {
+			msg = msg[:i]
+			break
+		}
+	}
+	if len(msg) > 200 {
+		msg = msg[:200]
+	}
+	return msg
+}
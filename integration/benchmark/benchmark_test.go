@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/prometheus"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type caller struct {
+	calls int64
+	fail  int64
+}
+
+func (c *caller) CallView(fid string, input []byte) (interface{}, error) {
+	n := atomic.AddInt64(&c.calls, 1)
+	time.Sleep(time.Millisecond)
+	if c.fail > 0 && n%c.fail == 0 {
+		return nil, errors.Errorf("failed calling [%s]\nsome details", fid)
+	}
+	return input, nil
+}
+
+func TestRun(t *testing.T) {
+	c := &caller{fail: 4}
+	report, err := Run(context.Background(), &Config{
+		Operations: []*Operation{
+			{Name: "transfer", Caller: c, View: "transfer", Weight: 3},
+			{Name: "query", Caller: &caller{}, View: "query", Input: []byte("alice")},
+		},
+		Concurrency: 4,
+		WarmUp:      50 * time.Millisecond,
+		Duration:    300 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	transfer := report.Operations["transfer"]
+	query := report.Operations["query"]
+	assert.NotNil(t, transfer)
+	assert.NotNil(t, query)
+	assert.True(t, transfer.Calls > query.Calls, "weights are not honoured [%d] <= [%d]", transfer.Calls, query.Calls)
+	assert.True(t, transfer.Errors > 0)
+	assert.Equal(t, transfer.Errors, transfer.ErrorBreakdown["failed calling [transfer]"])
+	assert.Equal(t, 0, query.Errors)
+	assert.Nil(t, query.ErrorBreakdown)
+	assert.Equal(t, transfer.Calls+query.Calls, report.Total.Calls)
+	assert.Equal(t, transfer.Errors, report.Total.Errors)
+	assert.True(t, report.Total.Latency.P50 >= 0.001)
+	assert.True(t, report.Total.Latency.Min <= report.Total.Latency.P99)
+	assert.True(t, report.Total.Throughput > 0)
+	assert.Nil(t, report.Phases)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, report.WriteJSON(buf))
+	decoded := &Report{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Equal(t, report.Total.Calls, decoded.Total.Calls)
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := Run(ctx, &Config{
+		Operations: []*Operation{{Name: "query", Caller: &caller{}, View: "query"}},
+		Duration:   time.Minute,
+	})
+	assert.Error(t, err)
+
+	_, err = Run(context.Background(), &Config{Duration: time.Second})
+	assert.EqualError(t, err, "no operations to run")
+}
+
+func TestLatency(t *testing.T) {
+	assert.Nil(t, newLatency(nil))
+
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	l := newLatency(latencies)
+	assert.Equal(t, 0.001, l.Min)
+	assert.Equal(t, 0.05, l.P50)
+	assert.Equal(t, 0.09, l.P90)
+	assert.Equal(t, 0.099, l.P99)
+	assert.Equal(t, 0.1, l.Max)
+	assert.InDelta(t, 0.0505, l.Mean, 1e-9)
+}
+
+func TestPhases(t *testing.T) {
+	provider := &prometheus.Provider{}
+	h := provider.NewHistogram(metrics.HistogramOpts{
+		Namespace:  "fabric",
+		Subsystem:  "endorser",
+		Name:       "endorsement_duration",
+		LabelNames: []string{"status"},
+		Buckets:    []float64{0.1, 0.2, 0.5, 1},
+	})
+	server := httptest.NewServer(promhttp.Handler())
+	defer server.Close()
+	source, err := NewHTTPSource(server.URL, "")
+	assert.NoError(t, err)
+
+	// observations made before the window do not count
+	h.With("status", "SUCCESS").Observe(0.9)
+	h.With("status", "FAILURE").Observe(0.05)
+	before, err := scrape([]Source{source})
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		h.With("status", "SUCCESS").Observe(0.15)
+	}
+	h.With("status", "FAILURE").Observe(0.05)
+	after, err := scrape([]Source{source})
+	assert.NoError(t, err)
+
+	reports := phaseReports(DefaultPhases, before, after)
+	endorsement := reports["endorsement"]
+	assert.Equal(t, 10, endorsement.Count)
+	assert.InDelta(t, 0.15, endorsement.Mean, 1e-9)
+	assert.InDelta(t, 0.15, endorsement.P50, 1e-9)
+	assert.InDelta(t, 0.19, endorsement.P90, 1e-9)
+	assert.Equal(t, 0, reports["ordering"].Count)
+	assert.Equal(t, 0, reports["finality"].Count)
+}
+
+func TestParse(t *testing.T) {
+	samples, err := parse([]byte(`# HELP x_duration a help
+# TYPE x_duration histogram
+x_duration_bucket{network="a \"b\"",le="+Inf"} 3
+x_duration_sum{network="a \"b\""} 1.5
+up 1
+`))
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	assert.Equal(t, "a \"b\"", samples[0].labels["network"])
+	assert.Equal(t, "+Inf", samples[0].labels["le"])
+	assert.Equal(t, 1.5, samples[1].value)
+	assert.Equal(t, "up", samples[2].name)
+
+	_, err = parse([]byte(`x_duration_sum{network="a} 1`))
+	assert.Error(t, err)
+}
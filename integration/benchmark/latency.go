@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmark
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Latency summarizes a set of latencies, all values are in seconds
+type Latency struct {
+	Min  float64
+	Mean float64
+	P50  float64
+	P90  float64
+	P95  float64
+	P99  float64
+	Max  float64
+}
+
+// newLatency returns the summary of the passed latencies, nil if there are none
+func newLatency(latencies []time.Duration) *Latency {
+	if len(latencies) == 0 {
+		return nil
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	return &Latency{
+		Min:  sorted[0].Seconds(),
+		Mean: (sum / time.Duration(len(sorted))).Seconds(),
+		P50:  percentile(sorted, 0.50).Seconds(),
+		P90:  percentile(sorted, 0.90).Seconds(),
+		P95:  percentile(sorted, 0.95).Seconds(),
+		P99:  percentile(sorted, 0.99).Seconds(),
+		Max:  sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// percentile returns the q-th percentile, with the nearest-rank method, of the passed sorted latencies
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,282 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Phase maps a phase of the view protocols to the histogram that measures it on the nodes
+type Phase struct {
+	Name string
+	// Metric is the fully qualified name of the histogram
+	Metric string
+	// Labels restricts the series of the histogram to those with these label values
+	Labels map[string]string
+}
+
+// DefaultPhases are the phases of the endorser transactions: endorsement collection, ordering and finality.
+// Only the successful observations count.
+var DefaultPhases = []Phase{
+	{Name: "endorsement", Metric: "fabric_endorser_endorsement_duration", Labels: map[string]string{"status": "SUCCESS"}},
+	{Name: "ordering", Metric: "fabric_ordering_broadcast_duration", Labels: map[string]string{"status": "SUCCESS"}},
+	{Name: "finality", Metric: "fabric_endorser_finality_duration", Labels: map[string]string{"status": "SUCCESS"}},
+}
+
+// PhaseReport summarizes the time spent in a phase during the measurement window, all values are in seconds.
+// Percentiles are estimated from the histogram buckets.
+type PhaseReport struct {
+	Count int
+	Mean  float64
+	P50   float64
+	P90   float64
+	P99   float64
+}
+
+// Source returns the metrics of a node in the Prometheus text exposition format
+type Source interface {
+	Scrape() ([]byte, error)
+}
+
+// HTTPSource scrapes the metrics endpoint of the operations server of a node
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPSource returns a source scraping the passed URL. If tlsRootCAFile is not empty,
+// the server certificate is verified against the certificates in that file.
+func NewHTTPSource(url, tlsRootCAFile string) (*HTTPSource, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if len(tlsRootCAFile) != 0 {
+		raw, err := ioutil.ReadFile(tlsRootCAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading [%s]", tlsRootCAFile)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return nil, errors.Errorf("no certificates found in [%s]", tlsRootCAFile)
+		}
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+	return &HTTPSource{URL: url, Client: client}, nil
+}
+
+func (s *HTTPSource) Scrape() ([]byte, error) {
+	resp, err := s.Client.Get(s.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed scraping [%s]", s.URL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed scraping [%s], status [%s]", s.URL, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+type sample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// scrape scrapes all the passed sources
+func scrape(sources []Source) ([]sample, error) {
+	var res []sample
+	for _, source := range sources {
+		raw, err := source.Scrape()
+		if err != nil {
+			return nil, err
+		}
+		samples, err := parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, samples...)
+	}
+	return res, nil
+}
+
+// parse parses metrics in the Prometheus text exposition format
+func parse(raw []byte) ([]sample, error) {
+	var res []sample
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s := sample{labels: map[string]string{}}
+		rest := line
+		if i := strings.IndexByte(line, '{'); i >= 0 {
+			j := strings.LastIndexByte(line, '}')
+			if j < i {
+				return nil, errors.Errorf("invalid sample [%s]", line)
+			}
+			s.name = line[:i]
+			if err := parseLabels(line[i+1:j], s.labels); err != nil {
+				return nil, errors.WithMessagef(err, "invalid sample [%s]", line)
+			}
+			rest = line[j+1:]
+		} else {
+			fields := strings.Fields(line)
+			s.name = fields[0]
+			rest = strings.TrimPrefix(line, fields[0])
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, errors.Errorf("invalid sample [%s], no value", line)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid sample [%s]", line)
+		}
+		s.value = value
+		res = append(res, s)
+	}
+	return res, scanner.Err()
+}
+
+// parseLabels parses a list of label pairs like a="x",b="y"
+func parseLabels(raw string, labels map[string]string) error {
+	for len(strings.TrimSpace(raw)) != 0 {
+		raw = strings.TrimLeft(raw, " ,")
+		eq := strings.IndexByte(raw, '=')
+		if eq < 0 || len(raw) < eq+2 || raw[eq+1] != '"' {
+			return errors.Errorf("invalid labels [%s]", raw)
+		}
+		name := strings.TrimSpace(raw[:eq])
+		var value strings.Builder
+		i := eq + 2
+		for ; i < len(raw) && raw[i] != '"'; i++ {
+			if raw[i] == '\\' && i+1 < len(raw) {
+				i++
+				if raw[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(raw[i])
+		}
+		if i >= len(raw) {
+			return errors.Errorf("unterminated label value [%s]", raw)
+		}
+		labels[name] = value.String()
+		raw = raw[i+1:]
+	}
+	return nil
+}
+
+// histogram aggregates the series of a histogram, across sources and label values
+type histogram struct {
+	bounds []float64
+	counts map[float64]float64 // cumulative counts by upper bound
+	sum    float64
+	count  float64
+}
+
+func newHistogram(samples []sample, phase Phase) *histogram {
+	h := &histogram{counts: map[float64]float64{}}
+	for _, s := range samples {
+		if !matches(s.labels, phase.Labels) {
+			continue
+		}
+		switch s.name {
+		case phase.Metric + "_bucket":
+			le, err := strconv.ParseFloat(s.labels["le"], 64)
+			if err != nil {
+				continue
+			}
+			if _, ok := h.counts[le]; !ok {
+				h.bounds = append(h.bounds, le)
+			}
+			h.counts[le] += s.value
+		case phase.Metric + "_sum":
+			h.sum += s.value
+		case phase.Metric + "_count":
+			h.count += s.value
+		}
+	}
+	sort.Float64s(h.bounds)
+	return h
+}
+
+func matches(labels, filter map[string]string) bool {
+	for k, v := range filter {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// sub returns the observations made between the passed histogram and this one
+func (h *histogram) sub(before *histogram) *histogram {
+	res := &histogram{
+		bounds: h.bounds,
+		counts: map[float64]float64{},
+		sum:    h.sum - before.sum,
+		count:  h.count - before.count,
+	}
+	for _, b := range h.bounds {
+		res.counts[b] = h.counts[b] - before.counts[b]
+	}
+	return res
+}
+
+// quantile estimates the q-quantile by linear interpolation within the bucket it falls in,
+// as done by Prometheus' histogram_quantile
+func (h *histogram) quantile(q float64) float64 {
+	if h.count <= 0 || len(h.bounds) == 0 {
+		return 0
+	}
+	rank := q * h.count
+	lower, prev := 0.0, 0.0
+	for _, b := range h.bounds {
+		c := h.counts[b]
+		if c >= rank {
+			if math.IsInf(b, 1) {
+				// the best we can say is that it is above the largest finite bound
+				return lower
+			}
+			if c == prev {
+				return b
+			}
+			return lower + (b-lower)*(rank-prev)/(c-prev)
+		}
+		lower, prev = b, c
+	}
+	return lower
+}
+
+func phaseReports(phases []Phase, before, after []sample) map[string]*PhaseReport {
+	res := map[string]*PhaseReport{}
+	for _, phase := range phases {
+		h := newHistogram(after, phase).sub(newHistogram(before, phase))
+		report := &PhaseReport{Count: int(h.count)}
+		if h.count > 0 {
+			report.Mean = h.sum / h.count
+			report.P50 = h.quantile(0.50)
+			report.P90 = h.quantile(0.90)
+			report.P99 = h.quantile(0.99)
+		}
+		res[phase.Name] = report
+	}
+	return res
+}
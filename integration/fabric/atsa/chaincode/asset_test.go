@@ -25,7 +25,7 @@ var _ = Describe("EndToEnd", func() {
 
 	AfterEach(func() {
 		// Stop the network
-		network.Stop()
+		Expect(network.Stop()).NotTo(HaveOccurred())
 	})
 
 	Describe("Asset Transfer Secured Agreement", func() {
@@ -40,7 +40,7 @@ var _ = Describe("EndToEnd", func() {
 			network, err = integration.GenNetwork(StartPort(), chaincode.Topology()...)
 			Expect(err).NotTo(HaveOccurred())
 			// Start the integration network
-			network.Start()
+			Expect(network.Start()).NotTo(HaveOccurred())
 
 			alice = chaincode.NewClient(network.Client("alice"), network.Identity("alice"))
 			bob = chaincode.NewClient(network.Client("bob"), network.Identity("bob"))
@@ -23,7 +23,7 @@ var _ = Describe("EndToEnd", func() {
 
 	AfterEach(func() {
 		// Stop the network
-		network.Stop()
+		Expect(network.Stop()).NotTo(HaveOccurred())
 	})
 
 	Describe("Asset Transfer Secured Agreement", func() {
@@ -39,7 +39,7 @@ var _ = Describe("EndToEnd", func() {
 			network, err = integration.GenNetwork(StartPort(), nochaincode.Topology()...)
 			Expect(err).NotTo(HaveOccurred())
 			// Start the integration network
-			network.Start()
+			Expect(network.Start()).NotTo(HaveOccurred())
 
 			approver := network.Identity("approver")
 
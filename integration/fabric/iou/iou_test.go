@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/integration"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/fabric/iou"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -21,7 +22,7 @@ var _ = Describe("EndToEnd", func() {
 
 	AfterEach(func() {
 		// Stop the network
-		network.Stop()
+		Expect(network.Stop()).NotTo(HaveOccurred())
 	})
 
 	Describe("IOU Life Cycle", func() {
@@ -31,36 +32,58 @@ var _ = Describe("EndToEnd", func() {
 			network, err = integration.GenNetwork(StartPort(), iou.Topology()...)
 			Expect(err).NotTo(HaveOccurred())
 			// Start the integration network
-			network.Start()
+			Expect(network.Start()).NotTo(HaveOccurred())
 		})
 
 		It("succeeded", func() {
-			res, err := network.Client("borrower").CallView(
-				"create", common.JSONMarshall(&iou.Create{Amount: 10}),
-			)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(res).NotTo(BeNil())
-			id := common.JSONUnmarshalString(res)
-
-			res, err = network.Client("borrower").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(10))
-			res, err = network.Client("lender").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(10))
+			lifeCycle(network)
+		})
+	})
 
-			Expect(id).NotTo(BeNil())
-			_, err = network.Client("borrower").CallView(
-				"update", common.JSONMarshall(&iou.Update{LinearID: id, Amount: 5}),
-			)
+	Describe("IOU Life Cycle With Raft Ordering", func() {
+		BeforeEach(func() {
+			var err error
+			topologies := iou.Topology()
+			// Three raft orderers spread across two orderer organizations
+			topologies[0].(*topology.Topology).SetRaftOrderers(3, "OrdererOrg", "OrdererOrg2")
+			// Create the integration network
+			network, err = integration.GenNetwork(StartPort(), topologies...)
 			Expect(err).NotTo(HaveOccurred())
+			// Start the integration network
+			Expect(network.Start()).NotTo(HaveOccurred())
+		})
 
-			res, err = network.Client("borrower").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(5))
-			res, err = network.Client("lender").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(5))
+		It("succeeded", func() {
+			lifeCycle(network)
 		})
 	})
 })
+
+func lifeCycle(network *integration.Network) {
+	res, err := network.Client("borrower").CallView(
+		"create", common.JSONMarshall(&iou.Create{Amount: 10}),
+	)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(res).NotTo(BeNil())
+	id := common.JSONUnmarshalString(res)
+
+	res, err = network.Client("borrower").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(10))
+	res, err = network.Client("lender").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(10))
+
+	Expect(id).NotTo(BeNil())
+	_, err = network.Client("borrower").CallView(
+		"update", common.JSONMarshall(&iou.Update{LinearID: id, Amount: 5}),
+	)
+	Expect(err).NotTo(HaveOccurred())
+
+	res, err = network.Client("borrower").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(5))
+	res, err = network.Client("lender").CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(common.JSONUnmarshalInt(res)).To(BeEquivalentTo(5))
+}
@@ -62,7 +62,7 @@ var _ = Describe("EndToEnd", func() {
 
 		AfterEach(func() {
 			// Stop the network
-			network.Stop()
+			Expect(network.Stop()).NotTo(HaveOccurred())
 		})
 
 		It("generate artifacts & successful pingpong", func() {
@@ -71,7 +71,7 @@ var _ = Describe("EndToEnd", func() {
 			network, err = integration.GenNetwork(StartPort2(), pingpong.Topology()...)
 			Expect(err).NotTo(HaveOccurred())
 			// Start the integration network
-			network.Start()
+			Expect(network.Start()).NotTo(HaveOccurred())
 			time.Sleep(3 * time.Second)
 			// Get a client for the fsc node labelled initiator
 			initiator := network.Client("initiator")
@@ -87,7 +87,7 @@ var _ = Describe("EndToEnd", func() {
 			network, err = integration.LoadNetwork("./testdata", pingpong.Topology()...)
 			Expect(err).NotTo(HaveOccurred())
 			// Start the integration network
-			network.Start()
+			Expect(network.Start()).NotTo(HaveOccurred())
 			time.Sleep(3 * time.Second)
 			// Get a client for the fsc node labelled initiator
 			initiator := network.Client("initiator")
@@ -5,10 +5,21 @@ SPDX-License-Identifier: Apache-2.0
 */
 package stoprestart
 
-import "github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
 
 type InitiatorViewFactory struct{}
 
 func (i *InitiatorViewFactory) NewView(in []byte) (view.View, error) {
-	return &Initiator{}, nil
+	f := &Initiator{Timeout: 1 * time.Minute}
+	if len(in) != 0 {
+		if err := json.Unmarshal(in, f); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
 }
@@ -17,7 +17,10 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/assert"
 )
 
-type Initiator struct{}
+type Initiator struct {
+	// Timeout is how long to wait for the pong
+	Timeout time.Duration
+}
 
 func (p *Initiator) Call(context view.Context) (interface{}, error) {
 	// Retrieve responder identity
@@ -38,7 +41,7 @@ func (p *Initiator) Call(context view.Context) (interface{}, error) {
 		if m != "pong" {
 			return nil, fmt.Errorf("exptectd pong, got %s", m)
 		}
-	case <-time.After(1 * time.Minute):
+	case <-time.After(p.Timeout):
 		return nil, errors.New("responder didn't pong in time")
 	}
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/integration"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/fabric/iou"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/generic/stoprestart"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
 	. "github.com/onsi/ginkgo"
@@ -29,13 +30,13 @@ var _ = Describe("EndToEnd", func() {
 			network, err = integration.GenNetwork(StartPort(), stoprestart.Topology()...)
 			Expect(err).NotTo(HaveOccurred())
 			// Start the integration network
-			network.Start()
+			Expect(network.Start()).NotTo(HaveOccurred())
 			time.Sleep(3 * time.Second)
 		})
 
 		AfterEach(func() {
 			// Stop the network
-			network.Stop()
+			Expect(network.Stop()).NotTo(HaveOccurred())
 		})
 
 		It("stop and restart successfully", func() {
@@ -43,9 +44,9 @@ var _ = Describe("EndToEnd", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(common.JSONUnmarshalString(res)).To(BeEquivalentTo("OK"))
 
-			network.StopViewNode("bob")
+			Expect(network.StopViewNode("bob")).NotTo(HaveOccurred())
 			time.Sleep(3 * time.Second)
-			network.StartViewNode("bob")
+			Expect(network.StartViewNode("bob")).NotTo(HaveOccurred())
 			time.Sleep(3 * time.Second)
 
 			res, err = network.Client("alice").CallView("init", nil)
@@ -53,6 +54,107 @@ var _ = Describe("EndToEnd", func() {
 			Expect(common.JSONUnmarshalString(res)).To(BeEquivalentTo("OK"))
 		})
 
+		It("recover from a partition", func() {
+			init := common.JSONMarshall(&stoprestart.Initiator{Timeout: 5 * time.Second})
+
+			Expect(network.PartitionViewNodes("alice", "bob")).NotTo(HaveOccurred())
+			_, err := network.Client("alice").CallView("init", init)
+			Expect(err).To(HaveOccurred())
+
+			Expect(network.HealViewNodes("alice", "bob")).NotTo(HaveOccurred())
+			res, err := network.Client("alice").CallView("init", init)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(common.JSONUnmarshalString(res)).To(BeEquivalentTo("OK"))
+		})
+
+		It("tolerate slow links", func() {
+			Expect(network.DelayViewNodes("alice", "bob", time.Second)).NotTo(HaveOccurred())
+			res, err := network.Client("alice").CallView("init", common.JSONMarshall(&stoprestart.Initiator{Timeout: 10 * time.Second}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(common.JSONUnmarshalString(res)).To(BeEquivalentTo("OK"))
+		})
+
+	})
+
+	Describe("Faults with Fabric", func() {
+		const (
+			orderer = "OrdererOrg.orderer"
+			peer    = "Org2.bob"
+		)
+
+		var (
+			network *integration.Network
+			id      string
+		)
+
+		query := func(node string) int {
+			res, err := network.Client(node).CallView("query", common.JSONMarshall(&iou.Query{LinearID: id}))
+			Expect(err).NotTo(HaveOccurred())
+			return common.JSONUnmarshalInt(res)
+		}
+		update := func(amount uint) error {
+			_, err := network.Client("borrower").CallView("update", common.JSONMarshall(&iou.Update{LinearID: id, Amount: amount}))
+			return err
+		}
+
+		BeforeEach(func() {
+			var err error
+			network, err = integration.GenNetwork(StartPort(), stoprestart.FabricTopology()...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(network.Start()).NotTo(HaveOccurred())
+
+			res, err := network.Client("borrower").CallView("create", common.JSONMarshall(&iou.Create{Amount: 10}))
+			Expect(err).NotTo(HaveOccurred())
+			id = common.JSONUnmarshalString(res)
+		})
+
+		AfterEach(func() {
+			Expect(network.Stop()).NotTo(HaveOccurred())
+		})
+
+		It("recover from an orderer killed mid-broadcast", func() {
+			done := make(chan error, 1)
+			go func() { done <- update(5) }()
+			Expect(network.KillMember(orderer)).NotTo(HaveOccurred())
+			Expect(network.StartMember(orderer)).NotTo(HaveOccurred())
+
+			// the update either went through or failed, in both cases the vaults must agree once it is retried.
+			// The update is retried only if it did not get committed, a second update to the same amount is rejected.
+			if err := <-done; err != nil {
+				Eventually(func() int {
+					if query("borrower") != 5 {
+						update(5)
+					}
+					return query("borrower")
+				}, time.Minute, 5*time.Second).Should(BeEquivalentTo(5))
+			}
+			Expect(query("borrower")).To(BeEquivalentTo(5))
+			Eventually(func() int { return query("lender") }, time.Minute, time.Second).Should(BeEquivalentTo(5))
+		})
+
+		It("recover from a partition from the orderer", func() {
+			Expect(network.PartitionFromProcess("borrower", orderer)).NotTo(HaveOccurred())
+			Expect(update(5)).To(HaveOccurred())
+			Expect(query("borrower")).To(BeEquivalentTo(10))
+
+			Expect(network.HealFromProcess("borrower", orderer)).NotTo(HaveOccurred())
+			Eventually(func() error { return update(5) }, time.Minute, 5*time.Second).Should(Succeed())
+			Expect(query("borrower")).To(BeEquivalentTo(5))
+			Eventually(func() int { return query("lender") }, time.Minute, time.Second).Should(BeEquivalentTo(5))
+		})
+
+		It("recover from a peer and a node restart", func() {
+			Expect(network.StopMember(peer)).NotTo(HaveOccurred())
+			Expect(network.StopViewNode("lender")).NotTo(HaveOccurred())
+			Expect(network.StartMember(peer)).NotTo(HaveOccurred())
+			Expect(network.StartViewNode("lender")).NotTo(HaveOccurred())
+			time.Sleep(5 * time.Second)
+
+			// the vault of the restarted node still holds the state and catches up with the new transactions
+			Expect(query("lender")).To(BeEquivalentTo(10))
+			Eventually(func() error { return update(5) }, time.Minute, 5*time.Second).Should(Succeed())
+			Eventually(func() int { return query("lender") }, time.Minute, time.Second).Should(BeEquivalentTo(5))
+		})
 	})
 
 })
@@ -6,13 +6,16 @@ SPDX-License-Identifier: Apache-2.0
 package stoprestart
 
 import (
+	"github.com/hyperledger-labs/fabric-smart-client/integration/fabric/iou"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo"
+	topology2 "github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fsc"
 )
 
 func Topology() []nwo.Topology {
 	// Create an empty FSC topology
 	topology := fsc.NewTopology()
+	topology.EnableFaultInjection()
 
 	topology.AddNodeByName("alice").RegisterViewFactory("init", &InitiatorViewFactory{})
 
@@ -21,3 +24,12 @@ func Topology() []nwo.Topology {
 	)
 	return []nwo.Topology{topology}
 }
+
+// FabricTopology returns the iou topology with fault injection enabled,
+// this makes it possible to check that the vaults recover when the orderer, or a peer, fails
+func FabricTopology() []nwo.Topology {
+	topologies := iou.Topology()
+	topologies[0].(*topology2.Topology).EnableFaultInjection()
+	topologies[1].(*fsc.Topology).EnableFaultInjection()
+	return topologies
+}
@@ -10,12 +10,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/onsi/ginkgo"
-	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
+	"github.com/hyperledger-labs/fabric-smart-client/integration/benchmark"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/deploy"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric"
 	network2 "github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/network"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fsc"
@@ -34,7 +36,7 @@ type BuildServer interface {
 }
 
 type Builder interface {
-	Build(path string) string
+	Build(path string) (string, error)
 }
 
 type Network struct {
@@ -50,22 +52,24 @@ func GenNetwork(startPort int, topologies ...nwo.Topology) (*Network, error) {
 }
 
 func GenNetworkAt(startPort int, path string, topologies ...nwo.Topology) (*Network, error) {
-	RegisterFailHandler(failMe)
-	defer ginkgo.GinkgoRecover()
-
 	// Setup the network
 	var testDir string
 	var err error
 	if len(path) != 0 {
 		testDir, err = filepath.Abs(path)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed getting absolute path of [%s]", path)
+		}
 		if _, err := os.Stat(testDir); os.IsNotExist(err) {
-			err = os.MkdirAll(testDir, 0700)
-			Expect(err).NotTo(HaveOccurred())
+			if err := os.MkdirAll(testDir, 0700); err != nil {
+				return nil, errors.Wrapf(err, "failed creating [%s]", testDir)
+			}
 		}
 	} else {
 		testDir, err = ioutil.TempDir("", "integration")
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed creating temporary folder")
+		}
 	}
 
 	reg := registry.NewRegistry(topologies...)
@@ -76,23 +80,36 @@ func GenNetworkAt(startPort int, path string, topologies ...nwo.Topology) (*Netw
 	var builder Builder
 	var buildServer BuildServer
 	var platforms []nwo.Platform
+	fail := func(err error) (*Network, error) {
+		if buildServer != nil {
+			buildServer.Shutdown()
+		}
+		return nil, err
+	}
 	for _, topology := range topologies {
 		switch strings.ToLower(topology.Name()) {
 		case "fabric":
 			bd := network2.NewBuildServer()
-			bd.Serve()
+			if err := bd.Serve(); err != nil {
+				return fail(err)
+			}
 
 			buildServer = bd
 			builder = bd.Components()
 			reg.Builder = builder
 
-			platform := fabric.NewPlatform(reg, bd.Components())
+			platform, err := fabric.NewPlatform(reg, bd.Components())
+			if err != nil {
+				return fail(err)
+			}
 			reg.AddPlatform(platform.Name(), platform)
 
 			platforms = append(platforms, platform)
 		case "generic":
 			bd := generic.NewBuildServer()
-			bd.Serve()
+			if err := bd.Serve(); err != nil {
+				return fail(err)
+			}
 
 			buildServer = bd
 			builder = bd.Components()
@@ -107,7 +124,9 @@ func GenNetworkAt(startPort int, path string, topologies ...nwo.Topology) (*Netw
 	if len(platforms) == 0 {
 		//  Add generic by default if no other platform has been added
 		bd := generic.NewBuildServer()
-		bd.Serve()
+		if err := bd.Serve(); err != nil {
+			return fail(err)
+		}
 
 		buildServer = bd
 		builder = bd.Components()
@@ -130,24 +149,30 @@ func GenNetworkAt(startPort int, path string, topologies ...nwo.Topology) (*Netw
 		buildServer:  buildServer,
 		deleteOnStop: true,
 	}
-	n.Generate()
-	n.Load()
+	if err := n.Generate(); err != nil {
+		return fail(err)
+	}
+	if err := n.Load(); err != nil {
+		return fail(err)
+	}
 
 	return n, nil
 }
 
 func LoadNetwork(dir string, topologies ...nwo.Topology) (*Network, error) {
-	RegisterFailHandler(failMe)
-	defer ginkgo.GinkgoRecover()
+	testDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting absolute path of [%s]", dir)
+	}
+	if _, err := os.Stat(testDir); os.IsNotExist(err) {
+		return nil, errors.Errorf("network folder [%s] does not exist", testDir)
+	}
 
 	// Setup the network
 	buildServer := network2.NewBuildServer()
-	buildServer.Serve()
-
-	testDir, err := filepath.Abs(dir)
-	Expect(err).NotTo(HaveOccurred())
-	_, err = os.Stat(testDir)
-	Expect(os.IsNotExist(err)).To(BeEquivalentTo(false))
+	if err := buildServer.Serve(); err != nil {
+		return nil, err
+	}
 
 	reg := registry.NewRegistry(topologies...)
 	reg.NetworkID = common.UniqueName()
@@ -157,7 +182,12 @@ func LoadNetwork(dir string, topologies ...nwo.Topology) (*Network, error) {
 	for _, topology := range topologies {
 		switch strings.ToLower(topology.Name()) {
 		case "fabric":
-			platforms = append(platforms, fabric.NewPlatform(reg, buildServer.Components()))
+			platform, err := fabric.NewPlatform(reg, buildServer.Components())
+			if err != nil {
+				buildServer.Shutdown()
+				return nil, err
+			}
+			platforms = append(platforms, platform)
 		case "generic":
 			platforms = append(platforms, generic.NewPlatform(reg, buildServer.Components()))
 		}
@@ -175,30 +205,58 @@ func LoadNetwork(dir string, topologies ...nwo.Topology) (*Network, error) {
 		buildServer:  buildServer,
 		deleteOnStop: false,
 	}
-	n.Load()
+	if err := n.Load(); err != nil {
+		buildServer.Shutdown()
+		return nil, err
+	}
 
 	return n, nil
 }
 
-func (f *Network) Generate() {
-	f.network.Generate()
+func (f *Network) Generate() error {
+	return f.network.Generate()
 }
 
-func (f *Network) Load() {
-	f.network.Load()
+func (f *Network) Load() error {
+	return f.network.Load()
 }
 
-func (f *Network) Start() {
-	f.network.Start()
+func (f *Network) Start() error {
+	return f.network.Start()
 }
 
-func (f *Network) Stop() {
+// Stop stops the network. The network folder is removed if the network was generated.
+func (f *Network) Stop() error {
 	defer f.buildServer.Shutdown()
 	if f.deleteOnStop {
 		defer os.RemoveAll(f.testDir)
 	}
 
-	f.network.Stop()
+	return f.network.Stop()
+}
+
+// Bundle returns the processes of the network as a bundle to be deployed with docker-compose or Kubernetes.
+// The images built by the bundle use the current working folder as build context.
+func (f *Network) Bundle() (*deploy.Bundle, error) {
+	workloads, err := f.network.Workloads()
+	if err != nil {
+		return nil, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting working folder")
+	}
+	return &deploy.Bundle{
+		Name:         f.registry.NetworkID,
+		RootDir:      f.testDir,
+		BuildContext: wd,
+		Workloads:    workloads,
+	}, nil
+}
+
+// Dir returns the folder containing the artifacts of the network
+func (f *Network) Dir() string {
+	return f.testDir
 }
 
 func (f *Network) Client(name string) Client {
@@ -213,14 +271,76 @@ func (f *Network) Identity(name string) view.Identity {
 	return id
 }
 
-func (f *Network) StopViewNode(id string) {
-	f.network.StopViewNode(id)
+func (f *Network) StopViewNode(id string) error {
+	return f.network.StopViewNode(id)
 }
 
-func (f *Network) StartViewNode(id string) {
-	f.network.StartViewNode(id)
+func (f *Network) StartViewNode(id string) error {
+	return f.network.StartViewNode(id)
 }
 
-func failMe(message string, callerSkip ...int) {
-	panic(message)
+// ReloadViewNode makes the fsc node with the passed name reload its configuration and credentials.
+// The reload is asynchronous, the node logs its outcome.
+func (f *Network) ReloadViewNode(id string) error {
+	return f.network.ReloadViewNode(id)
+}
+
+// PartitionViewNodes drops the messages exchanged by the passed fsc nodes.
+// The fsc topology must have fault injection enabled.
+func (f *Network) PartitionViewNodes(a, b string) error {
+	return f.network.PartitionViewNodes(a, b)
+}
+
+// DelayViewNodes delays the messages exchanged by the passed fsc nodes.
+// The fsc topology must have fault injection enabled.
+func (f *Network) DelayViewNodes(a, b string, delay time.Duration) error {
+	return f.network.DelayViewNodes(a, b, delay)
+}
+
+// HealViewNodes removes the faults injected between the passed fsc nodes
+func (f *Network) HealViewNodes(a, b string) error {
+	return f.network.HealViewNodes(a, b)
+}
+
+// PartitionFromProcess cuts the connections of the passed fsc node to the process with the passed identifier,
+// an orderer or a peer (e.g. OrdererOrg.orderer, Org1.peer0). The fabric topology must have fault injection enabled.
+func (f *Network) PartitionFromProcess(node, target string) error {
+	return f.network.PartitionFromProcess(node, target)
+}
+
+// DelayToProcess delays the data exchanged by the passed fsc node and the process with the passed identifier
+func (f *Network) DelayToProcess(node, target string, delay time.Duration) error {
+	return f.network.DelayToProcess(node, target, delay)
+}
+
+// HealFromProcess removes the partition, and the delay, between the passed fsc node and the process
+func (f *Network) HealFromProcess(node, target string) error {
+	return f.network.HealFromProcess(node, target)
+}
+
+// StopMember stops the orderer, or the peer, with the passed identifier.
+// The fabric topology must have fault injection enabled.
+func (f *Network) StopMember(id string) error {
+	return f.network.StopMember(id)
+}
+
+// KillMember kills the orderer, or the peer, with the passed identifier.
+// The fabric topology must have fault injection enabled.
+func (f *Network) KillMember(id string) error {
+	return f.network.KillMember(id)
+}
+
+// StartMember restarts the orderer, or the peer, with the passed identifier
+func (f *Network) StartMember(id string) error {
+	return f.network.StartMember(id)
+}
+
+// MetricsSource returns a benchmark source scraping the metrics of the passed fsc node,
+// it gives the benchmarks the time the node spends in each phase of the view protocols
+func (f *Network) MetricsSource(name string) (benchmark.Source, error) {
+	url, caFile, err := f.network.MetricsEndpoint(name)
+	if err != nil {
+		return nil, err
+	}
+	return benchmark.NewHTTPSource(url, caFile)
 }
@@ -64,21 +64,36 @@ var cobraCommand = &cobra.Command{
 
 // gen read topology and generates artifacts
 func gen(args []string) error {
+	t, err := ReadTopologies(topologyFile)
+	if err != nil {
+		return err
+	}
+
+	_, err = integration.GenNetworkAt(port, output, t...)
+	if err != nil {
+		return errors.Wrapf(err, "failed instantiating generator for [%s]", topologyFile)
+	}
+
+	return nil
+}
+
+// ReadTopologies reads the topologies exported, in yaml format, to the passed file
+func ReadTopologies(topologyFile string) ([]nwo.Topology, error) {
 	if len(topologyFile) == 0 {
-		return errors.Errorf("expecting topology file path")
+		return nil, errors.Errorf("expecting topology file path")
 	}
 	raw, err := ioutil.ReadFile(topologyFile)
 	if err != nil {
-		return errors.Wrapf(err, "failed reading topology file [%s]", topologyFile)
+		return nil, errors.Wrapf(err, "failed reading topology file [%s]", topologyFile)
 	}
 	names := &Topologies{}
 	if err := yaml.Unmarshal(raw, names); err != nil {
-		return errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
+		return nil, errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
 	}
 
 	t := &T{}
 	if err := yaml.Unmarshal(raw, t); err != nil {
-		return errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
+		return nil, errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
 	}
 	t2 := []nwo.Topology{}
 	for i, topology := range names.Topologies {
@@ -87,29 +102,23 @@ func gen(args []string) error {
 			top := fabric.NewDefaultTopology()
 			r, err := yaml.Marshal(t.Topologies[i])
 			if err != nil {
-				return errors.Wrapf(err, "failed remarshalling topology configuration [%s]", topologyFile)
+				return nil, errors.Wrapf(err, "failed remarshalling topology configuration [%s]", topologyFile)
 			}
 			if err := yaml.Unmarshal(r, top); err != nil {
-				return errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
+				return nil, errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
 			}
 			t2 = append(t2, top)
 		case fsc.TopologyName:
 			top := fsc.NewTopology()
 			r, err := yaml.Marshal(t.Topologies[i])
 			if err != nil {
-				return errors.Wrapf(err, "failed remarshalling topology configuration [%s]", topologyFile)
+				return nil, errors.Wrapf(err, "failed remarshalling topology configuration [%s]", topologyFile)
 			}
 			if err := yaml.Unmarshal(r, top); err != nil {
-				return errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
+				return nil, errors.Wrapf(err, "failed unmarshalling topology file [%s]", topologyFile)
 			}
 			t2 = append(t2, top)
 		}
 	}
-
-	_, err = integration.GenNetworkAt(port, output, t2...)
-	if err != nil {
-		return errors.Wrapf(err, "failed instantiating generator for [%s]", topologyFile)
-	}
-
-	return nil
+	return t2, nil
 }
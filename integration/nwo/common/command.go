@@ -9,8 +9,15 @@ package common
 import (
 	"os"
 	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega/gexec"
+	"github.com/pkg/errors"
 )
 
+const retryInterval = time.Second
+
 type Command interface {
 	Args() []string
 	SessionName() string
@@ -53,3 +60,47 @@ func NewCommand(path string, command Command) *exec.Cmd {
 	}
 	return cmd
 }
+
+// WaitSession waits for the passed session to exit with code zero.
+// The session is killed if it is still running when the timeout expires.
+func WaitSession(sess *gexec.Session, timeout time.Duration) error {
+	if _, err := WaitExit(sess, timeout); err != nil {
+		return err
+	}
+	if code := sess.ExitCode(); code != 0 {
+		return errors.Errorf("[%s] exited with code [%d]: %s", commandLine(sess), code, sess.Err.Contents())
+	}
+	return nil
+}
+
+// WaitExit waits for the passed session to exit and returns its exit code.
+// The session is killed if it is still running when the timeout expires.
+func WaitExit(sess *gexec.Session, timeout time.Duration) (int, error) {
+	select {
+	case <-sess.Exited:
+		return sess.ExitCode(), nil
+	case <-time.After(timeout):
+		sess.Kill()
+		return -1, errors.Errorf("[%s] still running after [%s]", commandLine(sess), timeout)
+	}
+}
+
+// Retry invokes the passed function until it succeeds or the timeout expires,
+// in the latter case the last error is returned.
+func Retry(timeout time.Duration, f func() error) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.WithMessagef(err, "still failing after [%s]", timeout)
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+func commandLine(sess *gexec.Session) string {
+	return strings.Join(sess.Command.Args, " ")
+}
@@ -12,34 +12,34 @@ import (
 	"net/http"
 
 	"github.com/hyperledger/fabric/integration/runner"
-	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
 type Components struct {
 	ServerAddress string `json:"server_address"`
 }
 
-func (c *Components) ConfigTxGen() string {
+func (c *Components) ConfigTxGen() (string, error) {
 	return c.Build("github.com/hyperledger/fabric/cmd/configtxgen")
 }
 
-func (c *Components) Cryptogen() string {
+func (c *Components) Cryptogen() (string, error) {
 	return c.Build("github.com/hyperledger-labs/fabric-smart-client/cmd/cryptogen")
 }
 
-func (c *Components) Discover() string {
+func (c *Components) Discover() (string, error) {
 	return c.Build("github.com/hyperledger/fabric/cmd/discover")
 }
 
-func (c *Components) Idemixgen() string {
+func (c *Components) Idemixgen() (string, error) {
 	return c.Build("github.com/hyperledger/fabric/cmd/idemixgen")
 }
 
-func (c *Components) Orderer() string {
+func (c *Components) Orderer() (string, error) {
 	return c.Build("github.com/hyperledger/fabric/cmd/orderer")
 }
 
-func (c *Components) Peer(peerPath string) string {
+func (c *Components) Peer(peerPath string) (string, error) {
 	if len(peerPath) != 0 {
 		return c.Build(peerPath)
 	}
@@ -48,20 +48,27 @@ func (c *Components) Peer(peerPath string) string {
 
 func (c *Components) Cleanup() {}
 
-func (c *Components) Build(path string) string {
-	Expect(c.ServerAddress).NotTo(BeEmpty(), "build server address is empty")
+// Build asks the build server for the binary of the passed package and returns its path
+func (c *Components) Build(path string) (string, error) {
+	if len(c.ServerAddress) == 0 {
+		return "", errors.New("build server address is empty")
+	}
 
 	resp, err := http.Get(fmt.Sprintf("http://%s/%s", c.ServerAddress, path))
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed requesting build of [%s]", path)
+	}
+	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
-	Expect(err).NotTo(HaveOccurred())
-
+	if err != nil {
+		return "", errors.Wrapf(err, "failed reading build of [%s]", path)
+	}
 	if resp.StatusCode != http.StatusOK {
-		Expect(resp.StatusCode).To(Equal(http.StatusOK), fmt.Sprintf("%s", body))
+		return "", errors.Errorf("failed building [%s]: %s", path, body)
 	}
 
-	return string(body)
+	return string(body), nil
 }
 
 const CCEnvDefaultImage = "hyperledger/fabric-ccenv:latest"
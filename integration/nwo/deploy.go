@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package nwo
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/deploy"
+)
+
+// Deployer is implemented by the platforms whose processes can be deployed outside of the nwo runner
+type Deployer interface {
+	Workloads() ([]*deploy.Workload, error)
+}
+
+// Workloads returns the processes of the platforms as deployable workloads.
+// As done by Start, the fsc nodes come after, and depend on, the processes of the other platforms.
+func (n *Network) Workloads() ([]*deploy.Workload, error) {
+	var workloads, fscWorkloads []*deploy.Workload
+	for _, platform := range n.Platforms {
+		d, ok := platform.(Deployer)
+		if !ok {
+			continue
+		}
+		w, err := d.Workloads()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "platform [%s] failed collecting workloads", platform.Name())
+		}
+		if platform.Name() == "fsc" {
+			fscWorkloads = append(fscWorkloads, w...)
+		} else {
+			workloads = append(workloads, w...)
+		}
+	}
+	for _, fscWorkload := range fscWorkloads {
+		for _, w := range workloads {
+			fscWorkload.DependsOn = append(fscWorkload.DependsOn, w.Name)
+		}
+	}
+	return append(workloads, fscWorkloads...), nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ComposeFileName = "docker-compose.yaml"
+	DockerfileName  = "Dockerfile"
+)
+
+// DockerfileTemplate builds the image of a workload from its go package, passed as the PACKAGE build argument,
+// with the golang image whose version is passed as the GO_VERSION build argument
+const DockerfileTemplate = `ARG GO_VERSION
+FROM golang:${GO_VERSION} AS builder
+ARG PACKAGE
+WORKDIR /src
+COPY . .
+RUN CGO_ENABLED=0 go build -o /fscnode ${PACKAGE}
+
+FROM alpine:3.12
+COPY --from=builder /fscnode /usr/local/bin/fscnode
+`
+
+// Binary is the name, in the PATH, of the binary of a workload whose image is built from its package
+const Binary = "fscnode"
+
+type compose struct {
+	Version  string                     `yaml:"version"`
+	Services map[string]*composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image       string            `yaml:"image"`
+	Build       *composeBuild     `yaml:"build,omitempty"`
+	Command     []string          `yaml:"command,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	NetworkMode string            `yaml:"network_mode"`
+	Volumes     []string          `yaml:"volumes"`
+	DependsOn   []string          `yaml:"depends_on,omitempty"`
+}
+
+type composeBuild struct {
+	Context    string            `yaml:"context"`
+	Dockerfile string            `yaml:"dockerfile"`
+	Args       map[string]string `yaml:"args,omitempty"`
+}
+
+// Compose returns the docker-compose file running the workloads of the bundle.
+// The containers share the network of the host and mount the root folder at the same path,
+// so that the configuration generated for the local processes holds.
+// The images of the workloads with a package are built with the Dockerfile at the passed path and,
+// if the bundle has a registry, named after it so that they can be pushed with docker-compose push.
+func Compose(b *Bundle, dockerfile string) ([]byte, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+	c := &compose{
+		Version:  "3.7",
+		Services: map[string]*composeService{},
+	}
+	for _, w := range b.Workloads {
+		s := &composeService{
+			Image:       b.image(w),
+			Command:     w.Command,
+			Environment: w.Env,
+			NetworkMode: "host",
+			Volumes:     []string{b.RootDir + ":" + b.RootDir},
+		}
+		if len(w.Package) != 0 {
+			s.Build = &composeBuild{
+				Context:    b.BuildContext,
+				Dockerfile: dockerfile,
+				Args:       map[string]string{"PACKAGE": w.Package, "GO_VERSION": goVersion()},
+			}
+		}
+		for _, d := range w.DependsOn {
+			s.DependsOn = append(s.DependsOn, Name(d))
+		}
+		c.Services[Name(w.Name)] = s
+	}
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling compose file")
+	}
+	if err := ValidateCompose(raw); err != nil {
+		return nil, errors.WithMessage(err, "invalid compose file")
+	}
+	return raw, nil
+}
+
+// WriteCompose writes to the passed folder the docker-compose file of the bundle together with
+// the Dockerfile of the images it builds, if any
+func WriteCompose(b *Bundle, dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed getting absolute path of [%s]", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed creating [%s]", dir)
+	}
+	dockerfile := filepath.Join(dir, DockerfileName)
+	raw, err := Compose(b, dockerfile)
+	if err != nil {
+		return err
+	}
+	if b.hasPackages() {
+		if err := ioutil.WriteFile(dockerfile, []byte(DockerfileTemplate), 0644); err != nil {
+			return errors.Wrapf(err, "failed writing [%s]", dockerfile)
+		}
+	}
+	path := filepath.Join(dir, ComposeFileName)
+	if err := ioutil.WriteFile(path, raw, 0644); err != nil {
+		return errors.Wrapf(err, "failed writing [%s]", path)
+	}
+	return nil
+}
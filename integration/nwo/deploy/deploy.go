@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package deploy turns the processes of a generated network into deployable bundles,
+// a docker-compose file or a set of Kubernetes manifests.
+// The bundles run the very same processes, with the very same configuration and crypto material,
+// the nwo runner starts locally. Channel creation and chaincode deployment, done by the platforms
+// once the network is up, are not part of the bundles.
+package deploy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Workload is a process of the network
+type Workload struct {
+	// Name identifies the workload in the network
+	Name string
+	// Image is the container image the workload runs in
+	Image string
+	// Package, if not empty, is the go package whose binary the image must contain.
+	// The image is then built by the bundle itself, see PackageImage.
+	Package string
+	// Command is the command line of the workload
+	Command []string
+	// Env is the environment of the workload
+	Env map[string]string
+	// Ports are the ports the workload listens to
+	Ports []uint16
+	// DependsOn lists the workloads to be started before this one
+	DependsOn []string
+}
+
+// Bundle describes a generated network to be deployed
+type Bundle struct {
+	// Name of the network
+	Name string
+	// RootDir is the folder containing the artifacts of the network.
+	// The configuration of the workloads refers to it, therefore it is mounted at the same path in the containers.
+	RootDir string
+	// BuildContext is the folder the images of the workloads with a package are built from
+	BuildContext string
+	// Registry, if not empty, is the registry the images of the workloads with a package are pushed to.
+	// It is required by Kubernetes, whose nodes pull the images.
+	Registry  string
+	Workloads []*Workload
+}
+
+// file is a file of the root folder of a bundle
+type file struct {
+	// Path relative to the root folder
+	Path    string
+	Content []byte
+	Secret  bool
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Name turns the passed name into a valid container and service name
+func Name(name string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// PackageImage returns the image of the passed workload of the passed network, built from its package.
+// The image is tagged with the network, so that images of different networks never clash.
+func PackageImage(network, workload string) string {
+	return fmt.Sprintf("fsc/%s:%s", Name(workload), Name(network))
+}
+
+// image returns the image the passed workload runs in, as pushed to the registry of the bundle
+func (b *Bundle) image(w *Workload) string {
+	if len(w.Package) == 0 || len(b.Registry) == 0 {
+		return w.Image
+	}
+	return path.Join(b.Registry, w.Image)
+}
+
+// goVersion returns the version of the golang image the images of the workloads with a package
+// are built with, the one of the running toolchain
+func goVersion() string {
+	if v := runtime.Version(); strings.HasPrefix(v, "go") {
+		return strings.TrimPrefix(v, "go")
+	}
+	return "latest"
+}
+
+// files returns the files under the root folder of the bundle, sorted by path.
+// The passed folders are skipped.
+func (b *Bundle) files(skip ...string) ([]*file, error) {
+	var files []*file
+	err := filepath.Walk(b.RootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			for _, s := range skip {
+				if path == s {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(b.RootDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &file{
+			Path:    filepath.ToSlash(rel),
+			Content: content,
+			Secret:  isSecret(rel),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed collecting files in [%s]", b.RootDir)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// isSecret returns true if the file at the passed path contains key material
+func isSecret(path string) bool {
+	base := filepath.Base(path)
+	return filepath.Base(filepath.Dir(path)) == "keystore" ||
+		strings.HasSuffix(base, "_sk") ||
+		strings.HasSuffix(base, ".key")
+}
+
+func (b *Bundle) check() error {
+	if len(b.Workloads) == 0 {
+		return errors.New("no workload to deploy")
+	}
+	if !filepath.IsAbs(b.RootDir) {
+		return errors.Errorf("root folder [%s] must be absolute", b.RootDir)
+	}
+	names := map[string]bool{}
+	for _, w := range b.Workloads {
+		name := Name(w.Name)
+		if len(name) == 0 {
+			return errors.Errorf("invalid workload name [%s]", w.Name)
+		}
+		if names[name] {
+			return errors.Errorf("workload name [%s] used more than once", name)
+		}
+		names[name] = true
+		if len(w.Image) == 0 {
+			return errors.Errorf("workload [%s] has no image", w.Name)
+		}
+		if len(w.Package) != 0 && len(b.BuildContext) == 0 {
+			return errors.Errorf("workload [%s] must be built but no build context is set", w.Name)
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) hasPackages() bool {
+	for _, w := range b.Workloads {
+		if len(w.Package) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
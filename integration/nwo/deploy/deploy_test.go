@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploy
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func newBundle(t *testing.T) *Bundle {
+	root, err := ioutil.TempDir("", "deploy")
+	assert.NoError(t, err)
+	for path, content := range map[string]string{
+		"fscnodes/alice/core.yaml":          "fsc:\n  id: alice\n",
+		"crypto/org/alice/msp/keystore/key": "secret key",
+		"crypto/org/alice/tls/server.key":   "tls key",
+		"crypto/org/alice/tls/server.crt":   "tls cert",
+		"orderers/orderer/genesis.block":    "\xff\xfe binary",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+	}
+	return &Bundle{
+		Name:         "Test.Network",
+		RootDir:      root,
+		BuildContext: "/src",
+		Registry:     "registry.example.com:5000",
+		Workloads: []*Workload{
+			{
+				Name:    "orderer.example.com",
+				Image:   "hyperledger/fabric-orderer:2.2",
+				Command: []string{"orderer"},
+				Env:     map[string]string{"FABRIC_CFG_PATH": filepath.Join(root, "orderers/orderer")},
+				Ports:   []uint16{7050},
+			},
+			{
+				Name:      "alice",
+				Image:     PackageImage("Test.Network", "alice"),
+				Package:   "github.com/org/alice",
+				Command:   []string{Binary, "node", "start"},
+				Env:       map[string]string{"FSCNODE_CFG_PATH": filepath.Join(root, "fscnodes/alice")},
+				Ports:     []uint16{20000, 20001},
+				DependsOn: []string{"orderer.example.com"},
+			},
+		},
+	}
+}
+
+func TestCompose(t *testing.T) {
+	b := newBundle(t)
+	defer os.RemoveAll(b.RootDir)
+
+	raw, err := Compose(b, "/bundle/Dockerfile")
+	assert.NoError(t, err)
+	c := &compose{}
+	assert.NoError(t, yaml.UnmarshalStrict(raw, c))
+	assert.Equal(t, "3.7", c.Version)
+	assert.Len(t, c.Services, 2)
+
+	orderer := c.Services["orderer-example-com"]
+	assert.NotNil(t, orderer)
+	assert.Nil(t, orderer.Build)
+	assert.Equal(t, "host", orderer.NetworkMode)
+	assert.Equal(t, []string{b.RootDir + ":" + b.RootDir}, orderer.Volumes)
+
+	alice := c.Services["alice"]
+	assert.NotNil(t, alice)
+	assert.Equal(t, "registry.example.com:5000/fsc/alice:test-network", alice.Image)
+	assert.Equal(t, &composeBuild{
+		Context:    "/src",
+		Dockerfile: "/bundle/Dockerfile",
+		Args:       map[string]string{"PACKAGE": "github.com/org/alice", "GO_VERSION": goVersion()},
+	}, alice.Build)
+	assert.Equal(t, []string{"orderer-example-com"}, alice.DependsOn)
+
+	b.Workloads[1].Name = "Orderer.Example.com"
+	_, err = Compose(b, "/bundle/Dockerfile")
+	assert.EqualError(t, err, "workload name [orderer-example-com] used more than once")
+}
+
+func TestKubernetes(t *testing.T) {
+	b := newBundle(t)
+	defer os.RemoveAll(b.RootDir)
+
+	raw, err := Kubernetes(b)
+	assert.NoError(t, err)
+
+	var objects []string
+	deployments := map[string]*deployment{}
+	services := map[string]*service{}
+	items := map[string]string{}
+	data := map[string]string{}
+	for _, doc := range strings.Split(string(raw), "---\n") {
+		header := &struct {
+			APIVersion string     `yaml:"apiVersion"`
+			Kind       string     `yaml:"kind"`
+			Metadata   objectMeta `yaml:"metadata"`
+		}{}
+		assert.NoError(t, yaml.Unmarshal([]byte(doc), header))
+		assert.NotEmpty(t, header.APIVersion)
+		assert.NotEmpty(t, header.Metadata.Name)
+		objects = append(objects, header.Kind+"/"+header.Metadata.Name)
+
+		switch header.Kind {
+		case "ConfigMap":
+			cm := &configMap{}
+			assert.NoError(t, yaml.UnmarshalStrict([]byte(doc), cm))
+			for k, v := range cm.Data {
+				data[k] = v
+			}
+			for k, v := range cm.BinaryData {
+				decoded, err := base64.StdEncoding.DecodeString(v)
+				assert.NoError(t, err)
+				data[k] = string(decoded)
+			}
+		case "Secret":
+			s := &secret{}
+			assert.NoError(t, yaml.UnmarshalStrict([]byte(doc), s))
+			for k, v := range s.Data {
+				decoded, err := base64.StdEncoding.DecodeString(v)
+				assert.NoError(t, err)
+				data[k] = string(decoded)
+			}
+		case "Deployment":
+			d := &deployment{}
+			assert.NoError(t, yaml.UnmarshalStrict([]byte(doc), d))
+			deployments[header.Metadata.Name] = d
+		case "Service":
+			svc := &service{}
+			assert.NoError(t, yaml.UnmarshalStrict([]byte(doc), svc))
+			services[header.Metadata.Name] = svc
+		}
+	}
+	assert.Equal(t, []string{
+		"Secret/test-network-keys-0",
+		"ConfigMap/test-network-files-0",
+		"Deployment/test-network-orderer-example-com",
+		"Service/test-network-orderer-example-com",
+		"Deployment/test-network-alice",
+		"Service/test-network-alice",
+	}, objects)
+
+	// all the files are projected at their path
+	d := deployments["test-network-alice"]
+	assert.NotNil(t, d)
+	spec := d.Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 2)
+	for _, source := range spec.Volumes[0].Projected.Sources {
+		s := source.ConfigMap
+		if s == nil {
+			s = source.Secret
+		}
+		for _, item := range s.Items {
+			items[item.Path] = data[item.Key]
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"fscnodes/alice/core.yaml":          "fsc:\n  id: alice\n",
+		"crypto/org/alice/msp/keystore/key": "secret key",
+		"crypto/org/alice/tls/server.key":   "tls key",
+		"crypto/org/alice/tls/server.crt":   "tls cert",
+		"orderers/orderer/genesis.block":    "\xff\xfe binary",
+	}, items)
+
+	// one pod per workload, on the network of the host of the others, mounting the root folder
+	assert.True(t, spec.HostNetwork)
+	assert.Equal(t, map[string]string{partOfLabel: "test-network"}, spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector.MatchLabels)
+	assert.Len(t, spec.InitContainers, 1)
+	assert.Len(t, spec.Containers, 1)
+	assert.Equal(t, "alice", spec.Containers[0].Name)
+	assert.Equal(t, "registry.example.com:5000/fsc/alice:test-network", spec.Containers[0].Image)
+	assert.Equal(t, []envVar{{Name: "FSCNODE_CFG_PATH", Value: filepath.Join(b.RootDir, "fscnodes/alice")}}, spec.Containers[0].Env)
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		assert.Contains(t, c.VolumeMounts, volumeMount{Name: rootVolume, MountPath: b.RootDir})
+	}
+	assert.Equal(t, "hyperledger/fabric-orderer:2.2", deployments["test-network-orderer-example-com"].Spec.Template.Spec.Containers[0].Image)
+
+	svc := services["test-network-alice"]
+	assert.NotNil(t, svc)
+	assert.Len(t, svc.Spec.Ports, 2)
+	assert.Equal(t, d.Spec.Selector.MatchLabels, svc.Spec.Selector)
+
+	// the built images must be pushed somewhere
+	b.Registry = ""
+	_, err = Kubernetes(b)
+	assert.EqualError(t, err, "the bundle builds images, a registry to push them to must be set")
+}
+
+func TestValidateCompose(t *testing.T) {
+	b := newBundle(t)
+	defer os.RemoveAll(b.RootDir)
+	raw, err := Compose(b, "/bundle/Dockerfile")
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateCompose(raw))
+
+	for _, tc := range []struct {
+		old, new, err string
+	}{
+		{"version: \"3.7\"", "version: \"2\"", "invalid version [2]"},
+		{"network_mode: host", "network_mode: host\n    privileged: true", "failed unmarshalling compose file"},
+		{"- orderer-example-com", "- bob", "service [alice] depends on invalid service [bob]"},
+	} {
+		err := ValidateCompose([]byte(strings.Replace(string(raw), tc.old, tc.new, 1)))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), tc.err)
+	}
+}
+
+func TestValidateKubernetes(t *testing.T) {
+	b := newBundle(t)
+	defer os.RemoveAll(b.RootDir)
+	raw, err := Kubernetes(b)
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateKubernetes(raw))
+
+	for _, tc := range []struct {
+		old, new, err string
+	}{
+		{"kind: Deployment", "kind: StatefulSet", "unsupported kind [apps/v1/StatefulSet]"},
+		{"replicas: 1", "replicas: 1\n  paused: true", "failed unmarshalling manifest"},
+		{"key: file-0000", "key: file-9999", "projects unknown key [file-9999]"},
+		{"name: test-network-alice\n", "name: Test_Alice\n", "invalid name [Test_Alice]"},
+		{"mountPath: /bundle", "mountPath: bundle", "at relative path [bundle]"},
+		{"selector:\n    app.kubernetes.io/name: alice\n    app.kubernetes.io/part-of: test-network\n  ports", "selector:\n    app.kubernetes.io/name: bob\n  ports", "selector matches no pod"},
+	} {
+		err := ValidateKubernetes([]byte(strings.Replace(string(raw), tc.old, tc.new, 1)))
+		assert.Error(t, err, tc.err)
+		if err != nil {
+			assert.Contains(t, err.Error(), tc.err)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	KubernetesFileName = "kubernetes.yaml"
+	ImagesScriptName   = "images.sh"
+
+	// maxObjectData bounds the data of a ConfigMap or Secret, Kubernetes rejects objects larger than 1MiB
+	maxObjectData = 900 * 1024
+
+	bundleVolume = "bundle"
+	rootVolume   = "root"
+	bundleDir    = "/bundle"
+
+	partOfLabel         = "app.kubernetes.io/part-of"
+	nameLabel           = "app.kubernetes.io/name"
+	hostnameTopologyKey = "kubernetes.io/hostname"
+	documentSeparator   = "---\n"
+)
+
+type objectMeta struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type configMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   objectMeta        `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+	BinaryData map[string]string `yaml:"binaryData,omitempty"`
+}
+
+type secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   objectMeta        `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type deployment struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   objectMeta     `yaml:"metadata"`
+	Spec       deploymentSpec `yaml:"spec"`
+}
+
+type deploymentSpec struct {
+	Replicas int             `yaml:"replicas"`
+	Selector labelSelector   `yaml:"selector"`
+	Strategy strategy        `yaml:"strategy"`
+	Template podTemplateSpec `yaml:"template"`
+}
+
+type labelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type strategy struct {
+	Type string `yaml:"type"`
+}
+
+type podTemplateSpec struct {
+	Metadata objectMeta `yaml:"metadata"`
+	Spec     podSpec    `yaml:"spec"`
+}
+
+type podSpec struct {
+	HostNetwork    bool        `yaml:"hostNetwork,omitempty"`
+	DNSPolicy      string      `yaml:"dnsPolicy,omitempty"`
+	Affinity       *affinity   `yaml:"affinity,omitempty"`
+	InitContainers []container `yaml:"initContainers,omitempty"`
+	Containers     []container `yaml:"containers"`
+	Volumes        []volume    `yaml:"volumes"`
+}
+
+type affinity struct {
+	PodAffinity *podAffinity `yaml:"podAffinity,omitempty"`
+}
+
+type podAffinity struct {
+	RequiredDuringSchedulingIgnoredDuringExecution []podAffinityTerm `yaml:"requiredDuringSchedulingIgnoredDuringExecution"`
+}
+
+type podAffinityTerm struct {
+	LabelSelector labelSelector `yaml:"labelSelector"`
+	TopologyKey   string        `yaml:"topologyKey"`
+}
+
+type container struct {
+	Name         string          `yaml:"name"`
+	Image        string          `yaml:"image"`
+	Command      []string        `yaml:"command,omitempty"`
+	Env          []envVar        `yaml:"env,omitempty"`
+	Ports        []containerPort `yaml:"ports,omitempty"`
+	VolumeMounts []volumeMount   `yaml:"volumeMounts"`
+}
+
+type envVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type containerPort struct {
+	ContainerPort uint16 `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol"`
+}
+
+type volumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+type volume struct {
+	Name      string           `yaml:"name"`
+	Projected *projectedVolume `yaml:"projected,omitempty"`
+	EmptyDir  *struct{}        `yaml:"emptyDir,omitempty"`
+}
+
+type projectedVolume struct {
+	Sources []volumeProjection `yaml:"sources"`
+}
+
+type volumeProjection struct {
+	ConfigMap *projectionSource `yaml:"configMap,omitempty"`
+	Secret    *projectionSource `yaml:"secret,omitempty"`
+}
+
+type projectionSource struct {
+	Name  string      `yaml:"name"`
+	Items []keyToPath `yaml:"items"`
+}
+
+type keyToPath struct {
+	Key  string `yaml:"key"`
+	Path string `yaml:"path"`
+}
+
+type service struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   objectMeta  `yaml:"metadata"`
+	Spec       serviceSpec `yaml:"spec"`
+}
+
+type serviceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []servicePort     `yaml:"ports"`
+}
+
+type servicePort struct {
+	Name       string `yaml:"name"`
+	Port       uint16 `yaml:"port"`
+	TargetPort uint16 `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
+
+// Kubernetes returns the Kubernetes manifests running the workloads of the bundle.
+// The files of the root folder are carried by ConfigMaps, key material by Secrets.
+// Each workload runs in a Deployment of its own, exposed by a Service of its own.
+// As done by Compose, the pods share the network of the host, and are therefore scheduled on the same node,
+// so that they reach each other on localhost as the configuration generated for the local processes expects.
+// An init container copies the files to a writable volume mounted at the path of the root folder.
+// The images of the workloads with a package are pulled from the registry of the bundle.
+// The passed folders are not part of the bundle.
+func Kubernetes(b *Bundle, skip ...string) ([]byte, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+	if b.hasPackages() && len(b.Registry) == 0 {
+		return nil, errors.New("the bundle builds images, a registry to push them to must be set")
+	}
+	files, err := b.files(skip...)
+	if err != nil {
+		return nil, err
+	}
+
+	name := Name(b.Name)
+	if len(name) == 0 {
+		return nil, errors.Errorf("invalid network name [%s]", b.Name)
+	}
+	labels := map[string]string{partOfLabel: name}
+
+	var objects []interface{}
+	var sources []volumeProjection
+
+	// ConfigMaps and Secrets, split to stay within the size limit
+	var cm *configMap
+	var cmSource *projectionSource
+	var s *secret
+	var sSource *projectionSource
+	var cmSize, sSize, cmCount, sCount int
+	for i, f := range files {
+		if len(f.Content) > maxObjectData {
+			return nil, errors.Errorf("file [%s] is too large, [%d] bytes", f.Path, len(f.Content))
+		}
+		key := fmt.Sprintf("file-%04d", i)
+		if f.Secret {
+			if s == nil || sSize+len(f.Content) > maxObjectData {
+				s = &secret{
+					APIVersion: "v1",
+					Kind:       "Secret",
+					Metadata:   objectMeta{Name: fmt.Sprintf("%s-keys-%d", name, sCount), Labels: labels},
+					Type:       "Opaque",
+					Data:       map[string]string{},
+				}
+				sCount++
+				sSource = &projectionSource{Name: s.Metadata.Name}
+				objects = append(objects, s)
+				sources = append(sources, volumeProjection{Secret: sSource})
+				sSize = 0
+			}
+			s.Data[key] = base64.StdEncoding.EncodeToString(f.Content)
+			sSource.Items = append(sSource.Items, keyToPath{Key: key, Path: f.Path})
+			sSize += len(f.Content)
+			continue
+		}
+
+		if cm == nil || cmSize+len(f.Content) > maxObjectData {
+			cm = &configMap{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Metadata:   objectMeta{Name: fmt.Sprintf("%s-files-%d", name, cmCount), Labels: labels},
+			}
+			cmCount++
+			cmSource = &projectionSource{Name: cm.Metadata.Name}
+			objects = append(objects, cm)
+			sources = append(sources, volumeProjection{ConfigMap: cmSource})
+			cmSize = 0
+		}
+		if utf8.Valid(f.Content) {
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[key] = string(f.Content)
+		} else {
+			if cm.BinaryData == nil {
+				cm.BinaryData = map[string]string{}
+			}
+			cm.BinaryData[key] = base64.StdEncoding.EncodeToString(f.Content)
+		}
+		cmSource.Items = append(cmSource.Items, keyToPath{Key: key, Path: f.Path})
+		cmSize += len(f.Content)
+	}
+
+	// A Deployment and a Service for each workload
+	rootMount := volumeMount{Name: rootVolume, MountPath: b.RootDir}
+	for _, w := range b.Workloads {
+		workloadName := Name(w.Name)
+		workloadLabels := map[string]string{partOfLabel: name, nameLabel: workloadName}
+		c := container{
+			Name:         workloadName,
+			Image:        b.image(w),
+			Command:      w.Command,
+			VolumeMounts: []volumeMount{rootMount},
+		}
+		for _, k := range sortedKeys(w.Env) {
+			c.Env = append(c.Env, envVar{Name: k, Value: w.Env[k]})
+		}
+		svc := &service{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   objectMeta{Name: name + "-" + workloadName, Labels: workloadLabels},
+			Spec:       serviceSpec{Selector: workloadLabels},
+		}
+		for _, port := range w.Ports {
+			c.Ports = append(c.Ports, containerPort{ContainerPort: port, Protocol: "TCP"})
+			svc.Spec.Ports = append(svc.Spec.Ports, servicePort{
+				Name:       fmt.Sprintf("p%d", port),
+				Port:       port,
+				TargetPort: port,
+				Protocol:   "TCP",
+			})
+		}
+
+		objects = append(objects, &deployment{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Metadata:   objectMeta{Name: name + "-" + workloadName, Labels: workloadLabels},
+			Spec: deploymentSpec{
+				Replicas: 1,
+				Selector: labelSelector{MatchLabels: workloadLabels},
+				// the workload owns its ports and state, two pods must never run at the same time
+				Strategy: strategy{Type: "Recreate"},
+				Template: podTemplateSpec{
+					Metadata: objectMeta{Labels: workloadLabels},
+					Spec: podSpec{
+						HostNetwork: true,
+						DNSPolicy:   "ClusterFirstWithHostNet",
+						Affinity: &affinity{PodAffinity: &podAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []podAffinityTerm{{
+								LabelSelector: labelSelector{MatchLabels: labels},
+								TopologyKey:   hostnameTopologyKey,
+							}},
+						}},
+						InitContainers: []container{{
+							Name:    "bundle",
+							Image:   "busybox:1.32",
+							Command: []string{"sh", "-c", fmt.Sprintf("cd %s && cp -rL * %s/", bundleDir, b.RootDir)},
+							VolumeMounts: []volumeMount{
+								{Name: bundleVolume, MountPath: bundleDir, ReadOnly: true},
+								rootMount,
+							},
+						}},
+						Containers: []container{c},
+						Volumes: []volume{
+							{Name: bundleVolume, Projected: &projectedVolume{Sources: sources}},
+							{Name: rootVolume, EmptyDir: &struct{}{}},
+						},
+					},
+				},
+			},
+		})
+		if len(svc.Spec.Ports) != 0 {
+			objects = append(objects, svc)
+		}
+	}
+
+	var out bytes.Buffer
+	for i, o := range objects {
+		raw, err := yaml.Marshal(o)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed marshalling manifest")
+		}
+		if i > 0 {
+			out.WriteString(documentSeparator)
+		}
+		out.Write(raw)
+	}
+	if err := ValidateKubernetes(out.Bytes()); err != nil {
+		return nil, errors.WithMessage(err, "invalid manifests")
+	}
+	return out.Bytes(), nil
+}
+
+// WriteKubernetes writes to the passed folder the Kubernetes manifests of the bundle together with
+// the Dockerfile of the images they use, if any, and the script building and pushing them
+func WriteKubernetes(b *Bundle, dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed getting absolute path of [%s]", dir)
+	}
+	raw, err := Kubernetes(b, dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "failed creating [%s]", dir)
+	}
+	if b.hasPackages() {
+		dockerfile := filepath.Join(dir, DockerfileName)
+		if err := ioutil.WriteFile(dockerfile, []byte(DockerfileTemplate), 0644); err != nil {
+			return errors.Wrapf(err, "failed writing [%s]", dockerfile)
+		}
+		script := filepath.Join(dir, ImagesScriptName)
+		if err := ioutil.WriteFile(script, ImagesScript(b, dockerfile), 0755); err != nil {
+			return errors.Wrapf(err, "failed writing [%s]", script)
+		}
+	}
+	path := filepath.Join(dir, KubernetesFileName)
+	if err := ioutil.WriteFile(path, raw, 0644); err != nil {
+		return errors.Wrapf(err, "failed writing [%s]", path)
+	}
+	return nil
+}
+
+// ImagesScript returns the shell script building, with the Dockerfile at the passed path,
+// the images of the workloads with a package and pushing them to the registry of the bundle
+func ImagesScript(b *Bundle, dockerfile string) []byte {
+	var out bytes.Buffer
+	out.WriteString("#!/bin/sh\nset -e\n")
+	for _, w := range b.Workloads {
+		if len(w.Package) == 0 {
+			continue
+		}
+		fmt.Fprintf(&out, "docker build -f %q --build-arg GO_VERSION=%s --build-arg PACKAGE=%q -t %q %q\n",
+			dockerfile, goVersion(), w.Package, b.image(w), b.BuildContext)
+		fmt.Fprintf(&out, "docker push %q\n", b.image(w))
+	}
+	return out.Bytes()
+}
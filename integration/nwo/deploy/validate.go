@@ -0,0 +1,335 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deploy
+
+import (
+	"encoding/base64"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// The following checks implement, offline, the constraints the schemas of the compose file format
+// and of the Kubernetes API put on the fields the bundles use. Unknown fields are rejected, as done
+// by the schemas for the objects and by the API server with strict field validation.
+
+var (
+	composeVersion     = regexp.MustCompile(`^3(\.[0-9]+)?$`)
+	composeServiceName = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	dns1123Label       = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	dns1123Subdomain   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	labelValue         = regexp.MustCompile(`^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$`)
+	labelName          = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	dataKey            = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	envVarName         = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+)
+
+// ValidateCompose checks the passed docker-compose file against the compose file format
+func ValidateCompose(raw []byte) error {
+	c := &compose{}
+	if err := yaml.UnmarshalStrict(raw, c); err != nil {
+		return errors.Wrap(err, "failed unmarshalling compose file")
+	}
+	if !composeVersion.MatchString(c.Version) {
+		return errors.Errorf("invalid version [%s]", c.Version)
+	}
+	if len(c.Services) == 0 {
+		return errors.New("no service")
+	}
+	for name, s := range c.Services {
+		if !composeServiceName.MatchString(name) {
+			return errors.Errorf("invalid service name [%s]", name)
+		}
+		if len(s.Image) == 0 && s.Build == nil {
+			return errors.Errorf("service [%s] has neither image nor build", name)
+		}
+		if s.Build != nil && len(s.Build.Context) == 0 {
+			return errors.Errorf("service [%s] has no build context", name)
+		}
+		for k := range s.Environment {
+			if len(k) == 0 {
+				return errors.Errorf("service [%s] has an empty environment variable name", name)
+			}
+		}
+		for _, v := range s.Volumes {
+			parts := strings.Split(v, ":")
+			if len(parts) < 2 || len(parts) > 3 || !path.IsAbs(parts[1]) {
+				return errors.Errorf("service [%s] has invalid volume [%s]", name, v)
+			}
+		}
+		for _, d := range s.DependsOn {
+			if _, ok := c.Services[d]; !ok || d == name {
+				return errors.Errorf("service [%s] depends on invalid service [%s]", name, d)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateKubernetes checks the passed Kubernetes manifests against the schemas of their objects.
+// The references among the objects, from the volumes to the ConfigMaps and Secrets and from the
+// Services to the pods, must resolve within the manifests.
+func ValidateKubernetes(raw []byte) error {
+	keys := map[string]map[string]bool{}
+	var deployments []*deployment
+	var services []*service
+	for i, doc := range strings.Split(string(raw), documentSeparator) {
+		header := &struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+		}{}
+		if err := yaml.Unmarshal([]byte(doc), header); err != nil {
+			return errors.Wrapf(err, "failed unmarshalling manifest [%d]", i)
+		}
+		var meta objectMeta
+		switch header.APIVersion + "/" + header.Kind {
+		case "v1/ConfigMap":
+			cm := &configMap{}
+			if err := yaml.UnmarshalStrict([]byte(doc), cm); err != nil {
+				return errors.Wrapf(err, "failed unmarshalling manifest [%d]", i)
+			}
+			meta = cm.Metadata
+			if err := validateData(meta.Name, cm.Data, cm.BinaryData, keys); err != nil {
+				return err
+			}
+		case "v1/Secret":
+			s := &secret{}
+			if err := yaml.UnmarshalStrict([]byte(doc), s); err != nil {
+				return errors.Wrapf(err, "failed unmarshalling manifest [%d]", i)
+			}
+			meta = s.Metadata
+			if err := validateData(meta.Name, nil, s.Data, keys); err != nil {
+				return err
+			}
+		case "apps/v1/Deployment":
+			d := &deployment{}
+			if err := yaml.UnmarshalStrict([]byte(doc), d); err != nil {
+				return errors.Wrapf(err, "failed unmarshalling manifest [%d]", i)
+			}
+			meta = d.Metadata
+			deployments = append(deployments, d)
+		case "v1/Service":
+			s := &service{}
+			if err := yaml.UnmarshalStrict([]byte(doc), s); err != nil {
+				return errors.Wrapf(err, "failed unmarshalling manifest [%d]", i)
+			}
+			meta = s.Metadata
+			if !dns1123Label.MatchString(meta.Name) || len(meta.Name) > 63 {
+				return errors.Errorf("invalid service name [%s]", meta.Name)
+			}
+			services = append(services, s)
+		default:
+			return errors.Errorf("manifest [%d] has unsupported kind [%s/%s]", i, header.APIVersion, header.Kind)
+		}
+		if err := validateMeta(meta); err != nil {
+			return errors.WithMessagef(err, "invalid %s", header.Kind)
+		}
+	}
+
+	for _, d := range deployments {
+		if err := validateDeployment(d, keys); err != nil {
+			return errors.WithMessagef(err, "invalid deployment [%s]", d.Metadata.Name)
+		}
+	}
+	for _, s := range services {
+		if err := validateService(s, deployments); err != nil {
+			return errors.WithMessagef(err, "invalid service [%s]", s.Metadata.Name)
+		}
+	}
+	return nil
+}
+
+func validateMeta(meta objectMeta) error {
+	if !dns1123Subdomain.MatchString(meta.Name) || len(meta.Name) > 253 {
+		return errors.Errorf("invalid name [%s]", meta.Name)
+	}
+	if err := validateLabels(meta.Labels); err != nil {
+		return errors.WithMessagef(err, "invalid [%s]", meta.Name)
+	}
+	return nil
+}
+
+func validateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if !labelName.MatchString(k) || len(k) > 316 {
+			return errors.Errorf("invalid label name [%s]", k)
+		}
+		if !labelValue.MatchString(v) || len(v) > 63 {
+			return errors.Errorf("invalid value [%s] of label [%s]", v, k)
+		}
+	}
+	return nil
+}
+
+// validateData checks the keys and the base64 encoded values of a ConfigMap or Secret,
+// and records its keys
+func validateData(name string, data, binaryData map[string]string, keys map[string]map[string]bool) error {
+	if _, ok := keys[name]; ok {
+		return errors.Errorf("[%s] defined more than once", name)
+	}
+	keys[name] = map[string]bool{}
+	for k := range data {
+		keys[name][k] = true
+	}
+	for k, v := range binaryData {
+		if keys[name][k] {
+			return errors.Errorf("key [%s] of [%s] defined more than once", k, name)
+		}
+		keys[name][k] = true
+		if _, err := base64.StdEncoding.DecodeString(v); err != nil {
+			return errors.Wrapf(err, "key [%s] of [%s] is not base64 encoded", k, name)
+		}
+	}
+	for k := range keys[name] {
+		if !dataKey.MatchString(k) || len(k) > 253 {
+			return errors.Errorf("invalid key [%s] of [%s]", k, name)
+		}
+	}
+	return nil
+}
+
+func validateDeployment(d *deployment, keys map[string]map[string]bool) error {
+	spec := d.Spec
+	if spec.Replicas < 0 {
+		return errors.Errorf("invalid replicas [%d]", spec.Replicas)
+	}
+	if spec.Strategy.Type != "Recreate" && spec.Strategy.Type != "RollingUpdate" {
+		return errors.Errorf("invalid strategy [%s]", spec.Strategy.Type)
+	}
+	if len(spec.Selector.MatchLabels) == 0 {
+		return errors.New("empty selector")
+	}
+	if err := validateLabels(spec.Template.Metadata.Labels); err != nil {
+		return err
+	}
+	if !selects(spec.Selector.MatchLabels, spec.Template.Metadata.Labels) {
+		return errors.New("selector does not match the template labels")
+	}
+
+	pod := spec.Template.Spec
+	switch pod.DNSPolicy {
+	case "", "ClusterFirst", "ClusterFirstWithHostNet", "Default", "None":
+	default:
+		return errors.Errorf("invalid dns policy [%s]", pod.DNSPolicy)
+	}
+	if pod.Affinity != nil && pod.Affinity.PodAffinity != nil {
+		for _, term := range pod.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if len(term.TopologyKey) == 0 {
+				return errors.New("pod affinity term without topology key")
+			}
+			if err := validateLabels(term.LabelSelector.MatchLabels); err != nil {
+				return err
+			}
+		}
+	}
+
+	volumes := map[string]bool{}
+	for _, v := range pod.Volumes {
+		if !dns1123Label.MatchString(v.Name) || len(v.Name) > 63 || volumes[v.Name] {
+			return errors.Errorf("invalid volume name [%s]", v.Name)
+		}
+		volumes[v.Name] = true
+		if (v.Projected == nil) == (v.EmptyDir == nil) {
+			return errors.Errorf("volume [%s] must have exactly one source", v.Name)
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, source := range v.Projected.Sources {
+			ps := source.ConfigMap
+			if ps == nil {
+				ps = source.Secret
+			}
+			if ps == nil || (source.ConfigMap != nil && source.Secret != nil) {
+				return errors.Errorf("volume [%s] has an invalid projection", v.Name)
+			}
+			for _, item := range ps.Items {
+				if !keys[ps.Name][item.Key] {
+					return errors.Errorf("volume [%s] projects unknown key [%s] of [%s]", v.Name, item.Key, ps.Name)
+				}
+				if len(item.Path) == 0 || path.IsAbs(item.Path) || strings.HasPrefix(path.Clean(item.Path), "..") {
+					return errors.Errorf("volume [%s] projects key [%s] to invalid path [%s]", v.Name, item.Key, item.Path)
+				}
+			}
+		}
+	}
+
+	if len(pod.Containers) == 0 {
+		return errors.New("no container")
+	}
+	names := map[string]bool{}
+	for _, c := range append(append([]container{}, pod.InitContainers...), pod.Containers...) {
+		if !dns1123Label.MatchString(c.Name) || len(c.Name) > 63 || names[c.Name] {
+			return errors.Errorf("invalid container name [%s]", c.Name)
+		}
+		names[c.Name] = true
+		if len(c.Image) == 0 {
+			return errors.Errorf("container [%s] has no image", c.Name)
+		}
+		for _, e := range c.Env {
+			if !envVarName.MatchString(e.Name) {
+				return errors.Errorf("container [%s] has invalid environment variable [%s]", c.Name, e.Name)
+			}
+		}
+		for _, p := range c.Ports {
+			if p.ContainerPort == 0 || !validProtocol(p.Protocol) {
+				return errors.Errorf("container [%s] has invalid port [%d/%s]", c.Name, p.ContainerPort, p.Protocol)
+			}
+		}
+		for _, m := range c.VolumeMounts {
+			if !volumes[m.Name] {
+				return errors.Errorf("container [%s] mounts unknown volume [%s]", c.Name, m.Name)
+			}
+			if !path.IsAbs(m.MountPath) {
+				return errors.Errorf("container [%s] mounts [%s] at relative path [%s]", c.Name, m.Name, m.MountPath)
+			}
+		}
+	}
+	return nil
+}
+
+func validateService(s *service, deployments []*deployment) error {
+	if len(s.Spec.Ports) == 0 {
+		return errors.New("no port")
+	}
+	names := map[string]bool{}
+	for _, p := range s.Spec.Ports {
+		if !dns1123Label.MatchString(p.Name) || len(p.Name) > 15 || names[p.Name] {
+			return errors.Errorf("invalid port name [%s]", p.Name)
+		}
+		names[p.Name] = true
+		if p.Port == 0 || p.TargetPort == 0 || !validProtocol(p.Protocol) {
+			return errors.Errorf("invalid port [%s]", p.Name)
+		}
+	}
+	if err := validateLabels(s.Spec.Selector); err != nil {
+		return err
+	}
+	for _, d := range deployments {
+		if len(s.Spec.Selector) != 0 && selects(s.Spec.Selector, d.Spec.Template.Metadata.Labels) {
+			return nil
+		}
+	}
+	return errors.New("selector matches no pod")
+}
+
+// selects returns true if the passed labels match the passed selector
+func selects(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func validProtocol(protocol string) bool {
+	return protocol == "TCP" || protocol == "UDP" || protocol == "SCTP"
+}
@@ -8,29 +8,28 @@ package helpers
 
 import (
 	"encoding/base32"
-	"fmt"
 	"strings"
 
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/hyperledger/fabric/common/util"
-	. "github.com/onsi/ginkgo"
-	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
-func AssertImagesExist(imageNames ...string) {
-	dockerClient, err := docker.NewClientFromEnv()
-	Expect(err).NotTo(HaveOccurred())
-
+// CheckImagesExist returns an error if any of the passed docker images is missing
+func CheckImagesExist(dockerClient *docker.Client, imageNames ...string) error {
 	for _, imageName := range imageNames {
 		images, err := dockerClient.ListImages(docker.ListImagesOptions{
 			Filters: map[string][]string{"reference": {imageName}},
 		})
-		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed listing images [%s]", imageName)
+		}
 
 		if len(images) != 1 {
-			Fail(fmt.Sprintf("missing required image: %s", imageName), 1)
+			return errors.Errorf("missing required image: %s", imageName)
 		}
 	}
+	return nil
 }
 
 // UniqueName generates base-32 enocded UUIDs for container names.
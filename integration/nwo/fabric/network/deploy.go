@@ -21,11 +21,10 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer/lifecycle"
 	"github.com/hyperledger/fabric/integration/nwo/commands"
 	"github.com/hyperledger/fabric/protoutil"
-	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
+	"github.com/pkg/errors"
 
-	. "github.com/onsi/gomega"
-	. "github.com/onsi/gomega/gstruct"
+	common2 "github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
 )
 
 // DeployChaincode is a helper that will install chaincode to all peers that
@@ -34,27 +33,36 @@ import (
 // on the channel using one of the peers, and wait for the chaincode commit to
 // complete on all of the peers. It uses the _lifecycle implementation.
 // NOTE V2_0 capabilities must be enabled for this functionality to work.
-func DeployChaincode(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peers ...*topology.Peer) {
+func DeployChaincode(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peers ...*topology.Peer) error {
 	if len(peers) == 0 {
 		peers = n.PeersWithChannel(channel)
 	}
 	if len(peers) == 0 {
-		return
+		return nil
 	}
 
-	PackageAndInstallChaincode(n, chaincode, peers...)
+	if err := PackageAndInstallChaincode(n, chaincode, peers...); err != nil {
+		return err
+	}
 
 	// approve for each org
-	ApproveChaincodeForMyOrg(n, channel, orderer, chaincode, peers...)
+	if err := ApproveChaincodeForMyOrg(n, channel, orderer, chaincode, peers...); err != nil {
+		return err
+	}
 
 	// commit definition
-	CheckCommitReadinessUntilReady(n, channel, chaincode, n.PeerOrgs(), peers...)
-	CommitChaincode(n, channel, orderer, chaincode, peers[0], peers...)
+	if err := CheckCommitReadinessUntilReady(n, channel, chaincode, n.PeerOrgs(), peers...); err != nil {
+		return err
+	}
+	if err := CommitChaincode(n, channel, orderer, chaincode, peers[0], peers...); err != nil {
+		return err
+	}
 
 	// init the chaincode, if required
 	if chaincode.InitRequired {
-		InitChaincode(n, channel, orderer, chaincode, peers...)
+		return InitChaincode(n, channel, orderer, chaincode, peers...)
 	}
+	return nil
 }
 
 // DeployChaincodeLegacy is a helper that will install chaincode to all peers
@@ -65,18 +73,20 @@ func DeployChaincode(n *Network, channel string, orderer *topology.Orderer, chai
 // NOTE: This helper should not be used to deploy the same chaincode on
 // multiple channels as the install will fail on subsequent calls. Instead,
 // simply use InstantiateChaincode().
-func DeployChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer, chaincode topology.Chaincode, peers ...*topology.Peer) {
+func DeployChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer, chaincode topology.Chaincode, peers ...*topology.Peer) error {
 	if len(peers) == 0 {
 		peers = n.PeersWithChannel(channel)
 	}
 	if len(peers) == 0 {
-		return
+		return nil
 	}
 
 	// create temp file for chaincode package if not provided
 	if chaincode.PackageFile == "" {
 		tempFile, err := ioutil.TempFile("", "chaincode-package")
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrap(err, "failed creating chaincode package file")
+		}
 		tempFile.Close()
 		defer os.Remove(tempFile.Name())
 		chaincode.PackageFile = tempFile.Name()
@@ -84,21 +94,27 @@ func DeployChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer
 
 	// only create chaincode package if it doesn't already exist
 	if fi, err := os.Stat(chaincode.PackageFile); os.IsNotExist(err) || fi.Size() == 0 {
-		PackageChaincodeLegacy(n, chaincode, peers[0])
+		if err := PackageChaincodeLegacy(n, chaincode, peers[0]); err != nil {
+			return err
+		}
 	}
 
 	// install on all peers
-	InstallChaincodeLegacy(n, chaincode, peers...)
+	if err := InstallChaincodeLegacy(n, chaincode, peers...); err != nil {
+		return err
+	}
 
 	// instantiate on the first peer
-	InstantiateChaincodeLegacy(n, channel, orderer, chaincode, peers[0], peers...)
+	return InstantiateChaincodeLegacy(n, channel, orderer, chaincode, peers[0], peers...)
 }
 
-func PackageAndInstallChaincode(n *Network, chaincode *topology.Chaincode, peers ...*topology.Peer) {
+func PackageAndInstallChaincode(n *Network, chaincode *topology.Chaincode, peers ...*topology.Peer) error {
 	// create temp file for chaincode package if not provided
 	if chaincode.PackageFile == "" {
 		tempFile, err := ioutil.TempFile("", "chaincode-package")
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrap(err, "failed creating chaincode package file")
+		}
 		tempFile.Close()
 		defer os.Remove(tempFile.Name())
 		chaincode.PackageFile = tempFile.Name()
@@ -108,83 +124,97 @@ func PackageAndInstallChaincode(n *Network, chaincode *topology.Chaincode, peers
 	if _, err := os.Stat(chaincode.PackageFile); os.IsNotExist(err) {
 		switch chaincode.Lang {
 		case "binary":
-			PackageChaincodeBinary(chaincode)
+			err = PackageChaincodeBinary(chaincode)
 		default:
-			PackageChaincode(n, chaincode, peers[0])
+			err = PackageChaincode(n, chaincode, peers[0])
+		}
+		if err != nil {
+			return err
 		}
 	}
 
 	// install on all peers
-	InstallChaincode(n, chaincode, peers...)
+	return InstallChaincode(n, chaincode, peers...)
 }
 
-func PackageChaincode(n *Network, chaincode *topology.Chaincode, peer *topology.Peer) {
-	sess, err := n.PeerAdminSession(peer, commands.ChaincodePackage{
+func PackageChaincode(n *Network, chaincode *topology.Chaincode, peer *topology.Peer) error {
+	err := n.wait(n.PeerAdminSession(peer, commands.ChaincodePackage{
 		Path:       chaincode.Path,
 		Lang:       chaincode.Lang,
 		Label:      chaincode.Label,
 		OutputFile: chaincode.PackageFile,
 		ClientAuth: n.ClientAuthRequired,
-	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+	}))
+	return errors.WithMessagef(err, "failed packaging chaincode [%s]", chaincode.Name)
 }
 
-func PackageChaincodeLegacy(n *Network, chaincode topology.Chaincode, peer *topology.Peer) {
-	sess, err := n.PeerAdminSession(peer, commands.ChaincodePackageLegacy{
+func PackageChaincodeLegacy(n *Network, chaincode topology.Chaincode, peer *topology.Peer) error {
+	err := n.wait(n.PeerAdminSession(peer, commands.ChaincodePackageLegacy{
 		Name:       chaincode.Name,
 		Version:    chaincode.Version,
 		Path:       chaincode.Path,
 		Lang:       chaincode.Lang,
 		OutputFile: chaincode.PackageFile,
 		ClientAuth: n.ClientAuthRequired,
-	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+	}))
+	return errors.WithMessagef(err, "failed packaging chaincode [%s]", chaincode.Name)
 }
 
-func InstallChaincode(n *Network, chaincode *topology.Chaincode, peers ...*topology.Peer) {
+func InstallChaincode(n *Network, chaincode *topology.Chaincode, peers ...*topology.Peer) error {
 	if chaincode.PackageID == "" {
-		chaincode.SetPackageIDFromPackageFile()
+		if err := chaincode.SetPackageIDFromPackageFile(); err != nil {
+			return err
+		}
 	}
 
 	for _, p := range peers {
-		sess, err := n.PeerAdminSession(p, commands.ChaincodeInstall{
+		err := n.wait(n.PeerAdminSession(p, commands.ChaincodeInstall{
 			PackageFile: chaincode.PackageFile,
 			ClientAuth:  n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed installing chaincode [%s] on [%s]", chaincode.Name, p.ID())
+		}
 
-		EnsureInstalled(n, chaincode.Label, chaincode.PackageID, p)
+		if err := EnsureInstalled(n, chaincode.Label, chaincode.PackageID, p); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func InstallChaincodeLegacy(n *Network, chaincode topology.Chaincode, peers ...*topology.Peer) {
+func InstallChaincodeLegacy(n *Network, chaincode topology.Chaincode, peers ...*topology.Peer) error {
 	for _, p := range peers {
-		sess, err := n.PeerAdminSession(p, commands.ChaincodeInstallLegacy{
+		err := n.wait(n.PeerAdminSession(p, commands.ChaincodeInstallLegacy{
 			Name:        chaincode.Name,
 			Version:     chaincode.Version,
 			Path:        chaincode.Path,
 			Lang:        chaincode.Lang,
 			PackageFile: chaincode.PackageFile,
 			ClientAuth:  n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed installing chaincode [%s] on [%s]", chaincode.Name, p.ID())
+		}
 
-		sess, err = n.PeerAdminSession(p, commands.ChaincodeListInstalledLegacy{
+		sess, err := n.PeerAdminSession(p, commands.ChaincodeListInstalledLegacy{
 			ClientAuth: n.ClientAuthRequired,
 		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-		Expect(sess).To(gbytes.Say(fmt.Sprintf("Name: %s, Version: %s,", chaincode.Name, chaincode.Version)))
+		if err := n.wait(sess, err); err != nil {
+			return errors.WithMessagef(err, "failed listing chaincodes installed on [%s]", p.ID())
+		}
+		if !strings.Contains(string(sess.Out.Contents()), fmt.Sprintf("Name: %s, Version: %s,", chaincode.Name, chaincode.Version)) {
+			return errors.Errorf("chaincode [%s:%s] not installed on [%s]", chaincode.Name, chaincode.Version, p.ID())
+		}
 	}
+	return nil
 }
 
-func ApproveChaincodeForMyOrg(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peers ...*topology.Peer) {
+func ApproveChaincodeForMyOrg(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peers ...*topology.Peer) error {
 	if chaincode.PackageID == "" {
-		chaincode.SetPackageIDFromPackageFile()
+		if err := chaincode.SetPackageIDFromPackageFile(); err != nil {
+			return err
+		}
 	}
 
 	// used to ensure we only approve once per org
@@ -206,25 +236,40 @@ func ApproveChaincodeForMyOrg(n *Network, channel string, orderer *topology.Orde
 				CollectionsConfig:   chaincode.CollectionsConfig,
 				ClientAuth:          n.ClientAuthRequired,
 			})
-			Expect(err).NotTo(HaveOccurred())
-			Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+			if err := n.wait(sess, err); err != nil {
+				return errors.WithMessagef(err, "failed approving chaincode [%s] for [%s]", chaincode.Name, p.Organization)
+			}
 			approvedOrgs[p.Organization] = true
-			Eventually(sess.Err, n.EventuallyTimeout).Should(gbytes.Say(`\Qcommitted with status (VALID)\E`))
+			if committedValid(sess) < 1 {
+				return errors.Errorf("approval of chaincode [%s] for [%s] not committed as valid", chaincode.Name, p.Organization)
+			}
 		}
 	}
+	return nil
 }
 
-func CheckCommitReadinessUntilReady(n *Network, channel string, chaincode *topology.Chaincode, checkOrgs []*topology.Organization, peers ...*topology.Peer) {
+func CheckCommitReadinessUntilReady(n *Network, channel string, chaincode *topology.Chaincode, checkOrgs []*topology.Organization, peers ...*topology.Peer) error {
 	for _, p := range peers {
-		keys := Keys{}
-		for _, org := range checkOrgs {
-			keys[org.MSPID] = BeTrue()
+		err := common2.Retry(n.EventuallyTimeout, func() error {
+			approvals, err := checkCommitReadiness(n, p, channel, chaincode)
+			if err != nil {
+				return err
+			}
+			for _, org := range checkOrgs {
+				if !approvals[org.MSPID] {
+					return errors.Errorf("missing approval of [%s]", org.MSPID)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "chaincode [%s] not ready to be committed on [%s]", chaincode.Name, p.ID())
 		}
-		Eventually(checkCommitReadiness(n, p, channel, chaincode), n.EventuallyTimeout).Should(MatchKeys(IgnoreExtras, keys))
 	}
+	return nil
 }
 
-func CommitChaincode(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peer *topology.Peer, checkPeers ...*topology.Peer) {
+func CommitChaincode(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peer *topology.Peer, checkPeers ...*topology.Peer) error {
 	// commit using one peer per org
 	commitOrgs := map[string]bool{}
 	var peerAddresses []string
@@ -250,39 +295,50 @@ func CommitChaincode(n *Network, channel string, orderer *topology.Orderer, chai
 		PeerAddresses:       peerAddresses,
 		ClientAuth:          n.ClientAuthRequired,
 	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-	for i := 0; i < len(peerAddresses); i++ {
-		Eventually(sess.Err, n.EventuallyTimeout).Should(gbytes.Say(`\Qcommitted with status (VALID)\E`))
+	if err := n.wait(sess, err); err != nil {
+		return errors.WithMessagef(err, "failed committing chaincode [%s]", chaincode.Name)
+	}
+	if committedValid(sess) < len(peerAddresses) {
+		return errors.Errorf("commit of chaincode [%s] not committed as valid by all peers", chaincode.Name)
 	}
 	checkOrgs := []*topology.Organization{}
 	for org := range commitOrgs {
 		checkOrgs = append(checkOrgs, n.Organization(org))
 	}
-	EnsureChaincodeCommitted(n, channel, chaincode.Name, chaincode.Version, chaincode.Sequence, checkOrgs, checkPeers...)
+	return EnsureChaincodeCommitted(n, channel, chaincode.Name, chaincode.Version, chaincode.Sequence, checkOrgs, checkPeers...)
 }
 
 // EnsureChaincodeCommitted polls each supplied peer until the chaincode definition
 // has been committed to the peer's rwset.
-func EnsureChaincodeCommitted(n *Network, channel, name, version, sequence string, checkOrgs []*topology.Organization, peers ...*topology.Peer) {
+func EnsureChaincodeCommitted(n *Network, channel, name, version, sequence string, checkOrgs []*topology.Organization, peers ...*topology.Peer) error {
+	sequenceInt, err := strconv.ParseInt(sequence, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid sequence [%s]", sequence)
+	}
 	for _, p := range peers {
-		sequenceInt, err := strconv.ParseInt(sequence, 10, 64)
-		Expect(err).NotTo(HaveOccurred())
-		approvedKeys := Keys{}
-		for _, org := range checkOrgs {
-			approvedKeys[org.MSPID] = BeTrue()
+		err := common2.Retry(n.EventuallyTimeout, func() error {
+			committed, err := listCommitted(n, p, channel, name)
+			if err != nil {
+				return err
+			}
+			if committed.Version != version || committed.Sequence != sequenceInt {
+				return errors.Errorf("committed [%s:%d], expected [%s:%d]", committed.Version, committed.Sequence, version, sequenceInt)
+			}
+			for _, org := range checkOrgs {
+				if !committed.Approvals[org.MSPID] {
+					return errors.Errorf("missing approval of [%s]", org.MSPID)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "chaincode [%s] not committed on [%s]", name, p.ID())
 		}
-		Eventually(listCommitted(n, p, channel, name), n.EventuallyTimeout).Should(
-			MatchFields(IgnoreExtras, Fields{
-				"Version":   Equal(version),
-				"Sequence":  Equal(sequenceInt),
-				"Approvals": MatchKeys(IgnoreExtras, approvedKeys),
-			}),
-		)
 	}
+	return nil
 }
 
-func InitChaincode(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peers ...*topology.Peer) {
+func InitChaincode(n *Network, channel string, orderer *topology.Orderer, chaincode *topology.Chaincode, peers ...*topology.Peer) error {
 	// init using one peer per org
 	initOrgs := map[string]bool{}
 	var peerAddresses []string
@@ -303,16 +359,20 @@ func InitChaincode(n *Network, channel string, orderer *topology.Orderer, chainc
 		IsInit:        true,
 		ClientAuth:    n.ClientAuthRequired,
 	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-	for i := 0; i < len(peerAddresses); i++ {
-		Eventually(sess.Err, n.EventuallyTimeout).Should(gbytes.Say(`\Qcommitted with status (VALID)\E`))
+	if err := n.wait(sess, err); err != nil {
+		return errors.WithMessagef(err, "failed initializing chaincode [%s]", chaincode.Name)
 	}
-	Expect(sess.Err).To(gbytes.Say("Chaincode invoke successful. result: status:200"))
+	if committedValid(sess) < len(peerAddresses) {
+		return errors.Errorf("init of chaincode [%s] not committed as valid by all peers", chaincode.Name)
+	}
+	if !strings.Contains(string(sess.Err.Contents()), "Chaincode invoke successful. result: status:200") {
+		return errors.Errorf("init of chaincode [%s] not successful", chaincode.Name)
+	}
+	return nil
 }
 
-func InstantiateChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer, chaincode topology.Chaincode, peer *topology.Peer, checkPeers ...*topology.Peer) {
-	sess, err := n.PeerAdminSession(peer, commands.ChaincodeInstantiateLegacy{
+func InstantiateChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer, chaincode topology.Chaincode, peer *topology.Peer, checkPeers ...*topology.Peer) error {
+	err := n.wait(n.PeerAdminSession(peer, commands.ChaincodeInstantiateLegacy{
 		ChannelID:         channel,
 		Orderer:           n.OrdererAddress(orderer, ListenPort),
 		Name:              chaincode.Name,
@@ -322,34 +382,48 @@ func InstantiateChaincodeLegacy(n *Network, channel string, orderer *topology.Or
 		Lang:              chaincode.Lang,
 		CollectionsConfig: chaincode.CollectionsConfig,
 		ClientAuth:        n.ClientAuthRequired,
-	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+	}))
+	if err != nil {
+		return errors.WithMessagef(err, "failed instantiating chaincode [%s]", chaincode.Name)
+	}
 
-	EnsureInstantiatedLegacy(n, channel, chaincode.Name, chaincode.Version, checkPeers...)
+	return EnsureInstantiatedLegacy(n, channel, chaincode.Name, chaincode.Version, checkPeers...)
 }
 
-func EnsureInstantiatedLegacy(n *Network, channel, name, version string, peers ...*topology.Peer) {
+func EnsureInstantiatedLegacy(n *Network, channel, name, version string, peers ...*topology.Peer) error {
 	for _, p := range peers {
-		Eventually(listInstantiatedLegacy(n, p, channel), n.EventuallyTimeout).Should(
-			gbytes.Say(fmt.Sprintf("Name: %s, Version: %s,", name, version)),
-		)
+		err := common2.Retry(n.EventuallyTimeout, func() error {
+			out, err := listInstantiatedLegacy(n, p, channel)
+			if err != nil {
+				return err
+			}
+			if !strings.Contains(out, fmt.Sprintf("Name: %s, Version: %s,", name, version)) {
+				return errors.Errorf("chaincode [%s:%s] not instantiated", name, version)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "chaincode [%s] not instantiated on [%s]", name, p.ID())
+		}
 	}
+	return nil
 }
 
-func UpgradeChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer, chaincode topology.Chaincode, peers ...*topology.Peer) {
+func UpgradeChaincodeLegacy(n *Network, channel string, orderer *topology.Orderer, chaincode topology.Chaincode, peers ...*topology.Peer) error {
 	if len(peers) == 0 {
 		peers = n.PeersWithChannel(channel)
 	}
 	if len(peers) == 0 {
-		return
+		return nil
 	}
 
 	// install on all peers
-	InstallChaincodeLegacy(n, chaincode, peers...)
+	if err := InstallChaincodeLegacy(n, chaincode, peers...); err != nil {
+		return err
+	}
 
 	// upgrade from the first peer
-	sess, err := n.PeerAdminSession(peers[0], commands.ChaincodeUpgradeLegacy{
+	err := n.wait(n.PeerAdminSession(peers[0], commands.ChaincodeUpgradeLegacy{
 		ChannelID:         channel,
 		Orderer:           n.OrdererAddress(orderer, ListenPort),
 		Name:              chaincode.Name,
@@ -358,48 +432,66 @@ func UpgradeChaincodeLegacy(n *Network, channel string, orderer *topology.Ordere
 		Policy:            chaincode.Policy,
 		CollectionsConfig: chaincode.CollectionsConfig,
 		ClientAuth:        n.ClientAuthRequired,
-	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+	}))
+	if err != nil {
+		return errors.WithMessagef(err, "failed upgrading chaincode [%s]", chaincode.Name)
+	}
 
-	EnsureInstantiatedLegacy(n, channel, chaincode.Name, chaincode.Version, peers...)
+	return EnsureInstantiatedLegacy(n, channel, chaincode.Name, chaincode.Version, peers...)
 }
 
-func EnsureInstalled(n *Network, label, packageID string, peers ...*topology.Peer) {
+func EnsureInstalled(n *Network, label, packageID string, peers ...*topology.Peer) error {
 	for _, p := range peers {
-		Eventually(QueryInstalled(n, p), n.EventuallyTimeout).Should(
-			ContainElement(MatchFields(IgnoreExtras,
-				Fields{
-					"Label":     Equal(label),
-					"PackageId": Equal(packageID),
-				},
-			)),
-		)
+		err := common2.Retry(n.EventuallyTimeout, func() error {
+			installed, err := QueryInstalled(n, p)
+			if err != nil {
+				return err
+			}
+			if findInstalled(installed, label, packageID) == nil {
+				return errors.Errorf("package [%s] not installed", packageID)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "chaincode [%s] not installed on [%s]", label, p.ID())
+		}
 	}
+	return nil
 }
 
-func QueryInstalledReferences(n *Network, channel, label, packageID string, checkPeer *topology.Peer, nameVersions ...[]string) {
-	chaincodes := make([]*lifecycle.QueryInstalledChaincodesResult_Chaincode, len(nameVersions))
-	for i, nameVersion := range nameVersions {
-		chaincodes[i] = &lifecycle.QueryInstalledChaincodesResult_Chaincode{
-			Name:    nameVersion[0],
-			Version: nameVersion[1],
-		}
+// QueryInstalledReferences checks that the package installed on the passed peer is referenced,
+// on the passed channel, by exactly the passed chaincode name and version pairs
+func QueryInstalledReferences(n *Network, channel, label, packageID string, checkPeer *topology.Peer, nameVersions ...[]string) error {
+	installed, err := QueryInstalled(n, checkPeer)
+	if err != nil {
+		return err
+	}
+	chaincode := findInstalled(installed, label, packageID)
+	if chaincode == nil {
+		return errors.Errorf("package [%s] not installed on [%s]", packageID, checkPeer.ID())
+	}
+	references, ok := chaincode.References[channel]
+	if !ok || references == nil {
+		return errors.Errorf("package [%s] not referenced on channel [%s] by [%s]", packageID, channel, checkPeer.ID())
 	}
 
-	Expect(QueryInstalled(n, checkPeer)()).To(
-		ContainElement(MatchFields(IgnoreExtras,
-			Fields{
-				"Label":     Equal(label),
-				"PackageId": Equal(packageID),
-				"References": HaveKeyWithValue(channel, PointTo(MatchFields(IgnoreExtras,
-					Fields{
-						"Chaincodes": ConsistOf(chaincodes),
-					},
-				))),
-			},
-		)),
-	)
+	expected := map[string]bool{}
+	for _, nameVersion := range nameVersions {
+		expected[nameVersion[0]+":"+nameVersion[1]] = true
+	}
+	actual := map[string]bool{}
+	for _, c := range references.Chaincodes {
+		actual[c.Name+":"+c.Version] = true
+	}
+	if len(expected) != len(references.Chaincodes) || len(expected) != len(actual) {
+		return errors.Errorf("package [%s] referenced by [%v] on [%s], expected [%v]", packageID, actual, checkPeer.ID(), expected)
+	}
+	for k := range expected {
+		if !actual[k] {
+			return errors.Errorf("package [%s] referenced by [%v] on [%s], expected [%v]", packageID, actual, checkPeer.ID(), expected)
+		}
+	}
+	return nil
 }
 
 func QueryInstalledNoReferences(n *Network, channel, label, packageID string, checkPeer *topology.Peer) {
@@ -409,46 +501,63 @@ type queryInstalledOutput struct {
 	InstalledChaincodes []lifecycle.QueryInstalledChaincodesResult_InstalledChaincode `json:"installed_chaincodes"`
 }
 
-func QueryInstalled(n *Network, peer *topology.Peer) func() []lifecycle.QueryInstalledChaincodesResult_InstalledChaincode {
-	return func() []lifecycle.QueryInstalledChaincodesResult_InstalledChaincode {
-		sess, err := n.PeerAdminSession(peer, commands.ChaincodeQueryInstalled{
-			ClientAuth: n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-		output := &queryInstalledOutput{}
-		err = json.Unmarshal(sess.Out.Contents(), output)
-		Expect(err).NotTo(HaveOccurred())
-		return output.InstalledChaincodes
+func QueryInstalled(n *Network, peer *topology.Peer) ([]lifecycle.QueryInstalledChaincodesResult_InstalledChaincode, error) {
+	sess, err := n.PeerAdminSession(peer, commands.ChaincodeQueryInstalled{
+		ClientAuth: n.ClientAuthRequired,
+	})
+	if err := n.wait(sess, err); err != nil {
+		return nil, errors.WithMessagef(err, "failed querying chaincodes installed on [%s]", peer.ID())
 	}
+	output := &queryInstalledOutput{}
+	err = json.Unmarshal(sess.Out.Contents(), output)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling chaincodes installed on [%s]", peer.ID())
+	}
+	return output.InstalledChaincodes, nil
+}
+
+// findInstalled returns the installed chaincode with the passed label and package id, nil if not found
+func findInstalled(installed []lifecycle.QueryInstalledChaincodesResult_InstalledChaincode, label, packageID string) *lifecycle.QueryInstalledChaincodesResult_InstalledChaincode {
+	for i := range installed {
+		if installed[i].Label == label && installed[i].PackageId == packageID {
+			return &installed[i]
+		}
+	}
+	return nil
+}
+
+// committedValid returns the number of peers that reported the transaction of the passed session as valid
+func committedValid(sess *gexec.Session) int {
+	return strings.Count(string(sess.Err.Contents()), "committed with status (VALID)")
 }
 
 type checkCommitReadinessOutput struct {
 	Approvals map[string]bool `json:"approvals"`
 }
 
-func checkCommitReadiness(n *Network, peer *topology.Peer, channel string, chaincode *topology.Chaincode) func() map[string]bool {
-	return func() map[string]bool {
-		sess, err := n.PeerAdminSession(peer, commands.ChaincodeCheckCommitReadiness{
-			ChannelID:           channel,
-			Name:                chaincode.Name,
-			Version:             chaincode.Version,
-			Sequence:            chaincode.Sequence,
-			EndorsementPlugin:   chaincode.EndorsementPlugin,
-			ValidationPlugin:    chaincode.ValidationPlugin,
-			SignaturePolicy:     chaincode.SignaturePolicy,
-			ChannelConfigPolicy: chaincode.ChannelConfigPolicy,
-			InitRequired:        chaincode.InitRequired,
-			CollectionsConfig:   chaincode.CollectionsConfig,
-			ClientAuth:          n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-		output := &checkCommitReadinessOutput{}
-		err = json.Unmarshal(sess.Out.Contents(), output)
-		Expect(err).NotTo(HaveOccurred())
-		return output.Approvals
+func checkCommitReadiness(n *Network, peer *topology.Peer, channel string, chaincode *topology.Chaincode) (map[string]bool, error) {
+	sess, err := n.PeerAdminSession(peer, commands.ChaincodeCheckCommitReadiness{
+		ChannelID:           channel,
+		Name:                chaincode.Name,
+		Version:             chaincode.Version,
+		Sequence:            chaincode.Sequence,
+		EndorsementPlugin:   chaincode.EndorsementPlugin,
+		ValidationPlugin:    chaincode.ValidationPlugin,
+		SignaturePolicy:     chaincode.SignaturePolicy,
+		ChannelConfigPolicy: chaincode.ChannelConfigPolicy,
+		InitRequired:        chaincode.InitRequired,
+		CollectionsConfig:   chaincode.CollectionsConfig,
+		ClientAuth:          n.ClientAuthRequired,
+	})
+	if err := n.wait(sess, err); err != nil {
+		return nil, errors.WithMessagef(err, "failed checking commit readiness on [%s]", peer.ID())
 	}
+	output := &checkCommitReadinessOutput{}
+	err = json.Unmarshal(sess.Out.Contents(), output)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling commit readiness on [%s]", peer.ID())
+	}
+	return output.Approvals, nil
 }
 
 type queryCommittedOutput struct {
@@ -460,36 +569,40 @@ type queryCommittedOutput struct {
 // listCommitted returns the result of the queryCommitted command.
 // If the command fails for any reason (e.g. namespace not defined
 // or a database access issue), it will return an empty output object.
-func listCommitted(n *Network, peer *topology.Peer, channel, name string) func() queryCommittedOutput {
-	return func() queryCommittedOutput {
-		sess, err := n.PeerAdminSession(peer, commands.ChaincodeListCommitted{
-			ChannelID:  channel,
-			Name:       name,
-			ClientAuth: n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit())
-		output := &queryCommittedOutput{}
-		if sess.ExitCode() == 1 {
-			// don't try to unmarshal the output as JSON if the query failed
-			return *output
-		}
-		err = json.Unmarshal(sess.Out.Contents(), output)
-		Expect(err).NotTo(HaveOccurred())
-		return *output
+func listCommitted(n *Network, peer *topology.Peer, channel, name string) (queryCommittedOutput, error) {
+	output := &queryCommittedOutput{}
+	sess, err := n.PeerAdminSession(peer, commands.ChaincodeListCommitted{
+		ChannelID:  channel,
+		Name:       name,
+		ClientAuth: n.ClientAuthRequired,
+	})
+	if err != nil {
+		return *output, err
+	}
+	code, err := common2.WaitExit(sess, n.EventuallyTimeout)
+	if err != nil {
+		return *output, err
+	}
+	if code == 1 {
+		// don't try to unmarshal the output as JSON if the query failed
+		return *output, nil
 	}
+	err = json.Unmarshal(sess.Out.Contents(), output)
+	if err != nil {
+		return *output, errors.Wrapf(err, "failed unmarshalling committed chaincodes on [%s]", peer.ID())
+	}
+	return *output, nil
 }
 
-func listInstantiatedLegacy(n *Network, peer *topology.Peer, channel string) func() *gbytes.Buffer {
-	return func() *gbytes.Buffer {
-		sess, err := n.PeerAdminSession(peer, commands.ChaincodeListInstantiatedLegacy{
-			ChannelID:  channel,
-			ClientAuth: n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-		return sess.Buffer()
+func listInstantiatedLegacy(n *Network, peer *topology.Peer, channel string) (string, error) {
+	sess, err := n.PeerAdminSession(peer, commands.ChaincodeListInstantiatedLegacy{
+		ChannelID:  channel,
+		ClientAuth: n.ClientAuthRequired,
+	})
+	if err := n.wait(sess, err); err != nil {
+		return "", errors.WithMessagef(err, "failed listing chaincodes instantiated on [%s]", peer.ID())
 	}
+	return string(sess.Buffer().Contents()), nil
 }
 
 // EnableCapabilities enables a specific capabilities flag for a running network.
@@ -520,44 +633,63 @@ func EnableCapabilities(network *Network, channel, capabilitiesGroup, capabiliti
 
 // WaitUntilEqualLedgerHeight waits until all specified peers have the
 // provided rwset height on a channel
-func WaitUntilEqualLedgerHeight(n *Network, channel string, height int, peers ...*topology.Peer) {
+func WaitUntilEqualLedgerHeight(n *Network, channel string, height int, peers ...*topology.Peer) error {
 	for _, peer := range peers {
-		Eventually(func() int {
-			return GetLedgerHeight(n, peer, channel)
-		}, n.EventuallyTimeout).Should(Equal(height))
+		err := common2.Retry(n.EventuallyTimeout, func() error {
+			h, err := GetLedgerHeight(n, peer, channel)
+			if err != nil {
+				return err
+			}
+			if h != height {
+				return errors.Errorf("ledger height is [%d], expected [%d]", h, height)
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "ledger of [%s] on [%s] not at height [%d]", channel, peer.ID(), height)
+		}
 	}
+	return nil
 }
 
 // GetLedgerHeight returns the current rwset height for a peer on
 // a channel
-func GetLedgerHeight(n *Network, peer *topology.Peer, channel string) int {
+func GetLedgerHeight(n *Network, peer *topology.Peer, channel string) (int, error) {
 	sess, err := n.PeerUserSession(peer, "User1", commands.ChannelInfo{
 		ChannelID:  channel,
 		ClientAuth: n.ClientAuthRequired,
 	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit())
+	if err != nil {
+		return 0, err
+	}
+	code, err := common2.WaitExit(sess, n.EventuallyTimeout)
+	if err != nil {
+		return 0, err
+	}
 
-	if sess.ExitCode() == 1 {
+	if code == 1 {
 		// if org is not yet member of channel, peer will return error
-		return -1
+		return -1, nil
 	}
 
 	channelInfoStr := strings.TrimPrefix(string(sess.Buffer().Contents()[:]), "Blockchain info:")
 	var channelInfo = common.BlockchainInfo{}
 	json.Unmarshal([]byte(channelInfoStr), &channelInfo)
-	return int(channelInfo.Height)
+	return int(channelInfo.Height), nil
 }
 
 // GetMaxLedgerHeight returns the maximum rwset height for the
 // peers on a channel
-func GetMaxLedgerHeight(n *Network, channel string, peers ...*topology.Peer) int {
+func GetMaxLedgerHeight(n *Network, channel string, peers ...*topology.Peer) (int, error) {
 	var maxHeight int
 	for _, peer := range peers {
-		peerHeight := GetLedgerHeight(n, peer, channel)
+		peerHeight, err := GetLedgerHeight(n, peer, channel)
+		if err != nil {
+			return 0, err
+		}
 		if peerHeight > maxHeight {
 			maxHeight = peerHeight
 		}
 	}
-	return maxHeight
+	return maxHeight, nil
 }
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tedsuo/ifrit/ginkgomon"
+	"github.com/tedsuo/ifrit/grouper"
+
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fault"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/runner"
+)
+
+// ProxyName returns the name of the proxy standing between the passed FSC node
+// and the orderer, or peer, with the passed identifier
+func ProxyName(node, target string) string {
+	return fmt.Sprintf("%s->%s", node, target)
+}
+
+// reserveProxyPorts reserves, if fault injection is enabled, the ports of the proxies standing between
+// each FSC node and the orderers and peers
+func (n *Network) reserveProxyPorts() {
+	if !n.FaultInjection {
+		return
+	}
+	for _, p := range n.Peers {
+		if p.Type != topology.ViewPeer {
+			continue
+		}
+		for _, o := range n.Orderers {
+			n.PortsByProxyName[ProxyName(p.Name, o.ID())] = n.Registry.ReservePort()
+		}
+		for _, target := range n.Peers {
+			if target.Type == topology.FabricPeer {
+				n.PortsByProxyName[ProxyName(p.Name, target.ID())] = n.Registry.ReservePort()
+			}
+		}
+	}
+}
+
+// viewPeerAddress returns the address the passed FSC node uses to connect to the passed target.
+// If fault injection is enabled, this is the address of the proxy in between.
+func (n *Network) viewPeerAddress(p *topology.Peer, target string, portName registry.PortName, address string) string {
+	if !n.FaultInjection || portName != ListenPort {
+		return address
+	}
+	port, ok := n.PortsByProxyName[ProxyName(p.Name, target)]
+	if !ok {
+		return address
+	}
+	return fmt.Sprintf("127.0.0.1:%d", port)
+}
+
+// Proxies returns the proxies standing between the FSC nodes and the orderers and peers,
+// empty if fault injection is not enabled
+func (n *Network) Proxies() fault.Proxies {
+	if n.proxies != nil || !n.FaultInjection {
+		return n.proxies
+	}
+	for _, p := range n.Peers {
+		if p.Type != topology.ViewPeer {
+			continue
+		}
+		for _, o := range n.Orderers {
+			name := ProxyName(p.Name, o.ID())
+			n.proxies = append(n.proxies, fault.NewProxy(
+				name,
+				fmt.Sprintf("127.0.0.1:%d", n.PortsByProxyName[name]),
+				n.OrdererAddress(o, ListenPort),
+			))
+		}
+		for _, target := range n.Peers {
+			if target.Type != topology.FabricPeer {
+				continue
+			}
+			name := ProxyName(p.Name, target.ID())
+			n.proxies = append(n.proxies, fault.NewProxy(
+				name,
+				fmt.Sprintf("127.0.0.1:%d", n.PortsByProxyName[name]),
+				n.PeerAddress(target, ListenPort),
+			))
+		}
+	}
+	return n.proxies
+}
+
+// Proxy returns the proxy standing between the passed FSC node and the orderer, or peer, with the passed identifier
+func (n *Network) Proxy(node, target string) (*fault.Proxy, error) {
+	p := n.Proxies().Proxy(ProxyName(node, target))
+	if p == nil {
+		return nil, errors.Errorf("no proxy between [%s] and [%s], is fault injection enabled?", node, target)
+	}
+	return p, nil
+}
+
+// faultMembers returns the members of the network when fault injection is enabled.
+// The proxies come first, then each orderer and peer runs as a member on its own, so that it can be
+// stopped, or killed, and restarted without affecting the others.
+func (n *Network) faultMembers() ([]grouper.Member, error) {
+	members := grouper.Members{}
+	if r := n.BrokerGroupRunner(); r != nil {
+		members = append(members, grouper.Member{Name: "brokers", Runner: r})
+	}
+	if proxies := n.Proxies(); len(proxies) != 0 {
+		members = append(members, grouper.Member{Name: "proxies", Runner: proxies})
+	}
+	for _, o := range n.Orderers {
+		r, err := n.OrdererRunner(o)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, grouper.Member{Name: o.ID(), Runner: restartable(r)})
+	}
+	for _, p := range n.Peers {
+		if p.Type == topology.FabricPeer {
+			r, err := n.PeerRunner(p)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, grouper.Member{Name: p.ID(), Runner: restartable(r)})
+		}
+	}
+	return members, nil
+}
+
+// restartable returns a runner that, unlike the passed one, can be stopped and cloned
+func restartable(r *ginkgomon.Runner) *runner.Runner {
+	return runner.New(runner.Config{
+		Command:           r.Command,
+		Name:              r.Name,
+		AnsiColorCode:     r.AnsiColorCode,
+		StartCheck:        r.StartCheck,
+		StartCheckTimeout: r.StartCheckTimeout,
+		Cleanup:           r.Cleanup,
+	})
+}
@@ -13,16 +13,18 @@ import (
 	"time"
 
 	docker "github.com/fsouza/go-dockerclient"
-	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/tedsuo/ifrit/grouper"
 
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/commands"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/fabricconfig"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/helpers"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/identity"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fault"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/registry"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 )
@@ -46,6 +48,7 @@ type Network struct {
 
 	PortsByBrokerID   map[string]registry.Ports
 	PortsByOrdererID  map[string]registry.Ports
+	PortsByProxyName  map[string]uint16
 	Logging           *topology.Logging
 	ChaincodeMode     string
 	PvtTxSupport      bool
@@ -55,6 +58,7 @@ type Network struct {
 	FabTokenSupport   bool
 	FabTokenCCSupport bool
 	GRPCLogging       bool
+	FaultInjection    bool
 	Organizations     []*topology.Organization
 	SystemChannel     *topology.SystemChannel
 	Channels          []*topology.Channel
@@ -68,23 +72,31 @@ type Network struct {
 
 	colorIndex uint
 	ccps       []ChaincodeProcessor
+	proxies    fault.Proxies
 }
 
-func New(reg *registry.Registry, components *common.Components, ccps []ChaincodeProcessor) *Network {
+func New(reg *registry.Registry, components *common.Components, ccps []ChaincodeProcessor) (*Network, error) {
 	topologyBoxed := reg.TopologyByName("fabric")
 	if topologyBoxed == nil {
 		topologyBoxed = NewEmptyTopology()
 	}
 
 	client, err := docker.NewClientFromEnv()
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating docker client")
+	}
+	if err := helpers.CheckImagesExist(client, common.RequiredImages...); err != nil {
+		return nil, err
+	}
 	_, err = client.CreateNetwork(
 		docker.CreateNetworkOptions{
 			Name:   reg.NetworkID,
 			Driver: "bridge",
 		},
 	)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed creating docker network [%s]", reg.NetworkID)
+	}
 
 	fabricTopology := topologyBoxed.(*topology.Topology)
 	network := &Network{
@@ -99,6 +111,7 @@ func New(reg *registry.Registry, components *common.Components, ccps []Chaincode
 		MetricsProvider:   "prometheus",
 		PortsByBrokerID:   map[string]registry.Ports{},
 		PortsByOrdererID:  map[string]registry.Ports{},
+		PortsByProxyName:  map[string]uint16{},
 
 		Organizations:     fabricTopology.Organizations,
 		Consensus:         fabricTopology.Consensus,
@@ -118,78 +131,104 @@ func New(reg *registry.Registry, components *common.Components, ccps []Chaincode
 		GRPCLogging:       fabricTopology.GRPCLogging,
 		PvtTxSupport:      fabricTopology.PvtTxSupport,
 		PvtTxCCSupport:    fabricTopology.PvtTxCCSupport,
+		FaultInjection:    fabricTopology.FaultInjection,
 		ccps:              ccps,
 	}
-	return network
+	return network, nil
 }
 
-func (n *Network) GenerateConfigTree() {
-	n.CheckTopology()
-	n.GenerateCryptoConfig()
+func (n *Network) GenerateConfigTree() error {
+	if err := n.CheckTopology(); err != nil {
+		return err
+	}
+	if err := n.GenerateCryptoConfig(); err != nil {
+		return err
+	}
 	if len(n.Channels) != 0 {
-		n.GenerateConfigTxConfig()
+		if err := n.GenerateConfigTxConfig(); err != nil {
+			return err
+		}
 	}
 	for _, o := range n.Orderers {
-		n.GenerateOrdererConfig(o)
+		if err := n.GenerateOrdererConfig(o); err != nil {
+			return err
+		}
 	}
 	for _, p := range n.Peers {
 		if p.Type == topology.FabricPeer {
-			n.GenerateCoreConfig(p)
+			if err := n.GenerateCoreConfig(p); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (n *Network) GenerateArtifacts() {
-	sess, err := n.Cryptogen(commands.Generate{
+func (n *Network) GenerateArtifacts() error {
+	err := n.wait(n.Cryptogen(commands.Generate{
 		Config: n.CryptoConfigPath(),
 		Output: n.CryptoPath(),
-	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
-	n.bootstrapIdemix()
-	n.bootstrapExtraIdentities()
+	}))
+	if err != nil {
+		return errors.WithMessage(err, "failed generating crypto material")
+	}
+	if err := n.bootstrapIdemix(); err != nil {
+		return err
+	}
+	if err := n.bootstrapExtraIdentities(); err != nil {
+		return err
+	}
 
 	if len(n.SystemChannel.Name) != 0 {
-		sess, err = n.ConfigTxGen(commands.OutputBlock{
+		err := n.wait(n.ConfigTxGen(commands.OutputBlock{
 			ChannelID:   n.SystemChannel.Name,
 			Profile:     n.SystemChannel.Profile,
 			ConfigPath:  n.Registry.RootDir,
 			OutputBlock: n.OutputBlockPath(n.SystemChannel.Name),
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed generating genesis block of [%s]", n.SystemChannel.Name)
+		}
 	}
 
 	for _, c := range n.Channels {
-		sess, err := n.ConfigTxGen(commands.CreateChannelTx{
+		err := n.wait(n.ConfigTxGen(commands.CreateChannelTx{
 			ChannelID:             c.Name,
 			Profile:               c.Profile,
 			BaseProfile:           c.BaseProfile,
 			ConfigPath:            n.Registry.RootDir,
 			OutputCreateChannelTx: n.CreateChannelTxPath(c.Name),
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed generating create channel transaction of [%s]", c.Name)
+		}
 	}
 
-	n.ConcatenateTLSCACertificates()
+	if err := n.ConcatenateTLSCACertificates(); err != nil {
+		return err
+	}
 	n.GenerateResolverMap()
 	for _, p := range n.Peers {
 		switch p.Type {
 		case topology.ViewPeer:
-			n.GenerateCoreConfig(p)
+			if err := n.GenerateCoreConfig(p); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-func (n *Network) Load() {
+func (n *Network) Load() error {
 	for _, p := range n.Peers {
 		switch p.Type {
 		case topology.ViewPeer:
 			v := viper.New()
 			v.SetConfigFile(n.NodeConfigPath(p))
 			err := v.ReadInConfig() // Find and read the config file
-			Expect(err).NotTo(HaveOccurred())
+			if err != nil {
+				return errors.Wrapf(err, "failed reading configuration of [%s]", p.Name)
+			}
 
 			cc := &grpc.ConnectionConfig{
 				Address:           v.GetString("fsc.address"),
@@ -204,7 +243,9 @@ func (n *Network) Load() {
 				n.Organization(p.Organization).MSPID,
 				"bccsp",
 			)
-			Expect(err).ToNot(HaveOccurred())
+			if err != nil {
+				return errors.WithMessagef(err, "failed loading client identity of [%s]", p.Name)
+			}
 			n.Registry.ClientSigningIdentities[p.Name] = clientID
 
 			peerID, err := identity.GetSigningIdentity(
@@ -212,34 +253,55 @@ func (n *Network) Load() {
 				n.Organization(p.Organization).MSPID,
 				"bccsp",
 			)
-			Expect(err).ToNot(HaveOccurred())
+			if err != nil {
+				return errors.WithMessagef(err, "failed loading identity of [%s]", p.Name)
+			}
 			raw, err := peerID.Serialize()
-			Expect(err).ToNot(HaveOccurred())
+			if err != nil {
+				return errors.WithMessagef(err, "failed serializing identity of [%s]", p.Name)
+			}
 			n.Registry.ViewIdentities[p.Name] = raw
 		}
 	}
+	return nil
 }
 
-func (n *Network) Members() []grouper.Member {
+func (n *Network) Members() ([]grouper.Member, error) {
+	if n.FaultInjection {
+		return n.faultMembers()
+	}
+
 	members := grouper.Members{}
 
 	if r := n.BrokerGroupRunner(); r != nil {
 		members = append(members, grouper.Member{Name: "brokers", Runner: r})
 	}
-	if r := n.OrdererGroupRunner(); r != nil {
+	r, err := n.OrdererGroupRunner()
+	if err != nil {
+		return nil, err
+	}
+	if r != nil {
 		members = append(members, grouper.Member{Name: "orderers", Runner: r})
 	}
-	if r := n.PeerGroupRunner(); r != nil {
+	r, err = n.PeerGroupRunner()
+	if err != nil {
+		return nil, err
+	}
+	if r != nil {
 		members = append(members, grouper.Member{Name: "peers", Runner: r})
 	}
-	return members
+	return members, nil
 }
 
-func (n *Network) PostRun() {
-	orderer := n.Orderer("orderer")
+func (n *Network) PostRun() error {
+	orderer := n.Orderers[0]
 	for _, channel := range n.Channels {
-		n.CreateAndJoinChannel(orderer, channel.Name)
-		n.UpdateChannelAnchors(orderer, channel.Name)
+		if err := n.CreateAndJoinChannel(orderer, channel.Name); err != nil {
+			return err
+		}
+		if err := n.UpdateChannelAnchors(orderer, channel.Name); err != nil {
+			return err
+		}
 	}
 
 	// Wait a few second to make peers discovering each other
@@ -251,75 +313,119 @@ func (n *Network) PostRun() {
 			for _, ccp := range n.ccps {
 				chaincode = ccp.Process(n, chaincode)
 			}
-			n.DeployChaincode(chaincode)
+			if err := n.DeployChaincode(chaincode); err != nil {
+				return err
+			}
 		}
 	}
 
 	// Wait a few second to make peers discovering each other
 	time.Sleep(5 * time.Second)
+	return nil
 }
 
-func (n *Network) Cleanup() {
+func (n *Network) Cleanup() error {
 	if n.DockerClient == nil {
-		return
+		return nil
 	}
 
 	nw, err := n.DockerClient.NetworkInfo(n.NetworkID)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed getting docker network [%s]", n.NetworkID)
+	}
 
 	err = n.DockerClient.RemoveNetwork(nw.ID)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed removing docker network [%s]", n.NetworkID)
+	}
 
 	containers, err := n.DockerClient.ListContainers(docker.ListContainersOptions{All: true})
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed listing docker containers")
+	}
 	for _, c := range containers {
 		for _, name := range c.Names {
 			if strings.HasPrefix(name, "/"+n.NetworkID) {
 				err := n.DockerClient.RemoveContainer(docker.RemoveContainerOptions{ID: c.ID, Force: true})
-				Expect(err).NotTo(HaveOccurred())
+				if err != nil {
+					return errors.Wrapf(err, "failed removing docker container [%s]", name)
+				}
 				break
 			}
 		}
 	}
 
 	images, err := n.DockerClient.ListImages(docker.ListImagesOptions{All: true})
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed listing docker images")
+	}
 	for _, i := range images {
 		for _, tag := range i.RepoTags {
 			if strings.HasPrefix(tag, n.NetworkID) {
 				err := n.DockerClient.RemoveImage(i.ID)
-				Expect(err).NotTo(HaveOccurred())
+				if err != nil {
+					return errors.Wrapf(err, "failed removing docker image [%s]", tag)
+				}
 				break
 			}
 		}
 	}
+	return nil
 }
 
-func (n *Network) DeployChaincode(chaincode *topology.ChannelChaincode) {
-	orderer := n.Orderer("orderer")
+func (n *Network) DeployChaincode(chaincode *topology.ChannelChaincode) error {
+	orderer := n.Orderers[0]
 	peers := n.PeersByName(chaincode.Peers)
+	if len(peers) == 0 {
+		return errors.Errorf("no peers to deploy chaincode [%s] on", chaincode.Chaincode.Name)
+	}
 
 	if len(chaincode.Chaincode.PackageFile) == 0 {
 		if len(chaincode.Path) != 0 {
-			chaincodePath := n.Components.Build(chaincode.Path)
+			chaincodePath, err := n.Components.Build(chaincode.Path)
+			if err != nil {
+				return err
+			}
 			chaincode.Chaincode.Path = chaincodePath
 			chaincode.Chaincode.Lang = "binary"
 		}
 		chaincode.Chaincode.PackageFile = filepath.Join(n.Registry.RootDir, chaincode.Chaincode.Name+".tar.gz")
-		PackageChaincode(n, &chaincode.Chaincode, peers[0])
+		if err := PackageChaincode(n, &chaincode.Chaincode, peers[0]); err != nil {
+			return err
+		}
 	}
 
-	PackageAndInstallChaincode(n, &chaincode.Chaincode, peers...)
-	ApproveChaincodeForMyOrg(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers...)
-	CheckCommitReadinessUntilReady(n, chaincode.Channel, &chaincode.Chaincode, n.PeerOrgsByPeers(peers), peers...)
-	CommitChaincode(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers[0], peers...)
+	if err := PackageAndInstallChaincode(n, &chaincode.Chaincode, peers...); err != nil {
+		return err
+	}
+	if err := ApproveChaincodeForMyOrg(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers...); err != nil {
+		return err
+	}
+	if err := CheckCommitReadinessUntilReady(n, chaincode.Channel, &chaincode.Chaincode, n.PeerOrgsByPeers(peers), peers...); err != nil {
+		return err
+	}
+	if err := CommitChaincode(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers[0], peers...); err != nil {
+		return err
+	}
 	for _, peer := range peers {
-		QueryInstalledReferences(n,
+		err := QueryInstalledReferences(n,
 			chaincode.Channel, chaincode.Chaincode.Label, chaincode.Chaincode.PackageID,
 			peer,
 			[]string{chaincode.Chaincode.Name, chaincode.Chaincode.Version})
+		if err != nil {
+			return err
+		}
 	}
 	if chaincode.Chaincode.InitRequired {
-		InitChaincode(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers...)
+		return InitChaincode(n, chaincode.Channel, orderer, &chaincode.Chaincode, peers...)
+	}
+	return nil
+}
+
+// wait waits for the session, started by one of the command methods, to exit successfully
+func (n *Network) wait(sess *gexec.Session, err error) error {
+	if err != nil {
+		return err
 	}
+	return common.WaitSession(sess, n.EventuallyTimeout)
 }
@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 	"github.com/onsi/gomega/matchers"
 	"github.com/onsi/gomega/types"
+	"github.com/pkg/errors"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/ginkgomon"
 	"github.com/tedsuo/ifrit/grouper"
@@ -114,47 +115,59 @@ func (n *Network) OrdererConfigPath(o *topology.Orderer) string {
 
 // ReadOrdererConfig  unmarshals an orderer's orderer.yaml and returns an
 // object approximating its contents.
-func (n *Network) ReadOrdererConfig(o *topology.Orderer) *fabricconfig.Orderer {
+func (n *Network) ReadOrdererConfig(o *topology.Orderer) (*fabricconfig.Orderer, error) {
 	var orderer fabricconfig.Orderer
 	ordererBytes, err := ioutil.ReadFile(n.OrdererConfigPath(o))
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading configuration of [%s]", o.ID())
+	}
 
 	err = yaml.Unmarshal(ordererBytes, &orderer)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling configuration of [%s]", o.ID())
+	}
 
-	return &orderer
+	return &orderer, nil
 }
 
 // WriteOrdererConfig serializes the provided configuration as the specified
 // orderer's orderer.yaml document.
-func (n *Network) WriteOrdererConfig(o *topology.Orderer, config *fabricconfig.Orderer) {
+func (n *Network) WriteOrdererConfig(o *topology.Orderer, config *fabricconfig.Orderer) error {
 	ordererBytes, err := yaml.Marshal(config)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed marshalling configuration of [%s]", o.ID())
+	}
 
 	err = ioutil.WriteFile(n.OrdererConfigPath(o), ordererBytes, 0644)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrapf(err, "failed writing configuration of [%s]", o.ID())
 }
 
 // ReadConfigTxConfig  unmarshals the configtx.yaml and returns an
 // object approximating its contents.
-func (n *Network) ReadConfigTxConfig() *fabricconfig.ConfigTx {
+func (n *Network) ReadConfigTxConfig() (*fabricconfig.ConfigTx, error) {
 	var configtx fabricconfig.ConfigTx
 	configtxBytes, err := ioutil.ReadFile(n.ConfigTxConfigPath())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading configtx.yaml")
+	}
 
 	err = yaml.Unmarshal(configtxBytes, &configtx)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling configtx.yaml")
+	}
 
-	return &configtx
+	return &configtx, nil
 }
 
 // WriteConfigTxConfig serializes the provided configuration to configtx.yaml.
-func (n *Network) WriteConfigTxConfig(config *fabricconfig.ConfigTx) {
+func (n *Network) WriteConfigTxConfig(config *fabricconfig.ConfigTx) error {
 	configtxBytes, err := yaml.Marshal(config)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling configtx.yaml")
+	}
 
 	err = ioutil.WriteFile(n.ConfigTxConfigPath(), configtxBytes, 0644)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrap(err, "failed writing configtx.yaml")
 }
 
 // PeerDir returns the path to the configuration directory for the specified
@@ -176,33 +189,37 @@ func (n *Network) PeerLedgerDir(p *topology.Peer) string {
 
 // ReadPeerConfig unmarshals a peer's core.yaml and returns an object
 // approximating its contents.
-func (n *Network) ReadPeerConfig(p *topology.Peer) *fabricconfig.Core {
+func (n *Network) ReadPeerConfig(p *topology.Peer) (*fabricconfig.Core, error) {
 	var core fabricconfig.Core
 	coreBytes, err := ioutil.ReadFile(n.PeerConfigPath(p))
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading configuration of [%s]", p.ID())
+	}
 
 	err = yaml.Unmarshal(coreBytes, &core)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling configuration of [%s]", p.ID())
+	}
 
-	return &core
+	return &core, nil
 }
 
 // WritePeerConfig serializes the provided configuration as the specified
 // peer's core.yaml document.
-func (n *Network) WritePeerConfig(p *topology.Peer, config *fabricconfig.Core) {
+func (n *Network) WritePeerConfig(p *topology.Peer, config *fabricconfig.Core) error {
 	coreBytes, err := yaml.Marshal(config)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed marshalling configuration of [%s]", p.ID())
+	}
 
 	err = ioutil.WriteFile(n.PeerConfigPath(p), coreBytes, 0644)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrapf(err, "failed writing configuration of [%s]", p.ID())
 }
 
 // peerUserCryptoDir returns the path to the directory containing the
 // certificates and keys for the specified user of the peer.
 func (n *Network) peerUserCryptoDir(p *topology.Peer, user, cryptoMaterialType string) string {
 	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return n.userCryptoDir(org, "peerOrganizations", user, cryptoMaterialType)
 }
 
@@ -210,8 +227,6 @@ func (n *Network) peerUserCryptoDir(p *topology.Peer, user, cryptoMaterialType s
 // certificates and keys for the specified user of the orderer.
 func (n *Network) ordererUserCryptoDir(o *topology.Orderer, user, cryptoMaterialType string) string {
 	org := n.Organization(o.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return n.userCryptoDir(org, "ordererOrganizations", user, cryptoMaterialType)
 }
 
@@ -290,8 +305,6 @@ func (n *Network) PeerUserTLSDir(p *topology.Peer, user string) string {
 // the peer organization.
 func (n *Network) PeerUserCert(p *topology.Peer, user string) string {
 	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.PeerUserMSPDir(p, user),
 		"signcerts",
@@ -303,8 +316,6 @@ func (n *Network) PeerUserCert(p *topology.Peer, user string) string {
 // the orderer organization.
 func (n *Network) OrdererUserCert(o *topology.Orderer, user string) string {
 	org := n.Organization(o.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.OrdererUserMSPDir(o, user),
 		"signcerts",
@@ -315,9 +326,6 @@ func (n *Network) OrdererUserCert(o *topology.Orderer, user string) string {
 // PeerUserKey returns the path to the private key for the specified user in
 // the peer organization.
 func (n *Network) PeerUserKey(p *topology.Peer, user string) string {
-	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.PeerUserMSPDir(p, user),
 		"keystore",
@@ -326,9 +334,6 @@ func (n *Network) PeerUserKey(p *topology.Peer, user string) string {
 }
 
 func (n *Network) PeerKey(p *topology.Peer) string {
-	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.PeerLocalMSPDir(p),
 		"keystore",
@@ -339,9 +344,6 @@ func (n *Network) PeerKey(p *topology.Peer) string {
 // OrdererUserKey returns the path to the private key for the specified user in
 // the orderer organization.
 func (n *Network) OrdererUserKey(o *topology.Orderer, user string) string {
-	org := n.Organization(o.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.OrdererUserMSPDir(o, user),
 		"keystore",
@@ -352,8 +354,6 @@ func (n *Network) OrdererUserKey(o *topology.Orderer, user string) string {
 // peerLocalCryptoDir returns the path to the local crypto directory for the peer.
 func (n *Network) peerLocalCryptoDir(p *topology.Peer, cryptoType string) string {
 	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.Registry.RootDir,
 		"crypto",
@@ -367,8 +367,6 @@ func (n *Network) peerLocalCryptoDir(p *topology.Peer, cryptoType string) string
 
 func (n *Network) peerUserLocalCryptoDir(p *topology.Peer, user, cryptoType string) string {
 	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.Registry.RootDir,
 		"crypto",
@@ -431,8 +429,6 @@ func (n *Network) PeerLocalTLSDir(p *topology.Peer) string {
 // PeerCert returns the path to the peer's certificate.
 func (n *Network) PeerCert(p *topology.Peer) string {
 	org := n.Organization(p.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.PeerLocalMSPDir(p),
 		"signcerts",
@@ -494,8 +490,6 @@ func (n *Network) OrdererOrgMSPDir(o *topology.Organization) string {
 // Orderer.
 func (n *Network) OrdererLocalCryptoDir(o *topology.Orderer, cryptoType string) string {
 	org := n.Organization(o.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		n.Registry.RootDir,
 		"crypto",
@@ -541,19 +535,21 @@ func (n *Network) CACertsBundlePath() string {
 }
 
 // bootstrapIdemix creates the idemix-related crypto material
-func (n *Network) bootstrapIdemix() {
+func (n *Network) bootstrapIdemix() error {
 	for _, org := range n.IdemixOrgs() {
 		output := n.IdemixOrgMSPDir(org)
 		// - ca-keygen
-		sess, err := n.Idemixgen(commands.CAKeyGen{
+		err := n.wait(n.Idemixgen(commands.CAKeyGen{
 			Output: output,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed generating idemix keys of [%s]", org.Name)
+		}
 	}
+	return nil
 }
 
-func (n *Network) bootstrapExtraIdentities() {
+func (n *Network) bootstrapExtraIdentities() error {
 	for i, peer := range n.Peers {
 		for j, identity := range peer.ExtraIdentities {
 			switch identity.MSPType {
@@ -561,29 +557,36 @@ func (n *Network) bootstrapExtraIdentities() {
 				org := n.Organization(identity.Org)
 				output := n.IdemixOrgMSPDir(org)
 				userOutput := filepath.Join(n.PeerLocalIdemixExtraIdentitiesDir(peer), identity.ID)
-				sess, err := n.Idemixgen(commands.SignerConfig{
+				err := n.wait(n.Idemixgen(commands.SignerConfig{
 					CAInput:          output,
 					Output:           userOutput,
 					OrgUnit:          org.Domain,
 					EnrollmentID:     identity.EnrollmentID,
 					RevocationHandle: fmt.Sprintf("1%d%d", i, j),
-				})
-				Expect(err).NotTo(HaveOccurred())
-				Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+				}))
+				if err != nil {
+					return errors.WithMessagef(err, "failed generating idemix identity [%s] of [%s]", identity.ID, peer.Name)
+				}
 			case "bccsp":
 				// Nothing to do here cause the extra identities are generated by crypto gen.
 			default:
-				Expect(identity.MSPType).To(Equal("idemix"))
+				return errors.Errorf("identity [%s] of [%s] has unknown msp type [%s]", identity.ID, peer.Name, identity.MSPType)
 			}
 		}
 	}
+	return nil
 }
 
-func (n *Network) CheckTopology() {
+func (n *Network) CheckTopology() error {
 	cwd, err := os.Getwd()
-	Expect(err).NotTo(HaveOccurred())
-
+	if err != nil {
+		return errors.Wrap(err, "failed getting working directory")
+	}
 	substring := "github.com/hyperledger-labs/fabric-smart-client"
+	if !strings.Contains(cwd, substring) {
+		return errors.Errorf("working directory [%s] is not within [%s]", cwd, substring)
+	}
+
 	n.ExternalBuilders = []fabricconfig.ExternalBuilder{{
 		Path: filepath.Join(
 			cwd[:strings.Index(cwd, substring)],
@@ -633,7 +636,9 @@ func (n *Network) CheckTopology() {
 
 		po := node.PlatformOpts()
 		opts := opts.Get(po)
-		Expect(opts.Organization()).NotTo(BeEmpty())
+		if n.Organization(opts.Organization()) == nil {
+			return errors.Errorf("fsc node [%s] has unknown organization [%s]", node.Name, opts.Organization())
+		}
 
 		userNames[opts.Organization()] = append(userNames[opts.Organization()], node.Name)
 
@@ -693,6 +698,18 @@ func (n *Network) CheckTopology() {
 		organization.UserNames = append(userNames[organization.Name], "User1", "User2")
 	}
 
+	// the paths of the peers and of the orderers are derived from their organizations
+	for _, p := range n.Peers {
+		if n.Organization(p.Organization) == nil {
+			return errors.Errorf("peer [%s] has unknown organization [%s]", p.Name, p.Organization)
+		}
+	}
+	for _, o := range n.Orderers {
+		if n.Organization(o.Organization) == nil {
+			return errors.Errorf("orderer [%s] has unknown organization [%s]", o.Name, o.Organization)
+		}
+	}
+
 	for _, p := range n.Peers {
 		if p.Type == topology.ViewPeer {
 			continue
@@ -703,23 +720,28 @@ func (n *Network) CheckTopology() {
 		}
 		n.Registry.PortsByPeerID[p.ID()] = ports
 	}
+
+	n.reserveProxyPorts()
+	return nil
 }
 
 // ConcatenateTLSCACertificates concatenates all TLS CA certificates into a
 // single file to be used by peer CLI.
-func (n *Network) ConcatenateTLSCACertificates() {
+func (n *Network) ConcatenateTLSCACertificates() error {
 	bundle := &bytes.Buffer{}
 	for _, tlsCertPath := range n.listTLSCACertificates() {
 		certBytes, err := ioutil.ReadFile(tlsCertPath)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed reading [%s]", tlsCertPath)
+		}
 		bundle.Write(certBytes)
 	}
 	if len(bundle.Bytes()) == 0 {
-		return
+		return nil
 	}
 
 	err := ioutil.WriteFile(n.CACertsBundlePath(), bundle.Bytes(), 0660)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrap(err, "failed writing tls ca certificates bundle")
 }
 
 // listTLSCACertificates returns the paths of all TLS CA certificates in the
@@ -750,32 +772,39 @@ func (n *Network) listTLSCACertificates() []string {
 // channel(s).
 //
 // The network must be running before this is called.
-func (n *Network) CreateAndJoinChannels(o *topology.Orderer) {
+func (n *Network) CreateAndJoinChannels(o *topology.Orderer) error {
 	for _, c := range n.Channels {
-		n.CreateAndJoinChannel(o, c.Name)
+		if err := n.CreateAndJoinChannel(o, c.Name); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // CreateAndJoinChannel will create the specified channel. The referencing
 // peers will then be joined to the channel.
 //
 // The network must be running before this is called.
-func (n *Network) CreateAndJoinChannel(o *topology.Orderer, channelName string) {
+func (n *Network) CreateAndJoinChannel(o *topology.Orderer, channelName string) error {
 	peers := n.PeersWithChannel(channelName)
 	if len(peers) == 0 {
-		return
+		return nil
 	}
 
-	n.CreateChannel(channelName, o, peers[0])
-	n.JoinChannel(channelName, o, peers...)
+	if err := n.CreateChannel(channelName, o, peers[0]); err != nil {
+		return err
+	}
+	return n.JoinChannel(channelName, o, peers...)
 }
 
 // UpdateChannelAnchors determines the anchor peers for the specified channel,
 // creates an anchor peer update transaction for each organization, and submits
 // the update transactions to the orderer.
-func (n *Network) UpdateChannelAnchors(o *topology.Orderer, channelName string) {
+func (n *Network) UpdateChannelAnchors(o *topology.Orderer, channelName string) error {
 	tempFile, err := ioutil.TempFile("", "update-anchors")
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed creating anchor peers update file")
+	}
 	tempFile.Close()
 	defer os.Remove(tempFile.Name())
 
@@ -792,19 +821,21 @@ func (n *Network) UpdateChannelAnchors(o *topology.Orderer, channelName string)
 			ConfigPath:              n.Registry.RootDir,
 			AsOrg:                   orgName,
 		}
-		sess, err := n.ConfigTxGen(anchorUpdate)
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		if err := n.wait(n.ConfigTxGen(anchorUpdate)); err != nil {
+			return errors.WithMessagef(err, "failed generating anchor peers update of [%s] for [%s]", orgName, channelName)
+		}
 
-		sess, err = n.PeerAdminSession(p, commands.ChannelUpdate{
+		err = n.wait(n.PeerAdminSession(p, commands.ChannelUpdate{
 			ChannelID:  channelName,
 			Orderer:    n.OrdererAddress(o, ListenPort),
 			File:       tempFile.Name(),
 			ClientAuth: n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed updating anchor peers of [%s] for [%s]", orgName, channelName)
+		}
 	}
+	return nil
 }
 
 // VerifyMembership checks that each peer has discovered the expected
@@ -828,22 +859,22 @@ func (n *Network) VerifyMembership(expectedPeers []*topology.Peer, channel strin
 // aspects of the channel config for the new channel.
 //
 // The orderer must be running when this is called.
-func (n *Network) CreateChannel(channelName string, o *topology.Orderer, p *topology.Peer, additionalSigners ...interface{}) {
+func (n *Network) CreateChannel(channelName string, o *topology.Orderer, p *topology.Peer, additionalSigners ...interface{}) error {
 	channelCreateTxPath := n.CreateChannelTxPath(channelName)
-	n.signConfigTransaction(channelCreateTxPath, p, additionalSigners...)
+	if err := n.signConfigTransaction(channelCreateTxPath, p, additionalSigners...); err != nil {
+		return err
+	}
 
-	createChannel := func() int {
-		sess, err := n.PeerAdminSession(p, commands.ChannelCreate{
+	err := common.Retry(n.EventuallyTimeout, func() error {
+		return n.wait(n.PeerAdminSession(p, commands.ChannelCreate{
 			ChannelID:   channelName,
 			Orderer:     n.OrdererAddress(o, ListenPort),
 			File:        channelCreateTxPath,
 			OutputBlock: "/dev/null",
 			ClientAuth:  n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		return sess.Wait(n.EventuallyTimeout).ExitCode()
-	}
-	Eventually(createChannel, n.EventuallyTimeout).Should(Equal(0))
+		}))
+	})
+	return errors.WithMessagef(err, "failed creating channel [%s]", channelName)
 }
 
 // CreateChannelExitCode will submit an existing create channel transaction to
@@ -852,9 +883,11 @@ func (n *Network) CreateChannel(channelName string, o *topology.Orderer, p *topo
 //
 // The channel transaction must exist at the location returned by
 // CreateChannelTxPath and the orderer must be running when this is called.
-func (n *Network) CreateChannelExitCode(channelName string, o *topology.Orderer, p *topology.Peer, additionalSigners ...interface{}) int {
+func (n *Network) CreateChannelExitCode(channelName string, o *topology.Orderer, p *topology.Peer, additionalSigners ...interface{}) (int, error) {
 	channelCreateTxPath := n.CreateChannelTxPath(channelName)
-	n.signConfigTransaction(channelCreateTxPath, p, additionalSigners...)
+	if err := n.signConfigTransaction(channelCreateTxPath, p, additionalSigners...); err != nil {
+		return -1, err
+	}
 
 	sess, err := n.PeerAdminSession(p, commands.ChannelCreate{
 		ChannelID:   channelName,
@@ -863,90 +896,116 @@ func (n *Network) CreateChannelExitCode(channelName string, o *topology.Orderer,
 		OutputBlock: "/dev/null",
 		ClientAuth:  n.ClientAuthRequired,
 	})
-	Expect(err).NotTo(HaveOccurred())
-	return sess.Wait(n.EventuallyTimeout).ExitCode()
+	if err != nil {
+		return -1, err
+	}
+	return common.WaitExit(sess, n.EventuallyTimeout)
 }
 
-func (n *Network) signConfigTransaction(channelTxPath string, submittingPeer *topology.Peer, signers ...interface{}) {
+func (n *Network) signConfigTransaction(channelTxPath string, submittingPeer *topology.Peer, signers ...interface{}) error {
 	for _, signer := range signers {
 		switch signer := signer.(type) {
 		case *topology.Peer:
-			sess, err := n.PeerAdminSession(signer, commands.SignConfigTx{
+			err := n.wait(n.PeerAdminSession(signer, commands.SignConfigTx{
 				File:       channelTxPath,
 				ClientAuth: n.ClientAuthRequired,
-			})
-			Expect(err).NotTo(HaveOccurred())
-			Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+			}))
+			if err != nil {
+				return errors.WithMessagef(err, "failed signing [%s] by [%s]", channelTxPath, signer.ID())
+			}
 
 		case *topology.Orderer:
-			sess, err := n.OrdererAdminSession(signer, submittingPeer, commands.SignConfigTx{
+			err := n.wait(n.OrdererAdminSession(signer, submittingPeer, commands.SignConfigTx{
 				File:       channelTxPath,
 				ClientAuth: n.ClientAuthRequired,
-			})
-			Expect(err).NotTo(HaveOccurred())
-			Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+			}))
+			if err != nil {
+				return errors.WithMessagef(err, "failed signing [%s] by [%s]", channelTxPath, signer.ID())
+			}
 
 		default:
-			panic(fmt.Sprintf("unknown signer type %T, expect Peer or Orderer", signer))
+			return errors.Errorf("unknown signer type %T, expect Peer or Orderer", signer)
 		}
 	}
+	return nil
 }
 
 // JoinChannel will join peers to the specified channel. The orderer is used to
 // obtain the current configuration block for the channel.
 //
 // The orderer and listed peers must be running before this is called.
-func (n *Network) JoinChannel(name string, o *topology.Orderer, peers ...*topology.Peer) {
+func (n *Network) JoinChannel(name string, o *topology.Orderer, peers ...*topology.Peer) error {
 	if len(peers) == 0 {
-		return
+		return nil
 	}
 
 	tempFile, err := ioutil.TempFile("", "genesis-block")
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed creating genesis block file")
+	}
 	tempFile.Close()
 	defer os.Remove(tempFile.Name())
 
-	sess, err := n.PeerAdminSession(peers[0], commands.ChannelFetch{
+	err = n.wait(n.PeerAdminSession(peers[0], commands.ChannelFetch{
 		Block:      "0",
 		ChannelID:  name,
 		Orderer:    n.OrdererAddress(o, ListenPort),
 		OutputFile: tempFile.Name(),
 		ClientAuth: n.ClientAuthRequired,
-	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+	}))
+	if err != nil {
+		return errors.WithMessagef(err, "failed fetching genesis block of [%s]", name)
+	}
 
 	for _, p := range peers {
-		sess, err := n.PeerAdminSession(p, commands.ChannelJoin{
+		err := n.wait(n.PeerAdminSession(p, commands.ChannelJoin{
 			BlockPath:  tempFile.Name(),
 			ClientAuth: n.ClientAuthRequired,
-		})
-		Expect(err).NotTo(HaveOccurred())
-		Eventually(sess, n.EventuallyTimeout).Should(gexec.Exit(0))
+		}))
+		if err != nil {
+			return errors.WithMessagef(err, "failed joining [%s] to [%s]", p.ID(), name)
+		}
 	}
+	return nil
 }
 
 // Cryptogen starts a gexec.Session for the provided cryptogen command.
 func (n *Network) Cryptogen(command common.Command) (*gexec.Session, error) {
-	cmd := common.NewCommand(n.Components.Cryptogen(), command)
+	path, err := n.Components.Cryptogen()
+	if err != nil {
+		return nil, err
+	}
+	cmd := common.NewCommand(path, command)
 	return n.StartSession(cmd, command.SessionName())
 }
 
 // Idemixgen starts a gexec.Session for the provided idemixgen command.
 func (n *Network) Idemixgen(command common.Command) (*gexec.Session, error) {
-	cmd := common.NewCommand(n.Components.Idemixgen(), command)
+	path, err := n.Components.Idemixgen()
+	if err != nil {
+		return nil, err
+	}
+	cmd := common.NewCommand(path, command)
 	return n.StartSession(cmd, command.SessionName())
 }
 
 // ConfigTxGen starts a gexec.Session for the provided configtxgen command.
 func (n *Network) ConfigTxGen(command common.Command) (*gexec.Session, error) {
-	cmd := common.NewCommand(n.Components.ConfigTxGen(), command)
+	path, err := n.Components.ConfigTxGen()
+	if err != nil {
+		return nil, err
+	}
+	cmd := common.NewCommand(path, command)
 	return n.StartSession(cmd, command.SessionName())
 }
 
 // Discover starts a gexec.Session for the provided discover command.
 func (n *Network) Discover(command common.Command) (*gexec.Session, error) {
-	cmd := common.NewCommand(n.Components.Discover(), command)
+	path, err := n.Components.Discover()
+	if err != nil {
+		return nil, err
+	}
+	cmd := common.NewCommand(path, command)
 	cmd.Args = append(cmd.Args, "--peerTLSCA", n.CACertsBundlePath())
 	return n.StartSession(cmd, command.SessionName())
 }
@@ -1038,8 +1097,12 @@ func (n *Network) BrokerGroupRunner() ifrit.Runner {
 
 // OrdererRunner returns an ifrit.Runner for the specified orderer. The runner
 // can be used to start and manage an orderer process.
-func (n *Network) OrdererRunner(o *topology.Orderer) *ginkgomon.Runner {
-	cmd := exec.Command(n.Components.Orderer())
+func (n *Network) OrdererRunner(o *topology.Orderer) (*ginkgomon.Runner, error) {
+	path, err := n.Components.Orderer()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(path)
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, fmt.Sprintf("FABRIC_CFG_PATH=%s", n.OrdererDir(o)))
 
@@ -1057,32 +1120,39 @@ func (n *Network) OrdererRunner(o *topology.Orderer) *ginkgomon.Runner {
 		config.StartCheckTimeout = 3 * time.Minute
 	}
 
-	return ginkgomon.New(config)
+	return ginkgomon.New(config), nil
 }
 
 // OrdererGroupRunner returns a runner that can be used to start and stop all
 // orderers in a network.
-func (n *Network) OrdererGroupRunner() ifrit.Runner {
+func (n *Network) OrdererGroupRunner() (ifrit.Runner, error) {
 	members := grouper.Members{}
 	for _, o := range n.Orderers {
-		members = append(members, grouper.Member{Name: o.ID(), Runner: n.OrdererRunner(o)})
+		r, err := n.OrdererRunner(o)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, grouper.Member{Name: o.ID(), Runner: r})
 	}
 	if len(members) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	return grouper.NewParallel(syscall.SIGTERM, members)
+	return grouper.NewParallel(syscall.SIGTERM, members), nil
 }
 
 // PeerRunner returns an ifrit.Runner for the specified peer. The runner can be
 // used to start and manage a peer process.
-func (n *Network) PeerRunner(p *topology.Peer, env ...string) *ginkgomon.Runner {
-	cmd := n.peerCommand(
+func (n *Network) PeerRunner(p *topology.Peer, env ...string) (*ginkgomon.Runner, error) {
+	cmd, err := n.peerCommand(
 		p.ExecutablePath,
 		commands.NodeStart{PeerID: p.ID(), DevMode: p.DevMode},
 		"",
 		fmt.Sprintf("FABRIC_CFG_PATH=%s", n.PeerDir(p)),
 	)
+	if err != nil {
+		return nil, err
+	}
 	cmd.Env = append(cmd.Env, env...)
 
 	return ginkgomon.New(ginkgomon.Config{
@@ -1091,27 +1161,35 @@ func (n *Network) PeerRunner(p *topology.Peer, env ...string) *ginkgomon.Runner
 		Command:           cmd,
 		StartCheck:        `Started peer with ID=.*, .*, address=`,
 		StartCheckTimeout: 1 * time.Minute,
-	})
+	}), nil
 }
 
 // PeerGroupRunner returns a runner that can be used to start and stop all
 // peers in a network.
-func (n *Network) PeerGroupRunner() ifrit.Runner {
+func (n *Network) PeerGroupRunner() (ifrit.Runner, error) {
 	members := grouper.Members{}
 	for _, p := range n.Peers {
 		switch {
 		case p.Type == topology.FabricPeer:
-			members = append(members, grouper.Member{Name: p.ID(), Runner: n.PeerRunner(p)})
+			r, err := n.PeerRunner(p)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, grouper.Member{Name: p.ID(), Runner: r})
 		}
 	}
 	if len(members) == 0 {
-		return nil
+		return nil, nil
 	}
-	return grouper.NewParallel(syscall.SIGTERM, members)
+	return grouper.NewParallel(syscall.SIGTERM, members), nil
 }
 
-func (n *Network) peerCommand(executablePath string, command common.Command, tlsDir string, env ...string) *exec.Cmd {
-	cmd := common.NewCommand(n.Components.Peer(executablePath), command)
+func (n *Network) peerCommand(executablePath string, command common.Command, tlsDir string, env ...string) (*exec.Cmd, error) {
+	path, err := n.Components.Peer(executablePath)
+	if err != nil {
+		return nil, err
+	}
+	cmd := common.NewCommand(path, command)
 	cmd.Env = append(cmd.Env, env...)
 	cmd.Env = append(cmd.Env, "FABRIC_LOGGING_SPEC="+n.Logging.Spec)
 
@@ -1145,7 +1223,7 @@ func (n *Network) peerCommand(executablePath string, command common.Command, tls
 		cmd.Args = append(cmd.Args, "--tlsRootCertFiles")
 		cmd.Args = append(cmd.Args, n.CACertsBundlePath())
 	}
-	return cmd
+	return cmd, nil
 }
 
 func flagCount(flag string, args []string) int {
@@ -1169,20 +1247,23 @@ func (n *Network) PeerAdminSession(p *topology.Peer, command common.Command) (*g
 // command. This is intended to be used by short running peer cli commands that
 // execute in the context of a peer configuration.
 func (n *Network) PeerUserSession(p *topology.Peer, user string, command common.Command) (*gexec.Session, error) {
-	cmd := n.peerCommand(
+	cmd, err := n.peerCommand(
 		p.ExecutablePath,
 		command,
 		n.PeerUserTLSDir(p, user),
 		fmt.Sprintf("FABRIC_CFG_PATH=%s", n.PeerDir(p)),
 		fmt.Sprintf("CORE_PEER_MSPCONFIGPATH=%s", n.PeerUserMSPDir(p, user)),
 	)
+	if err != nil {
+		return nil, err
+	}
 	return n.StartSession(cmd, command.SessionName())
 }
 
 // OrdererAdminSession starts a gexec.Session as an orderer admin user. This
 // is used primarily to generate orderer configuration updates.
 func (n *Network) OrdererAdminSession(o *topology.Orderer, p *topology.Peer, command common.Command) (*gexec.Session, error) {
-	cmd := n.peerCommand(
+	cmd, err := n.peerCommand(
 		p.ExecutablePath,
 		command,
 		n.ordererUserCryptoDir(o, "Admin", "tls"),
@@ -1190,6 +1271,9 @@ func (n *Network) OrdererAdminSession(o *topology.Orderer, p *topology.Peer, com
 		fmt.Sprintf("FABRIC_CFG_PATH=%s", n.PeerDir(p)),
 		fmt.Sprintf("CORE_PEER_MSPCONFIGPATH=%s", n.OrdererUserMSPDir(o, "Admin")),
 	)
+	if err != nil {
+		return nil, err
+	}
 	return n.StartSession(cmd, command.SessionName())
 }
 
@@ -1480,10 +1564,9 @@ func (n *Network) OrdererAddress(o *topology.Orderer, portName registry.PortName
 }
 
 // OrdererPort returns the named port reserved for the Orderer instance.
+// The ports are reserved by CheckTopology.
 func (n *Network) OrdererPort(o *topology.Orderer, portName registry.PortName) uint16 {
-	ordererPorts := n.PortsByOrdererID[o.ID()]
-	Expect(ordererPorts).NotTo(BeNil())
-	return ordererPorts[portName]
+	return n.PortsByOrdererID[o.ID()][portName]
 }
 
 // PeerAddress returns the address (host and port) exposed by the Peer for the
@@ -1501,19 +1584,13 @@ func (n *Network) PeerAddressByName(p *topology.Peer, portName registry.PortName
 }
 
 // PeerPort returns the named port reserved for the Peer instance.
+// The ports are reserved by CheckTopology.
 func (n *Network) PeerPort(p *topology.Peer, portName registry.PortName) uint16 {
-	peerPorts := n.Registry.PortsByPeerID[p.ID()]
-	if peerPorts == nil {
-		fmt.Printf("PeerPort [%s,%s] not found", p.ID(), portName)
-	}
-	Expect(peerPorts).NotTo(BeNil(), "PeerPort [%s,%s] not found", p.ID(), portName)
-	return peerPorts[portName]
+	return n.Registry.PortsByPeerID[p.ID()][portName]
 }
 
 func (n *Network) PeerPortByName(p *topology.Peer, portName registry.PortName) uint16 {
-	peerPorts := n.Registry.PortsByPeerID[p.Name]
-	Expect(peerPorts).NotTo(BeNil())
-	return peerPorts[portName]
+	return n.Registry.PortsByPeerID[p.Name][portName]
 }
 
 func (n *Network) BootstrapNode(me *topology.Peer) string {
@@ -1567,38 +1644,50 @@ func (n *Network) StartSession(cmd *exec.Cmd, name string) (*gexec.Session, erro
 	)
 }
 
-func (n *Network) GenerateCryptoConfig() {
+func (n *Network) GenerateCryptoConfig() error {
 	crypto, err := os.Create(n.CryptoConfigPath())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed creating crypto-config.yaml")
+	}
 	defer crypto.Close()
 
 	t, err := template.New("crypto").Parse(n.Templates.CryptoTemplate())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed parsing crypto-config template")
+	}
 
 	//pw := gexec.NewPrefixedWriter("[crypto-config.yaml] ", ginkgo.GinkgoWriter)
 	err = t.Execute(io.MultiWriter(crypto), n)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrap(err, "failed generating crypto-config.yaml")
 }
 
-func (n *Network) GenerateConfigTxConfig() {
+func (n *Network) GenerateConfigTxConfig() error {
 	config, err := os.Create(n.ConfigTxConfigPath())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed creating configtx.yaml")
+	}
 	defer config.Close()
 
 	t, err := template.New("configtx").Parse(n.Templates.ConfigTxTemplate())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed parsing configtx template")
+	}
 
 	//pw := gexec.NewPrefixedWriter("[configtx.yaml] ", ginkgo.GinkgoWriter)
 	err = t.Execute(io.MultiWriter(config), n)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrap(err, "failed generating configtx.yaml")
 }
 
-func (n *Network) GenerateOrdererConfig(o *topology.Orderer) {
+func (n *Network) GenerateOrdererConfig(o *topology.Orderer) error {
 	err := os.MkdirAll(n.OrdererDir(o), 0755)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed creating folder of [%s]", o.ID())
+	}
 
 	orderer, err := os.Create(n.OrdererConfigPath(o))
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed creating orderer.yaml of [%s]", o.ID())
+	}
 	defer orderer.Close()
 
 	t, err := template.New("orderer").Funcs(template.FuncMap{
@@ -1606,21 +1695,27 @@ func (n *Network) GenerateOrdererConfig(o *topology.Orderer) {
 		"ToLower":    func(s string) string { return strings.ToLower(s) },
 		"ReplaceAll": func(s, old, new string) string { return strings.Replace(s, old, new, -1) },
 	}).Parse(n.Templates.OrdererTemplate())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed parsing orderer template")
+	}
 
 	//pw := gexec.NewPrefixedWriter(fmt.Sprintf("[%s#orderer.yaml] ", o.ID()), ginkgo.GinkgoWriter)
 	err = t.Execute(io.MultiWriter(orderer), n)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrapf(err, "failed generating orderer.yaml of [%s]", o.ID())
 }
 
-func (n *Network) GenerateCoreConfig(p *topology.Peer) {
+func (n *Network) GenerateCoreConfig(p *topology.Peer) error {
 	switch p.Type {
 	case topology.FabricPeer:
 		err := os.MkdirAll(n.PeerDir(p), 0755)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed creating folder of [%s]", p.ID())
+		}
 
 		core, err := os.Create(n.PeerConfigPath(p))
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed creating core.yaml of [%s]", p.ID())
+		}
 		defer core.Close()
 
 		coreTemplate := n.Templates.CoreTemplate()
@@ -1632,16 +1727,22 @@ func (n *Network) GenerateCoreConfig(p *topology.Peer) {
 			"ToLower":                   func(s string) string { return strings.ToLower(s) },
 			"ReplaceAll":                func(s, old, new string) string { return strings.Replace(s, old, new, -1) },
 		}).Parse(coreTemplate)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrap(err, "failed parsing core template")
+		}
 
 		//pw := gexec.NewPrefixedWriter(fmt.Sprintf("[%s#core.yaml] ", p.ID()), ginkgo.GinkgoWriter)
 		extension := bytes.NewBuffer([]byte{})
 		err = t.Execute(io.MultiWriter(core, extension), n)
+		if err != nil {
+			return errors.Wrapf(err, "failed generating core.yaml of [%s]", p.ID())
+		}
 		n.Registry.AddExtension(p.ID(), registry.FabricExtension, extension.String())
-		Expect(err).NotTo(HaveOccurred())
 	case topology.ViewPeer:
 		err := os.MkdirAll(n.PeerDir(p), 0755)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed creating folder of [%s]", p.ID())
+		}
 
 		var refPeers []*topology.Peer
 		coreTemplate := n.Templates.CoreTemplate()
@@ -1662,19 +1763,28 @@ func (n *Network) GenerateCoreConfig(p *topology.Peer) {
 			"ToLower":                   func(s string) string { return strings.ToLower(s) },
 			"ReplaceAll":                func(s, old, new string) string { return strings.Replace(s, old, new, -1) },
 			"Peers":                     func() []*topology.Peer { return refPeers },
-			"OrdererAddress":            func(o *topology.Orderer, portName registry.PortName) string { return n.OrdererAddress(o, portName) },
-			"PeerAddress":               func(o *topology.Peer, portName registry.PortName) string { return n.PeerAddress(o, portName) },
-			"CACertsBundlePath":         func() string { return n.CACertsBundlePath() },
-			"NodeVaultPath":             func() string { return n.NodeVaultDir(p) },
+			"OrdererAddress": func(o *topology.Orderer, portName registry.PortName) string {
+				return n.viewPeerAddress(p, o.ID(), portName, n.OrdererAddress(o, portName))
+			},
+			"PeerAddress": func(o *topology.Peer, portName registry.PortName) string {
+				return n.viewPeerAddress(p, o.ID(), portName, n.PeerAddress(o, portName))
+			},
+			"CACertsBundlePath": func() string { return n.CACertsBundlePath() },
+			"NodeVaultPath":     func() string { return n.NodeVaultDir(p) },
 		}).Parse(coreTemplate)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrap(err, "failed parsing fsc extension template")
+		}
 
 		//pw := gexec.NewPrefixedWriter(fmt.Sprintf("[%s#extension#core.yaml] ", p.ID()), ginkgo.GinkgoWriter)
 		extension := bytes.NewBuffer([]byte{})
 		err = t.Execute(io.MultiWriter(extension), n)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed generating fsc extension of [%s]", p.ID())
+		}
 		n.Registry.AddExtension(p.Name, registry.FabricExtension, extension.String())
 	}
+	return nil
 }
 
 func (n *Network) PeersByName(names []string) []*topology.Peer {
@@ -14,29 +14,34 @@ import (
 	"io/ioutil"
 	"os"
 
-	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
+	"github.com/pkg/errors"
 
-	. "github.com/onsi/gomega"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
 )
 
 // PackageChaincodeBinary is a helper function to package
 // an already built chaincode and write it to the location
 // specified by Chaincode.PackageFile.
-func PackageChaincodeBinary(c *topology.Chaincode) {
+func PackageChaincodeBinary(c *topology.Chaincode) error {
 	file, err := os.Create(c.PackageFile)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed creating package file of [%s]", c.Name)
+	}
 	defer file.Close()
-	writeTarGz(c, file)
+	return errors.WithMessagef(writeTarGz(c, file), "failed packaging chaincode [%s]", c.Name)
 }
 
-func writeTarGz(c *topology.Chaincode, w io.Writer) {
+func writeTarGz(c *topology.Chaincode, w io.Writer) error {
 	gw := gzip.NewWriter(w)
 	tw := tar.NewWriter(gw)
-	defer closeAll(tw, gw)
-
-	writeMetadataJSON(tw, c.Path, "binary", c.Label)
 
-	writeCodeTarGz(tw, c.CodeFiles)
+	if err := writeMetadataJSON(tw, c.Path, "binary", c.Label); err != nil {
+		return err
+	}
+	if err := writeCodeTarGz(tw, c.CodeFiles); err != nil {
+		return err
+	}
+	return closeAll(tw, gw)
 }
 
 // packageMetadata holds the path, type, and label for a chaincode package
@@ -46,13 +51,15 @@ type packageMetadata struct {
 	Label string `json:"label"`
 }
 
-func writeMetadataJSON(tw *tar.Writer, path, ccType, label string) {
+func writeMetadataJSON(tw *tar.Writer, path, ccType, label string) error {
 	metadata, err := json.Marshal(&packageMetadata{
 		Path:  path,
 		Type:  ccType,
 		Label: label,
 	})
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling metadata")
+	}
 
 	// write it to the package as metadata.json
 	err = tw.WriteHeader(&tar.Header{
@@ -60,52 +67,75 @@ func writeMetadataJSON(tw *tar.Writer, path, ccType, label string) {
 		Size: int64(len(metadata)),
 		Mode: 0100644,
 	})
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed writing metadata header")
+	}
 	_, err = tw.Write(metadata)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrap(err, "failed writing metadata")
 }
 
-func writeCodeTarGz(tw *tar.Writer, codeFiles map[string]string) {
+func writeCodeTarGz(tw *tar.Writer, codeFiles map[string]string) error {
 	// create temp file to hold code.tar.gz
 	tempfile, err := ioutil.TempFile("", "code.tar.gz")
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed creating code package")
+	}
 	defer os.Remove(tempfile.Name())
+	defer tempfile.Close()
 
 	gzipWriter := gzip.NewWriter(tempfile)
 	tarWriter := tar.NewWriter(gzipWriter)
 
 	for source, target := range codeFiles {
 		file, err := os.Open(source)
-		Expect(err).NotTo(HaveOccurred())
-		writeFileToTar(tarWriter, file, target)
+		if err != nil {
+			return errors.Wrapf(err, "failed opening [%s]", source)
+		}
+		err = writeFileToTar(tarWriter, file, target)
 		file.Close()
+		if err != nil {
+			return err
+		}
 	}
 
 	// close down the inner tar
-	closeAll(tarWriter, gzipWriter)
+	if err := closeAll(tarWriter, gzipWriter); err != nil {
+		return err
+	}
 
-	writeFileToTar(tw, tempfile, "code.tar.gz")
+	return writeFileToTar(tw, tempfile, "code.tar.gz")
 }
 
-func writeFileToTar(tw *tar.Writer, file *os.File, name string) {
+func writeFileToTar(tw *tar.Writer, file *os.File, name string) error {
 	_, err := file.Seek(0, 0)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed seeking [%s]", file.Name())
+	}
 
 	fi, err := file.Stat()
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed reading info of [%s]", file.Name())
+	}
 	header, err := tar.FileInfoHeader(fi, "")
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed creating header of [%s]", file.Name())
+	}
 
 	header.Name = name
 	err = tw.WriteHeader(header)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed writing header of [%s]", name)
+	}
 
 	_, err = io.Copy(tw, file)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrapf(err, "failed writing [%s]", name)
 }
 
-func closeAll(closers ...io.Closer) {
+func closeAll(closers ...io.Closer) error {
 	for _, c := range closers {
-		Expect(c.Close()).To(Succeed())
+		if err := c.Close(); err != nil {
+			return errors.Wrap(err, "failed closing package")
+		}
 	}
+	return nil
 }
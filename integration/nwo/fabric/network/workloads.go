@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package network
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/deploy"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/commands"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/registry"
+)
+
+// FabricImageTag is the tag of the fabric images the deployable workloads run in
+const FabricImageTag = "2.2"
+
+// Workloads returns the orderers and the fabric peers of the network as deployable workloads.
+// Peers with a custom executable get an image built from it.
+func (n *Network) Workloads() ([]*deploy.Workload, error) {
+	if n.Consensus.Brokers != 0 || n.Consensus.ZooKeepers != 0 {
+		return nil, errors.New("kafka based networks cannot be deployed")
+	}
+
+	var workloads []*deploy.Workload
+	for _, o := range n.Orderers {
+		workloads = append(workloads, &deploy.Workload{
+			Name:    o.ID(),
+			Image:   "hyperledger/fabric-orderer:" + FabricImageTag,
+			Command: []string{"orderer"},
+			Env: map[string]string{
+				"FABRIC_CFG_PATH":     n.OrdererDir(o),
+				"FABRIC_LOGGING_SPEC": n.Logging.Spec,
+			},
+			Ports: ports(n.PortsByOrdererID[o.ID()], OrdererPortNames()),
+		})
+	}
+	for _, p := range n.Peers {
+		if p.Type != topology.FabricPeer {
+			continue
+		}
+		w := &deploy.Workload{
+			Name:    p.ID(),
+			Image:   "hyperledger/fabric-peer:" + FabricImageTag,
+			Command: append([]string{"peer"}, commands.NodeStart{PeerID: p.ID(), DevMode: p.DevMode}.Args()...),
+			Env: map[string]string{
+				"FABRIC_CFG_PATH":     n.PeerDir(p),
+				"FABRIC_LOGGING_SPEC": n.Logging.Spec,
+			},
+			Ports: ports(n.Registry.PortsByPeerID[p.ID()], PeerPortNames()),
+		}
+		if len(p.ExecutablePath) != 0 {
+			w.Image = deploy.PackageImage(n.Registry.NetworkID, p.ID())
+			w.Package = p.ExecutablePath
+			w.Command[0] = deploy.Binary
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads, nil
+}
+
+func ports(ports registry.Ports, names []registry.PortName) []uint16 {
+	var res []uint16
+	for _, name := range names {
+		if port, ok := ports[name]; ok {
+			res = append(res, port)
+		}
+	}
+	return res
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/tedsuo/ifrit/grouper"
 
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/deploy"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/network"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fabric/topology"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fault"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/registry"
 )
 
@@ -18,46 +20,48 @@ type platform struct {
 	Network *network.Network
 }
 
-func NewPlatform(registry *registry.Registry, components *common.Components) *platform {
-	return &platform{
-		Network: network.New(
-			registry,
-			components,
-			[]network.ChaincodeProcessor{},
-		),
+func NewPlatform(registry *registry.Registry, components *common.Components) (*platform, error) {
+	n, err := network.New(
+		registry,
+		components,
+		[]network.ChaincodeProcessor{},
+	)
+	if err != nil {
+		return nil, err
 	}
+	return &platform{Network: n}, nil
 }
 
 func (p *platform) Name() string {
 	return "fabric"
 }
 
-func (p *platform) GenerateConfigTree() {
-	p.Network.GenerateConfigTree()
+func (p *platform) GenerateConfigTree() error {
+	return p.Network.GenerateConfigTree()
 }
 
-func (p *platform) GenerateArtifacts() {
-	p.Network.GenerateArtifacts()
+func (p *platform) GenerateArtifacts() error {
+	return p.Network.GenerateArtifacts()
 }
 
-func (p *platform) Load() {
-	p.Network.Load()
+func (p *platform) Load() error {
+	return p.Network.Load()
 }
 
-func (p *platform) Members() []grouper.Member {
+func (p *platform) Members() ([]grouper.Member, error) {
 	return p.Network.Members()
 }
 
-func (p *platform) PostRun() {
-	p.Network.PostRun()
+func (p *platform) PostRun() error {
+	return p.Network.PostRun()
 }
 
-func (p *platform) Cleanup() {
-	p.Network.Cleanup()
+func (p *platform) Cleanup() error {
+	return p.Network.Cleanup()
 }
 
-func (p *platform) DeployChaincode(chaincode *topology.ChannelChaincode) {
-	p.Network.DeployChaincode(chaincode)
+func (p *platform) DeployChaincode(chaincode *topology.ChannelChaincode) error {
+	return p.Network.DeployChaincode(chaincode)
 }
 
 func (p *platform) DefaultIdemixOrgMSPDir() string {
@@ -71,3 +75,12 @@ func (p *platform) Topology() *topology.Topology {
 func (p *platform) PeerChaincodeAddress(peerName string) string {
 	return p.Network.PeerAddress(p.Network.PeerByName(peerName), network.ChaincodePort)
 }
+
+func (p *platform) Workloads() ([]*deploy.Workload, error) {
+	return p.Network.Workloads()
+}
+
+// Proxy returns the proxy standing between the passed FSC node and the orderer, or peer, with the passed identifier
+func (p *platform) Proxy(node, target string) (*fault.Proxy, error) {
+	return p.Network.Proxy(node, target)
+}
@@ -10,7 +10,7 @@ import (
 	"io/ioutil"
 
 	"github.com/hyperledger/fabric/common/util"
-	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
 type Chaincode struct {
@@ -33,9 +33,12 @@ type Chaincode struct {
 	ChannelConfigPolicy string
 }
 
-func (c *Chaincode) SetPackageIDFromPackageFile() {
+func (c *Chaincode) SetPackageIDFromPackageFile() error {
 	fileBytes, err := ioutil.ReadFile(c.PackageFile)
-	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed reading chaincode package [%s]", c.PackageFile)
+	}
 	hashStr := fmt.Sprintf("%x", util.ComputeSHA256(fileBytes))
 	c.PackageID = c.Label + ":" + hashStr
+	return nil
 }
@@ -374,11 +374,30 @@ fabric:
       - name: {{ .Name }}
         default: {{ .Default }}
     {{- end }}
+    discovery:
+      # how long the endorsers returned by the discovery service are cached for
+      ttl: 5m
+    ordering:
+      tracker:
+        # resubmit the transactions that do not appear in a block, discard them once the resubmissions are exhausted
+        enabled: false
+        waitBlocks: 10
+        maxResubmissions: 3
     vault:
       persistence:
         type: file
         opts:
           path: {{ NodeVaultPath }}
+      history:
+        # record the versions of the keys written by the committed transactions
+        enabled: false
+        # number of blocks the history is kept for, 0 to keep it forever
+        retention: 0
+        pruneInterval: 10m
+      local:
+        # namespaces the transactions committed locally, without going through Fabric, can write.
+        # The transactions committed with endorser.NewCommitInVault are rejected if they write any other namespace.
+        namespaces:
   endpoint:
     resolves: {{ range .Resolvers }}
     - name: {{ .Name }}
@@ -36,12 +36,19 @@ type Topology struct {
 	FabTokenCCSupport bool                `yaml:"fabtokenccsupport,omitempty"`
 	GRPCLogging       bool                `yaml:"grpcLogging,omitempty"`
 	NodeOUs           bool                `yaml:"nodeous,omitempty"`
+	FaultInjection    bool                `yaml:"faultInjection,omitempty"`
 }
 
 func (c *Topology) Name() string {
 	return c.TopologyName
 }
 
+// EnableFaultInjection routes the connections of the FSC nodes to the orderers and the peers through proxies
+// that can be partitioned, and runs each orderer and peer on its own so that it can be killed and restarted.
+func (c *Topology) EnableFaultInjection() {
+	c.FaultInjection = true
+}
+
 func (c *Topology) SetLogging(spec, format string) {
 	c.Logging = &Logging{
 		Spec:   spec,
@@ -129,6 +136,101 @@ func (c *Topology) AddOrganization(name string) *fscOrg {
 	return &fscOrg{c: c, o: o}
 }
 
+// AddOrdererOrganization adds an organization that owns orderers only, it does not join the consortium
+func (c *Topology) AddOrdererOrganization(name string) *ordererOrg {
+	o := &Organization{
+		ID:            name,
+		Name:          name,
+		MSPID:         name + "MSP",
+		Domain:        strings.ToLower(name) + ".example.com",
+		EnableNodeOUs: c.NodeOUs,
+		Users:         0,
+		CA:            &CA{Hostname: "ca"},
+	}
+	c.Organizations = append(c.Organizations, o)
+	return &ordererOrg{c: c, o: o}
+}
+
+// AddOrderer adds an orderer, owned by the passed organization, to the ordering service.
+// The orderer is a consenter of the system channel. Orderer names must be unique across organizations.
+func (c *Topology) AddOrderer(name string, org string) *Orderer {
+	o := &Orderer{Name: name, Organization: org}
+	c.Orderers = append(c.Orderers, o)
+	if profile := c.systemChannelProfile(); profile != nil {
+		profile.Orderers = append(profile.Orderers, name)
+	}
+	return o
+}
+
+// EnableRaft switches the ordering service to etcdraft. Each consenter is bound to the TLS certificate
+// of its orderer, which serves the cluster endpoint as well.
+func (c *Topology) EnableRaft() *Topology {
+	c.Consensus.Type = "etcdraft"
+	return c
+}
+
+// SetRaftOrderers replaces the orderers with n etcdraft orderers assigned, in round robin, to the passed
+// organizations. Organizations that do not exist yet are added as orderer organizations.
+// If no organization is passed, the organizations owning the current orderers are used.
+// The first orderer keeps the name of the default one, "orderer", the others are named orderer2, orderer3, and so on.
+func (c *Topology) SetRaftOrderers(n int, orgs ...string) *Topology {
+	if n < 1 {
+		panic("an ordering service needs at least one orderer")
+	}
+	if len(orgs) == 0 {
+		seen := map[string]bool{}
+		for _, o := range c.Orderers {
+			if !seen[o.Organization] {
+				seen[o.Organization] = true
+				orgs = append(orgs, o.Organization)
+			}
+		}
+	}
+	if len(orgs) == 0 {
+		panic("no organization to assign the orderers to")
+	}
+	for _, org := range orgs {
+		if c.organization(org) == nil {
+			c.AddOrdererOrganization(org)
+		}
+	}
+
+	c.Orderers = nil
+	if profile := c.systemChannelProfile(); profile != nil {
+		profile.Orderers = nil
+	}
+	for i := 0; i < n; i++ {
+		name := "orderer"
+		if i > 0 {
+			name = fmt.Sprintf("orderer%d", i+1)
+		}
+		c.AddOrderer(name, orgs[i%len(orgs)])
+	}
+	return c.EnableRaft()
+}
+
+func (c *Topology) organization(name string) *Organization {
+	for _, o := range c.Organizations {
+		if o.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// systemChannelProfile returns the profile of the genesis block of the system channel, nil if there is none
+func (c *Topology) systemChannelProfile() *Profile {
+	if c.SystemChannel == nil {
+		return nil
+	}
+	for _, profile := range c.Profiles {
+		if profile.Name == c.SystemChannel.Profile {
+			return profile
+		}
+	}
+	return nil
+}
+
 func (c *Topology) AddOrganizationsByName(names ...string) *Topology {
 	for _, name := range names {
 		c.AddOrganization(name).AddPeer(fmt.Sprintf("%s_peer_0", name))
@@ -341,6 +443,17 @@ func (fo *fscOrg) AddPeer(name string) *fscOrg {
 	return fo
 }
 
+type ordererOrg struct {
+	c *Topology
+	o *Organization
+}
+
+func (oo *ordererOrg) AddOrderer(name string) *ordererOrg {
+	oo.c.AddOrderer(name, oo.o.Name)
+
+	return oo
+}
+
 type namespace struct {
 	cc *ChannelChaincode
 }
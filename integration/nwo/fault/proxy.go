@@ -0,0 +1,230 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fault provides the means to inject faults in a network generated by nwo.
+package fault
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("fsc.integration.fault")
+
+// Proxy forwards the TCP connections accepted on Address to Target.
+// A partitioned proxy drops the established connections and refuses the new ones,
+// a delayed proxy holds each chunk of data for the configured delay before forwarding it.
+type Proxy struct {
+	Name    string
+	Address string
+	Target  string
+
+	mutex       sync.Mutex
+	listener    net.Listener
+	conns       map[net.Conn]struct{}
+	partitioned bool
+	delay       time.Duration
+	wg          sync.WaitGroup
+}
+
+// NewProxy returns a Proxy forwarding the connections accepted on address to target
+func NewProxy(name, address, target string) *Proxy {
+	return &Proxy{
+		Name:    name,
+		Address: address,
+		Target:  target,
+		conns:   map[net.Conn]struct{}{},
+	}
+}
+
+// Start starts accepting connections
+func (p *Proxy) Start() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.listener != nil {
+		return errors.Errorf("proxy [%s] already started", p.Name)
+	}
+	listener, err := net.Listen("tcp", p.Address)
+	if err != nil {
+		return errors.Wrapf(err, "failed listening on [%s]", p.Address)
+	}
+	p.listener = listener
+	p.wg.Add(1)
+	go p.accept(listener)
+	return nil
+}
+
+// Stop stops accepting connections and closes the established ones
+func (p *Proxy) Stop() {
+	p.mutex.Lock()
+	listener := p.listener
+	p.listener = nil
+	p.mutex.Unlock()
+	if listener == nil {
+		return
+	}
+	listener.Close()
+	p.closeConns()
+	p.wg.Wait()
+}
+
+// Partition drops the established connections and refuses the new ones until Heal is called
+func (p *Proxy) Partition() {
+	logger.Infof("partitioning [%s]", p.Name)
+	p.mutex.Lock()
+	p.partitioned = true
+	p.mutex.Unlock()
+	p.closeConns()
+}
+
+// Heal removes the partition and the delay
+func (p *Proxy) Heal() {
+	logger.Infof("healing [%s]", p.Name)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.partitioned = false
+	p.delay = 0
+}
+
+// SetDelay delays the data forwarded in both directions by the passed duration
+func (p *Proxy) SetDelay(delay time.Duration) {
+	logger.Infof("delaying [%s] by [%s]", p.Name, delay)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.delay = delay
+}
+
+// Partitioned returns true if the proxy is partitioned
+func (p *Proxy) Partitioned() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.partitioned
+}
+
+func (p *Proxy) accept(listener net.Listener) {
+	defer p.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		if p.Partitioned() {
+			conn.Close()
+			continue
+		}
+		target, err := net.Dial("tcp", p.Target)
+		if err != nil {
+			logger.Debugf("failed connecting [%s] to [%s]: %s", p.Name, p.Target, err)
+			conn.Close()
+			continue
+		}
+		if !p.track(conn, target) {
+			conn.Close()
+			target.Close()
+			continue
+		}
+
+		p.wg.Add(2)
+		go p.forward(conn, target)
+		go p.forward(target, conn)
+	}
+}
+
+func (p *Proxy) forward(from, to net.Conn) {
+	defer p.wg.Done()
+	defer p.untrack(from, to)
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := from.Read(buf)
+		if n > 0 {
+			p.mutex.Lock()
+			delay := p.delay
+			p.mutex.Unlock()
+			if delay > 0 {
+				time.Sleep(delay)
+			}
+			if _, err := to.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				logger.Debugf("connection through [%s] closed: %s", p.Name, err)
+			}
+			return
+		}
+	}
+}
+
+// track records the passed connections so that they can be closed on partition,
+// it returns false if the proxy got stopped, or partitioned, in the meantime
+func (p *Proxy) track(conns ...net.Conn) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.listener == nil || p.partitioned {
+		return false
+	}
+	for _, conn := range conns {
+		p.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+func (p *Proxy) untrack(conns ...net.Conn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, conn := range conns {
+		conn.Close()
+		delete(p.conns, conn)
+	}
+}
+
+func (p *Proxy) closeConns() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for conn := range p.conns {
+		conn.Close()
+		delete(p.conns, conn)
+	}
+}
+
+// Proxies is an ifrit.Runner running a set of proxies
+type Proxies []*Proxy
+
+func (ps Proxies) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	for i, p := range ps {
+		if err := p.Start(); err != nil {
+			for _, started := range ps[:i] {
+				started.Stop()
+			}
+			return err
+		}
+	}
+	close(ready)
+
+	<-signals
+	for _, p := range ps {
+		p.Stop()
+	}
+	return nil
+}
+
+// Proxy returns the proxy with the passed name, nil if not found
+func (ps Proxies) Proxy(name string) *Proxy {
+	for _, p := range ps {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fault
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func echoServer(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if _, err := conn.Write([]byte(line)); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l
+}
+
+func echo(conn net.Conn, msg string) (string, error) {
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write([]byte(msg + "\n")); err != nil {
+		return "", err
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return line[:len(line)-1], nil
+}
+
+func TestProxy(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	p := NewProxy("alice->peer0", "127.0.0.1:0", server.Addr().String())
+	assert.NoError(t, p.Start())
+	defer p.Stop()
+	address := p.listener.Addr().String()
+
+	conn, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+	res, err := echo(conn, "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res)
+
+	// the established connections are dropped, the new ones refused
+	p.Partition()
+	_, err = echo(conn, "hello")
+	assert.Error(t, err)
+	conn, err = net.Dial("tcp", address)
+	assert.NoError(t, err)
+	_, err = echo(conn, "hello")
+	assert.Error(t, err)
+
+	// the delay applies in both directions
+	p.Heal()
+	p.SetDelay(100 * time.Millisecond)
+	conn, err = net.Dial("tcp", address)
+	assert.NoError(t, err)
+	start := time.Now()
+	res, err = echo(conn, "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	conn.Close()
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package nwo
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fault"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm"
+)
+
+// FaultInjector is implemented by the platforms that can inject faults in the communication among fsc nodes
+type FaultInjector interface {
+	// InjectFault sets, in the communication layer of node, the fault rule for the messages exchanged with peer.
+	// If rule is nil, the rule in place is cleared.
+	InjectFault(node, peer string, rule *comm.FaultRule) error
+}
+
+// ProxyProvider is implemented by the platforms that put proxies between the fsc nodes and their processes
+type ProxyProvider interface {
+	// Proxy returns the proxy between the passed fsc node and the process with the passed identifier
+	Proxy(node, target string) (*fault.Proxy, error)
+}
+
+// PartitionViewNodes drops all the messages exchanged by the passed fsc nodes
+func (n *Network) PartitionViewNodes(a, b string) error {
+	logger.Infof("Partitioning fsc nodes [%s] and [%s]...", a, b)
+	return n.injectFault(a, b, &comm.FaultRule{Drop: true})
+}
+
+// DelayViewNodes delays all the messages exchanged by the passed fsc nodes
+func (n *Network) DelayViewNodes(a, b string, delay time.Duration) error {
+	logger.Infof("Delaying fsc nodes [%s] and [%s] by [%s]...", a, b, delay)
+	return n.injectFault(a, b, &comm.FaultRule{Delay: delay})
+}
+
+// HealViewNodes removes the faults injected between the passed fsc nodes
+func (n *Network) HealViewNodes(a, b string) error {
+	logger.Infof("Healing fsc nodes [%s] and [%s]...", a, b)
+	return n.injectFault(a, b, nil)
+}
+
+// PartitionFromProcess cuts the connections of the passed fsc node to the process, an orderer or a peer,
+// with the passed identifier
+func (n *Network) PartitionFromProcess(node, target string) error {
+	p, err := n.proxy(node, target)
+	if err != nil {
+		return err
+	}
+	p.Partition()
+	return nil
+}
+
+// DelayToProcess delays the data exchanged by the passed fsc node and the process with the passed identifier
+func (n *Network) DelayToProcess(node, target string, delay time.Duration) error {
+	p, err := n.proxy(node, target)
+	if err != nil {
+		return err
+	}
+	p.SetDelay(delay)
+	return nil
+}
+
+// HealFromProcess removes the partition, and the delay, between the passed fsc node and the process
+// with the passed identifier
+func (n *Network) HealFromProcess(node, target string) error {
+	p, err := n.proxy(node, target)
+	if err != nil {
+		return err
+	}
+	p.Heal()
+	return nil
+}
+
+// StopMember stops a restartable member, an orderer or a peer run on its own, and waits for it to exit
+func (n *Network) StopMember(id string) error {
+	logger.Infof("Stopping [%s]...", id)
+	if err := n.stop(n.RestartableMembers, id, false); err != nil {
+		return errors.WithMessagef(err, "failed stopping [%s]", id)
+	}
+	return nil
+}
+
+// KillMember kills a restartable member, it doesn't get the chance to shut down gracefully
+func (n *Network) KillMember(id string) error {
+	logger.Infof("Killing [%s]...", id)
+	if err := n.stop(n.RestartableMembers, id, true); err != nil {
+		return errors.WithMessagef(err, "failed killing [%s]", id)
+	}
+	return nil
+}
+
+// StartMember restarts a restartable member previously stopped, or killed
+func (n *Network) StartMember(id string) error {
+	logger.Infof("Starting [%s]...", id)
+	if err := n.start(n.RestartableMembers, id); err != nil {
+		return errors.WithMessagef(err, "failed starting [%s]", id)
+	}
+	return nil
+}
+
+// injectFault injects the passed rule at both ends so that the fault applies in both directions
+// no matter which node opened the stream
+func (n *Network) injectFault(a, b string, rule *comm.FaultRule) error {
+	for _, platform := range n.Platforms {
+		injector, ok := platform.(FaultInjector)
+		if !ok {
+			continue
+		}
+		if err := injector.InjectFault(a, b, rule); err != nil {
+			return err
+		}
+		return injector.InjectFault(b, a, rule)
+	}
+	return errors.New("no platform can inject faults")
+}
+
+func (n *Network) proxy(node, target string) (*fault.Proxy, error) {
+	for _, platform := range n.Platforms {
+		provider, ok := platform.(ProxyProvider)
+		if !ok {
+			continue
+		}
+		return provider.Proxy(node, target)
+	}
+	return nil, errors.New("no platform provides proxies")
+}
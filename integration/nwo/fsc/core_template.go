@@ -45,11 +45,29 @@ fsc:
   p2p:
     listenAddress: /ip4/127.0.0.1/tcp/{{ .NodePort Peer "P2P" }}
     bootstrapNode: {{ .BootstrapNode Peer }}
+    {{- if Topology.FaultInjection }}
+    faults:
+      enabled: true
+    {{- end }}
   kvs:
     persistence:
       type: badger
       opts:
         path: {{ NodeKVSPath }}
+  operations:
+    listenAddress: 127.0.0.1:{{ .NodePort Peer "Operations" }}
+    tls:
+      enabled: true
+      cert:
+        file: {{ .NodeLocalTLSDir Peer }}/server.crt
+      key:
+        file: {{ .NodeLocalTLSDir Peer }}/server.key
+      clientAuthRequired: true
+      clientRootCAs:
+        files:
+        - {{ .NodeLocalTLSDir Peer }}/ca.crt
+  metrics:
+    provider: prometheus
 
 {{ range Extensions }}
 {{.}}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package fsc
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/admin"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm"
+)
+
+// InjectFault sets, in the communication layer of the passed node, the fault rule for the messages exchanged with peer.
+// If rule is nil, the rule in place is cleared. The topology must have fault injection enabled.
+func (p *platform) InjectFault(node, peer string, rule *comm.FaultRule) error {
+	if !p.Topology.FaultInjection {
+		return errors.New("fault injection is not enabled in the fsc topology")
+	}
+	n := p.node(node)
+	if n == nil {
+		return errors.Errorf("fsc node [%s] not found", node)
+	}
+
+	c, err := admin.NewClient(p.NodeDir(n))
+	if err != nil {
+		return errors.WithMessagef(err, "failed creating admin client for [%s]", node)
+	}
+	input := &admin.Faults{Node: peer, Clear: rule == nil}
+	if rule != nil {
+		input.Rule = *rule
+	}
+	if _, err := c.Call(admin.FaultsViewID, input); err != nil {
+		return errors.WithMessagef(err, "failed injecting fault on [%s] for [%s]", node, peer)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package fsc
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// MetricsEndpoint returns the URL of the metrics endpoint of the operations server of the passed node,
+// and the file with the TLS root certificate the server certificate chains to
+func (p *platform) MetricsEndpoint(node string) (string, string, error) {
+	n := p.node(node)
+	if n == nil {
+		return "", "", errors.Errorf("fsc node [%s] not found", node)
+	}
+	url := fmt.Sprintf("https://127.0.0.1:%d/metrics", p.NodePort(n, OperationsPort))
+	return url, filepath.Join(p.NodeLocalTLSDir(n), "ca.crt"), nil
+}
+
+// node returns the node with the passed name, nil if there is none
+func (p *platform) node(name string) *Node {
+	for _, n := range p.Topology.Nodes {
+		if n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
@@ -33,7 +33,8 @@ var _ = Describe("EndToEnd", func() {
 			n.RegisterViewFactory("initiator2", &initiator.Factory{})
 			n.RegisterResponder(&responder.Responder{}, &initiator.Initiator{})
 			buf := bytes.NewBuffer(nil)
-			p.GenerateCmd(buf, n)
+			_, err := p.GenerateCmd(buf, n)
+			Expect(err).ToNot(HaveOccurred())
 
 			ExpectedMainOne, err := ioutil.ReadFile("./testdata/main/main.go.output")
 			Expect(err).ToNot(HaveOccurred())
@@ -17,12 +17,13 @@ import (
 	"text/template"
 	"time"
 
-	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/grouper"
 
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/deploy"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/fsc/commands"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/registry"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/runner"
@@ -31,13 +32,14 @@ import (
 )
 
 const (
-	ListenPort registry.PortName = "Listen" // Port at which the fsc node might listen for some service
-	ViewPort   registry.PortName = "View"   // Port at which the View Service Server respond
-	P2PPort    registry.PortName = "P2P"    // Port at which the P2P Communication Layer respond
+	ListenPort     registry.PortName = "Listen"     // Port at which the fsc node might listen for some service
+	ViewPort       registry.PortName = "View"       // Port at which the View Service Server respond
+	P2PPort        registry.PortName = "P2P"        // Port at which the P2P Communication Layer respond
+	OperationsPort registry.PortName = "Operations" // Port at which the Operations Server respond
 )
 
 type Builder interface {
-	Build(path string) string
+	Build(path string) (string, error)
 }
 
 type platform struct {
@@ -58,7 +60,7 @@ func (p *platform) Name() string {
 	return TopologyName
 }
 
-func (p *platform) GenerateConfigTree() {
+func (p *platform) GenerateConfigTree() error {
 	// Allocations
 	bootstrapNodeFound := false
 	for _, node := range p.Topology.Nodes {
@@ -77,42 +79,45 @@ func (p *platform) GenerateConfigTree() {
 	if !bootstrapNodeFound {
 		p.Topology.Nodes[0].Bootstrap = true
 	}
-
+	return nil
 }
 
-func (p *platform) GenerateArtifacts() {
+func (p *platform) GenerateArtifacts() error {
 	// Generate core.yaml for all fsc nodes by including all the additional configurations coming
 	// from other platforms
 	for _, node := range p.Topology.Nodes {
-		p.GenerateCoreConfig(node)
+		if err := p.GenerateCoreConfig(node); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (p *platform) Load() {
+func (p *platform) Load() error {
 	// Nothing to do here
+	return nil
 }
 
-func (p *platform) Members() []grouper.Member {
-	members := grouper.Members{}
-	for _, node := range p.Topology.Nodes {
-		if node.Bootstrap {
-			members = append(members, grouper.Member{Name: node.ID(), Runner: p.ViewNodeRunner(node)})
-		}
+func (p *platform) Members() ([]grouper.Member, error) {
+	bootstrap, err := p.nodeMembers(true)
+	if err != nil {
+		return nil, err
 	}
-	for _, node := range p.Topology.Nodes {
-		if !node.Bootstrap {
-			members = append(members, grouper.Member{Name: node.ID(), Runner: p.ViewNodeRunner(node)})
-		}
+	others, err := p.nodeMembers(false)
+	if err != nil {
+		return nil, err
 	}
-	return members
+	return append(bootstrap, others...), nil
 }
 
-func (p *platform) PostRun() {
+func (p *platform) PostRun() error {
 	for _, node := range p.Topology.Nodes {
 		v := viper.New()
 		v.SetConfigFile(p.NodeConfigPath(node))
 		err := v.ReadInConfig() // Find and read the config file
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed reading configuration of [%s]", node.ID())
+		}
 
 		// Get from the registry the signing identity and the connection config
 		c, err := client.New(
@@ -123,24 +128,72 @@ func (p *platform) PostRun() {
 			p.Registry.ClientSigningIdentities[node.Name],
 			crypto.NewProvider(),
 		)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.WithMessagef(err, "failed creating view client for [%s]", node.ID())
+		}
 
 		p.Registry.ViewClients[node.ID()] = c
 		for _, identity := range p.Registry.ViewIdentityAliases[node.ID()] {
 			p.Registry.ViewClients[identity] = c
 		}
 	}
+	return nil
+}
+
+func (p *platform) Cleanup() error {
+	return nil
 }
 
-func (p *platform) Cleanup() {
+// Workloads returns the fsc nodes as deployable workloads, each with an image built from its executable.
+// The command of the nodes without an executable is generated, the topology is left untouched.
+func (p *platform) Workloads() ([]*deploy.Workload, error) {
+	var workloads []*deploy.Workload
+	for _, node := range p.Topology.Nodes {
+		executablePath := node.ExecutablePath
+		if len(executablePath) == 0 {
+			var err error
+			executablePath, err = p.GenerateCmd(nil, node)
+			if err != nil {
+				return nil, err
+			}
+		}
+		env := map[string]string{
+			"FSCNODE_CFG_PATH":     p.NodeDir(node),
+			"FSCNODE_LOGGING_SPEC": p.Topology.Logging.Spec,
+		}
+		if p.Topology.GRPCLogging {
+			env["GRPC_GO_LOG_VERBOSITY_LEVEL"] = "2"
+			env["GRPC_GO_LOG_SEVERITY_LEVEL"] = "debug"
+		}
+		var ports []uint16
+		for _, portName := range PeerPortNames() {
+			ports = append(ports, p.NodePort(node, portName))
+		}
+		workloads = append(workloads, &deploy.Workload{
+			Name:    node.ID(),
+			Image:   deploy.PackageImage(p.Registry.NetworkID, node.ID()),
+			Package: executablePath,
+			Command: append(
+				append([]string{deploy.Binary}, commands.NodeStart{NodeID: node.ID()}.Args()...),
+				"--logging-level", p.Topology.Logging.Spec,
+			),
+			Env:   env,
+			Ports: ports,
+		})
+	}
+	return workloads, nil
 }
 
-func (p *platform) GenerateCoreConfig(peer *Node) {
+func (p *platform) GenerateCoreConfig(peer *Node) error {
 	err := os.MkdirAll(p.NodeDir(peer), 0755)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed creating folder of [%s]", peer.ID())
+	}
 
 	core, err := os.Create(p.NodeConfigPath(peer))
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrapf(err, "failed creating configuration of [%s]", peer.ID())
+	}
 	defer core.Close()
 
 	var extensions []string
@@ -158,37 +211,53 @@ func (p *platform) GenerateCoreConfig(peer *Node) {
 		"ReplaceAll":    func(s, old, new string) string { return strings.Replace(s, old, new, -1) },
 		"NodeKVSPath":   func() string { return p.NodeKVSDir(peer) },
 	}).Parse(CoreTemplate)
-	Expect(err).NotTo(HaveOccurred())
-	Expect(t.Execute(io.MultiWriter(core), p)).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed parsing core template")
+	}
+	return errors.Wrapf(t.Execute(io.MultiWriter(core), p), "failed generating configuration of [%s]", peer.ID())
 }
 
-func (p *platform) BootstrapViewNodeGroupRunner() ifrit.Runner {
-	members := grouper.Members{}
-	for _, node := range p.Topology.Nodes {
-		if node.Bootstrap {
-			members = append(members, grouper.Member{Name: node.ID(), Runner: p.ViewNodeRunner(node)})
-		}
+func (p *platform) BootstrapViewNodeGroupRunner() (ifrit.Runner, error) {
+	members, err := p.nodeMembers(true)
+	if err != nil {
+		return nil, err
 	}
-	return runner.NewParallel(syscall.SIGTERM, members)
+	return runner.NewParallel(syscall.SIGTERM, members), nil
 }
 
-func (p *platform) ViewNodeGroupRunner() ifrit.Runner {
+func (p *platform) ViewNodeGroupRunner() (ifrit.Runner, error) {
+	members, err := p.nodeMembers(false)
+	if err != nil {
+		return nil, err
+	}
+	return runner.NewParallel(syscall.SIGTERM, members), nil
+}
+
+// nodeMembers returns the runners of the bootstrap nodes, or of the other nodes
+func (p *platform) nodeMembers(bootstrap bool) (grouper.Members, error) {
 	members := grouper.Members{}
 	for _, node := range p.Topology.Nodes {
-		if !node.Bootstrap {
-			members = append(members, grouper.Member{Name: node.ID(), Runner: p.ViewNodeRunner(node)})
+		if node.Bootstrap == bootstrap {
+			r, err := p.ViewNodeRunner(node)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, grouper.Member{Name: node.ID(), Runner: r})
 		}
 	}
-	return runner.NewParallel(syscall.SIGTERM, members)
+	return members, nil
 }
 
-func (p *platform) ViewNodeRunner(node *Node, env ...string) *runner.Runner {
-	cmd := p.fscNodeCommand(
+func (p *platform) ViewNodeRunner(node *Node, env ...string) (*runner.Runner, error) {
+	cmd, err := p.fscNodeCommand(
 		node,
 		commands.NodeStart{NodeID: node.ID()},
 		"",
 		fmt.Sprintf("FSCNODE_CFG_PATH=%s", p.NodeDir(node)),
 	)
+	if err != nil {
+		return nil, err
+	}
 	cmd.Env = append(cmd.Env, env...)
 
 	return runner.New(runner.Config{
@@ -197,14 +266,22 @@ func (p *platform) ViewNodeRunner(node *Node, env ...string) *runner.Runner {
 		Command:           cmd,
 		StartCheck:        `Started peer with ID=.*, .*, address=`,
 		StartCheckTimeout: 1 * time.Minute,
-	})
+	}), nil
 }
 
-func (p *platform) fscNodeCommand(node *Node, command common.Command, tlsDir string, env ...string) *exec.Cmd {
+func (p *platform) fscNodeCommand(node *Node, command common.Command, tlsDir string, env ...string) (*exec.Cmd, error) {
 	if len(node.ExecutablePath) == 0 {
-		node.ExecutablePath = p.GenerateCmd(nil, node)
+		executablePath, err := p.GenerateCmd(nil, node)
+		if err != nil {
+			return nil, err
+		}
+		node.ExecutablePath = executablePath
 	}
-	cmd := common.NewCommand(p.Builder.Build(node.ExecutablePath), command)
+	executable, err := p.Builder.Build(node.ExecutablePath)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed building [%s]", node.ID())
+	}
+	cmd := common.NewCommand(executable, command)
 	cmd.Env = append(cmd.Env, env...)
 	cmd.Env = append(cmd.Env, "FSCNODE_LOGGING_SPEC="+p.Topology.Logging.Spec)
 
@@ -222,16 +299,23 @@ func (p *platform) fscNodeCommand(node *Node, command common.Command, tlsDir str
 
 	cmd.Args = append(cmd.Args, "--logging-level", p.Topology.Logging.Spec)
 
-	return cmd
+	return cmd, nil
 }
 
-func (p *platform) GenerateCmd(output io.Writer, node *Node) string {
-	err := os.MkdirAll(p.NodeCmdDir(node), 0755)
-	Expect(err).NotTo(HaveOccurred())
+func (p *platform) GenerateCmd(output io.Writer, node *Node) (string, error) {
+	cmdDir, err := p.NodeCmdDir(node)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(cmdDir, 0755); err != nil {
+		return "", errors.Wrapf(err, "failed creating command folder of [%s]", node.ID())
+	}
 
 	if output == nil {
-		main, err := os.Create(p.NodeCmdPath(node))
-		Expect(err).NotTo(HaveOccurred())
+		main, err := os.Create(filepath.Join(cmdDir, "main.go"))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed creating command of [%s]", node.ID())
+		}
 		output = main
 		defer main.Close()
 	}
@@ -240,8 +324,12 @@ func (p *platform) GenerateCmd(output io.Writer, node *Node) string {
 		"Alias":       func(s string) string { return node.Alias(s) },
 		"InstallView": func() bool { return len(node.Responders) != 0 || len(node.Factories) != 0 },
 	}).Parse(DefaultTemplate)
-	Expect(err).NotTo(HaveOccurred())
-	Expect(t.Execute(io.MultiWriter(output), node)).NotTo(HaveOccurred())
+	if err != nil {
+		return "", errors.Wrap(err, "failed parsing node template")
+	}
+	if err := t.Execute(io.MultiWriter(output), node); err != nil {
+		return "", errors.Wrapf(err, "failed generating command of [%s]", node.ID())
+	}
 
 	return p.NodeCmdPackage(node)
 }
@@ -258,35 +346,42 @@ func (p *platform) NodeConfigPath(peer *Node) string {
 	return filepath.Join(p.NodeDir(peer), "core.yaml")
 }
 
-func (p *platform) NodeCmdDir(peer *Node) string {
+func (p *platform) NodeCmdDir(peer *Node) (string, error) {
 	wd, err := os.Getwd()
-	Expect(err).ToNot(HaveOccurred())
+	if err != nil {
+		return "", errors.Wrap(err, "failed getting working directory")
+	}
 
-	return filepath.Join(wd, "cmd", peer.Name)
+	return filepath.Join(wd, "cmd", peer.Name), nil
 }
 
-func (p *platform) NodeCmdPackage(peer *Node) string {
+func (p *platform) NodeCmdPackage(peer *Node) (string, error) {
 	gopath := os.Getenv("GOPATH")
 	if gopath == "" {
 		gopath = build.Default.GOPATH
 	}
 	wd, err := os.Getwd()
-	Expect(err).ToNot(HaveOccurred())
+	if err != nil {
+		return "", errors.Wrap(err, "failed getting working directory")
+	}
 
 	return strings.TrimPrefix(
 		filepath.Join(strings.TrimPrefix(wd, filepath.Join(gopath, "src")), "cmd", peer.Name),
 		string(filepath.Separator),
-	)
+	), nil
 }
 
-func (p *platform) NodeCmdPath(peer *Node) string {
-	return filepath.Join(p.NodeCmdDir(peer), "main.go")
+func (p *platform) NodeCmdPath(peer *Node) (string, error) {
+	cmdDir, err := p.NodeCmdDir(peer)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cmdDir, "main.go"), nil
 }
 
+// NodePort returns the passed port of the passed node, the ports are reserved by GenerateConfigTree
 func (p *platform) NodePort(node *Node, portName registry.PortName) uint16 {
-	peerPorts := p.Registry.PortsByPeerID[node.ID()]
-	Expect(peerPorts).NotTo(BeNil())
-	return peerPorts[portName]
+	return p.Registry.PortsByPeerID[node.ID()][portName]
 }
 
 func (p *platform) BootstrapNode(me *Node) string {
@@ -323,5 +418,5 @@ func (p *platform) NodeLocalPrivateKeyPath(node *Node) string {
 
 // PeerPortNames returns the list of ports that need to be reserved for a Peer.
 func PeerPortNames() []registry.PortName {
-	return []registry.PortName{ListenPort, P2PPort}
+	return []registry.PortName{ListenPort, P2PPort, OperationsPort}
 }
@@ -15,10 +15,11 @@ type Logging struct {
 }
 
 type Topology struct {
-	TopologyName string   `yaml:"name,omitempty"`
-	Nodes        []*Node  `yaml:"peers,omitempty"`
-	GRPCLogging  bool     `yaml:"grpcLogging,omitempty"`
-	Logging      *Logging `yaml:"logging,omitempty"`
+	TopologyName   string   `yaml:"name,omitempty"`
+	Nodes          []*Node  `yaml:"peers,omitempty"`
+	GRPCLogging    bool     `yaml:"grpcLogging,omitempty"`
+	Logging        *Logging `yaml:"logging,omitempty"`
+	FaultInjection bool     `yaml:"faultInjection,omitempty"`
 }
 
 func NewTopology() *Topology {
@@ -39,6 +40,12 @@ func (t *Topology) SetLogging(spec, format string) {
 	}
 }
 
+// EnableFaultInjection enables the test hook that drops and delays the messages exchanged by the nodes.
+// Never use it outside of tests.
+func (t *Topology) EnableFaultInjection() {
+	t.FaultInjection = true
+}
+
 func (t *Topology) Name() string {
 	return t.TopologyName
 }
@@ -0,0 +1,296 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fscnet
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger-labs/fabric-smart-client/integration"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/artifactgen/gen"
+	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/deploy"
+)
+
+var topologyFile string
+var output string
+var port int
+var clean bool
+var formats []string
+var bundleDir string
+var imageRegistry string
+
+// Cmds returns the commands to generate, run and inspect a network described by a topology file
+func Cmds() []*cobra.Command {
+	generate := generateCmd()
+	up := upCmd()
+	export := exportCmd()
+	for _, cmd := range []*cobra.Command{generate, up, export} {
+		flags := cmd.Flags()
+		flags.StringVarP(&topologyFile, "topology", "t", "", "topology file in yaml format")
+		flags.IntVarP(&port, "port", "p", 20000, "host starting port")
+		flags.BoolVar(&clean, "clean", false, "remove the output folder, if it exists, before generating the network")
+	}
+	cmds := []*cobra.Command{generate, up, export, downCmd(), restartNodeCmd(), statusCmd()}
+	for _, cmd := range cmds {
+		cmd.Flags().StringVarP(&output, "output", "o", "./testdata", "network folder")
+	}
+	return cmds
+}
+
+func generateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "generate",
+		Short: "Generates the artifacts of a network.",
+		Long:  `Reads the topology from file and generates the artifacts of the network in the output folder.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			n, err := generate()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "network generated in [%s]\n", n.Dir())
+			return nil
+		},
+	}
+}
+
+func upCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "up",
+		Short: "Generates and starts a network.",
+		Long: `Reads the topology from file, generates the artifacts of the network in the output folder and starts it.
+The network keeps running until this command is interrupted or 'down' is invoked on the same output folder.
+Stopping the network removes the output folder.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return up(cmd)
+		},
+	}
+}
+
+func exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Generates a network and exports it as a docker-compose file and Kubernetes manifests.",
+		Long: `Reads the topology from file, generates the artifacts of the network in the output folder and
+exports its processes, together with their configuration and crypto material, in the bundle folder.
+The images of the fsc nodes are built, from the current folder, with the Dockerfile written next to the bundles,
+either by docker-compose or by the images script written next to the Kubernetes manifests, which pushes them
+to the registry the manifests pull them from.
+Channels and chaincodes are not part of the bundles.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			n, err := generate()
+			if err != nil {
+				return err
+			}
+			b, err := n.Bundle()
+			if err != nil {
+				return err
+			}
+			b.Registry = imageRegistry
+			dir := bundleDir
+			if len(dir) == 0 {
+				dir = filepath.Join(n.Dir(), "deploy")
+			}
+			for _, format := range formats {
+				switch format {
+				case "compose":
+					err = deploy.WriteCompose(b, dir)
+				case "kubernetes":
+					err = deploy.WriteKubernetes(b, dir)
+				default:
+					err = errors.Errorf("unknown format [%s], expected compose or kubernetes", format)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "network exported in [%s]\n", dir)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&formats, "format", []string{"compose", "kubernetes"}, "formats to export to, compose and/or kubernetes")
+	cmd.Flags().StringVar(&bundleDir, "bundle", "", "folder the bundles are written to, the deploy folder of the network if empty")
+	cmd.Flags().StringVar(&imageRegistry, "registry", "", "registry the images of the fsc nodes are pushed to, required by kubernetes")
+	return cmd
+}
+
+func downCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "down",
+		Short: "Stops the network running from the output folder.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			pid, err := runningLauncher(output)
+			if err != nil {
+				return err
+			}
+			if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+				return errors.Wrapf(err, "failed signalling network process [%d]", pid)
+			}
+			if err := waitFor(func() bool { return !isRunning(pid) }, 5*time.Minute); err != nil {
+				return errors.WithMessagef(err, "network process [%d] still running", pid)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "network stopped")
+			return nil
+		},
+	}
+}
+
+func restartNodeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart-node <name>",
+		Short: "Restarts an fsc node of the network running from the output folder.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			pid, err := runningLauncher(output)
+			if err != nil {
+				return err
+			}
+			if err := requestRestart(output, args[0]); err != nil {
+				return err
+			}
+			if err := syscall.Kill(pid, syscall.SIGUSR1); err != nil {
+				return errors.Wrapf(err, "failed signalling network process [%d]", pid)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "restart of [%s] requested, check the output of 'up'\n", args[0])
+			return nil
+		},
+	}
+}
+
+func statusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Shows the status of the network in the output folder.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			nodes, err := fscNodes(output)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			if pid, err := runningLauncher(output); err != nil {
+				fmt.Fprintln(w, "network:\tdown")
+			} else {
+				fmt.Fprintf(w, "network:\tup (pid %d)\n", pid)
+			}
+			fmt.Fprintln(w, "NODE\tADDRESS\tSTATUS")
+			for _, node := range nodes {
+				status := "down"
+				if node.running() {
+					status = "up"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", node.Name, node.Address, status)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// generate generates the network in the output folder
+func generate() (*integration.Network, error) {
+	topologies, err := gen.ReadTopologies(topologyFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := runningLauncher(output); err == nil {
+		return nil, errors.Errorf("a network is running from [%s], stop it first", output)
+	}
+	if clean {
+		if err := os.RemoveAll(output); err != nil {
+			return nil, errors.Wrapf(err, "failed removing [%s]", output)
+		}
+	} else if !isEmpty(output) {
+		return nil, errors.Errorf("[%s] is not empty, use --clean to replace its content", output)
+	}
+	return integration.GenNetworkAt(port, output, topologies...)
+}
+
+// up generates and starts the network, then serves the restart requests until signalled to stop
+func up(cmd *cobra.Command) error {
+	n, err := generate()
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	if err := writeLauncherPID(output); err != nil {
+		return err
+	}
+	defer removeLauncherPID(output)
+
+	if err := n.Start(); err != nil {
+		if err2 := n.Stop(); err2 != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "failed stopping network: %s\n", err2)
+		}
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "network up from [%s], stop it with 'down' or by interrupting this command\n", n.Dir())
+
+	for sig := range signals {
+		if sig != syscall.SIGUSR1 {
+			break
+		}
+		if err := restartNode(n); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "failed restarting node: %s\n", err)
+		}
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "stopping network...")
+	return n.Stop()
+}
+
+// restartNode restarts the node whose restart has been requested
+func restartNode(n *integration.Network) error {
+	name, err := restartRequest(output)
+	if err != nil {
+		return err
+	}
+	node, err := fscNode(output, name)
+	if err != nil {
+		return err
+	}
+	if err := n.StopViewNode(name); err != nil {
+		return err
+	}
+	// wait for the node to release its address before starting it again
+	if err := waitFor(func() bool { return !node.running() }, time.Minute); err != nil {
+		return errors.WithMessagef(err, "node [%s] still running", name)
+	}
+	return n.StartViewNode(name)
+}
+
+// waitFor polls the passed condition until it holds or the timeout expires
+func waitFor(condition func() bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout after [%s]", timeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fscnet
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	pidFileName     = "fscnet.pid"
+	restartFileName = "fscnet.restart"
+)
+
+// Node is an fsc node of a generated network
+type Node struct {
+	Name    string
+	Address string
+}
+
+// running returns true if the address of the node is bound.
+// The address is not dialed to avoid spurious handshake errors in the logs of the node.
+func (n *Node) running() bool {
+	l, err := net.Listen("tcp", n.Address)
+	if err != nil {
+		return true
+	}
+	l.Close()
+	return false
+}
+
+// fscNodes returns the fsc nodes of the network generated in the passed folder, sorted by name
+func fscNodes(dir string) ([]*Node, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "fscnodes", "*", "core.yaml"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed listing fsc nodes in [%s]", dir)
+	}
+	if len(paths) == 0 {
+		return nil, errors.Errorf("no fsc node found in [%s], has the network been generated?", dir)
+	}
+	var nodes []*Node
+	for _, path := range paths {
+		v := viper.New()
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, errors.Wrapf(err, "failed reading [%s]", path)
+		}
+		nodes = append(nodes, &Node{
+			Name:    filepath.Base(filepath.Dir(path)),
+			Address: v.GetString("fsc.address"),
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, nil
+}
+
+func fscNode(dir, name string) (*Node, error) {
+	nodes, err := fscNodes(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if node.Name == name {
+			return node, nil
+		}
+	}
+	return nil, errors.Errorf("fsc node [%s] not found in [%s]", name, dir)
+}
+
+func writeLauncherPID(dir string) error {
+	path := filepath.Join(dir, pidFileName)
+	if err := ioutil.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		return errors.Wrapf(err, "failed writing [%s]", path)
+	}
+	return nil
+}
+
+func removeLauncherPID(dir string) {
+	os.Remove(filepath.Join(dir, pidFileName))
+}
+
+// runningLauncher returns the pid of the process running the network from the passed folder
+func runningLauncher(dir string) (int, error) {
+	path := filepath.Join(dir, pidFileName)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, errors.Errorf("no network running from [%s]", dir)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid pid in [%s]", path)
+	}
+	if !isRunning(pid) {
+		return 0, errors.Errorf("no network running from [%s], process [%d] is gone", dir, pid)
+	}
+	return pid, nil
+}
+
+func isRunning(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+func requestRestart(dir, name string) error {
+	if _, err := fscNode(dir, name); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, restartFileName)
+	if err := ioutil.WriteFile(path, []byte(name), 0600); err != nil {
+		return errors.Wrapf(err, "failed writing [%s]", path)
+	}
+	return nil
+}
+
+// restartRequest returns, and consumes, the name of the node whose restart has been requested
+func restartRequest(dir string) (string, error) {
+	path := filepath.Join(dir, restartFileName)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed reading restart request [%s]", path)
+	}
+	os.Remove(path)
+	return strings.TrimSpace(string(raw)), nil
+}
+
+func isEmpty(dir string) bool {
+	infos, err := ioutil.ReadDir(dir)
+	return err != nil || len(infos) == 0
+}
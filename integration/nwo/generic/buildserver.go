@@ -19,8 +19,8 @@ import (
 	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
-	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
+	"github.com/pkg/errors"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 )
@@ -38,12 +38,15 @@ func NewBuildServer(args ...string) *BuildServer {
 	}
 }
 
-func (s *BuildServer) Serve() {
+func (s *BuildServer) Serve() error {
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed listening for build requests")
+	}
 
 	s.lis = lis
 	go s.server.Serve(lis)
+	return nil
 }
 
 func (s *BuildServer) Shutdown() {
@@ -54,9 +57,8 @@ func (s *BuildServer) Shutdown() {
 	s.server.Shutdown(ctx)
 }
 
+// Components returns the components built by the server, it must be serving
 func (s *BuildServer) Components() *common.Components {
-	Expect(s.lis).NotTo(BeNil())
-
 	return &common.Components{
 		ServerAddress: s.lis.Addr().String(),
 	}
@@ -23,8 +23,8 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 
 	"github.com/onsi/ginkgo"
-	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
+	"github.com/pkg/errors"
 	"github.com/tedsuo/ifrit/grouper"
 
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo/common"
@@ -33,8 +33,8 @@ import (
 )
 
 type Builder interface {
-	Cryptogen() string
-	Build(path string) string
+	Cryptogen() (string, error)
+	Build(path string) (string, error)
 }
 
 type platform struct {
@@ -62,19 +62,25 @@ func (p *platform) Name() string {
 	return "generic"
 }
 
-func (p *platform) GenerateConfigTree() {
-	p.GenerateCryptoConfig()
+func (p *platform) GenerateConfigTree() error {
+	return p.GenerateCryptoConfig()
 }
 
-func (p *platform) GenerateArtifacts() {
+func (p *platform) GenerateArtifacts() error {
 	sess, err := p.Cryptogen(Generate{
 		Config: p.CryptoConfigPath(),
 		Output: p.CryptoPath(),
 	})
-	Expect(err).NotTo(HaveOccurred())
-	Eventually(sess, p.EventuallyTimeout).Should(gexec.Exit(0))
+	if err != nil {
+		return err
+	}
+	if err := common.WaitSession(sess, p.EventuallyTimeout); err != nil {
+		return errors.WithMessage(err, "failed generating crypto material")
+	}
 
-	p.ConcatenateTLSCACertificates()
+	if err := p.ConcatenateTLSCACertificates(); err != nil {
+		return err
+	}
 
 	p.GenerateResolverMap()
 	for _, peer := range p.Peers {
@@ -86,26 +92,27 @@ func (p *platform) GenerateArtifacts() {
 		}
 		p.Registry.ConnectionConfigs[peer.Name] = cc
 
-		clientID, err := p.GetSigningIdentity(peer)
-		Expect(err).ToNot(HaveOccurred())
-		p.Registry.ClientSigningIdentities[peer.Name] = clientID
-
-		cert, err := ioutil.ReadFile(p.PeerLocalMSPIdentityCert(peer))
-		Expect(err).ToNot(HaveOccurred())
-		p.Registry.ViewIdentities[peer.Name] = cert
+		if err := p.loadIdentities(peer); err != nil {
+			return err
+		}
 
-		p.GenerateCoreConfig(peer)
+		if err := p.GenerateCoreConfig(peer); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (p *platform) Load() {
+func (p *platform) Load() error {
 	p.CheckTopology()
 
 	for _, peer := range p.Peers {
 		v := viper.New()
 		v.SetConfigFile(p.NodeConfigPath(peer))
 		err := v.ReadInConfig() // Find and read the config file
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed reading configuration of [%s]", peer.Name)
+		}
 
 		cc := &grpc.ConnectionConfig{
 			Address:           v.GetString("fsc.address"),
@@ -115,23 +122,40 @@ func (p *platform) Load() {
 		}
 		p.Registry.ConnectionConfigs[peer.Name] = cc
 
-		clientID, err := p.GetSigningIdentity(peer)
-		Expect(err).ToNot(HaveOccurred())
-		p.Registry.ClientSigningIdentities[peer.Name] = clientID
-
-		cert, err := ioutil.ReadFile(p.PeerLocalMSPIdentityCert(peer))
-		Expect(err).ToNot(HaveOccurred())
-		p.Registry.ViewIdentities[peer.Name] = cert
+		if err := p.loadIdentities(peer); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+func (p *platform) Members() ([]grouper.Member, error) {
+	return nil, nil
 }
 
-func (p *platform) Members() []grouper.Member {
+func (p *platform) PostRun() error {
 	return nil
 }
 
-func (p *platform) PostRun() {}
+func (p *platform) Cleanup() error {
+	return nil
+}
 
-func (p *platform) Cleanup() {}
+// loadIdentities registers the client signing identity and the view identity of the passed peer
+func (p *platform) loadIdentities(peer *Peer) error {
+	clientID, err := p.GetSigningIdentity(peer)
+	if err != nil {
+		return errors.WithMessagef(err, "failed loading signing identity of [%s]", peer.Name)
+	}
+	p.Registry.ClientSigningIdentities[peer.Name] = clientID
+
+	cert, err := ioutil.ReadFile(p.PeerLocalMSPIdentityCert(peer))
+	if err != nil {
+		return errors.Wrapf(err, "failed reading identity of [%s]", peer.Name)
+	}
+	p.Registry.ViewIdentities[peer.Name] = cert
+	return nil
+}
 
 func (p *platform) CheckTopology() {
 	fscTopology := p.Registry.TopologyByName("fsc").(*fsc.Topology)
@@ -176,19 +200,27 @@ func (p *platform) CheckTopology() {
 }
 
 func (p *platform) Cryptogen(command common.Command) (*gexec.Session, error) {
-	cmd := common.NewCommand(p.Builder.Cryptogen(), command)
+	cryptogen, err := p.Builder.Cryptogen()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed building cryptogen")
+	}
+	cmd := common.NewCommand(cryptogen, command)
 	return p.StartSession(cmd, command.SessionName())
 }
 
-func (p *platform) GenerateCryptoConfig() {
+func (p *platform) GenerateCryptoConfig() error {
 	crypto, err := os.Create(p.CryptoConfigPath())
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed creating crypto configuration")
+	}
 	defer crypto.Close()
 
 	t, err := template.New("crypto").Parse(DefaultCryptoTemplate)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed parsing crypto template")
+	}
 
-	Expect(t.Execute(io.MultiWriter(crypto), p)).NotTo(HaveOccurred())
+	return errors.Wrap(t.Execute(io.MultiWriter(crypto), p), "failed generating crypto configuration")
 }
 
 func (p *platform) CryptoPath() string {
@@ -222,7 +254,7 @@ func (p *platform) StartSession(cmd *exec.Cmd, name string) (*gexec.Session, err
 	)
 }
 
-func (p *platform) GenerateCoreConfig(peer *Peer) {
+func (p *platform) GenerateCoreConfig(peer *Peer) error {
 	coreTemplate := DefaultViewExtensionTemplate
 	t, err := template.New("peer").Funcs(template.FuncMap{
 		"Peer":              func() *Peer { return peer },
@@ -230,13 +262,18 @@ func (p *platform) GenerateCoreConfig(peer *Peer) {
 		"ReplaceAll":        func(s, old, new string) string { return strings.Replace(s, old, new, -1) },
 		"CACertsBundlePath": func() string { return p.CACertsBundlePath() },
 	}).Parse(coreTemplate)
-	Expect(err).NotTo(HaveOccurred())
+	if err != nil {
+		return errors.Wrap(err, "failed parsing core template")
+	}
 
 	//pw := gexec.NewPrefixedWriter(fmt.Sprintf("[%s#extension#core.yaml] ", p.ID()), ginkgo.GinkgoWriter)
 	extension := bytes.NewBuffer([]byte{})
 	err = t.Execute(io.MultiWriter(extension), p)
+	if err != nil {
+		return errors.Wrapf(err, "failed generating core extension of [%s]", peer.Name)
+	}
 	p.Registry.AddExtension(peer.Name, registry.GenericExtension, extension.String())
-	Expect(err).NotTo(HaveOccurred())
+	return nil
 }
 
 func (p *platform) Organization(orgName string) *Organization {
@@ -299,10 +336,9 @@ func (p *platform) PeerAddressByName(peer *Peer, portName registry.PortName) str
 	return fmt.Sprintf("127.0.0.1:%d", p.PeerPortByName(peer, portName))
 }
 
+// PeerPortByName returns the passed port of the passed peer, the ports are reserved by the fsc platform
 func (p *platform) PeerPortByName(peer *Peer, portName registry.PortName) uint16 {
-	peerPorts := p.Registry.PortsByPeerID[peer.Name]
-	Expect(peerPorts).NotTo(BeNil())
-	return peerPorts[portName]
+	return p.Registry.PortsByPeerID[peer.Name][portName]
 }
 
 func (p *platform) Peer(orgName, peerName string) *Peer {
@@ -314,19 +350,21 @@ func (p *platform) Peer(orgName, peerName string) *Peer {
 	return nil
 }
 
-func (p *platform) ConcatenateTLSCACertificates() {
+func (p *platform) ConcatenateTLSCACertificates() error {
 	bundle := &bytes.Buffer{}
 	for _, tlsCertPath := range p.listTLSCACertificates() {
 		certBytes, err := ioutil.ReadFile(tlsCertPath)
-		Expect(err).NotTo(HaveOccurred())
+		if err != nil {
+			return errors.Wrapf(err, "failed reading [%s]", tlsCertPath)
+		}
 		bundle.Write(certBytes)
 	}
 	if len(bundle.Bytes()) == 0 {
-		return
+		return nil
 	}
 
 	err := ioutil.WriteFile(p.CACertsBundlePath(), bundle.Bytes(), 0660)
-	Expect(err).NotTo(HaveOccurred())
+	return errors.Wrap(err, "failed writing tls ca certificates bundle")
 }
 
 func (p *platform) listTLSCACertificates() []string {
@@ -352,8 +390,6 @@ func (p *platform) listTLSCACertificates() []string {
 
 func (p *platform) peerLocalCryptoDir(peer *Peer, cryptoType string) string {
 	org := p.Organization(peer.Organization)
-	Expect(org).NotTo(BeNil())
-
 	return filepath.Join(
 		p.Registry.RootDir,
 		"crypto",
@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package nwo
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MetricsProvider is implemented by the platforms whose nodes expose metrics
+type MetricsProvider interface {
+	// MetricsEndpoint returns the URL of the metrics endpoint of the passed node, and the file
+	// with the TLS root certificate the endpoint's certificate chains to
+	MetricsEndpoint(node string) (string, string, error)
+}
+
+// MetricsEndpoint returns the URL of the metrics endpoint of the passed fsc node, and the file
+// with the TLS root certificate the endpoint's certificate chains to
+func (n *Network) MetricsEndpoint(node string) (string, string, error) {
+	for _, platform := range n.Platforms {
+		provider, ok := platform.(MetricsProvider)
+		if !ok {
+			continue
+		}
+		return provider.MetricsEndpoint(node)
+	}
+	return "", "", errors.New("no platform exposes metrics")
+}
@@ -10,7 +10,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
-	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/tedsuo/ifrit"
 	"github.com/tedsuo/ifrit/grouper"
 
@@ -22,13 +22,13 @@ var logger = flogging.MustGetLogger("fsc.integration")
 type Platform interface {
 	Name() string
 
-	GenerateConfigTree()
-	GenerateArtifacts()
-	Load()
+	GenerateConfigTree() error
+	GenerateArtifacts() error
+	Load() error
 
-	Members() []grouper.Member
-	PostRun()
-	Cleanup()
+	Members() ([]grouper.Member, error)
+	PostRun() error
+	Cleanup() error
 }
 
 type Network struct {
@@ -39,46 +39,65 @@ type Network struct {
 	StartEventuallyTimeout time.Duration
 	StopEventuallyTimeout  time.Duration
 	ViewMembers            grouper.Members
+	// RestartableMembers are the members, other than the fsc nodes, that run on their own
+	// so that they can be stopped and restarted
+	RestartableMembers grouper.Members
+
+	processes map[string]ifrit.Process
 }
 
+// New returns a Network orchestrating the passed platforms
 func New(platforms ...Platform) *Network {
 	return &Network{
 		Platforms:              platforms,
 		StartEventuallyTimeout: 10 * time.Minute,
 		StopEventuallyTimeout:  time.Minute,
+		processes:              map[string]ifrit.Process{},
 	}
 }
 
-func (n *Network) Generate() {
+func (n *Network) Generate() error {
 	logger.Infof("Generate Configuration...")
 	for _, platform := range n.Platforms {
-		platform.GenerateConfigTree()
+		if err := platform.GenerateConfigTree(); err != nil {
+			return errors.WithMessagef(err, "platform [%s] failed generating config tree", platform.Name())
+		}
 	}
 
 	for _, platform := range n.Platforms {
-		platform.GenerateArtifacts()
+		if err := platform.GenerateArtifacts(); err != nil {
+			return errors.WithMessagef(err, "platform [%s] failed generating artifacts", platform.Name())
+		}
 	}
 	logger.Infof("Generate Configuration...done!")
+	return nil
 }
 
-func (n *Network) Load() {
+func (n *Network) Load() error {
 	logger.Infof("Load Configuration...")
 	for _, platform := range n.Platforms {
-		platform.Load()
+		if err := platform.Load(); err != nil {
+			return errors.WithMessagef(err, "platform [%s] failed loading", platform.Name())
+		}
 	}
 	logger.Infof("Load Configuration...done")
+	return nil
 }
 
-func (n *Network) Start() {
+func (n *Network) Start() error {
 	logger.Infof("Starting...")
 
 	logger.Infof("Collect members...")
 	members := grouper.Members{}
 
 	fscMembers := grouper.Members{}
+	restartableMembers := grouper.Members{}
 	for _, platform := range n.Platforms {
 		logger.Infof("From [%s]...", platform.Name())
-		m := platform.Members()
+		m, err := platform.Members()
+		if err != nil {
+			return errors.WithMessagef(err, "platform [%s] failed collecting members", platform.Name())
+		}
 		if m == nil {
 			continue
 		}
@@ -88,13 +107,19 @@ func (n *Network) Start() {
 
 		if platform.Name() == "fsc" {
 			fscMembers = append(fscMembers, m...)
-		} else {
-			members = append(members, m...)
+			continue
+		}
+		for _, member := range m {
+			if _, ok := member.Runner.(*runner.Runner); ok {
+				restartableMembers = append(restartableMembers, member)
+			} else {
+				members = append(members, member)
+			}
 		}
-
 	}
 	n.Members = members
 	n.ViewMembers = fscMembers
+	n.RestartableMembers = restartableMembers
 
 	logger.Infof("Run nodes...")
 
@@ -102,65 +127,164 @@ func (n *Network) Start() {
 	Runner := runner.NewOrdered(syscall.SIGTERM, members)
 	process := ifrit.Invoke(Runner)
 	n.Processes = append(n.Processes, process)
-	Eventually(process.Ready(), n.StartEventuallyTimeout).Should(BeClosed())
+	if err := waitReady(process, n.StartEventuallyTimeout); err != nil {
+		return errors.WithMessage(err, "failed starting network")
+	}
 
-	// Execute the fsc members in isolation so can be stopped and restarted as needed
+	// Execute the restartable and the fsc members in isolation so can be stopped and restarted as needed
+	for _, member := range restartableMembers {
+		if err := n.invoke(member); err != nil {
+			return errors.WithMessagef(err, "failed starting [%s]", member.Name)
+		}
+	}
 	for _, member := range fscMembers {
-		runner := runner.NewOrdered(syscall.SIGTERM, []grouper.Member{member})
-		process := ifrit.Invoke(runner)
-		Eventually(process.Ready(), n.StartEventuallyTimeout).Should(BeClosed())
-		n.Processes = append(n.Processes, process)
+		if err := n.invoke(member); err != nil {
+			return errors.WithMessagef(err, "failed starting fsc node [%s]", member.Name)
+		}
 	}
 
 	logger.Infof("Post execution...")
 	for _, platform := range n.Platforms {
-		platform.PostRun()
+		if err := platform.PostRun(); err != nil {
+			return errors.WithMessagef(err, "platform [%s] failed post run", platform.Name())
+		}
 	}
+	return nil
 }
 
-func (n *Network) Stop() {
+// Stop stops all the processes of the network and cleans up the platforms.
+// It keeps going on failure and returns the first error encountered.
+func (n *Network) Stop() error {
+	var firstErr error
 	logger.Infof("Stopping...")
 	if len(n.Processes) != 0 {
 		logger.Infof("Sending sigtem signal...")
 		for _, process := range n.Processes {
 			process.Signal(syscall.SIGTERM)
-			Eventually(process.Wait(), n.StopEventuallyTimeout).Should(Receive())
+			if err := waitExit(process, n.StopEventuallyTimeout); err != nil && firstErr == nil {
+				firstErr = err
+			}
 		}
 	}
 
 	logger.Infof("Cleanup...")
 	for _, platform := range n.Platforms {
-		platform.Cleanup()
+		if err := platform.Cleanup(); err != nil && firstErr == nil {
+			firstErr = errors.WithMessagef(err, "platform [%s] failed cleaning up", platform.Name())
+		}
 	}
 	logger.Infof("Stopping...done!")
+	return firstErr
 }
 
-func (n *Network) StopViewNode(id string) {
+// StopViewNode stops the fsc node with the passed name and waits for it to exit
+func (n *Network) StopViewNode(id string) error {
 	logger.Infof("Stopping fsc node [%s]...", id)
-	for _, member := range n.ViewMembers {
-		if member.Name == id {
-			member.Runner.(*runner.Runner).Stop()
-			logger.Infof("Stopping fsc node [%s]...done", id)
-			return
-		}
+	if err := n.stop(n.ViewMembers, id, false); err != nil {
+		logger.Errorf("Stopping fsc node [%s]...failed: %s", id, err)
+		return errors.WithMessagef(err, "failed stopping fsc node [%s]", id)
 	}
-	logger.Errorf("Stopping fsc node [%s]...not found", id)
+	logger.Infof("Stopping fsc node [%s]...done", id)
+	return nil
 }
 
-func (n *Network) StartViewNode(id string) {
+// StartViewNode restarts the fsc node with the passed name
+func (n *Network) StartViewNode(id string) error {
 	logger.Infof("Starting fsc node [%s]...", id)
+	if err := n.start(n.ViewMembers, id); err != nil {
+		logger.Errorf("Starting fsc node [%s]...failed: %s", id, err)
+		return errors.WithMessagef(err, "failed starting fsc node [%s]", id)
+	}
+	logger.Infof("Starting fsc node [%s]...done", id)
+	return nil
+}
+
+// ReloadViewNode makes the fsc node with the passed name reload its configuration and credentials
+func (n *Network) ReloadViewNode(id string) error {
+	logger.Infof("Reloading fsc node [%s]...", id)
 	for _, member := range n.ViewMembers {
-		if member.Name == id {
-			runner := runner.NewOrdered(syscall.SIGTERM, []grouper.Member{{
-				Name: id, Runner: member.Runner.(*runner.Runner).Clone(),
-			}})
-			member.Runner = runner
-			process := ifrit.Invoke(runner)
-			Eventually(process.Ready(), n.StartEventuallyTimeout).Should(BeClosed())
-			n.Processes = append(n.Processes, process)
-			logger.Infof("Starting fsc node [%s]...done", id)
-			return
-		}
-	}
-	logger.Errorf("Starting fsc node [%s]...not found", id)
+		if member.Name != id {
+			continue
+		}
+		r, ok := member.Runner.(*runner.Runner)
+		if !ok || r.ExitCode() != -1 {
+			return errors.Errorf("fsc node [%s] not running", id)
+		}
+		r.Signal(syscall.SIGHUP)
+		return nil
+	}
+	return errors.Errorf("fsc node [%s] not found", id)
+}
+
+// invoke runs the passed member on its own
+func (n *Network) invoke(member grouper.Member) error {
+	process := ifrit.Invoke(runner.NewOrdered(syscall.SIGTERM, []grouper.Member{member}))
+	n.Processes = append(n.Processes, process)
+	n.processes[member.Name] = process
+	return waitReady(process, n.StartEventuallyTimeout)
+}
+
+// stop stops, or kills, the member with the passed name and waits for it to exit
+func (n *Network) stop(members grouper.Members, id string, kill bool) error {
+	for _, member := range members {
+		if member.Name != id {
+			continue
+		}
+		process, ok := n.processes[id]
+		if !ok {
+			return errors.New("not started")
+		}
+		r := member.Runner.(*runner.Runner)
+		if r.ExitCode() != -1 {
+			return errors.New("not running")
+		}
+		if kill {
+			r.Kill()
+		} else {
+			r.Stop()
+		}
+		return waitExit(process, n.StopEventuallyTimeout)
+	}
+	return errors.New("not found")
+}
+
+// start restarts the member with the passed name
+func (n *Network) start(members grouper.Members, id string) error {
+	for i, member := range members {
+		if member.Name != id {
+			continue
+		}
+		// the clone replaces the stopped runner so that the member can be stopped again
+		clone := member.Runner.(*runner.Runner).Clone()
+		members[i].Runner = clone
+		return n.invoke(grouper.Member{Name: id, Runner: clone})
+	}
+	return errors.New("not found")
+}
+
+// waitReady waits for the passed process to become ready.
+// It fails if the process exits before or the timeout expires.
+func waitReady(process ifrit.Process, timeout time.Duration) error {
+	select {
+	case <-process.Ready():
+		return nil
+	case err := <-process.Wait():
+		if err == nil {
+			return errors.New("process exited before becoming ready")
+		}
+		return errors.Wrap(err, "process exited before becoming ready")
+	case <-time.After(timeout):
+		return errors.Errorf("process not ready after [%s]", timeout)
+	}
+}
+
+// waitExit waits for the passed process to exit, the exit error is ignored
+// since processes are expected to exit on signal.
+func waitExit(process ifrit.Process, timeout time.Duration) error {
+	select {
+	case <-process.Wait():
+		return nil
+	case <-time.After(timeout):
+		return errors.Errorf("process still running after [%s]", timeout)
+	}
 }
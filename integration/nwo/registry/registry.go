@@ -28,7 +28,7 @@ const (
 )
 
 type Builder interface {
-	Build(path string) string
+	Build(path string) (string, error)
 }
 
 type SigningIdentity interface {
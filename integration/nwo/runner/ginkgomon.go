@@ -17,7 +17,6 @@ import (
 	"time"
 
 	"github.com/onsi/ginkgo"
-	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 )
@@ -124,7 +123,9 @@ func (r *Runner) Run(sigChan <-chan os.Signal, ready chan<- struct{}) error {
 		),
 	)
 
-	Ω(err).ShouldNot(HaveOccurred(), fmt.Sprintf("%s failed to start with err: %s", r.Name, err))
+	if err != nil {
+		return fmt.Errorf("%s failed to start with err: %s", r.Name, err)
+	}
 
 	fmt.Fprintf(debugWriter, "spawned %s (pid: %d)\n", r.Command.Path, r.Command.Process.Pid)
 
@@ -188,6 +189,16 @@ func (r *Runner) Stop() {
 	r.stop <- syscall.SIGTERM
 }
 
+// Kill kills the process without giving it the chance to shut down gracefully
+func (r *Runner) Kill() {
+	r.stop <- syscall.SIGKILL
+}
+
+// Signal sends the passed signal to the process
+func (r *Runner) Signal(signal os.Signal) {
+	r.stop <- signal
+}
+
 func (r *Runner) Clone() *Runner {
 	c := exec.Command(r.config.Command.Path)
 	c.Args = r.config.Command.Args
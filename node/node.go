@@ -26,6 +26,7 @@ type ExecuteCallbackFunc = func() error
 type FabricSmartClient interface {
 	Start() error
 	Stop()
+	Reload() error
 	InstallSDK(p api.SDK) error
 	GetService(v interface{}) (interface{}, error)
 	RegisterService(service interface{}) error
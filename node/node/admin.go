@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic"
+	admin2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/admin"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/config"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/admin"
+)
+
+// confPath is the folder containing the core.yaml of the node the admin commands talk to.
+// If empty, the configuration is looked up as done by the node itself.
+var confPath string
+
+func adminCmds() []*cobra.Command {
+	cmds := []*cobra.Command{
+		statusCmd(),
+		peersCmd(),
+		contextsCmd(),
+		logSpecCmd(),
+		viewCmd(),
+		vaultCmd(),
+	}
+	for _, cmd := range cmds {
+		cmd.PersistentFlags().StringVarP(&confPath, "conf", "c", "", "folder containing the core.yaml of the node")
+	}
+	return cmds
+}
+
+func statusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Shows the status of a running node.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callAndPrint(cmd, admin.StatusViewID, nil)
+		},
+	}
+}
+
+func peersCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "peers",
+		Short: "Lists the peers known to the communication layer of a running node.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callAndPrint(cmd, admin.PeersViewID, nil)
+		},
+	}
+}
+
+func contextsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "contexts",
+		Short: "Lists the views running on a running node.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callAndPrint(cmd, admin.ContextsViewID, nil)
+		},
+	}
+}
+
+func logSpecCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logspec [spec]",
+		Short: "Shows, or sets if a spec is passed, the logging spec of a running node.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := &admin.LogSpec{}
+			if len(args) == 1 {
+				input.Spec = args[0]
+			}
+			return callAndPrint(cmd, admin.LogSpecViewID, input)
+		},
+	}
+}
+
+func viewCmd() *cobra.Command {
+	viewCmd := &cobra.Command{
+		Use:   "view",
+		Short: "Operates on the views of a running node.",
+	}
+	viewCmd.AddCommand(&cobra.Command{
+		Use:   "call <fid> [input]",
+		Short: "Calls the view registered under the passed identifier with the passed input.",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var input json.RawMessage
+			if len(args) == 2 {
+				input = json.RawMessage(args[1])
+			}
+			return callAndPrint(cmd, args[0], input)
+		},
+	})
+	return viewCmd
+}
+
+func vaultCmd() *cobra.Command {
+	var network, channel string
+	var namespaces []string
+	var includePrivate bool
+
+	vaultCmd := &cobra.Command{
+		Use:   "vault",
+		Short: "Inspects the vault of a channel of a running node.",
+	}
+	vaultCmd.PersistentFlags().StringVarP(&network, "network", "n", "", "fabric network, the default if empty")
+	vaultCmd.PersistentFlags().StringVarP(&channel, "channel", "C", "", "channel, the default if empty")
+
+	vaultCmd.AddCommand(&cobra.Command{
+		Use:   "tx <txid>",
+		Short: "Shows the status and the RW set of a transaction.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callAndPrint(cmd, admin2.VaultTxViewID, &admin2.VaultTxRequest{
+				Network: network,
+				Channel: channel,
+				TxID:    args[0],
+			})
+		},
+	})
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exports the content of the vault for the given namespaces.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callAndPrint(cmd, admin2.VaultExportViewID, &admin2.VaultExportRequest{
+				Network:    network,
+				Channel:    channel,
+				Namespaces: namespaces,
+			})
+		},
+	}
+	exportCmd.Flags().StringSliceVar(&namespaces, "namespace", nil, "namespace to export, can be repeated")
+	vaultCmd.AddCommand(exportCmd)
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot <path>",
+		Short: "Writes a snapshot of the vault to the passed path, on the node.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return callAndPrint(cmd, admin2.VaultSnapshotViewID, &admin2.VaultSnapshotRequest{
+				Network:        network,
+				Channel:        channel,
+				Path:           args[0],
+				IncludePrivate: includePrivate,
+			})
+		},
+	}
+	snapshotCmd.Flags().BoolVar(&includePrivate, "include-private", false, "include the private data of the collections and the local transactions, for nodes of the same organization only")
+	vaultCmd.AddCommand(snapshotCmd)
+	vaultCmd.AddCommand(&cobra.Command{
+		Use:   "import <path>",
+		Short: "Bootstraps the empty vault of a node that is not running from the snapshot at the passed path.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			info, err := importSnapshot(network, channel, args[0])
+			if err != nil {
+				return err
+			}
+			raw, err := json.Marshal(info)
+			if err != nil {
+				return err
+			}
+			return printResult(cmd.OutOrStdout(), raw)
+		},
+	})
+
+	return vaultCmd
+}
+
+// importSnapshot imports the snapshot into the vault of the channel, as configured in the core.yaml of the node
+func importSnapshot(network, channel, path string) (*api.SnapshotInfo, error) {
+	// the configuration of a node describes a single fabric network, the default one
+	if len(network) != 0 && network != "default" {
+		return nil, errors.Errorf("network [%s] not configured, the node configuration describes the default network only", network)
+	}
+	cp, err := config.NewProvider(confPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed loading configuration from [%s]", confPath)
+	}
+	c := generic.NewConfig(cp)
+	if len(channel) == 0 {
+		channels, err := c.Channels()
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range channels {
+			if ch.Default {
+				channel = ch.Name
+				break
+			}
+		}
+		if len(channel) == 0 {
+			return nil, errors.New("no default channel configured")
+		}
+	}
+	return generic.ImportVaultSnapshot(c, channel, path)
+}
+
+// callAndPrint calls the passed view on the node and prints its result
+func callAndPrint(cmd *cobra.Command, fid string, input interface{}) error {
+	cmd.SilenceUsage = true
+
+	c, err := admin.NewClient(confPath)
+	if err != nil {
+		return err
+	}
+	res, err := c.Call(fid, input)
+	if err != nil {
+		return err
+	}
+	return printResult(cmd.OutOrStdout(), res)
+}
+
+// printResult prints the passed result indented, if it is JSON, as is otherwise
+func printResult(w io.Writer, res []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, res, "", "  "); err != nil {
+		_, err = fmt.Fprintln(w, string(res))
+		return err
+	}
+	_, err := fmt.Fprintln(w, out.String())
+	return err
+}
@@ -17,5 +17,10 @@ import (
 
 func addPlatformSignals(sigs map[os.Signal]func()) map[os.Signal]func() {
 	sigs[syscall.SIGUSR1] = func() { diag.LogGoRoutines(logger.Named("diag")) }
+	sigs[syscall.SIGHUP] = func() {
+		if err := node.Reload(); err != nil {
+			logger.Errorf("Failed reloading [%s]", err)
+		}
+	}
 	return sigs
 }
@@ -18,12 +18,14 @@ import (
 
 const (
 	nodeFuncName = "node"
-	nodeCmdDes   = "Operate a fabfsc node: start."
+	nodeCmdDes   = "Operate a fabfsc node: start, status, peers, contexts, logspec, view, vault."
 )
 
 type Node interface {
 	Start() error
 	Stop()
+	// Reload reloads the configuration and the credentials without restarting
+	Reload() error
 	Callback() chan<- error
 }
 
@@ -37,6 +39,7 @@ func Cmd(n Node) *cobra.Command {
 	node = n
 
 	nodeCmd.AddCommand(startCmd())
+	nodeCmd.AddCommand(adminCmds()...)
 	return nodeCmd
 }
 
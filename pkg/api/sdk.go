@@ -14,3 +14,26 @@ type SDK interface {
 
 	Start(ctx context.Context) error
 }
+
+// Drainer is implemented by the SDKs that have in-flight work to complete before the node stops
+type Drainer interface {
+	// Drain stops accepting new work and waits for the in-flight work to complete,
+	// or for the passed context to be done
+	Drain(ctx context.Context) error
+}
+
+// Stopper is implemented by the SDKs that hold resources to release when the node stops
+type Stopper interface {
+	// Stop releases the resources held by the SDK.
+	// It is invoked after the context passed to Start has been cancelled.
+	Stop() error
+}
+
+// Reloader is implemented by the SDKs whose configuration and credentials can be reloaded without restarting the node
+type Reloader interface {
+	// Reload re-reads the configuration and the credentials, and applies them.
+	// The sessions in place are not dropped, the new ones use what has been reloaded.
+	Reload() error
+	// WatchedPaths returns the files, and the folders, whose changes trigger a reload
+	WatchedPaths() []string
+}
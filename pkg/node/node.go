@@ -11,6 +11,8 @@ import (
 	"log"
 	"reflect"
 	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/pkg/errors"
@@ -25,6 +27,10 @@ import (
 
 var logger = flogging.MustGetLogger("fsc")
 
+// defaultGracePeriod is how long the node waits for the in-flight views to complete when stopping,
+// if fsc.shutdown.gracePeriod is not set
+const defaultGracePeriod = 30 * time.Second
+
 type ExecuteCallbackFunc = func() error
 
 type ViewManager interface {
@@ -48,6 +54,8 @@ type node struct {
 	context  context.Context
 	cancel   context.CancelFunc
 	running  bool
+
+	reloadMutex sync.Mutex
 }
 
 func New() *node {
@@ -98,13 +106,54 @@ func (n *node) Start() (err error) {
 			return err
 		}
 	}
+	go n.watch(n.context)
 
 	return nil
 }
 
+// Stop shuts the node down in phases:
+// the SDKs are drained, waiting at most fsc.shutdown.gracePeriod for the in-flight work to complete,
+// then the node context is cancelled and the SDKs are stopped in reverse installation order.
 func (n *node) Stop() {
+	n.reloadMutex.Lock()
+	defer n.reloadMutex.Unlock()
+
+	if !n.running {
+		return
+	}
 	n.running = false
+
+	if n.cancel == nil {
+		// the node did not start
+		return
+	}
+
+	gracePeriod := defaultGracePeriod
+	if configService := view3.GetConfigService(n.registry); configService.IsSet("fsc.shutdown.gracePeriod") {
+		gracePeriod = configService.GetDuration("fsc.shutdown.gracePeriod")
+	}
+	logger.Infof("Draining sdks, grace period [%s]...", gracePeriod)
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	for _, p := range n.sdks {
+		if d, ok := p.(api.Drainer); ok {
+			if err := d.Drain(ctx); err != nil {
+				logger.Warnf("Failed draining platform [%s]", err)
+			}
+		}
+	}
+	cancel()
+
 	n.cancel()
+
+	logger.Debugf("Stopping sdks...")
+	for i := len(n.sdks) - 1; i >= 0; i-- {
+		if s, ok := n.sdks[i].(api.Stopper); ok {
+			if err := s.Stop(); err != nil {
+				logger.Errorf("Failed stopping platform [%s]", err)
+			}
+		}
+	}
+	logger.Infof("Node stopped")
 }
 
 func (n *node) InstallSDK(p api.SDK) error {
@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package node
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/pkg/api"
+	view3 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+)
+
+// Reload reloads the configuration and the credentials of the SDKs that support it, in installation order.
+// The view SDK, installed first, reloads the configuration the other SDKs read from.
+func (n *node) Reload() error {
+	n.reloadMutex.Lock()
+	defer n.reloadMutex.Unlock()
+
+	if !n.running {
+		return errors.New("failed reloading, the node is not running")
+	}
+	logger.Infof("Reloading sdks...")
+	var errs []string
+	for _, p := range n.sdks {
+		if r, ok := p.(api.Reloader); ok {
+			if err := r.Reload(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return errors.Errorf("failed reloading sdks: [%s]", strings.Join(errs, ", "))
+	}
+	logger.Infof("Sdks reloaded")
+	return nil
+}
+
+// watch reloads the node when the files watched by the SDKs change.
+// The files are polled every fsc.reload.interval, the watch is off if it is not set.
+func (n *node) watch(ctx context.Context) {
+	interval := view3.GetConfigService(n.registry).GetDuration("fsc.reload.interval")
+	if interval <= 0 {
+		return
+	}
+	logger.Infof("Watching configuration and credentials every [%s]", interval)
+
+	last := n.fingerprint()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := n.fingerprint()
+			if current == last {
+				continue
+			}
+			logger.Infof("Configuration or credentials changed, reloading...")
+			if err := n.Reload(); err != nil {
+				logger.Errorf("Failed reloading [%s]", err)
+			}
+			// the paths might have changed with the configuration
+			last = n.fingerprint()
+		}
+	}
+}
+
+// fingerprint summarizes the size and the modification time of the files watched by the SDKs
+func (n *node) fingerprint() string {
+	var paths []string
+	for _, p := range n.sdks {
+		if r, ok := p.(api.Reloader); ok {
+			paths = append(paths, r.WatchedPaths()...)
+		}
+	}
+	sort.Strings(paths)
+
+	sb := strings.Builder{}
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				// the file might be in the middle of being replaced, next time
+				sb.WriteString(fmt.Sprintf("%s:missing;", path))
+				return nil
+			}
+			sb.WriteString(fmt.Sprintf("%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano()))
+			return nil
+		})
+		if err != nil {
+			logger.Warnf("Failed walking [%s]: [%s]", path, err)
+		}
+	}
+	return sb.String()
+}
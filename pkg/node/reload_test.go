@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package node
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/hyperledger-labs/fabric-smart-client/pkg/api"
+	api2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+)
+
+type reloaderSDK struct {
+	paths   []string
+	err     error
+	reloads atomic.Int32
+}
+
+func (r *reloaderSDK) Install() error { return nil }
+
+func (r *reloaderSDK) Start(ctx context.Context) error { return nil }
+
+func (r *reloaderSDK) Reload() error {
+	r.reloads.Inc()
+	return r.err
+}
+
+func (r *reloaderSDK) WatchedPaths() []string { return r.paths }
+
+type configProvider struct {
+	api2.ConfigProvider
+	interval time.Duration
+}
+
+func (c *configProvider) GetDuration(key string) time.Duration {
+	if key == "fsc.reload.interval" {
+		return c.interval
+	}
+	return 0
+}
+
+func TestReload(t *testing.T) {
+	ok := &reloaderSDK{}
+	failing := &reloaderSDK{err: errors.New("invalid credentials")}
+	n := &node{sdks: []api.SDK{failing, ok}}
+
+	assert.EqualError(t, n.Reload(), "failed reloading, the node is not running")
+	assert.Equal(t, int32(0), failing.reloads.Load())
+
+	// a failing sdk does not prevent the others from reloading
+	n.running = true
+	assert.EqualError(t, n.Reload(), "failed reloading sdks: [invalid credentials]")
+	assert.Equal(t, int32(1), failing.reloads.Load())
+	assert.Equal(t, int32(1), ok.reloads.Load())
+
+	failing.err = nil
+	assert.NoError(t, n.Reload())
+	assert.Equal(t, int32(2), ok.reloads.Load())
+}
+
+func TestFingerprint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fingerprint")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert := filepath.Join(dir, "cert.pem")
+	assert.NoError(t, ioutil.WriteFile(cert, []byte("cert"), 0644))
+
+	n := &node{sdks: []api.SDK{&reloaderSDK{paths: []string{dir, ""}}}}
+	fp := n.fingerprint()
+	assert.Contains(t, fp, cert)
+	assert.Equal(t, fp, n.fingerprint())
+
+	assert.NoError(t, ioutil.WriteFile(cert, []byte("a new cert"), 0644))
+	fp2 := n.fingerprint()
+	assert.NotEqual(t, fp, fp2)
+
+	assert.NoError(t, os.Remove(cert))
+	assert.NotEqual(t, fp2, n.fingerprint())
+
+	// the paths that do not exist yet are watched too
+	missing := filepath.Join(dir, "missing")
+	n = &node{sdks: []api.SDK{&reloaderSDK{paths: []string{missing}}}}
+	assert.Equal(t, missing+":missing;", n.fingerprint())
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cert := filepath.Join(dir, "cert.pem")
+	assert.NoError(t, ioutil.WriteFile(cert, []byte("cert"), 0644))
+
+	interval := 10 * time.Millisecond
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&configProvider{interval: interval}))
+	sdk := &reloaderSDK{paths: []string{cert}}
+	n := &node{registry: registry, sdks: []api.SDK{sdk}, running: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.watch(ctx)
+		close(done)
+	}()
+
+	// nothing changed, no reload
+	time.Sleep(10 * interval)
+	assert.Equal(t, int32(0), sdk.reloads.Load())
+
+	// a change triggers exactly one reload
+	assert.NoError(t, ioutil.WriteFile(cert, []byte("a new cert"), 0644))
+	assert.Eventually(t, func() bool { return sdk.reloads.Load() == 1 }, time.Second, interval)
+	time.Sleep(10 * interval)
+	assert.Equal(t, int32(1), sdk.reloads.Load())
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watch did not stop")
+	}
+}
+
+func TestWatchDisabled(t *testing.T) {
+	registry := registry2.New()
+	assert.NoError(t, registry.RegisterService(&configProvider{}))
+	n := &node{registry: registry, sdks: []api.SDK{&reloaderSDK{}}, running: true}
+
+	done := make(chan struct{})
+	go func() {
+		n.watch(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watch is on without an interval")
+	}
+}
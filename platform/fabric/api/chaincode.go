@@ -35,6 +35,9 @@ type ChaincodeInvocation interface {
 
 	WithEndorsersFromMyOrg() ChaincodeInvocation
 
+	// WithPreferredEndorsersByMSPIDs sets the MSPs whose peers are preferred when endorsers are discovered
+	WithPreferredEndorsersByMSPIDs(mspIDs ...string) ChaincodeInvocation
+
 	WithSignerIdentity(id view.Identity) ChaincodeInvocation
 
 	WithTxID(id TxID) ChaincodeInvocation
@@ -46,6 +49,8 @@ type ChaincodeInvocation interface {
 type ChaincodeDiscover interface {
 	Call() ([]view.Identity, error)
 	WithFilterByMSPIDs(mspIDs ...string) ChaincodeDiscover
+	// WithPreferredMSPIDs sets the MSPs whose peers are preferred when choosing among the peers of a group
+	WithPreferredMSPIDs(mspIDs ...string) ChaincodeDiscover
 }
 
 // Chaincode exposes chaincode-related functions
@@ -54,8 +59,62 @@ type Chaincode interface {
 	NewDiscover() ChaincodeDiscover
 }
 
+// ChaincodeDefinition is the definition of a chaincode the organizations approve and then commit to a channel
+type ChaincodeDefinition struct {
+	Name     string
+	Version  string
+	Sequence int64
+	// EndorsementPolicy is the signature policy of the chaincode, e.g. "AND('Org1MSP.member','Org2MSP.member')"
+	EndorsementPolicy string
+	// ChannelConfigPolicy is the channel configuration policy the chaincode refers to, instead of a signature policy,
+	// e.g. "/Channel/Application/Endorsement". If neither is set, the default endorsement policy of the channel applies.
+	ChannelConfigPolicy string
+	EndorsementPlugin   string
+	ValidationPlugin    string
+	// Collections is the marshalled CollectionConfigPackage of the chaincode, if any
+	Collections  []byte
+	InitRequired bool
+}
+
+// InstalledChaincode is a chaincode package installed on the peers
+type InstalledChaincode struct {
+	PackageID string
+	Label     string
+}
+
+// Lifecycle manages the chaincodes of a channel through the _lifecycle system chaincode of the peers
+type Lifecycle interface {
+	// Install installs the passed chaincode package on the peers of the network, it succeeds if it is already installed
+	Install(pkg []byte) (*InstalledChaincode, error)
+
+	// QueryInstalled returns the chaincode packages installed on the first peer of the network
+	QueryInstalled() ([]*InstalledChaincode, error)
+
+	// Approve approves the passed definition for the organization of the peers of the network.
+	// The package ID refers to the package the peers run the chaincode with, it can be empty if they don't.
+	Approve(definition *ChaincodeDefinition, packageID string) error
+
+	// CheckCommitReadiness returns, for each organization of the channel, whether it approved the passed definition
+	CheckCommitReadiness(definition *ChaincodeDefinition) (map[string]bool, error)
+
+	// Commit commits the passed definition to the channel. The proposal is endorsed by the passed endorsers,
+	// or by endorsers discovered to satisfy the lifecycle endorsement policy of the channel if none is passed.
+	Commit(definition *ChaincodeDefinition, endorsers ...view.Identity) error
+
+	// QueryCommitted returns the definition of the passed chaincode committed to the channel, and the organizations
+	// that approved it. The policies of the definition are not reported.
+	QueryCommitted(name string) (*ChaincodeDefinition, map[string]bool, error)
+
+	// WithSignerIdentity sets the identity the lifecycle operations are signed with,
+	// the default identity of the node is used otherwise
+	WithSignerIdentity(id view.Identity) Lifecycle
+}
+
 // ChaincodeManager manages chaincodes
 type ChaincodeManager interface {
 	// Chaincode returns a chaincode handler for the passed chaincode name
 	Chaincode(name string) Chaincode
+
+	// Lifecycle returns a handler of the lifecycle of the chaincodes of the channel
+	Lifecycle() Lifecycle
 }
@@ -16,6 +16,7 @@ type Channel interface {
 	ChannelMembership
 	TXIDStore
 	ChaincodeManager
+	ConfigManager
 
 	// Name returns the name of the channel this instance is bound to
 	Name() string
@@ -25,4 +26,7 @@ type Channel interface {
 	TransactionService() EndorserTransactionService
 
 	MetadataService() MetadataService
+
+	// Close stops the delivery of blocks and releases the resources held by the channel, its vault included
+	Close() error
 }
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package api
+
+import "github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+
+// ChannelConfig is the configuration of a channel, as of its last config block
+type ChannelConfig struct {
+	Sequence uint64
+	// Organizations are the organizations of the application group, sorted by name
+	Organizations []*Organization
+	Orderer       *OrdererConfig
+	// Capabilities are the capabilities enabled by group, Channel, Application and Orderer
+	Capabilities map[string][]string
+	// Policies are the policies of the channel by path, e.g. /Channel/Application/Admins
+	Policies map[string]*Policy
+}
+
+// Organization is an organization of the application group of a channel
+type Organization struct {
+	Name  string
+	MSPID string
+	// AnchorPeers are the endpoints, host:port, of the anchor peers of the organization
+	AnchorPeers []string
+}
+
+// OrdererConfig is the configuration of the ordering service of a channel
+type OrdererConfig struct {
+	ConsensusType string
+	// Endpoints are the orderer endpoints of the channel group, deprecated in favour of the ones of the organizations
+	Endpoints []string
+	// Organizations are the organizations of the orderer group, sorted by name
+	Organizations []*OrdererOrganization
+}
+
+// OrdererOrganization is an organization of the orderer group of a channel
+type OrdererOrganization struct {
+	Name      string
+	MSPID     string
+	Endpoints []string
+}
+
+// Policy is a policy of the channel configuration
+type Policy struct {
+	// Type is ImplicitMeta, Signature or, for the other types, their number
+	Type string
+	// Rule is the policy in a readable form, e.g. "MAJORITY Admins" or "OR('Org1MSP.admin')"
+	Rule string
+	// ModPolicy is the policy that governs the modification of this policy
+	ModPolicy string
+}
+
+// ConfigManager gives access to the configuration of a channel and updates it
+type ConfigManager interface {
+	// Config returns the current configuration of the channel
+	Config() (*ChannelConfig, error)
+
+	// RawConfig returns the current configuration of the channel as a marshalled common.Config,
+	// to be modified into an updated configuration
+	RawConfig() ([]byte, error)
+
+	// NewConfigUpdate returns the marshalled ConfigUpdate turning the current configuration of the channel
+	// into the passed marshalled common.Config
+	NewConfigUpdate(updated []byte) ([]byte, error)
+
+	// SignConfigUpdate returns the marshalled ConfigSignature of the passed update by the passed identity
+	SignConfigUpdate(update []byte, id view.Identity) ([]byte, error)
+
+	// VerifyConfigSignature checks that the passed marshalled ConfigSignature is a valid signature of the passed
+	// update by a member of the channel, and returns the signer
+	VerifyConfigSignature(update []byte, signature []byte) (view.Identity, error)
+
+	// SubmitConfigUpdate sends the passed update, with the passed signatures, to the ordering service
+	// in an envelope signed by the submitter, and waits for the resulting configuration to be committed
+	SubmitConfigUpdate(update []byte, signatures [][]byte, submitter view.Identity) error
+}
@@ -39,6 +39,16 @@ type Committer interface {
 	// otherwise, CommitTx commits the transaction.
 	CommitTX(txid string, block uint64, indexInBloc int) error
 
+	// CommitDiscardedTX commits the transaction with the passed id that has been discarded, while Fabric found it valid.
+	// This happens when the tracker of the transactions broadcast gives up on a transaction before it appears.
+	// The read-write set is taken from the envelope of the transaction, the private data is not recovered.
+	CommitDiscardedTX(txid string, block uint64, indexInBloc int) error
+
+	// CommitLocalTX commits the transaction with the passed id to the vault only, without going through Fabric.
+	// The transaction must write only the namespaces configured as local. It is rejected, and marked as invalid,
+	// if what it read has changed in the meantime. CommitLocalTX returns the sequence number assigned to the transaction.
+	CommitLocalTX(txid string) (uint64, error)
+
 	// CommitConfig commits the passed configuration envelope.
 	CommitConfig(blockNumber uint64, envelope []byte) error
 }
@@ -39,11 +39,17 @@ type FabricNetworkService interface {
 	Comm(name string) (Comm, error)
 
 	SigService() SigService
+
+	// Close closes all the channels opened so far
+	Close() error
 }
 
 type FabricNetworkServiceProvider interface {
 	// FabricNetworkService returns a FabricNetworkService instance for the passed parameters
 	FabricNetworkService(id string) (FabricNetworkService, error)
+
+	// Close closes all the fabric network services created so far
+	Close() error
 }
 
 func GetFabricManagementService(ctx view2.ServiceProvider) FabricNetworkServiceProvider {
@@ -5,18 +5,85 @@ SPDX-License-Identifier: Apache-2.0
 */
 package api
 
+import "time"
+
+// ChainInfo describes the chain of a channel
+type ChainInfo struct {
+	// Height is the number of blocks in the chain
+	Height uint64
+	// CurrentBlockHash is the hash of the last block of the chain
+	CurrentBlockHash []byte
+	// PreviousBlockHash is the hash of the block preceding the last one
+	PreviousBlockHash []byte
+}
+
 type Block interface {
 	DataAt(i int) []byte
+
+	// Number returns the number of the block
+	Number() uint64
+
+	// PreviousHash returns the hash of the previous block
+	PreviousHash() []byte
+
+	// DataHash returns the hash of the data of the block
+	DataHash() []byte
+
+	// Hash returns the hash of the header of the block
+	Hash() []byte
+
+	// NumTransactions returns the number of transactions in the block
+	NumTransactions() int
+
+	// TransactionAt decodes the transaction at the passed position in the block
+	TransactionAt(i int) (ProcessedTransaction, error)
+}
+
+// BlockIterator iterates over the blocks of a channel
+type BlockIterator interface {
+	// Next returns the next block, nil if there are no more blocks
+	Next() (Block, error)
+
+	// Close releases the resources held by the iterator
+	Close()
 }
 
 type ProcessedTransaction interface {
+	// TxID returns the transaction id
+	TxID() string
+
+	// Type returns the type of the transaction, a common.HeaderType
+	Type() int32
+
+	// Timestamp returns the time the transaction was created
+	Timestamp() time.Time
+
+	// Creator returns the serialized identity of the creator of the transaction
+	Creator() []byte
+
+	// Chaincode returns the name and the version of the invoked chaincode, empty for non-endorser transactions
+	Chaincode() (string, string)
+
+	// Function returns the invoked function and its arguments, empty for non-endorser transactions
+	Function() (string, []string)
+
+	// Results returns the marshalled read-write set, nil for non-endorser transactions
 	Results() []byte
+
+	// ValidationCode returns the validation code assigned by the committing peer, a peer.TxValidationCode
 	ValidationCode() int32
+
 	IsValid() bool
+
+	// Envelope returns the marshalled envelope of the transaction
+	Envelope() []byte
 }
 
 // Ledger gives access to the remote ledger
 type Ledger interface {
+	// GetChainInfo returns the height of the chain and the hashes of its last blocks
+	GetChainInfo() (*ChainInfo, error)
+
 	// GetTransactionByID retrieves a transaction by id
 	GetTransactionByID(txID string) (ProcessedTransaction, error)
 
@@ -25,4 +92,11 @@ type Ledger interface {
 
 	// GetBlockByNumber fetches a block by number
 	GetBlockByNumber(number uint64) (Block, error)
+
+	// GetBlocks returns an iterator over the blocks from start to stop, included.
+	// The iterator fails when it reaches a block that has not been committed yet.
+	GetBlocks(start, stop uint64) (BlockIterator, error)
+
+	// NewBlockStream returns an iterator over the blocks from start on, waiting for the new ones to be committed
+	NewBlockStream(start uint64) (BlockIterator, error)
 }
@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package api
 
 import (
+	"github.com/hyperledger/fabric-protos-go/msp"
+
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
@@ -34,21 +36,33 @@ type LocalMembership interface {
 	GetIdentityInfoByLabel(mspType string, label string) *IdentityInfo
 	GetIdentityInfoByIdentity(mspType string, id view.Identity) *IdentityInfo
 	Refresh() error
+	// UpdateRevocationInformation moves the idemix MSPs to the epoch of the passed credential revocation information
+	UpdateRevocationInformation(cri []byte) error
 }
 
 type MSPIdentity interface {
 	GetMSPIdentifier() string
 	Validate() error
 	Verify(message, sigma []byte) error
+	SatisfiesPrincipal(principal *msp.MSPPrincipal) error
 }
 
 type MSPManager interface {
 	DeserializeIdentity(serializedIdentity []byte) (MSPIdentity, error)
 }
 
+// SignedData models a signature over some data by an identity
+type SignedData struct {
+	Data      []byte
+	Identity  []byte
+	Signature []byte
+}
+
 type ChannelMembership interface {
 	GetMSPIDs() []string
 	MSPManager() MSPManager
 	IsValid(identity view.Identity) error
 	GetVerifier(identity view.Identity) (api.Verifier, error)
+	// EvaluatePolicy checks that the passed signatures satisfy the passed serialized SignaturePolicyEnvelope
+	EvaluatePolicy(policy []byte, signatures []*SignedData) error
 }
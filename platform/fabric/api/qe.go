@@ -10,6 +10,9 @@ import "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/d
 type QueryExecutor interface {
 	GetState(namespace string, key string) ([]byte, error)
 	GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error)
+	// GetPrivateState returns the value of the key in the collection of the namespace, nil if this node
+	// is not a member of the collection
+	GetPrivateState(namespace, collection, key string) ([]byte, error)
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error)
 	Done()
 }
@@ -5,6 +5,8 @@ SPDX-License-Identifier: Apache-2.0
 */
 package api
 
+import "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+
 type GetStateOpt int
 
 const (
@@ -32,6 +34,31 @@ type RWSet interface {
 	// ey-tuple <namespace, key>
 	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
 
+	// GetStateRangeScanIterator returns an iterator over the keys of the given namespace in the range [startKey, endKey).
+	// The range is recorded in this rwset when the iterator is closed, to let the validation detect phantom reads.
+	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error)
+
+	// SetPrivateState sets the given value for the given key in the given collection of the given namespace.
+	// The read-write set carries the hashes of the private writes, their preimages are in the private read-write set.
+	SetPrivateState(namespace, collection, key string, value []byte) error
+
+	// GetPrivateState returns the value of the given key in the given collection of the given namespace.
+	// The value is nil if this node has not received the private data of the collection.
+	GetPrivateState(namespace, collection, key string, opts ...GetStateOpt) ([]byte, error)
+
+	// DeletePrivateState deletes the given key in the given collection of the given namespace
+	DeletePrivateState(namespace, collection, key string) error
+
+	// Collections returns the collections of the namespace ns with private reads or writes in this rwset.
+	Collections(ns string) []string
+
+	// AppendPrivateRWSet adds the preimages of the private writes in the passed private read-write set.
+	// The preimages must match the hashes in this rwset.
+	AppendPrivateRWSet(raw []byte) error
+
+	// PrivateBytes returns the private read-write set, the preimages of the private writes known to this node.
+	PrivateBytes() ([]byte, error)
+
 	GetReadKeyAt(ns string, i int) (string, error)
 
 	// GetReadAt returns the i-th read (key, value) in the namespace ns  of this rwset.
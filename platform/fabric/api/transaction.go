@@ -112,6 +112,8 @@ type Transaction interface {
 	ProposalResponses() []ProposalResponse
 	ProposalResponse() ([]byte, error)
 	BytesNoTransient() ([]byte, error)
+	// BytesWithCollections marshals the transaction with the private data of the collections for which keep returns true
+	BytesWithCollections(noTransient bool, keep func(namespace, collection string) bool) ([]byte, error)
 }
 
 type SignedProposal interface {
@@ -5,6 +5,11 @@ SPDX-License-Identifier: Apache-2.0
 */
 package api
 
+import (
+	"io"
+	"time"
+)
+
 type Vault interface {
 	// NewQueryExecutor gives handle to a query executor.
 	// A client can obtain more than one 'QueryExecutor's for parallel execution.
@@ -23,4 +28,63 @@ type Vault interface {
 	GetRWSet(txid string, rwset []byte) (RWSet, error)
 
 	GetEphemeralRWSet(rwset []byte) (RWSet, error)
+
+	// GetHistoryForKey returns the versions of the passed key recorded in the history index, the oldest first
+	GetHistoryForKey(namespace, key string) ([]*HistoryEntry, error)
+
+	// GetNamespaceAsOf returns the keys of the namespace, with their values, as they were
+	// once the passed block was committed
+	GetNamespaceAsOf(namespace string, block uint64) ([]*HistoryEntry, error)
+
+	// ExportSnapshot writes the content of the vault to the passed writer, in a format that
+	// can be imported to bootstrap the vault of another node.
+	// The private data of the collections and the local transactions are included only if includePrivate is set.
+	ExportSnapshot(w io.Writer, includePrivate bool) (*SnapshotInfo, error)
+
+	// GetLocalLog returns the audit log of the transactions committed locally, from the passed sequence number on
+	GetLocalLog(from uint64) ([]*LocalTransaction, error)
+}
+
+// HistoryEntry is a version of a key recorded in the history index of the vault
+type HistoryEntry struct {
+	Key   string
+	Value []byte
+	Block uint64
+	TxNum uint64
+	// TxID is the id of the transaction that wrote this version
+	TxID string
+	// Deleted is true if the transaction deleted the key
+	Deleted bool
+}
+
+// SnapshotInfo describes a snapshot of the vault
+type SnapshotInfo struct {
+	// LastBlock is the block the delivery resumes from on the nodes bootstrapped from the snapshot
+	LastBlock uint64
+	// Entries is the number of entries of the snapshot
+	Entries uint64
+	// Checksum is the hex-encoded SHA-256 of the content of the snapshot
+	Checksum string
+}
+
+// LocalTransaction is an entry of the audit log of the transactions committed locally, without going through Fabric
+type LocalTransaction struct {
+	// Seq is the sequence number assigned to the transaction, its writes are at version 0:Seq
+	Seq  uint64
+	TxID string
+	// Timestamp is the time the transaction was committed or rejected
+	Timestamp time.Time
+	// Valid is false if the transaction was rejected
+	Valid bool
+	// Reason tells why the transaction was rejected
+	Reason string        `json:",omitempty"`
+	Writes []*LocalWrite `json:",omitempty"`
+}
+
+// LocalWrite is a key written by a transaction committed locally
+type LocalWrite struct {
+	Namespace string
+	Key       string
+	// Deleted is true if the transaction deleted the key
+	Deleted bool `json:",omitempty"`
 }
@@ -86,6 +86,12 @@ func (i *ChaincodeDiscover) WithFilterByMSPIDs(mspIDs ...string) *ChaincodeDisco
 	return i
 }
 
+// WithPreferredMSPIDs sets the MSPs whose peers are preferred when choosing among the peers of a group
+func (i *ChaincodeDiscover) WithPreferredMSPIDs(mspIDs ...string) *ChaincodeDiscover {
+	i.ChaincodeDiscover.WithPreferredMSPIDs(mspIDs...)
+	return i
+}
+
 type ChaincodeInvocation struct {
 	api.ChaincodeInvocation
 }
@@ -114,6 +120,12 @@ func (i *ChaincodeInvocation) WithEndorsersFromMyOrg() *ChaincodeInvocation {
 	return i
 }
 
+// WithPreferredEndorsersByMSPIDs sets the MSPs whose peers are preferred when endorsers are discovered
+func (i *ChaincodeInvocation) WithPreferredEndorsersByMSPIDs(mspIDs ...string) *ChaincodeInvocation {
+	i.ChaincodeInvocation.WithPreferredEndorsersByMSPIDs(mspIDs...)
+	return i
+}
+
 func (i *ChaincodeInvocation) WithInvokerIdentity(id view.Identity) *ChaincodeInvocation {
 	i.ChaincodeInvocation.WithSignerIdentity(id)
 	return i
@@ -155,6 +167,12 @@ func (i *ChaincodeEndorse) WithEndorsersFromMyOrg() *ChaincodeEndorse {
 	return i
 }
 
+// WithPreferredEndorsersByMSPIDs sets the MSPs whose peers are preferred when endorsers are discovered
+func (i *ChaincodeEndorse) WithPreferredEndorsersByMSPIDs(mspIDs ...string) *ChaincodeEndorse {
+	i.ci.WithPreferredEndorsersByMSPIDs(mspIDs...)
+	return i
+}
+
 func (i *ChaincodeEndorse) WithInvokerIdentity(id view.Identity) *ChaincodeEndorse {
 	i.ci.WithSignerIdentity(id)
 	return i
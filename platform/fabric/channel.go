@@ -46,6 +46,16 @@ func (c *Channel) Chaincode(name string) *Chaincode {
 	}
 }
 
+// ConfigManager returns the manager of the configuration of this channel
+func (c *Channel) ConfigManager() *ConfigManager {
+	return &ConfigManager{ch: c.ch}
+}
+
+// Lifecycle returns a handler of the lifecycle of the chaincodes of this channel
+func (c *Channel) Lifecycle() *Lifecycle {
+	return &Lifecycle{l: c.ch.Lifecycle()}
+}
+
 func (c *Channel) GetTLSRootCert(party view.Identity) ([][]byte, error) {
 	return c.ch.GetTLSRootCert(party)
 }
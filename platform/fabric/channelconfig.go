@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package fabric
+
+import (
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// ChannelConfig is the configuration of a channel, as of its last config block
+type ChannelConfig struct {
+	Sequence uint64
+	// Organizations are the organizations of the application group, sorted by name
+	Organizations []*Organization
+	Orderer       *OrdererConfig
+	// Capabilities are the capabilities enabled by group, Channel, Application and Orderer
+	Capabilities map[string][]string
+	// Policies are the policies of the channel by path, e.g. /Channel/Application/Admins
+	Policies map[string]*Policy
+}
+
+// Organization returns the organization of the application group with the passed MSP ID, nil if none
+func (c *ChannelConfig) Organization(mspID string) *Organization {
+	for _, org := range c.Organizations {
+		if org.MSPID == mspID {
+			return org
+		}
+	}
+	return nil
+}
+
+// Organization is an organization of the application group of a channel
+type Organization struct {
+	Name  string
+	MSPID string
+	// AnchorPeers are the endpoints, host:port, of the anchor peers of the organization
+	AnchorPeers []string
+}
+
+// OrdererConfig is the configuration of the ordering service of a channel
+type OrdererConfig struct {
+	ConsensusType string
+	// Endpoints are the orderer endpoints of the channel group, deprecated in favour of the ones of the organizations
+	Endpoints     []string
+	Organizations []*OrdererOrganization
+}
+
+// OrdererOrganization is an organization of the orderer group of a channel
+type OrdererOrganization struct {
+	Name      string
+	MSPID     string
+	Endpoints []string
+}
+
+// Policy is a policy of the channel configuration
+type Policy struct {
+	// Type is ImplicitMeta, Signature or, for the other types, their number
+	Type string
+	// Rule is the policy in a readable form, e.g. "MAJORITY Admins" or "OR('Org1MSP.admin')"
+	Rule      string
+	ModPolicy string
+}
+
+// ConfigManager gives access to the configuration of a channel and updates it.
+// An update goes through these steps: the current configuration is fetched with RawConfig and modified,
+// NewConfigUpdate computes the update, the admins of the organizations required by the modification policies
+// sign it with SignConfigUpdate, and SubmitConfigUpdate sends it to the ordering service.
+type ConfigManager struct {
+	ch api.Channel
+}
+
+// Config returns the current configuration of the channel
+func (c *ConfigManager) Config() (*ChannelConfig, error) {
+	config, err := c.ch.Config()
+	if err != nil {
+		return nil, err
+	}
+	res := &ChannelConfig{
+		Sequence:     config.Sequence,
+		Capabilities: config.Capabilities,
+		Policies:     map[string]*Policy{},
+	}
+	for _, org := range config.Organizations {
+		res.Organizations = append(res.Organizations, &Organization{Name: org.Name, MSPID: org.MSPID, AnchorPeers: org.AnchorPeers})
+	}
+	if config.Orderer != nil {
+		res.Orderer = &OrdererConfig{ConsensusType: config.Orderer.ConsensusType, Endpoints: config.Orderer.Endpoints}
+		for _, org := range config.Orderer.Organizations {
+			res.Orderer.Organizations = append(res.Orderer.Organizations, &OrdererOrganization{Name: org.Name, MSPID: org.MSPID, Endpoints: org.Endpoints})
+		}
+	}
+	for path, p := range config.Policies {
+		res.Policies[path] = &Policy{Type: p.Type, Rule: p.Rule, ModPolicy: p.ModPolicy}
+	}
+	return res, nil
+}
+
+// RawConfig returns the current configuration of the channel as a marshalled common.Config
+func (c *ConfigManager) RawConfig() ([]byte, error) {
+	return c.ch.RawConfig()
+}
+
+// NewConfigUpdate returns the marshalled ConfigUpdate turning the current configuration of the channel
+// into the passed marshalled common.Config
+func (c *ConfigManager) NewConfigUpdate(updated []byte) ([]byte, error) {
+	return c.ch.NewConfigUpdate(updated)
+}
+
+// SignConfigUpdate returns the marshalled ConfigSignature of the passed update by the passed identity
+func (c *ConfigManager) SignConfigUpdate(update []byte, id view.Identity) ([]byte, error) {
+	return c.ch.SignConfigUpdate(update, id)
+}
+
+// VerifyConfigSignature checks that the passed marshalled ConfigSignature is a valid signature of the passed
+// update by a member of the channel, and returns the signer
+func (c *ConfigManager) VerifyConfigSignature(update []byte, signature []byte) (view.Identity, error) {
+	return c.ch.VerifyConfigSignature(update, signature)
+}
+
+// SubmitConfigUpdate sends the passed update, with the passed signatures, to the ordering service
+// in an envelope signed by the submitter, and waits for the resulting configuration to be committed
+func (c *ConfigManager) SubmitConfigUpdate(update []byte, signatures [][]byte, submitter view.Identity) error {
+	return c.ch.SubmitConfigUpdate(update, signatures, submitter)
+}
@@ -14,3 +14,13 @@ import (
 func (c *channel) Chaincode(name string) api.Chaincode {
 	return chaincode.NewChaincode(name, c.sp, c.network, c)
 }
+
+// DiscoveryCache returns the cache of the results of the discovery service for this channel
+func (c *channel) DiscoveryCache() *chaincode.DiscoveryCache {
+	return c.discoveryCache
+}
+
+// Lifecycle returns a handler of the lifecycle of the chaincodes of this channel
+func (c *channel) Lifecycle() api.Lifecycle {
+	return chaincode.NewLifecycle(c.sp, c.network, c)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -37,21 +38,74 @@ type ServiceResponse interface {
 }
 
 type Discovery struct {
-	network        Network
-	channel        Channel
-	chaincode      string
-	filterByMSPIDs []string
+	network         Network
+	channel         Channel
+	chaincode       string
+	filterByMSPIDs  []string
+	preferredMSPIDs []string
 }
 
 func NewDiscovery(network Network, channel Channel, chaincode string) *Discovery {
 	return &Discovery{network: network, channel: channel, chaincode: chaincode}
 }
 
+// Call returns the endorsers of a layout satisfying the endorsement policy of the chaincode.
+// Among the layouts and the peers of each group, the preferred ones are chosen.
 func (d *Discovery) Call() ([]view.Identity, error) {
+	descriptors, err := d.Response()
+	if err != nil {
+		return nil, err
+	}
+	return d.selectEndorsers(descriptors, nil)
+}
+
+// Response returns the endorsement descriptors of the chaincode, from the cache of the channel
+// if they have not expired yet.
+func (d *Discovery) Response() ([]*discovery2.EndorsementDescriptor, error) {
 	if len(d.chaincode) == 0 {
 		return nil, errors.New("no chaincode specified")
 	}
 
+	cache := d.channel.DiscoveryCache()
+	if descriptors, ok := cache.Get(d.chaincode); ok {
+		logger.Debugf("discovery results for [%s:%s] found in cache", d.channel.Name(), d.chaincode)
+		return descriptors, nil
+	}
+
+	// ask the peers in order, until one answers
+	var errs []error
+	for _, peer := range d.network.Peers() {
+		descriptors, err := d.query(peer)
+		if err != nil {
+			logger.Warnf("failed querying discovery service of [%s] for [%s:%s]: [%s]", peer.Address, d.channel.Name(), d.chaincode, err)
+			errs = append(errs, errors.WithMessagef(err, "peer [%s]", peer.Address))
+			continue
+		}
+		cache.Put(d.chaincode, descriptors)
+		return descriptors, nil
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no peers configured")
+	}
+	return nil, errors.Errorf("failed querying discovery service: %v", errs)
+}
+
+// Invalidate removes the endorsement descriptors of the chaincode from the cache of the channel
+func (d *Discovery) Invalidate() {
+	d.channel.DiscoveryCache().Invalidate(d.chaincode)
+}
+
+func (d *Discovery) WithFilterByMSPIDs(mspIDs ...string) api.ChaincodeDiscover {
+	d.filterByMSPIDs = mspIDs
+	return d
+}
+
+func (d *Discovery) WithPreferredMSPIDs(mspIDs ...string) api.ChaincodeDiscover {
+	d.preferredMSPIDs = mspIDs
+	return d
+}
+
+func (d *Discovery) query(peer *grpc.ConnectionConfig) ([]*discovery2.EndorsementDescriptor, error) {
 	req, err := discovery.NewRequest().OfChannel(d.channel.Name()).AddEndorsersQuery(
 		&discovery2.ChaincodeInterest{Chaincodes: []*discovery2.ChaincodeCall{
 			{
@@ -63,7 +117,7 @@ func (d *Discovery) Call() ([]view.Identity, error) {
 		return nil, errors.Wrap(err, "failed creating request")
 	}
 
-	pc, err := d.channel.NewPeerClientForAddress(*d.network.Peers()[0])
+	pc, err := d.channel.NewPeerClientForAddress(*peer)
 	if err != nil {
 		return nil, err
 	}
@@ -113,47 +167,67 @@ func (d *Discovery) Call() ([]view.Identity, error) {
 	if ccQueryRes == nil {
 		return nil, errors.Errorf("server returned response of unexpected type: %v", reflect.TypeOf(res.Results[0]))
 	}
+	if len(ccQueryRes.Content) == 0 {
+		return nil, errors.New("server returned no endorsement descriptor")
+	}
+
+	return ccQueryRes.Content, nil
+}
 
+// selectEndorsers returns the endorsers of the first layout that can be satisfied without the excluded peers.
+// If endorsers are filtered by MSP ID, each group contributes with the peers that pass the filter,
+// up to the quantity required by the layout.
+func (d *Discovery) selectEndorsers(descriptors []*discovery2.EndorsementDescriptor, excluded map[string]error) ([]view.Identity, error) {
 	mspManager := d.channel.MSPManager()
+	mspIDs := map[string]string{}
+	mspIDOf := func(id view.Identity) (string, error) {
+		if mspID, ok := mspIDs[id.UniqueID()]; ok {
+			return mspID, nil
+		}
+		endorser, err := mspManager.DeserializeIdentity(id)
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed deserializing identity [%s]", id.String())
+		}
+		mspIDs[id.UniqueID()] = endorser.GetMSPIdentifier()
+		return endorser.GetMSPIdentifier(), nil
+	}
 
-	var endorsers []view.Identity
-	for _, descriptor := range ccQueryRes.Content {
-		for _, layout := range descriptor.Layouts {
-			for group, q := range layout.QuantitiesByGroup {
-				// Peek q peers from descriptor.EndorsersByGroups[group].Peers
-
-				for i := 0; i < int(q); i++ {
-					endorserID := descriptor.EndorsersByGroups[group].Peers[i].Identity
-					logger.Debugf("endorser discovered [%s,%s] [%s]", descriptor.Chaincode, group, view.Identity(endorserID))
-
-					if len(d.filterByMSPIDs) != 0 {
-						endorser, err := mspManager.DeserializeIdentity(endorserID)
-						if err != nil {
-							return nil, errors.WithMessagef(err, "failed deserializing identity [%s]", view.Identity(endorserID).String())
-						}
-						endorserMSPID := endorser.GetMSPIdentifier()
-						found := false
-						for _, mspID := range d.filterByMSPIDs {
-							if mspID == endorserMSPID {
-								found = true
-								break
-							}
-						}
-						if !found {
-							continue
-						}
-					}
-
-					endorsers = append(endorsers, endorserID)
-				}
+	selector := &selector{
+		excluded:  excluded,
+		filter:    len(d.filterByMSPIDs) != 0,
+		latencies: peerLatencies,
+		accept: func(id view.Identity) (bool, error) {
+			if len(d.filterByMSPIDs) == 0 {
+				return true, nil
 			}
-		}
+			mspID, err := mspIDOf(id)
+			if err != nil {
+				return false, err
+			}
+			return contains(d.filterByMSPIDs, mspID), nil
+		},
+		prefer: func(id view.Identity) (bool, error) {
+			if len(d.preferredMSPIDs) == 0 {
+				return false, nil
+			}
+			mspID, err := mspIDOf(id)
+			if err != nil {
+				return false, err
+			}
+			return contains(d.preferredMSPIDs, mspID), nil
+		},
 	}
 
+	var endorsers []view.Identity
+	for _, descriptor := range descriptors {
+		ids, err := selector.selectLayout(descriptor)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed selecting endorsers of [%s]", descriptor.Chaincode)
+		}
+		for _, id := range ids {
+			logger.Debugf("endorser selected [%s] [%s]", descriptor.Chaincode, id)
+		}
+		endorsers = append(endorsers, ids...)
+	}
 	return endorsers, nil
 }
-
-func (d *Discovery) WithFilterByMSPIDs(mspIDs ...string) api.ChaincodeDiscover {
-	d.filterByMSPIDs = mspIDs
-	return d
-}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
@@ -50,6 +51,9 @@ type Invoke struct {
 	EndorsersByConnConfig []*grpc.ConnectionConfig
 	Function              string
 	Args                  []interface{}
+
+	// PreferredEndorsersMSPIDs are the MSPs whose peers are preferred when endorsers are discovered
+	PreferredEndorsersMSPIDs []string
 }
 
 func NewInvoke(ServiceProvider view2.ServiceProvider, network Network, channel Channel, chaincode, function string, args ...interface{}) *Invoke {
@@ -96,10 +100,23 @@ func (i *Invoke) Call() (interface{}, error) {
 		return nil, errors.Errorf("no chaincode specified")
 	}
 
-	// load endorser clients
-	var endorserClients []pb.EndorserClient
+	// load signer
+	signer, err := i.Network.SigService().GetSigningIdentity(i.SignerIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	// prepare proposal
+	signedProp, prop, txid, err := i.prepareProposal(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect responses
+	var responses []*pb.ProposalResponse
 	switch {
 	case len(i.EndorsersByConnConfig) != 0:
+		var endorsers []*endorser
 		for _, config := range i.EndorsersByConnConfig {
 			peerClient, err := i.Channel.NewPeerClientForAddress(*config)
 			if err != nil {
@@ -109,57 +126,22 @@ func (i *Invoke) Call() (interface{}, error) {
 			if err != nil {
 				return nil, errors.WithMessagef(err, "error getting endorser client for config %v", config)
 			}
-			endorserClients = append(endorserClients, endorserClient)
+			endorsers = append(endorsers, &endorser{name: config.Address, client: endorserClient})
 		}
-	case len(i.Endorsers) == 0:
-		if i.EndorsersFromMyOrg && len(i.EndorsersMSPIDs) == 0 {
-			// retrieve invoker's MSP-ID
-			invokerMSPID, err := i.Channel.MSPManager().DeserializeIdentity(i.SignerIdentity)
+		responses, err = i.endorse(endorsers, signedProp)
+	case len(i.Endorsers) != 0:
+		var endorsers []*endorser
+		for _, id := range i.Endorsers {
+			e, err := i.newEndorser(id)
 			if err != nil {
-				return nil, errors.WithMessagef(err, "failed to deserializer the invoker identity")
+				return nil, err
 			}
-			i.EndorsersMSPIDs = []string{invokerMSPID.GetMSPIdentifier()}
-		}
-
-		// discover
-		var err error
-		i.Endorsers, err = NewDiscovery(i.Network, i.Channel, i.ChaincodeName).WithFilterByMSPIDs(i.EndorsersMSPIDs...).Call()
-		if err != nil {
-			return nil, err
+			endorsers = append(endorsers, e)
 		}
+		responses, err = i.endorse(endorsers, signedProp)
 	default:
-		return nil, errors.New("no rule set to find the endorsers")
+		responses, err = i.endorseWithDiscovery(signedProp)
 	}
-
-	for _, endorser := range i.Endorsers {
-		peerClient, err := i.Channel.NewPeerClientForIdentity(endorser)
-		if err != nil {
-			return nil, err
-		}
-		endorserClient, err := peerClient.Endorser()
-		if err != nil {
-			return nil, errors.WithMessagef(err, "error getting endorser client for %s", endorser)
-		}
-		endorserClients = append(endorserClients, endorserClient)
-	}
-	if len(endorserClients) == 0 {
-		return nil, errors.New("no endorser clients retrieved - this might indicate a bug")
-	}
-
-	// load signer
-	signer, err := i.Network.SigService().GetSigningIdentity(i.SignerIdentity)
-	if err != nil {
-		return nil, err
-	}
-
-	// prepare proposal
-	signedProp, prop, txid, err := i.prepareProposal(signer)
-	if err != nil {
-		return nil, err
-	}
-
-	// collect responses
-	responses, err := i.collectResponses(endorserClients, signedProp)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "error endorsing")
 	}
@@ -168,8 +150,7 @@ func (i *Invoke) Call() (interface{}, error) {
 		// this should only happen if some new code has introduced a bug
 		return nil, errors.New("no proposal responses received - this might indicate a bug")
 	}
-	// all responses will be checked when the signed transaction is created.
-	// for now, just set this so we check the first response's status
+	// all responses have been checked to be successful and consistent
 	proposalResp := responses[0]
 	if proposalResp == nil {
 		return nil, errors.New("error during query: received nil proposal response")
@@ -226,6 +207,11 @@ func (i *Invoke) WithEndorsersFromMyOrg() api.ChaincodeInvocation {
 	return i
 }
 
+func (i *Invoke) WithPreferredEndorsersByMSPIDs(mspIDs ...string) api.ChaincodeInvocation {
+	i.PreferredEndorsersMSPIDs = mspIDs
+	return i
+}
+
 func (i *Invoke) WithSignerIdentity(id view.Identity) api.ChaincodeInvocation {
 	i.SignerIdentity = id
 	return i
@@ -295,34 +281,144 @@ func (i *Invoke) createChaincodeProposalWithTxIDAndTransient(typ pcommon.HeaderT
 	return protoutil.CreateChaincodeProposalWithTxIDNonceAndTransient(txid, typ, channelID, cis, nonce, creator, transientMap)
 }
 
-// collectResponses sends a signed proposal to a set of peers, and gathers all the responses.
-func (i *Invoke) collectResponses(endorserClients []pb.EndorserClient, signedProposal *pb.SignedProposal) ([]*pb.ProposalResponse, error) {
-	responsesCh := make(chan *pb.ProposalResponse, len(endorserClients))
-	errorCh := make(chan error, len(endorserClients))
+// endorser is a peer to send proposals to
+type endorser struct {
+	// name identifies the peer, the unique id of its identity or its address
+	name   string
+	client pb.EndorserClient
+}
+
+type endorsement struct {
+	endorser *endorser
+	response *pb.ProposalResponse
+	err      error
+}
+
+func (i *Invoke) newEndorser(id view.Identity) (*endorser, error) {
+	peerClient, err := i.Channel.NewPeerClientForIdentity(id)
+	if err != nil {
+		return nil, err
+	}
+	endorserClient, err := peerClient.Endorser()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error getting endorser client for %s", id)
+	}
+	return &endorser{name: id.UniqueID(), client: endorserClient}, nil
+}
+
+// endorse sends the signed proposal to all the passed endorsers, any failure fails the endorsement
+func (i *Invoke) endorse(endorsers []*endorser, signedProposal *pb.SignedProposal) ([]*pb.ProposalResponse, error) {
+	var responses []*pb.ProposalResponse
+	var names []string
+	for _, e := range i.collectResponses(endorsers, signedProposal) {
+		if e.err != nil {
+			return nil, errors.WithMessagef(e.err, "failed getting endorsement from [%s]", e.endorser.name)
+		}
+		responses = append(responses, e.response)
+		names = append(names, e.endorser.name)
+	}
+	if err := checkResponses(names, responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// endorseWithDiscovery sends the signed proposal to the endorsers of a layout satisfying the endorsement policy.
+// When an endorser fails, another layout without the endorsers that failed is tried, the endorsers that already
+// answered are not asked again.
+func (i *Invoke) endorseWithDiscovery(signedProposal *pb.SignedProposal) ([]*pb.ProposalResponse, error) {
+	if i.EndorsersFromMyOrg && len(i.EndorsersMSPIDs) == 0 {
+		// retrieve invoker's MSP-ID
+		invokerMSPID, err := i.Channel.MSPManager().DeserializeIdentity(i.SignerIdentity)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to deserializer the invoker identity")
+		}
+		i.EndorsersMSPIDs = []string{invokerMSPID.GetMSPIdentifier()}
+	}
+
+	d := NewDiscovery(i.Network, i.Channel, i.ChaincodeName)
+	d.WithFilterByMSPIDs(i.EndorsersMSPIDs...)
+	d.WithPreferredMSPIDs(i.PreferredEndorsersMSPIDs...)
+	descriptors, err := d.Response()
+	if err != nil {
+		return nil, err
+	}
+
+	failed := map[string]error{}
+	answered := map[string]*pb.ProposalResponse{}
+	for {
+		ids, err := d.selectEndorsers(descriptors, failed)
+		if err != nil {
+			if len(failed) != 0 {
+				// the peers might have left, discover again next time
+				d.Invalidate()
+			}
+			return nil, err
+		}
+		var missing []*endorser
+		for _, id := range ids {
+			if _, ok := answered[id.UniqueID()]; ok {
+				continue
+			}
+			e, err := i.newEndorser(id)
+			if err != nil {
+				logger.Warnf("failed connecting to endorser [%s]: [%s]", id, err)
+				failed[id.UniqueID()] = errors.WithMessagef(err, "failed connecting to [%s]", id)
+				continue
+			}
+			missing = append(missing, e)
+		}
+		for _, e := range i.collectResponses(missing, signedProposal) {
+			if e.err == nil && (e.response.Response == nil || e.response.Response.Status >= 400) {
+				e.err = errors.Errorf("proposal response not successful [%v]", e.response.Response)
+			}
+			if e.err != nil {
+				logger.Warnf("endorser [%s] failed: [%s]", e.endorser.name, e.err)
+				failed[e.endorser.name] = errors.WithMessagef(e.err, "endorser [%s] failed", e.endorser.name)
+				continue
+			}
+			answered[e.endorser.name] = e.response
+		}
+
+		var responses []*pb.ProposalResponse
+		var names []string
+		for _, id := range ids {
+			response, ok := answered[id.UniqueID()]
+			if !ok {
+				break
+			}
+			responses = append(responses, response)
+			names = append(names, id.UniqueID())
+		}
+		if len(responses) != len(ids) {
+			// try another layout
+			continue
+		}
+		if err := checkResponses(names, responses); err != nil {
+			return nil, err
+		}
+		return responses, nil
+	}
+}
+
+// collectResponses sends a signed proposal to a set of peers, and gathers all the responses and errors.
+func (i *Invoke) collectResponses(endorsers []*endorser, signedProposal *pb.SignedProposal) []*endorsement {
+	endorsements := make([]*endorsement, len(endorsers))
 	wg := sync.WaitGroup{}
-	for _, endorser := range endorserClients {
+	for j, e := range endorsers {
 		wg.Add(1)
-		go func(endorser pb.EndorserClient) {
+		go func(j int, e *endorser) {
 			defer wg.Done()
-			proposalResp, err := endorser.ProcessProposal(context.Background(), signedProposal)
-			if err != nil {
-				errorCh <- err
-				return
+			start := time.Now()
+			proposalResp, err := e.client.ProcessProposal(context.Background(), signedProposal)
+			if err == nil {
+				peerLatencies.observe(e.name, time.Since(start))
 			}
-			responsesCh <- proposalResp
-		}(endorser)
+			endorsements[j] = &endorsement{endorser: e, response: proposalResp, err: err}
+		}(j, e)
 	}
 	wg.Wait()
-	close(responsesCh)
-	close(errorCh)
-	for err := range errorCh {
-		return nil, err
-	}
-	var responses []*pb.ProposalResponse
-	for response := range responsesCh {
-		responses = append(responses, response)
-	}
-	return responses, nil
+	return endorsements
 }
 
 // getChaincodeSpec get chaincode spec from the cli cmd parameters
@@ -0,0 +1,457 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package chaincode
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pcommon "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const (
+	lifecycleName = "_lifecycle"
+
+	// installTimeout bounds the installation of a package, the peers build the chaincode while installing it
+	installTimeout = 5 * time.Minute
+)
+
+// Lifecycle drives the _lifecycle system chaincode of the peers of the network.
+// The peers of the network are expected to belong to the organization of the node: the packages are installed
+// on them, and they endorse the approvals of the organization.
+type Lifecycle struct {
+	sp      view2.ServiceProvider
+	network Network
+	channel Channel
+	signer  view.Identity
+}
+
+func NewLifecycle(sp view2.ServiceProvider, network Network, channel Channel) *Lifecycle {
+	return &Lifecycle{sp: sp, network: network, channel: channel}
+}
+
+func (l *Lifecycle) Install(pkg []byte) (*api.InstalledChaincode, error) {
+	installed, err := PackageInfo(pkg)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := proto.Marshal(&lb.InstallChaincodeArgs{ChaincodeInstallPackage: pkg})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling install arguments")
+	}
+	peers := l.network.Peers()
+	if len(peers) == 0 {
+		return nil, errors.New("no peers configured")
+	}
+	for _, peer := range peers {
+		chaincodes, err := l.queryInstalled(peer)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed querying installed chaincodes on [%s]", peer.Address)
+		}
+		if containsPackage(chaincodes, installed.PackageID) {
+			logger.Debugf("package [%s] already installed on [%s]", installed.PackageID, peer.Address)
+			continue
+		}
+		payload, err := l.process(peer, "InstallChaincode", raw, installTimeout)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed installing package [%s] on [%s]", installed.PackageID, peer.Address)
+		}
+		res := &lb.InstallChaincodeResult{}
+		if err := proto.Unmarshal(payload, res); err != nil {
+			return nil, errors.Wrapf(err, "failed unmarshalling install result of [%s]", peer.Address)
+		}
+		if res.PackageId != installed.PackageID {
+			return nil, errors.Errorf("peer [%s] installed package [%s], expected [%s]", peer.Address, res.PackageId, installed.PackageID)
+		}
+	}
+	return installed, nil
+}
+
+// QueryInstalled returns the chaincodes installed on the first peer of the network that answers
+func (l *Lifecycle) QueryInstalled() ([]*api.InstalledChaincode, error) {
+	var installed []*api.InstalledChaincode
+	err := l.onPeers("querying installed chaincodes", func(peer *grpc.ConnectionConfig) error {
+		var err error
+		installed, err = l.queryInstalled(peer)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return installed, nil
+}
+
+func (l *Lifecycle) queryInstalled(peer *grpc.ConnectionConfig) ([]*api.InstalledChaincode, error) {
+	raw, err := proto.Marshal(&lb.QueryInstalledChaincodesArgs{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling query arguments")
+	}
+	payload, err := l.process(peer, "QueryInstalledChaincodes", raw, defaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	res := &lb.QueryInstalledChaincodesResult{}
+	if err := proto.Unmarshal(payload, res); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling installed chaincodes")
+	}
+	var installed []*api.InstalledChaincode
+	for _, cc := range res.InstalledChaincodes {
+		installed = append(installed, &api.InstalledChaincode{PackageID: cc.PackageId, Label: cc.Label})
+	}
+	return installed, nil
+}
+
+func (l *Lifecycle) Approve(definition *api.ChaincodeDefinition, packageID string) error {
+	params, err := newDefinitionParams(definition)
+	if err != nil {
+		return err
+	}
+	source := &lb.ChaincodeSource{Type: &lb.ChaincodeSource_Unavailable_{Unavailable: &lb.ChaincodeSource_Unavailable{}}}
+	if len(packageID) != 0 {
+		source = &lb.ChaincodeSource{Type: &lb.ChaincodeSource_LocalPackage{LocalPackage: &lb.ChaincodeSource_Local{PackageId: packageID}}}
+	}
+	raw, err := proto.Marshal(&lb.ApproveChaincodeDefinitionForMyOrgArgs{
+		Sequence:            definition.Sequence,
+		Name:                definition.Name,
+		Version:             definition.Version,
+		EndorsementPlugin:   definition.EndorsementPlugin,
+		ValidationPlugin:    definition.ValidationPlugin,
+		ValidationParameter: params.validationParameter,
+		Collections:         params.collections,
+		InitRequired:        definition.InitRequired,
+		Source:              source,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling approve arguments")
+	}
+
+	// the approval is stored in the implicit collection of the organization, only its peers can endorse it.
+	// A single peer is enough, asking them all would fail as soon as their ledgers are at different heights.
+	err = l.onPeers("approving", func(peer *grpc.ConnectionConfig) error {
+		invoke := l.newInvocation(InvokeCall, "ApproveChaincodeDefinitionForMyOrg", raw)
+		invoke.EndorsersByConnConfig = []*grpc.ConnectionConfig{peer}
+		_, err := invoke.Call()
+		return err
+	})
+	if err != nil {
+		return errors.WithMessagef(err, "failed approving [%s:%d]", definition.Name, definition.Sequence)
+	}
+	return nil
+}
+
+func (l *Lifecycle) CheckCommitReadiness(definition *api.ChaincodeDefinition) (map[string]bool, error) {
+	params, err := newDefinitionParams(definition)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := proto.Marshal(&lb.CheckCommitReadinessArgs{
+		Sequence:            definition.Sequence,
+		Name:                definition.Name,
+		Version:             definition.Version,
+		EndorsementPlugin:   definition.EndorsementPlugin,
+		ValidationPlugin:    definition.ValidationPlugin,
+		ValidationParameter: params.validationParameter,
+		Collections:         params.collections,
+		InitRequired:        definition.InitRequired,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling check commit readiness arguments")
+	}
+	payload, err := l.query("CheckCommitReadiness", raw)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed checking commit readiness of [%s:%d]", definition.Name, definition.Sequence)
+	}
+	res := &lb.CheckCommitReadinessResult{}
+	if err := proto.Unmarshal(payload, res); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling commit readiness")
+	}
+	return res.Approvals, nil
+}
+
+func (l *Lifecycle) Commit(definition *api.ChaincodeDefinition, endorsers ...view.Identity) error {
+	params, err := newDefinitionParams(definition)
+	if err != nil {
+		return err
+	}
+	raw, err := proto.Marshal(&lb.CommitChaincodeDefinitionArgs{
+		Sequence:            definition.Sequence,
+		Name:                definition.Name,
+		Version:             definition.Version,
+		EndorsementPlugin:   definition.EndorsementPlugin,
+		ValidationPlugin:    definition.ValidationPlugin,
+		ValidationParameter: params.validationParameter,
+		Collections:         params.collections,
+		InitRequired:        definition.InitRequired,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling commit arguments")
+	}
+
+	if len(endorsers) == 0 {
+		// the endorsers must satisfy the lifecycle endorsement policy of the channel
+		endorsers, err = NewDiscovery(l.network, l.channel, lifecycleName).Call()
+		if err != nil {
+			return errors.WithMessagef(err, "failed discovering the endorsers of [%s:%d]", definition.Name, definition.Sequence)
+		}
+		if len(endorsers) == 0 {
+			return errors.Errorf("no endorsers found for [%s:%d]", definition.Name, definition.Sequence)
+		}
+	}
+	invoke := l.newInvocation(InvokeCall, "CommitChaincodeDefinition", raw)
+	invoke.Endorsers = endorsers
+	if _, err := invoke.Call(); err != nil {
+		return errors.WithMessagef(err, "failed committing [%s:%d]", definition.Name, definition.Sequence)
+	}
+	return nil
+}
+
+func (l *Lifecycle) QueryCommitted(name string) (*api.ChaincodeDefinition, map[string]bool, error) {
+	raw, err := proto.Marshal(&lb.QueryChaincodeDefinitionArgs{Name: name})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed marshalling query arguments")
+	}
+	payload, err := l.query("QueryChaincodeDefinition", raw)
+	if err != nil {
+		return nil, nil, errors.WithMessagef(err, "failed querying definition of [%s]", name)
+	}
+	res := &lb.QueryChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(payload, res); err != nil {
+		return nil, nil, errors.Wrap(err, "failed unmarshalling chaincode definition")
+	}
+	definition := &api.ChaincodeDefinition{
+		Name:              name,
+		Version:           res.Version,
+		Sequence:          res.Sequence,
+		EndorsementPlugin: res.EndorsementPlugin,
+		ValidationPlugin:  res.ValidationPlugin,
+		InitRequired:      res.InitRequired,
+	}
+	if res.Collections != nil && len(res.Collections.Config) != 0 {
+		definition.Collections, err = proto.Marshal(res.Collections)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed marshalling collections")
+		}
+	}
+	return definition, res.Approvals, nil
+}
+
+func (l *Lifecycle) WithSignerIdentity(id view.Identity) api.Lifecycle {
+	l.signer = id
+	return l
+}
+
+func (l *Lifecycle) signerIdentity() view.Identity {
+	if l.signer.IsNone() {
+		return l.network.LocalMembership().DefaultIdentity()
+	}
+	return l.signer
+}
+
+func (l *Lifecycle) newInvocation(typ CallType, function string, args []byte) *Invoke {
+	return &Invoke{
+		CallType:        typ,
+		ServiceProvider: l.sp,
+		Network:         l.network,
+		Channel:         l.channel,
+		SignerIdentity:  l.signerIdentity(),
+		ChaincodeName:   lifecycleName,
+		Function:        function,
+		Args:            []interface{}{args},
+	}
+}
+
+// query evaluates the passed function on the first peer of the network that answers
+func (l *Lifecycle) query(function string, args []byte) ([]byte, error) {
+	var payload []byte
+	err := l.onPeers("querying "+function, func(peer *grpc.ConnectionConfig) error {
+		invoke := l.newInvocation(QueryCall, function, args)
+		invoke.EndorsersByConnConfig = []*grpc.ConnectionConfig{peer}
+		res, err := invoke.Call()
+		if err != nil {
+			return err
+		}
+		payload = res.([]byte)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// onPeers runs the passed operation on the peers of the network, one at a time, until it succeeds on one of them
+func (l *Lifecycle) onPeers(operation string, f func(peer *grpc.ConnectionConfig) error) error {
+	var errs []error
+	for _, peer := range l.network.Peers() {
+		if err := f(peer); err != nil {
+			logger.Warnf("failed %s on [%s]: [%s]", operation, peer.Address, err)
+			errs = append(errs, errors.WithMessagef(err, "peer [%s]", peer.Address))
+			continue
+		}
+		return nil
+	}
+	if len(errs) == 0 {
+		return errors.New("no peers configured")
+	}
+	return errors.Errorf("failed %s: %v", operation, errs)
+}
+
+// process sends to the passed peer a proposal outside of any channel, as the operations on the packages are
+func (l *Lifecycle) process(peer *grpc.ConnectionConfig, function string, args []byte, timeout time.Duration) ([]byte, error) {
+	signer, err := l.network.SigService().GetSigningIdentity(l.signerIdentity())
+	if err != nil {
+		return nil, err
+	}
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error serializing identity")
+	}
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lifecycleName},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(function), args}},
+		},
+	}
+	prop, _, err := protoutil.CreateProposalFromCIS(pcommon.HeaderType_ENDORSER_TRANSACTION, "", cis, creator)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error creating proposal for %s", function)
+	}
+	signedProp, err := protoutil.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error creating signed proposal for %s", function)
+	}
+
+	peerClient, err := l.channel.NewPeerClientForAddress(*peer)
+	if err != nil {
+		return nil, err
+	}
+	endorserClient, err := peerClient.Endorser()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error getting endorser client for %s", peer.Address)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	resp, err := endorserClient.ProcessProposal(ctx, signedProp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response == nil {
+		return nil, errors.New("received nil response")
+	}
+	if resp.Response.Status != int32(pcommon.Status_SUCCESS) {
+		return nil, errors.Errorf("bad response: %d - %s", resp.Response.Status, resp.Response.Message)
+	}
+	return resp.Response.Payload, nil
+}
+
+type definitionParams struct {
+	validationParameter []byte
+	collections         *pb.CollectionConfigPackage
+}
+
+// newDefinitionParams checks the passed definition and converts its policies and collections
+// into the parameters _lifecycle expects
+func newDefinitionParams(definition *api.ChaincodeDefinition) (*definitionParams, error) {
+	if definition == nil {
+		return nil, errors.New("no chaincode definition specified")
+	}
+	if len(definition.Name) == 0 || len(definition.Version) == 0 {
+		return nil, errors.New("chaincode name and version must be specified")
+	}
+	if definition.Sequence <= 0 {
+		return nil, errors.Errorf("invalid sequence [%d], it must be greater than 0", definition.Sequence)
+	}
+
+	params := &definitionParams{}
+	var policy *pb.ApplicationPolicy
+	switch {
+	case len(definition.EndorsementPolicy) != 0 && len(definition.ChannelConfigPolicy) != 0:
+		return nil, errors.New("cannot specify both an endorsement policy and a channel config policy")
+	case len(definition.EndorsementPolicy) != 0:
+		envelope, err := policydsl.FromString(definition.EndorsementPolicy)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid endorsement policy [%s]", definition.EndorsementPolicy)
+		}
+		policy = &pb.ApplicationPolicy{Type: &pb.ApplicationPolicy_SignaturePolicy{SignaturePolicy: envelope}}
+	case len(definition.ChannelConfigPolicy) != 0:
+		policy = &pb.ApplicationPolicy{Type: &pb.ApplicationPolicy_ChannelConfigPolicyReference{ChannelConfigPolicyReference: definition.ChannelConfigPolicy}}
+	}
+	if policy != nil {
+		var err error
+		params.validationParameter, err = proto.Marshal(policy)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed marshalling endorsement policy")
+		}
+	}
+	if len(definition.Collections) != 0 {
+		params.collections = &pb.CollectionConfigPackage{}
+		if err := proto.Unmarshal(definition.Collections, params.collections); err != nil {
+			return nil, errors.Wrap(err, "invalid collections")
+		}
+	}
+	return params, nil
+}
+
+func containsPackage(chaincodes []*api.InstalledChaincode, packageID string) bool {
+	for _, cc := range chaincodes {
+		if cc.PackageID == packageID {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageInfo returns the label and the ID of the passed chaincode package, as the peers compute them
+func PackageInfo(pkg []byte) (*api.InstalledChaincode, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(pkg))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid chaincode package")
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.New("invalid chaincode package: metadata.json not found")
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid chaincode package")
+		}
+		if header.Name != "metadata.json" {
+			continue
+		}
+		metadata := &struct {
+			Label string `json:"label"`
+		}{}
+		if err := json.NewDecoder(tr).Decode(metadata); err != nil {
+			return nil, errors.Wrap(err, "invalid chaincode package metadata")
+		}
+		if len(metadata.Label) == 0 {
+			return nil, errors.New("invalid chaincode package: no label")
+		}
+		return &api.InstalledChaincode{
+			PackageID: fmt.Sprintf("%s:%x", metadata.Label, sha256.Sum256(pkg)),
+			Label:     metadata.Label,
+		}, nil
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package chaincode
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+)
+
+func chaincodePackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestPackageInfo(t *testing.T) {
+	pkg := chaincodePackage(t, map[string]string{
+		"metadata.json": `{"path":"github.com/cc","type":"golang","label":"cc_1.0"}`,
+		"code.tar.gz":   "code",
+	})
+	info, err := PackageInfo(pkg)
+	assert.NoError(t, err)
+	assert.Equal(t, "cc_1.0", info.Label)
+	assert.Equal(t, fmt.Sprintf("cc_1.0:%x", sha256.Sum256(pkg)), info.PackageID)
+
+	_, err = PackageInfo(chaincodePackage(t, map[string]string{"code.tar.gz": "code"}))
+	assert.EqualError(t, err, "invalid chaincode package: metadata.json not found")
+	_, err = PackageInfo([]byte("not a package"))
+	assert.Error(t, err)
+}
+
+func TestDefinitionParams(t *testing.T) {
+	_, err := newDefinitionParams(&api.ChaincodeDefinition{Name: "cc", Version: "1.0"})
+	assert.EqualError(t, err, "invalid sequence [0], it must be greater than 0")
+	_, err = newDefinitionParams(&api.ChaincodeDefinition{Name: "cc", Version: "1.0", Sequence: 1, EndorsementPolicy: "OR('Org1MSP.member')", ChannelConfigPolicy: "/Channel/Application/Endorsement"})
+	assert.EqualError(t, err, "cannot specify both an endorsement policy and a channel config policy")
+	_, err = newDefinitionParams(&api.ChaincodeDefinition{Name: "cc", Version: "1.0", Sequence: 1, EndorsementPolicy: "OR("})
+	assert.Error(t, err)
+
+	// no policy, the default endorsement policy of the channel applies
+	params, err := newDefinitionParams(&api.ChaincodeDefinition{Name: "cc", Version: "1.0", Sequence: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, params.validationParameter)
+	assert.Nil(t, params.collections)
+
+	params, err = newDefinitionParams(&api.ChaincodeDefinition{Name: "cc", Version: "1.0", Sequence: 1, EndorsementPolicy: "AND('Org1MSP.member','Org2MSP.member')"})
+	assert.NoError(t, err)
+	policy := &pb.ApplicationPolicy{}
+	assert.NoError(t, proto.Unmarshal(params.validationParameter, policy))
+	assert.Len(t, policy.GetSignaturePolicy().Identities, 2)
+
+	collections, err := proto.Marshal(&pb.CollectionConfigPackage{Config: []*pb.CollectionConfig{{
+		Payload: &pb.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: &pb.StaticCollectionConfig{Name: "private"}},
+	}}})
+	assert.NoError(t, err)
+	params, err = newDefinitionParams(&api.ChaincodeDefinition{Name: "cc", Version: "1.0", Sequence: 1, ChannelConfigPolicy: "/Channel/Application/Endorsement", Collections: collections})
+	assert.NoError(t, err)
+	assert.NoError(t, proto.Unmarshal(params.validationParameter, policy))
+	assert.Equal(t, "/Channel/Application/Endorsement", policy.GetChannelConfigPolicyReference())
+	assert.Equal(t, "private", params.collections.Config[0].GetStaticCollectionConfig().Name)
+}
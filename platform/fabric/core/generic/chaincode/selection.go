@@ -0,0 +1,228 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package chaincode
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	discovery2 "github.com/hyperledger/fabric-protos-go/discovery"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// DefaultDiscoveryTTL is the time the discovery results are cached for, if not configured otherwise
+const DefaultDiscoveryTTL = 5 * time.Minute
+
+type discoveryEntry struct {
+	descriptors []*discovery2.EndorsementDescriptor
+	expiry      time.Time
+}
+
+// DiscoveryCache caches the endorsement descriptors returned by the discovery service, by chaincode
+type DiscoveryCache struct {
+	ttl     time.Duration
+	lock    sync.RWMutex
+	entries map[string]*discoveryEntry
+}
+
+// NewDiscoveryCache returns a cache whose entries expire after the passed time to live.
+// If ttl is not positive, DefaultDiscoveryTTL is used.
+func NewDiscoveryCache(ttl time.Duration) *DiscoveryCache {
+	if ttl <= 0 {
+		ttl = DefaultDiscoveryTTL
+	}
+	return &DiscoveryCache{ttl: ttl, entries: map[string]*discoveryEntry{}}
+}
+
+// Get returns the endorsement descriptors of the passed chaincode, if they have not expired yet
+func (c *DiscoveryCache) Get(chaincode string) ([]*discovery2.EndorsementDescriptor, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	entry, ok := c.entries[chaincode]
+	if !ok || time.Now().After(entry.expiry) {
+		return nil, false
+	}
+	return entry.descriptors, true
+}
+
+// Put stores the endorsement descriptors of the passed chaincode
+func (c *DiscoveryCache) Put(chaincode string, descriptors []*discovery2.EndorsementDescriptor) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries[chaincode] = &discoveryEntry{descriptors: descriptors, expiry: time.Now().Add(c.ttl)}
+}
+
+// Invalidate removes the endorsement descriptors of the passed chaincode
+func (c *DiscoveryCache) Invalidate(chaincode string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, chaincode)
+}
+
+// latencies keeps track of the time the peers take to answer endorsement requests
+type latencies struct {
+	lock sync.RWMutex
+	avg  map[string]time.Duration
+}
+
+var peerLatencies = &latencies{avg: map[string]time.Duration{}}
+
+// observe updates the moving average of the latency of the passed peer
+func (l *latencies) observe(peer string, d time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	avg, ok := l.avg[peer]
+	if !ok {
+		l.avg[peer] = d
+		return
+	}
+	l.avg[peer] = (3*avg + d) / 4
+}
+
+func (l *latencies) get(peer string) (time.Duration, bool) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	avg, ok := l.avg[peer]
+	return avg, ok
+}
+
+// selector chooses the endorsers of a layout
+type selector struct {
+	// excluded are the peers that failed
+	excluded map[string]error
+	// filter is true if the groups can contribute with less peers than required
+	filter    bool
+	latencies *latencies
+	accept    func(id view.Identity) (bool, error)
+	prefer    func(id view.Identity) (bool, error)
+}
+
+type candidate struct {
+	id        view.Identity
+	preferred bool
+	latency   time.Duration
+	measured  bool
+}
+
+// selectLayout returns the endorsers of the first layout of the descriptor that can be satisfied.
+// The peers of each group are ranked: preferred ones first, then the ones that answered faster.
+// Peers with no latency measured come last, in the order of the discovery service.
+func (s *selector) selectLayout(descriptor *discovery2.EndorsementDescriptor) ([]view.Identity, error) {
+	for _, layout := range descriptor.Layouts {
+		groups := make([]string, 0, len(layout.QuantitiesByGroup))
+		for group := range layout.QuantitiesByGroup {
+			groups = append(groups, group)
+		}
+		sort.Strings(groups)
+
+		var endorsers []view.Identity
+		satisfied := true
+		for _, group := range groups {
+			candidates, err := s.candidates(descriptor.EndorsersByGroups[group])
+			if err != nil {
+				return nil, err
+			}
+			q := int(layout.QuantitiesByGroup[group])
+			if len(candidates) < q {
+				if !s.filter {
+					satisfied = false
+					break
+				}
+				q = len(candidates)
+			}
+			for _, c := range candidates[:q] {
+				endorsers = append(endorsers, c.id)
+			}
+		}
+		if satisfied && len(endorsers) != 0 {
+			return endorsers, nil
+		}
+	}
+	if len(s.excluded) != 0 {
+		return nil, errors.Errorf("no layout can be satisfied without the peers that failed: %v", s.excludedErrors())
+	}
+	return nil, errors.New("no layout can be satisfied")
+}
+
+func (s *selector) candidates(peers *discovery2.Peers) ([]*candidate, error) {
+	if peers == nil {
+		return nil, nil
+	}
+	var candidates []*candidate
+	for _, peer := range peers.Peers {
+		id := view.Identity(peer.Identity)
+		if _, failed := s.excluded[id.UniqueID()]; failed {
+			continue
+		}
+		ok, err := s.accept(id)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		preferred, err := s.prefer(id)
+		if err != nil {
+			return nil, err
+		}
+		latency, measured := s.latencies.get(id.UniqueID())
+		candidates = append(candidates, &candidate{id: id, preferred: preferred, latency: latency, measured: measured})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.preferred != cj.preferred {
+			return ci.preferred
+		}
+		if ci.measured != cj.measured {
+			return ci.measured
+		}
+		return ci.latency < cj.latency
+	})
+	return candidates, nil
+}
+
+func (s *selector) excludedErrors() []string {
+	var res []string
+	for _, err := range s.excluded {
+		res = append(res, err.Error())
+	}
+	sort.Strings(res)
+	return res
+}
+
+// checkResponses checks that the passed proposal responses are successful and carry the same results,
+// so that they can be assembled into a valid transaction.
+func checkResponses(endorsers []string, responses []*pb.ProposalResponse) error {
+	for i, response := range responses {
+		if response == nil || response.Response == nil {
+			return errors.Errorf("received nil proposal response from [%s]", endorsers[i])
+		}
+		if response.Response.Status < 200 || response.Response.Status >= 400 {
+			return errors.Errorf("proposal response from [%s] not successful, status [%d]: [%s]", endorsers[i], response.Response.Status, response.Response.Message)
+		}
+		if i == 0 {
+			continue
+		}
+		if !bytes.Equal(responses[0].Payload, response.Payload) {
+			return errors.Errorf("inconsistent proposal responses, [%s] and [%s] returned different results", endorsers[0], endorsers[i])
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
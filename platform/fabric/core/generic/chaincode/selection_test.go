@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package chaincode
+
+import (
+	"testing"
+	"time"
+
+	discovery2 "github.com/hyperledger/fabric-protos-go/discovery"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+func peers(ids ...string) *discovery2.Peers {
+	res := &discovery2.Peers{}
+	for _, id := range ids {
+		res.Peers = append(res.Peers, &discovery2.Peer{Identity: []byte(id)})
+	}
+	return res
+}
+
+func TestSelectLayout(t *testing.T) {
+	descriptor := &discovery2.EndorsementDescriptor{
+		Chaincode: "cc",
+		EndorsersByGroups: map[string]*discovery2.Peers{
+			"G0": peers("org1.peer0", "org1.peer1"),
+			"G1": peers("org2.peer0", "org2.peer1"),
+			"G2": peers("org3.peer0"),
+		},
+		Layouts: []*discovery2.Layout{
+			{QuantitiesByGroup: map[string]uint32{"G0": 1, "G1": 1}},
+			{QuantitiesByGroup: map[string]uint32{"G0": 1, "G2": 1}},
+		},
+	}
+	mspOf := func(id view.Identity) string {
+		return string(id)[:4]
+	}
+	newSelector := func(excluded map[string]error, preferred ...string) *selector {
+		return &selector{
+			excluded:  excluded,
+			latencies: &latencies{avg: map[string]time.Duration{}},
+			accept:    func(id view.Identity) (bool, error) { return true, nil },
+			prefer: func(id view.Identity) (bool, error) {
+				return contains(preferred, mspOf(id)), nil
+			},
+		}
+	}
+
+	// the first layout, the first peers
+	endorsers, err := newSelector(nil).selectLayout(descriptor)
+	assert.NoError(t, err)
+	assert.Equal(t, []view.Identity{view.Identity("org1.peer0"), view.Identity("org2.peer0")}, endorsers)
+
+	// the faster peers
+	s := newSelector(nil)
+	s.latencies.observe(view.Identity("org1.peer0").UniqueID(), 2*time.Second)
+	s.latencies.observe(view.Identity("org1.peer1").UniqueID(), time.Second)
+	endorsers, err = s.selectLayout(descriptor)
+	assert.NoError(t, err)
+	assert.Equal(t, []view.Identity{view.Identity("org1.peer1"), view.Identity("org2.peer0")}, endorsers)
+
+	// a peer of group G1 failed, another one replaces it
+	endorsers, err = newSelector(map[string]error{view.Identity("org2.peer0").UniqueID(): errors.New("down")}).selectLayout(descriptor)
+	assert.NoError(t, err)
+	assert.Equal(t, []view.Identity{view.Identity("org1.peer0"), view.Identity("org2.peer1")}, endorsers)
+
+	// all the peers of group G1 failed, the second layout is chosen
+	endorsers, err = newSelector(map[string]error{
+		view.Identity("org2.peer0").UniqueID(): errors.New("down"),
+		view.Identity("org2.peer1").UniqueID(): errors.New("down"),
+	}).selectLayout(descriptor)
+	assert.NoError(t, err)
+	assert.Equal(t, []view.Identity{view.Identity("org1.peer0"), view.Identity("org3.peer0")}, endorsers)
+
+	// no layout left
+	_, err = newSelector(map[string]error{
+		view.Identity("org2.peer0").UniqueID(): errors.New("down"),
+		view.Identity("org2.peer1").UniqueID(): errors.New("down"),
+		view.Identity("org3.peer0").UniqueID(): errors.New("down"),
+	}).selectLayout(descriptor)
+	assert.EqualError(t, err, "no layout can be satisfied without the peers that failed: [down down down]")
+
+	// preferred MSPs come first, whatever their latency
+	s = newSelector(nil, "org1")
+	s.latencies.observe(view.Identity("org1.peer1").UniqueID(), time.Second)
+	descriptor.EndorsersByGroups["G0"] = peers("org4.peer0", "org1.peer0", "org1.peer1")
+	endorsers, err = s.selectLayout(descriptor)
+	assert.NoError(t, err)
+	assert.Equal(t, []view.Identity{view.Identity("org1.peer1"), view.Identity("org2.peer0")}, endorsers)
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	cache := NewDiscoveryCache(50 * time.Millisecond)
+	descriptors := []*discovery2.EndorsementDescriptor{{Chaincode: "cc"}}
+
+	_, ok := cache.Get("cc")
+	assert.False(t, ok)
+	cache.Put("cc", descriptors)
+	res, ok := cache.Get("cc")
+	assert.True(t, ok)
+	assert.Equal(t, descriptors, res)
+	time.Sleep(100 * time.Millisecond)
+	_, ok = cache.Get("cc")
+	assert.False(t, ok)
+
+	cache.Put("cc", descriptors)
+	cache.Invalidate("cc")
+	_, ok = cache.Get("cc")
+	assert.False(t, ok)
+}
+
+func TestCheckResponses(t *testing.T) {
+	ok := func(payload string) *pb.ProposalResponse {
+		return &pb.ProposalResponse{Payload: []byte(payload), Response: &pb.Response{Status: 200}}
+	}
+	names := []string{"peer0", "peer1"}
+
+	assert.NoError(t, checkResponses(names, []*pb.ProposalResponse{ok("a"), ok("a")}))
+	assert.EqualError(t, checkResponses(names, []*pb.ProposalResponse{ok("a"), ok("b")}), "inconsistent proposal responses, [peer0] and [peer1] returned different results")
+	assert.EqualError(t, checkResponses(names, []*pb.ProposalResponse{ok("a"), {Response: &pb.Response{Status: 500, Message: "boom"}}}), "proposal response from [peer1] not successful, status [500]: [boom]")
+}
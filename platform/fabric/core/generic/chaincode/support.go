@@ -48,4 +48,7 @@ type Channel interface {
 	IsFinal(txID string) error
 
 	MSPManager() api.MSPManager
+
+	// DiscoveryCache returns the cache of the results of the discovery service for this channel
+	DiscoveryCache() *DiscoveryCache
 }
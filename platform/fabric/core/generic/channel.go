@@ -21,9 +21,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/chaincode"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/committer"
 	delivery2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/delivery"
 	finality2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/finality"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/ordering"
 	peer2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/peer"
 	common2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/peer/common"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/rwset"
@@ -33,6 +35,7 @@ import (
 	api2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -45,8 +48,15 @@ const (
 	GetBlockByNumber   string = "GetBlockByNumber"
 	GetTransactionByID string = "GetTransactionByID"
 	GetBlockByTxID     string = "GetBlockByTxID"
+	GetChainInfo       string = "GetChainInfo"
 )
 
+// Delivery is the service that delivers the blocks of a channel to its committer
+type Delivery interface {
+	Start()
+	Stop()
+}
+
 type channel struct {
 	sp                 view2.ServiceProvider
 	config             *Config
@@ -59,6 +69,9 @@ type channel struct {
 	envelopeService    api.EnvelopeService
 	transactionService api.EndorserTransactionService
 	metadataService    api.MetadataService
+	deliveryService    Delivery
+	discoveryCache     *chaincode.DiscoveryCache
+	historyPruner      *historyPruner
 	api.TXIDStore
 
 	// applyLock is used to serialize calls to CommitConfig and bundle update processing.
@@ -72,7 +85,12 @@ type channel struct {
 func newChannel(network *network, name string, quiet bool) (*channel, error) {
 	sp := network.sp
 	// Vault
-	v, txIDStore, err := NewVault(network.config, name, sp)
+	v, txIDStore, err := NewVault(network.config, network.Name(), name, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	pruner, err := newHistoryPruner(network.config, name, v)
 	if err != nil {
 		return nil, err
 	}
@@ -99,8 +117,12 @@ func newChannel(network *network, name string, quiet bool) (*channel, error) {
 		return nil, err
 	}
 
-	// Delivery
-	deliveryService, err := delivery2.New(name, sp, network, committerInst, txIDStore, waitForEventTimeout)
+	// Delivery, the tracker of the transactions broadcast learns from the blocks which ones made it
+	var blockCommitter delivery2.Committer = committerInst
+	if tracker := network.ordering.Tracker(name); tracker != nil {
+		blockCommitter = &trackingCommitter{Committer: committerInst, tracker: tracker}
+	}
+	deliveryService, err := delivery2.New(name, sp, network, blockCommitter, &deliveryVault{txIDStore: txIDStore, vault: v}, waitForEventTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -123,17 +145,52 @@ func newChannel(network *network, name string, quiet bool) (*channel, error) {
 		envelopeService:    transaction.NewEnvelopeService(sp, network.Name(), name),
 		transactionService: transaction.NewEndorseTransactionService(sp, network.Name(), name),
 		metadataService:    transaction.NewMetadataService(sp, network.Name(), name),
+		deliveryService:    deliveryService,
+		discoveryCache:     chaincode.NewDiscoveryCache(network.config.DiscoveryTTL()),
+		historyPruner:      pruner,
 	}
 	if err := c.init(); err != nil {
 		return nil, errors.WithMessagef(err, "failed initializing channel [%s]", name)
 	}
 
+	// Health checkers
+	component := fmt.Sprintf("fabric.%s.%s", network.Name(), name)
+	if err := operations.RegisterChecker(sp, component+".vault", v); err != nil {
+		return nil, errors.WithMessagef(err, "failed registering vault health checker for channel [%s]", name)
+	}
+	if err := operations.RegisterChecker(sp, component+".delivery", deliveryService); err != nil {
+		return nil, errors.WithMessagef(err, "failed registering delivery health checker for channel [%s]", name)
+	}
+
 	// Start delivery
 	deliveryService.Start()
+	if pruner != nil {
+		pruner.Start()
+	}
 
 	return c, nil
 }
 
+// Close stops the delivery service first, so that no block is being committed, and then closes the vault
+func (c *channel) Close() error {
+	c.deliveryService.Stop()
+	if c.historyPruner != nil {
+		c.historyPruner.Stop()
+	}
+	return c.vault.Close()
+}
+
+// trackingCommitter commits the blocks and then passes them to the tracker of the transactions broadcast
+type trackingCommitter struct {
+	delivery2.Committer
+	tracker *ordering.Tracker
+}
+
+func (c *trackingCommitter) Commit(block *peer.FilteredBlock) {
+	c.Committer.Commit(block)
+	c.tracker.Commit(block)
+}
+
 func (c *channel) Name() string {
 	return c.name
 }
@@ -254,31 +311,27 @@ func (c *channel) GetClientConfig(tlsRootCerts [][]byte) (*grpc.ClientConfig, st
 }
 
 func (c *channel) GetTransactionByID(txID string) (api.ProcessedTransaction, error) {
-	res, err := c.Chaincode("qscc").NewInvocation(api.ChaincodeQuery, GetTransactionByID, c.name, txID).WithSignerIdentity(
-		c.network.LocalMembership().DefaultIdentity(),
-	).WithEndorsersByConnConfig(c.network.Peers()...).Call()
+	res, err := c.queryLedger(GetTransactionByID, txID)
 	if err != nil {
 		return nil, err
 	}
 
 	pt := &peer.ProcessedTransaction{}
-	err = proto.Unmarshal(res.([]byte), pt)
+	err = proto.Unmarshal(res, pt)
 	if err != nil {
 		return nil, err
 	}
-	return newProcessedTransaction(pt)
+	return newProcessedTransaction(pt.TransactionEnvelope, pt.ValidationCode)
 }
 
 func (c *channel) GetBlockNumberByTxID(txID string) (uint64, error) {
-	res, err := c.Chaincode("qscc").NewInvocation(api.ChaincodeQuery, GetBlockByTxID, c.name, txID).WithSignerIdentity(
-		c.network.LocalMembership().DefaultIdentity(),
-	).WithEndorsersByConnConfig(c.network.Peers()...).Call()
+	res, err := c.queryLedger(GetBlockByTxID, txID)
 	if err != nil {
 		return 0, err
 	}
 
 	block := &common.Block{}
-	err = proto.Unmarshal(res.([]byte), block)
+	err = proto.Unmarshal(res, block)
 	if err != nil {
 		return 0, err
 	}
@@ -384,28 +437,3 @@ func newPeerClientForClientConfig(address, override string, clientConfig grpc.Cl
 			Sn:         override}}
 	return pClient, nil
 }
-
-type processedTransaction struct {
-	pt *peer.ProcessedTransaction
-	ue *transaction.UnpackedEnvelope
-}
-
-func newProcessedTransaction(pt *peer.ProcessedTransaction) (*processedTransaction, error) {
-	ue, err := transaction.UnpackEnvelope(pt.TransactionEnvelope)
-	if err != nil {
-		return nil, err
-	}
-	return &processedTransaction{pt: pt, ue: ue}, nil
-}
-
-func (p *processedTransaction) Results() []byte {
-	return p.ue.Results
-}
-
-func (p *processedTransaction) IsValid() bool {
-	return p.pt.ValidationCode == int32(peer.TxValidationCode_VALID)
-}
-
-func (p *processedTransaction) ValidationCode() int32 {
-	return p.pt.ValidationCode
-}
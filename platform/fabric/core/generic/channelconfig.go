@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package generic
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/configtx"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// configPollingInterval is how often the sequence of the config is checked while waiting for an update to be committed
+const configPollingInterval = 100 * time.Millisecond
+
+// Config returns the current configuration of the channel
+func (c *channel) Config() (*api.ChannelConfig, error) {
+	config, err := c.configProto()
+	if err != nil {
+		return nil, err
+	}
+	return configtx.Inspect(config)
+}
+
+// RawConfig returns the current configuration of the channel as a marshalled common.Config
+func (c *channel) RawConfig() ([]byte, error) {
+	config, err := c.configProto()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := proto.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling config")
+	}
+	return raw, nil
+}
+
+// NewConfigUpdate returns the marshalled ConfigUpdate turning the current configuration into the passed one
+func (c *channel) NewConfigUpdate(updated []byte) ([]byte, error) {
+	original, err := c.configProto()
+	if err != nil {
+		return nil, err
+	}
+	config := &cb.Config{}
+	if err := proto.Unmarshal(updated, config); err != nil {
+		return nil, errors.Wrap(err, "invalid updated config")
+	}
+	update, err := configtx.NewConfigUpdate(c.name, original, config)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed computing config update for [%s]", c.name)
+	}
+	raw, err := proto.Marshal(update)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling config update")
+	}
+	return raw, nil
+}
+
+// SignConfigUpdate returns the marshalled ConfigSignature of the passed update by the passed identity
+func (c *channel) SignConfigUpdate(update []byte, id view.Identity) ([]byte, error) {
+	if _, err := configtx.UnmarshalConfigUpdate(c.name, update); err != nil {
+		return nil, err
+	}
+	signer, err := c.network.SigService().GetSigningIdentity(id)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting signer for [%s]", id)
+	}
+	signature, err := configtx.SignConfigUpdate(update, signer)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := proto.Marshal(signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling config signature")
+	}
+	return raw, nil
+}
+
+// VerifyConfigSignature checks that the passed marshalled ConfigSignature is a valid signature of the passed
+// update by a member of the channel, and returns the signer
+func (c *channel) VerifyConfigSignature(update []byte, signature []byte) (view.Identity, error) {
+	configSignature := &cb.ConfigSignature{}
+	if err := proto.Unmarshal(signature, configSignature); err != nil {
+		return nil, errors.Wrap(err, "invalid config signature")
+	}
+	creator, data, err := configtx.SignedData(update, configSignature)
+	if err != nil {
+		return nil, err
+	}
+	id, err := c.MSPManager().DeserializeIdentity(creator)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed deserializing the signer of the config signature")
+	}
+	if err := id.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid signer of the config signature")
+	}
+	if err := id.Verify(data, configSignature.Signature); err != nil {
+		return nil, errors.Wrap(err, "invalid config signature")
+	}
+	return creator, nil
+}
+
+// SubmitConfigUpdate sends the passed update to the ordering service and waits for the new configuration
+// to be committed. The signatures must satisfy the modification policies of the elements the update changes.
+func (c *channel) SubmitConfigUpdate(update []byte, signatures [][]byte, submitter view.Identity) error {
+	if _, err := configtx.UnmarshalConfigUpdate(c.name, update); err != nil {
+		return err
+	}
+	var configSignatures []*cb.ConfigSignature
+	for _, raw := range signatures {
+		signature := &cb.ConfigSignature{}
+		if err := proto.Unmarshal(raw, signature); err != nil {
+			return errors.Wrap(err, "invalid config signature")
+		}
+		configSignatures = append(configSignatures, signature)
+	}
+	signer, err := c.network.SigService().GetSigningIdentity(submitter)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting signer for [%s]", submitter)
+	}
+	env, err := configtx.NewConfigUpdateEnvelope(c.name, update, configSignatures, signer)
+	if err != nil {
+		return err
+	}
+
+	config, err := c.configProto()
+	if err != nil {
+		return err
+	}
+	if err := c.network.Broadcast(env); err != nil {
+		return errors.WithMessagef(err, "failed submitting config update for [%s]", c.name)
+	}
+
+	// the config block gets to the vault through the delivery service
+	deadline := time.Now().Add(waitForEventTimeout)
+	for {
+		current, err := c.configProto()
+		if err != nil {
+			return err
+		}
+		if current.Sequence > config.Sequence {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timeout waiting for config update of [%s] to be committed, still at sequence [%d]", c.name, current.Sequence)
+		}
+		time.Sleep(configPollingInterval)
+	}
+}
+
+func (c *channel) configProto() (*cb.Config, error) {
+	res := c.Resources()
+	if res == nil {
+		return nil, errors.Errorf("no config available for channel [%s]", c.name)
+	}
+	return res.ConfigtxValidator().ConfigProto(), nil
+}
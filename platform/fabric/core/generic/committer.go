@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
 )
 
 func (c *channel) Status(txid string) (api.ValidationCode, []string, error) {
@@ -90,6 +91,50 @@ func (c *channel) CommitTX(txid string, block uint64, indexInBlock int) (err err
 	}
 }
 
+func (c *channel) CommitDiscardedTX(txid string, block uint64, indexInBlock int) error {
+	logger.Debugf("Committing discarded transaction [%s,%d,%d]", txid, block, indexInBlock)
+
+	vc, _, err := c.Status(txid)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting tx's status in state db [%s]", txid)
+	}
+	if vc != api.Invalid {
+		return errors.Errorf("[%s] has not been discarded, its status is [%d]", txid, vc)
+	}
+
+	var raw []byte
+	if c.envelopeService.Exists(txid) {
+		raw, err = c.envelopeService.LoadEnvelope(txid)
+		if err != nil {
+			return errors.WithMessagef(err, "failed loading envelope of [%s]", txid)
+		}
+	} else {
+		pt, err := c.GetTransactionByID(txid)
+		if err != nil {
+			return errors.WithMessagef(err, "failed fetching tx [%s]", txid)
+		}
+		raw = pt.Envelope()
+	}
+	ue, err := transaction.UnpackEnvelopeFromBytes(raw)
+	if err != nil {
+		return errors.WithMessagef(err, "failed unpacking envelope of [%s]", txid)
+	}
+
+	rws, err := c.vault.GetRWSet(txid, ue.Results)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting rwset for tx [%s]", txid)
+	}
+	rws.Done()
+	return c.vault.CommitTX(txid, block, indexInBlock)
+}
+
+// CommitLocalTX commits the passed transaction to the vault only. The transaction never reaches Fabric,
+// its namespaces are not post-processed.
+func (c *channel) CommitLocalTX(txid string) (uint64, error) {
+	logger.Debugf("Committing local transaction [%s]", txid)
+	return c.vault.CommitLocalTX(txid)
+}
+
 func (c *channel) commitUnknown(txid string, block uint64, indexInBlock int) error {
 	if len(c.processNamespaces) == 0 {
 		// This should be ignored
@@ -41,9 +41,12 @@ func (c *committer) handleEndorserTransaction(block *pb.FilteredBlock, transacti
 			// Nothing to commit
 			return
 		case api.Invalid:
-			logger.Debugf("transaction [%s] in block [%d] is marked as invalid, skipping", tx.Txid, block.Number)
-			// Nothing to commit
-			return
+			// the tracker of the transactions broadcast might have given up on it before it appeared
+			logger.Warnf("transaction [%s] in block [%d] is marked as invalid but for fabric is valid, committing it", tx.Txid, block.Number)
+			if err := committer.CommitDiscardedTX(event.Txid, event.Block, event.IndexInBlock); err != nil {
+				logger.Errorf("failed committing discarded transaction [%s]: [%s]", tx.Txid, err)
+				event.Err = errors.WithMessagef(err, "transaction [%s] is valid for fabric but could not be committed", tx.Txid)
+			}
 		default:
 			err = committer.CommitTX(event.Txid, event.Block, event.IndexInBlock)
 			if err != nil {
@@ -6,8 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 package generic
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 )
@@ -47,6 +50,11 @@ func (c *Config) ClientConnTimeout() time.Duration {
 	return c.configService.GetDuration("fabric.client.connTimeout")
 }
 
+// DiscoveryTTL returns the time the results of the discovery service are cached for
+func (c *Config) DiscoveryTTL() time.Duration {
+	return c.configService.GetDuration("fabric.discovery.ttl")
+}
+
 func (c *Config) TLSClientKeyFile() string {
 	return c.configService.GetPath("fabric.tls.clientKey.file")
 }
@@ -91,6 +99,45 @@ func (c *Config) VaultPersistenceOpts(opts interface{}) error {
 	return c.configService.UnmarshalKey("fabric.vault.persistence.opts", opts)
 }
 
+// VaultHistoryEnabled returns true if the vault records the history of the keys
+func (c *Config) VaultHistoryEnabled() bool {
+	return c.configService.GetBool("fabric.vault.history.enabled")
+}
+
+// VaultHistoryRetention returns the number of blocks the history is kept for, 0 if it is kept forever
+func (c *Config) VaultHistoryRetention() (uint64, error) {
+	return c.getUint("fabric.vault.history.retention")
+}
+
+// VaultHistoryPruneInterval returns how often the history older than the retention is pruned
+func (c *Config) VaultHistoryPruneInterval() time.Duration {
+	return c.configService.GetDuration("fabric.vault.history.pruneInterval")
+}
+
+// VaultLocalNamespaces returns the namespaces the transactions committed locally, without going through Fabric, can write
+func (c *Config) VaultLocalNamespaces() ([]string, error) {
+	var res []string
+	if err := c.configService.UnmarshalKey("fabric.vault.local.namespaces", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// OrderingTrackerEnabled returns true if the transactions broadcast are tracked until they are committed
+func (c *Config) OrderingTrackerEnabled() bool {
+	return c.configService.GetBool("fabric.ordering.tracker.enabled")
+}
+
+// OrderingTrackerWaitBlocks returns the number of blocks a transaction has to appear within before being resubmitted, 0 if not set
+func (c *Config) OrderingTrackerWaitBlocks() (uint64, error) {
+	return c.getUint("fabric.ordering.tracker.waitBlocks")
+}
+
+// OrderingTrackerMaxResubmissions returns the number of times a transaction is resubmitted before being discarded, 0 if not set
+func (c *Config) OrderingTrackerMaxResubmissions() (uint64, error) {
+	return c.getUint("fabric.ordering.tracker.maxResubmissions")
+}
+
 func (c *Config) MSPConfigPath() string {
 	return c.configService.GetPath("fabric.mspConfigPath")
 }
@@ -113,3 +160,15 @@ func (c *Config) LocalMSPType() string {
 func (c *Config) TranslatePath(path string) string {
 	return c.configService.TranslatePath(path)
 }
+
+func (c *Config) getUint(key string) (uint64, error) {
+	v := c.configService.GetString(key)
+	if len(v) == 0 {
+		return 0, nil
+	}
+	res, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value [%s] for [%s]", v, key)
+	}
+	return res, nil
+}
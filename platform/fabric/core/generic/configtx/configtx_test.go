@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+)
+
+func value(t *testing.T, msg proto.Message) *cb.ConfigValue {
+	raw, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+	return &cb.ConfigValue{Value: raw, ModPolicy: channelconfig.AdminsPolicyKey}
+}
+
+func mspValue(t *testing.T, mspID string) *cb.ConfigValue {
+	raw, err := proto.Marshal(&mspproto.FabricMSPConfig{Name: mspID})
+	assert.NoError(t, err)
+	return value(t, &mspproto.MSPConfig{Config: raw})
+}
+
+func implicitMeta(t *testing.T, rule cb.ImplicitMetaPolicy_Rule, sub string) *cb.ConfigPolicy {
+	raw, err := proto.Marshal(&cb.ImplicitMetaPolicy{Rule: rule, SubPolicy: sub})
+	assert.NoError(t, err)
+	return &cb.ConfigPolicy{ModPolicy: channelconfig.AdminsPolicyKey, Policy: &cb.Policy{Type: int32(cb.Policy_IMPLICIT_META), Value: raw}}
+}
+
+func signature(t *testing.T, rule string) *cb.ConfigPolicy {
+	envelope, err := policydsl.FromString(rule)
+	assert.NoError(t, err)
+	raw, err := proto.Marshal(envelope)
+	assert.NoError(t, err)
+	return &cb.ConfigPolicy{ModPolicy: channelconfig.AdminsPolicyKey, Policy: &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: raw}}
+}
+
+func testConfig(t *testing.T) *cb.Config {
+	org1 := protoutil.NewConfigGroup()
+	org1.Values[channelconfig.MSPKey] = mspValue(t, "Org1MSP")
+	org1.Values[channelconfig.AnchorPeersKey] = value(t, &pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org1", Port: 7051}}})
+	org1.Policies[channelconfig.AdminsPolicyKey] = signature(t, "OR('Org1MSP.admin')")
+	org2 := protoutil.NewConfigGroup()
+	org2.Values[channelconfig.MSPKey] = mspValue(t, "Org2MSP")
+
+	application := protoutil.NewConfigGroup()
+	application.Groups["Org2"] = org2
+	application.Groups["Org1"] = org1
+	application.Values[channelconfig.CapabilitiesKey] = value(t, &cb.Capabilities{Capabilities: map[string]*cb.Capability{"V2_0": {}}})
+	application.Policies[channelconfig.AdminsPolicyKey] = implicitMeta(t, cb.ImplicitMetaPolicy_MAJORITY, channelconfig.AdminsPolicyKey)
+
+	ordererOrg := protoutil.NewConfigGroup()
+	ordererOrg.Values[channelconfig.MSPKey] = mspValue(t, "OrdererMSP")
+	ordererOrg.Values[channelconfig.EndpointsKey] = value(t, &cb.OrdererAddresses{Addresses: []string{"orderer:7050"}})
+	ordererGroup := protoutil.NewConfigGroup()
+	ordererGroup.Groups["OrdererOrg"] = ordererOrg
+	ordererGroup.Values[channelconfig.ConsensusTypeKey] = value(t, &orderer.ConsensusType{Type: "etcdraft"})
+
+	root := protoutil.NewConfigGroup()
+	root.Groups[channelconfig.ApplicationGroupKey] = application
+	root.Groups[channelconfig.OrdererGroupKey] = ordererGroup
+	root.Values[channelconfig.CapabilitiesKey] = value(t, &cb.Capabilities{Capabilities: map[string]*cb.Capability{"V2_0": {}}})
+	return &cb.Config{Sequence: 3, ChannelGroup: root}
+}
+
+func TestInspect(t *testing.T) {
+	config, err := Inspect(testConfig(t))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), config.Sequence)
+	assert.Equal(t, []*api.Organization{
+		{Name: "Org1", MSPID: "Org1MSP", AnchorPeers: []string{"peer0.org1:7051"}},
+		{Name: "Org2", MSPID: "Org2MSP"},
+	}, config.Organizations)
+	assert.Equal(t, &api.OrdererConfig{
+		ConsensusType: "etcdraft",
+		Organizations: []*api.OrdererOrganization{{Name: "OrdererOrg", MSPID: "OrdererMSP", Endpoints: []string{"orderer:7050"}}},
+	}, config.Orderer)
+	assert.Equal(t, map[string][]string{"Channel": {"V2_0"}, "Application": {"V2_0"}}, config.Capabilities)
+	assert.Equal(t, map[string]*api.Policy{
+		"/Channel/Application/Admins":      {Type: "ImplicitMeta", Rule: "MAJORITY Admins", ModPolicy: "Admins"},
+		"/Channel/Application/Org1/Admins": {Type: "Signature", Rule: "OR('Org1MSP.admin')", ModPolicy: "Admins"},
+	}, config.Policies)
+}
+
+func TestPolicyRule(t *testing.T) {
+	for _, rule := range []string{
+		"OR('Org1MSP.member')",
+		"AND('Org1MSP.admin','Org2MSP.peer')",
+		"OutOf(2,'Org1MSP.member','Org2MSP.member','Org3MSP.client')",
+		"OR(AND('Org1MSP.member','Org2MSP.member'),'Org3MSP.orderer')",
+	} {
+		typ, res, err := PolicyRule(signature(t, rule).Policy)
+		assert.NoError(t, err)
+		assert.Equal(t, "Signature", typ)
+		assert.Equal(t, rule, res)
+	}
+}
+
+func TestNewConfigUpdate(t *testing.T) {
+	original := testConfig(t)
+	_, err := NewConfigUpdate("ch", original, testConfig(t))
+	assert.EqualError(t, err, "no differences detected between original and updated config")
+
+	// add Org3 to the application group
+	updated := testConfig(t)
+	org3 := protoutil.NewConfigGroup()
+	org3.Values[channelconfig.MSPKey] = mspValue(t, "Org3MSP")
+	updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups["Org3"] = org3
+	update, err := NewConfigUpdate("ch", original, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "ch", update.ChannelId)
+	application := update.WriteSet.Groups[channelconfig.ApplicationGroupKey]
+	assert.Equal(t, uint64(1), application.Version)
+	assert.Contains(t, application.Groups, "Org3")
+	// the unchanged members of a group whose membership changes are read and written at their version
+	assert.Equal(t, uint64(0), application.Groups["Org1"].Version)
+	assert.Nil(t, application.Groups["Org1"].Values)
+	assert.Equal(t, uint64(0), update.ReadSet.Groups[channelconfig.ApplicationGroupKey].Version)
+
+	// change the anchor peers of Org1, only the changed value is written
+	updated = testConfig(t)
+	updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups["Org1"].Values[channelconfig.AnchorPeersKey] = value(t, &pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer1.org1", Port: 7051}}})
+	update, err = NewConfigUpdate("ch", original, updated)
+	assert.NoError(t, err)
+	application = update.WriteSet.Groups[channelconfig.ApplicationGroupKey]
+	assert.Equal(t, uint64(0), application.Version)
+	assert.NotContains(t, application.Groups, "Org2")
+	org1 := application.Groups["Org1"]
+	assert.Equal(t, uint64(0), org1.Version)
+	assert.Len(t, org1.Values, 1)
+	assert.Equal(t, uint64(1), org1.Values[channelconfig.AnchorPeersKey].Version)
+	assert.Empty(t, update.ReadSet.Groups[channelconfig.ApplicationGroupKey].Groups["Org1"].Values)
+
+	raw, err := proto.Marshal(update)
+	assert.NoError(t, err)
+	_, err = UnmarshalConfigUpdate("ch", raw)
+	assert.NoError(t, err)
+	_, err = UnmarshalConfigUpdate("other", raw)
+	assert.EqualError(t, err, "config update for channel [ch], expected [other]")
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(message []byte) ([]byte, error) {
+	return append([]byte("signed:"), message...), nil
+}
+
+func (fakeSigner) Serialize() ([]byte, error) {
+	return []byte("alice"), nil
+}
+
+func TestSignedData(t *testing.T) {
+	update := []byte("update")
+	signature, err := SignConfigUpdate(update, fakeSigner{})
+	assert.NoError(t, err)
+	creator, data, err := SignedData(update, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("alice"), creator)
+	assert.Equal(t, append([]byte("signed:"), data...), signature.Signature)
+
+	_, _, err = SignedData(update, &cb.ConfigSignature{SignatureHeader: []byte("invalid")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,263 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package configtx
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+)
+
+// Inspect returns the typed view of the passed channel configuration
+func Inspect(config *cb.Config) (*api.ChannelConfig, error) {
+	root := config.GetChannelGroup()
+	if root == nil {
+		return nil, errors.New("no channel group in config")
+	}
+
+	res := &api.ChannelConfig{
+		Sequence:     config.Sequence,
+		Capabilities: map[string][]string{},
+		Policies:     map[string]*api.Policy{},
+	}
+	groups := map[string]*cb.ConfigGroup{channelconfig.ChannelGroupKey: root}
+	if application, ok := root.Groups[channelconfig.ApplicationGroupKey]; ok {
+		groups[channelconfig.ApplicationGroupKey] = application
+		orgs, err := applicationOrganizations(application)
+		if err != nil {
+			return nil, err
+		}
+		res.Organizations = orgs
+	}
+	if ordererGroup, ok := root.Groups[channelconfig.OrdererGroupKey]; ok {
+		groups[channelconfig.OrdererGroupKey] = ordererGroup
+		oc, err := ordererConfig(root, ordererGroup)
+		if err != nil {
+			return nil, err
+		}
+		res.Orderer = oc
+	}
+	for name, group := range groups {
+		capabilities, err := capabilities(group)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid capabilities of [%s]", name)
+		}
+		if len(capabilities) != 0 {
+			res.Capabilities[name] = capabilities
+		}
+	}
+	if err := policies("/"+channelconfig.ChannelGroupKey, root, res.Policies); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func applicationOrganizations(application *cb.ConfigGroup) ([]*api.Organization, error) {
+	var res []*api.Organization
+	for name, group := range application.Groups {
+		mspID, err := mspID(group)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid organization [%s]", name)
+		}
+		org := &api.Organization{Name: name, MSPID: mspID}
+		if value, ok := group.Values[channelconfig.AnchorPeersKey]; ok {
+			anchorPeers := &pb.AnchorPeers{}
+			if err := proto.Unmarshal(value.Value, anchorPeers); err != nil {
+				return nil, errors.Wrapf(err, "invalid anchor peers of [%s]", name)
+			}
+			for _, ap := range anchorPeers.AnchorPeers {
+				org.AnchorPeers = append(org.AnchorPeers, net.JoinHostPort(ap.Host, strconv.Itoa(int(ap.Port))))
+			}
+		}
+		res = append(res, org)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+func ordererConfig(root, ordererGroup *cb.ConfigGroup) (*api.OrdererConfig, error) {
+	res := &api.OrdererConfig{}
+	if value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]; ok {
+		consensusType := &orderer.ConsensusType{}
+		if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+			return nil, errors.Wrap(err, "invalid consensus type")
+		}
+		res.ConsensusType = consensusType.Type
+	}
+	var err error
+	if res.Endpoints, err = endpoints(root, channelconfig.OrdererAddressesKey); err != nil {
+		return nil, err
+	}
+	for name, group := range ordererGroup.Groups {
+		mspID, err := mspID(group)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid orderer organization [%s]", name)
+		}
+		org := &api.OrdererOrganization{Name: name, MSPID: mspID}
+		if org.Endpoints, err = endpoints(group, channelconfig.EndpointsKey); err != nil {
+			return nil, errors.WithMessagef(err, "invalid orderer organization [%s]", name)
+		}
+		res.Organizations = append(res.Organizations, org)
+	}
+	sort.Slice(res.Organizations, func(i, j int) bool { return res.Organizations[i].Name < res.Organizations[j].Name })
+	return res, nil
+}
+
+func endpoints(group *cb.ConfigGroup, key string) ([]string, error) {
+	value, ok := group.Values[key]
+	if !ok {
+		return nil, nil
+	}
+	addresses := &cb.OrdererAddresses{}
+	if err := proto.Unmarshal(value.Value, addresses); err != nil {
+		return nil, errors.Wrapf(err, "invalid [%s]", key)
+	}
+	return addresses.Addresses, nil
+}
+
+func mspID(group *cb.ConfigGroup) (string, error) {
+	value, ok := group.Values[channelconfig.MSPKey]
+	if !ok {
+		return "", errors.New("no msp config")
+	}
+	mspConfig := &mspproto.MSPConfig{}
+	if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+		return "", errors.Wrap(err, "invalid msp config")
+	}
+	switch msp.ProviderType(mspConfig.Type) {
+	case msp.FABRIC:
+		fabricConfig := &mspproto.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return "", errors.Wrap(err, "invalid fabric msp config")
+		}
+		return fabricConfig.Name, nil
+	case msp.IDEMIX:
+		idemixConfig := &mspproto.IdemixMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, idemixConfig); err != nil {
+			return "", errors.Wrap(err, "invalid idemix msp config")
+		}
+		return idemixConfig.Name, nil
+	default:
+		return "", errors.Errorf("unsupported msp type [%d]", mspConfig.Type)
+	}
+}
+
+func capabilities(group *cb.ConfigGroup) ([]string, error) {
+	value, ok := group.Values[channelconfig.CapabilitiesKey]
+	if !ok {
+		return nil, nil
+	}
+	capabilities := &cb.Capabilities{}
+	if err := proto.Unmarshal(value.Value, capabilities); err != nil {
+		return nil, err
+	}
+	var res []string
+	for name := range capabilities.Capabilities {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// policies collects the policies of the passed group and of its subgroups, by path
+func policies(path string, group *cb.ConfigGroup, res map[string]*api.Policy) error {
+	for name, configPolicy := range group.Policies {
+		p := &api.Policy{ModPolicy: configPolicy.ModPolicy}
+		if configPolicy.Policy != nil {
+			var err error
+			p.Type, p.Rule, err = PolicyRule(configPolicy.Policy)
+			if err != nil {
+				return errors.WithMessagef(err, "invalid policy [%s/%s]", path, name)
+			}
+		}
+		res[path+"/"+name] = p
+	}
+	for name, subgroup := range group.Groups {
+		if err := policies(path+"/"+name, subgroup, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PolicyRule returns the type of the passed policy and its rule in a readable form.
+// Signature policies are written in the language policydsl parses.
+func PolicyRule(policy *cb.Policy) (string, string, error) {
+	switch cb.Policy_PolicyType(policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		implicitMeta := &cb.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMeta); err != nil {
+			return "", "", errors.Wrap(err, "invalid implicit meta policy")
+		}
+		return "ImplicitMeta", implicitMeta.Rule.String() + " " + implicitMeta.SubPolicy, nil
+	case cb.Policy_SIGNATURE:
+		envelope := &cb.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil {
+			return "", "", errors.Wrap(err, "invalid signature policy")
+		}
+		rule, err := signatureRule(envelope.Rule, envelope.Identities)
+		if err != nil {
+			return "", "", err
+		}
+		return "Signature", rule, nil
+	default:
+		return strconv.Itoa(int(policy.Type)), "", nil
+	}
+}
+
+func signatureRule(rule *cb.SignaturePolicy, identities []*mspproto.MSPPrincipal) (string, error) {
+	switch t := rule.GetType().(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return "", errors.Errorf("identity index [%d] out of range", t.SignedBy)
+		}
+		return principal(identities[t.SignedBy])
+	case *cb.SignaturePolicy_NOutOf_:
+		var rules []string
+		for _, r := range t.NOutOf.Rules {
+			s, err := signatureRule(r, identities)
+			if err != nil {
+				return "", err
+			}
+			rules = append(rules, s)
+		}
+		n := int(t.NOutOf.N)
+		switch {
+		case n == 1:
+			return "OR(" + strings.Join(rules, ",") + ")", nil
+		case n == len(rules):
+			return "AND(" + strings.Join(rules, ",") + ")", nil
+		default:
+			return fmt.Sprintf("OutOf(%d,%s)", n, strings.Join(rules, ",")), nil
+		}
+	default:
+		return "", errors.Errorf("unsupported signature policy type [%T]", t)
+	}
+}
+
+func principal(p *mspproto.MSPPrincipal) (string, error) {
+	if p.PrincipalClassification != mspproto.MSPPrincipal_ROLE {
+		return "'" + p.PrincipalClassification.String() + "'", nil
+	}
+	role := &mspproto.MSPRole{}
+	if err := proto.Unmarshal(p.Principal, role); err != nil {
+		return "", errors.Wrap(err, "invalid msp role")
+	}
+	return fmt.Sprintf("'%s.%s'", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package configtx
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// NewConfigUpdate returns the ConfigUpdate of the passed channel turning the original config into the updated one
+func NewConfigUpdate(channel string, original, updated *cb.Config) (*cb.ConfigUpdate, error) {
+	update, err := Compute(original, updated)
+	if err != nil {
+		return nil, err
+	}
+	update.ChannelId = channel
+	return update, nil
+}
+
+// UnmarshalConfigUpdate unmarshals the passed update and checks that it targets the passed channel
+func UnmarshalConfigUpdate(channel string, raw []byte) (*cb.ConfigUpdate, error) {
+	update := &cb.ConfigUpdate{}
+	if err := proto.Unmarshal(raw, update); err != nil {
+		return nil, errors.Wrap(err, "invalid config update")
+	}
+	if update.ChannelId != channel {
+		return nil, errors.Errorf("config update for channel [%s], expected [%s]", update.ChannelId, channel)
+	}
+	return update, nil
+}
+
+// SignConfigUpdate signs the passed marshalled ConfigUpdate, the signature goes along with the update
+// in the ConfigUpdateEnvelope
+func SignConfigUpdate(update []byte, signer protoutil.Signer) (*cb.ConfigSignature, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error serializing identity")
+	}
+	nonce, err := protoutil.CreateNonce()
+	if err != nil {
+		return nil, err
+	}
+	header, err := proto.Marshal(protoutil.MakeSignatureHeader(creator, nonce))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling signature header")
+	}
+	signature, err := signer.Sign(append(append([]byte{}, header...), update...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed signing config update")
+	}
+	return &cb.ConfigSignature{SignatureHeader: header, Signature: signature}, nil
+}
+
+// SignedData returns the creator of the passed signature of the passed marshalled ConfigUpdate,
+// and the data it signs
+func SignedData(update []byte, signature *cb.ConfigSignature) ([]byte, []byte, error) {
+	header := &cb.SignatureHeader{}
+	if err := proto.Unmarshal(signature.SignatureHeader, header); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid signature header")
+	}
+	if len(header.Creator) == 0 {
+		return nil, nil, errors.New("no creator in signature header")
+	}
+	return header.Creator, append(append([]byte{}, signature.SignatureHeader...), update...), nil
+}
+
+// NewConfigUpdateEnvelope returns the envelope, signed by the passed signer, that submits the passed
+// marshalled ConfigUpdate, with its signatures, to the ordering service
+func NewConfigUpdateEnvelope(channel string, update []byte, signatures []*cb.ConfigSignature, signer protoutil.Signer) (*cb.Envelope, error) {
+	env, err := protoutil.CreateSignedEnvelope(
+		cb.HeaderType_CONFIG_UPDATE,
+		channel,
+		signer,
+		&cb.ConfigUpdateEnvelope{ConfigUpdate: update, Signatures: signatures},
+		0,
+		0,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed creating config update envelope")
+	}
+	return env, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package configtx
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// Compute returns the ConfigUpdate turning the original config into the updated one.
+// The read and write sets follow the rules of configtxlator's compute_update, whose implementation lives in
+// an internal package of fabric and cannot be imported.
+func Compute(original, updated *cb.Config) (*cb.ConfigUpdate, error) {
+	if original.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for original config")
+	}
+	if updated.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for updated config")
+	}
+	readSet, writeSet, changed := groupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	if !changed {
+		return nil, errors.New("no differences detected between original and updated config")
+	}
+	return &cb.ConfigUpdate{ReadSet: readSet, WriteSet: writeSet}, nil
+}
+
+// groupUpdate returns the read and write sets turning the original group into the updated one, and whether
+// the group changed at all.
+// When members are added or removed, or the mod policy changes, the group is written at the next version along
+// with its unchanged members at their current version. Otherwise, only the members that changed are written
+// and the group keeps its version.
+func groupUpdate(original, updated *cb.ConfigGroup) (*cb.ConfigGroup, *cb.ConfigGroup, bool) {
+	readSet := newGroup(original.Version)
+	writeSet := newGroup(original.Version)
+	same := newGroup(0)
+	membersChanged := original.ModPolicy != updated.ModPolicy
+
+	for name, o := range original.Policies {
+		u, ok := updated.Policies[name]
+		switch {
+		case !ok:
+			membersChanged = true
+		case o.ModPolicy == u.ModPolicy && proto.Equal(o.Policy, u.Policy):
+			same.Policies[name] = &cb.ConfigPolicy{Version: o.Version}
+		default:
+			writeSet.Policies[name] = &cb.ConfigPolicy{Version: o.Version + 1, ModPolicy: u.ModPolicy, Policy: u.Policy}
+		}
+	}
+	for name, u := range updated.Policies {
+		if _, ok := original.Policies[name]; !ok {
+			membersChanged = true
+			writeSet.Policies[name] = &cb.ConfigPolicy{ModPolicy: u.ModPolicy, Policy: u.Policy}
+		}
+	}
+
+	for name, o := range original.Values {
+		u, ok := updated.Values[name]
+		switch {
+		case !ok:
+			membersChanged = true
+		case o.ModPolicy == u.ModPolicy && bytes.Equal(o.Value, u.Value):
+			same.Values[name] = &cb.ConfigValue{Version: o.Version}
+		default:
+			writeSet.Values[name] = &cb.ConfigValue{Version: o.Version + 1, ModPolicy: u.ModPolicy, Value: u.Value}
+		}
+	}
+	for name, u := range updated.Values {
+		if _, ok := original.Values[name]; !ok {
+			membersChanged = true
+			writeSet.Values[name] = &cb.ConfigValue{ModPolicy: u.ModPolicy, Value: u.Value}
+		}
+	}
+
+	for name, o := range original.Groups {
+		u, ok := updated.Groups[name]
+		if !ok {
+			membersChanged = true
+			continue
+		}
+		groupReadSet, groupWriteSet, changed := groupUpdate(o, u)
+		if !changed {
+			same.Groups[name] = groupReadSet
+			continue
+		}
+		readSet.Groups[name] = groupReadSet
+		writeSet.Groups[name] = groupWriteSet
+	}
+	for name, u := range updated.Groups {
+		if _, ok := original.Groups[name]; !ok {
+			membersChanged = true
+			// a new group is written in full, at version 0
+			_, groupWriteSet, _ := groupUpdate(protoutil.NewConfigGroup(), u)
+			groupWriteSet.Version = 0
+			groupWriteSet.ModPolicy = u.ModPolicy
+			writeSet.Groups[name] = groupWriteSet
+		}
+	}
+
+	if !membersChanged {
+		if len(writeSet.Policies) == 0 && len(writeSet.Values) == 0 && len(writeSet.Groups) == 0 {
+			return &cb.ConfigGroup{Version: original.Version}, &cb.ConfigGroup{Version: original.Version}, false
+		}
+		return readSet, writeSet, true
+	}
+
+	for name, policy := range same.Policies {
+		readSet.Policies[name] = policy
+		writeSet.Policies[name] = policy
+	}
+	for name, value := range same.Values {
+		readSet.Values[name] = value
+		writeSet.Values[name] = value
+	}
+	for name, group := range same.Groups {
+		readSet.Groups[name] = group
+		writeSet.Groups[name] = group
+	}
+	writeSet.Version = original.Version + 1
+	writeSet.ModPolicy = updated.ModPolicy
+	return readSet, writeSet, true
+}
+
+func newGroup(version uint64) *cb.ConfigGroup {
+	return &cb.ConfigGroup{
+		Version:  version,
+		Policies: map[string]*cb.ConfigPolicy{},
+		Values:   map[string]*cb.ConfigValue{},
+		Groups:   map[string]*cb.ConfigGroup{},
+	}
+}
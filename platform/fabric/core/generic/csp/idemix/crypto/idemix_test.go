@@ -162,3 +162,71 @@ func TestIdemix(t *testing.T) {
 		return
 	}
 }
+
+func TestPlainSignatureRevocation(t *testing.T) {
+	rng, err := GetRand()
+	require.NoError(t, err)
+
+	AttributeNames := []string{"Attr1", "Attr2", "Attr3", "Attr4"}
+	key, err := NewIssuerKey(AttributeNames, rng)
+	require.NoError(t, err)
+	revocationKey, err := GenerateLongTermRevocationKey()
+	require.NoError(t, err)
+
+	// issue two credentials with distinct revocation handles
+	rhindex := 3
+	issue := func(rh int) (*FP256BN.BIG, *Credential) {
+		sk := RandModOrder(rng)
+		m := NewCredRequest(sk, BigToBytes(RandModOrder(rng)), key.Ipk, rng)
+		attrs := []*FP256BN.BIG{FP256BN.NewBIGint(1), FP256BN.NewBIGint(2), FP256BN.NewBIGint(3), FP256BN.NewBIGint(rh)}
+		cred, err := NewCredential(key, m, attrs, rng)
+		require.NoError(t, err)
+		return sk, cred
+	}
+	sk1, cred1 := issue(1)
+	sk2, cred2 := issue(2)
+
+	// epoch 1: only the first credential is unrevoked
+	epoch := 1
+	cri, err := CreateCRI(revocationKey, []*FP256BN.BIG{FP256BN.NewBIGint(1)}, epoch, ALG_PLAIN_SIGNATURE, rng)
+	require.NoError(t, err)
+	require.NoError(t, VerifyEpochPK(&revocationKey.PublicKey, cri.EpochPk, cri.EpochPkSig, epoch, ALG_PLAIN_SIGNATURE))
+
+	disclosure := []byte{0, 1, 0, 0}
+	msg := []byte("hello world")
+	attrs := []*FP256BN.BIG{nil, FP256BN.NewBIGint(2), nil, nil}
+
+	Nym, RandNym := MakeNym(sk1, key.Ipk, rng)
+	sig, _, err := NewSignature(cred1, sk1, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, cri, rng)
+	require.NoError(t, err)
+	require.NoError(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch))
+
+	// the signature is bound to its epoch
+	require.Error(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch+1))
+
+	// tampering with the non-revocation proof must be detected
+	proof := sig.NonRevocationProof.NonRevocationProof
+	sig.NonRevocationProof.NonRevocationProof = append(EcpToBytes(GenG1.Mul(RandModOrder(rng))), proof[2*FieldBytes+1:]...)
+	require.Error(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch))
+	sig.NonRevocationProof.NonRevocationProof = proof
+
+	// falling back to no revocation is not possible in an epoch that uses revocation
+	noRevCRI, err := CreateCRI(revocationKey, nil, epoch+1, ALG_NO_REVOCATION, rng)
+	require.NoError(t, err)
+	noRevCRI.Epoch = int64(epoch)
+	sig, _, err = NewSignature(cred1, sk1, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, noRevCRI, rng)
+	require.NoError(t, err)
+	require.Error(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch))
+
+	// verifiers without revocation do not check epochs, but accept only signatures without revocation
+	require.NoError(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, nil, 0))
+	sig, _, err = NewSignature(cred1, sk1, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, cri, rng)
+	require.NoError(t, err)
+	require.Error(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, nil, epoch))
+
+	// the second credential has been revoked
+	Nym, RandNym = MakeNym(sk2, key.Ipk, rng)
+	_, _, err = NewSignature(cred2, sk2, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, cri, rng)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has been revoked")
+}
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric-amcl/amcl"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/pkg/errors"
+)
+
+// With ALG_PLAIN_SIGNATURE, the revocation authority places a weak Boneh-Boyen signature sigma = g1^(1/(x+rh))
+// on each unrevoked handle rh, where x is the secret key of the epoch.
+// The signer randomizes the signature, sigmaPrime = sigma^r, and proves knowledge of r and rh such that
+// e(sigmaPrime, W) = e(g1, g2)^r * e(sigmaPrime, g2)^(-rh), where W is the epoch public key.
+// The randomness used for rh is the one used by the signature to prove knowledge of the revocation handle attribute,
+// binding the non-revocation proof to the credential.
+
+// plainSigNonRevokedProver is the nonRevokedProver for ALG_PLAIN_SIGNATURE
+type plainSigNonRevokedProver struct {
+	sigmaPrime *FP256BN.ECP
+	r          *FP256BN.BIG
+	rR         *FP256BN.BIG
+}
+
+func (prover *plainSigNonRevokedProver) getFSContribution(rh *FP256BN.BIG, rRh *FP256BN.BIG, cri *CredentialRevocationInformation, rng *amcl.RAND) ([]byte, error) {
+	sigma, err := plainSigLookup(cri.RevocationData, rh)
+	if err != nil {
+		return nil, err
+	}
+
+	// randomize the signature
+	prover.r = RandModOrder(rng)
+	prover.sigmaPrime = sigma.Mul(prover.r)
+
+	// t = e(g1^rR * sigmaPrime^(-rRh), g2)
+	prover.rR = RandModOrder(rng)
+	t := FP256BN.Fexp(FP256BN.Ate(GenG2, GenG1.Mul2(prover.rR, prover.sigmaPrime, FP256BN.Modneg(rRh, GroupOrder))))
+
+	return plainSigFSContribution(prover.sigmaPrime, t), nil
+}
+
+func (prover *plainSigNonRevokedProver) getNonRevokedProof(chal *FP256BN.BIG) (*NonRevocationProof, error) {
+	if prover.sigmaPrime == nil {
+		return nil, errors.New("non-revocation proof not initialized")
+	}
+	// s_r = rR + C \cdot r
+	proofSR := Modadd(prover.rR, FP256BN.Modmul(chal, prover.r, GroupOrder), GroupOrder)
+
+	return &NonRevocationProof{
+		RevocationAlg:      int32(ALG_PLAIN_SIGNATURE),
+		NonRevocationProof: append(EcpToBytes(prover.sigmaPrime), BigToBytes(proofSR)...),
+	}, nil
+}
+
+// plainSigNonRevocationVerifier is the nonRevocationVerifier for ALG_PLAIN_SIGNATURE
+type plainSigNonRevocationVerifier struct{}
+
+func (verifier *plainSigNonRevocationVerifier) recomputeFSContribution(proof *NonRevocationProof, chal *FP256BN.BIG, epochPK *FP256BN.ECP2, proofSRh *FP256BN.BIG) ([]byte, error) {
+	if proof == nil || epochPK == nil || proofSRh == nil {
+		return nil, errors.New("non-revocation proof invalid: received nil input")
+	}
+	if len(proof.NonRevocationProof) != 2*FieldBytes+1+FieldBytes {
+		return nil, errors.New("non-revocation proof invalid: unexpected length")
+	}
+	sigmaPrime := FP256BN.ECP_fromBytes(proof.NonRevocationProof[:2*FieldBytes+1])
+	if sigmaPrime.Is_infinity() {
+		return nil, errors.New("non-revocation proof invalid: sigmaPrime = 1")
+	}
+	proofSR := FP256BN.FromBytes(proof.NonRevocationProof[2*FieldBytes+1:])
+
+	// t = e(g1^s_r * sigmaPrime^(-s_rh), g2) * e(sigmaPrime^(-C), W)
+	t := FP256BN.Fexp(FP256BN.Ate2(
+		GenG2, GenG1.Mul2(proofSR, sigmaPrime, FP256BN.Modneg(proofSRh, GroupOrder)),
+		epochPK, sigmaPrime.Mul(FP256BN.Modneg(chal, GroupOrder)),
+	))
+
+	return plainSigFSContribution(sigmaPrime, t), nil
+}
+
+// plainSigLookup returns the epoch signature on the passed revocation handle contained in the passed revocation data
+func plainSigLookup(revocationData []byte, rh *FP256BN.BIG) (*FP256BN.ECP, error) {
+	if len(revocationData)%plainSigEntryBytes != 0 {
+		return nil, errors.New("invalid revocation data")
+	}
+	rhBytes := BigToBytes(rh)
+	for i := 0; i < len(revocationData); i += plainSigEntryBytes {
+		if bytes.Equal(revocationData[i:i+FieldBytes], rhBytes) {
+			return FP256BN.ECP_fromBytes(revocationData[i+FieldBytes : i+plainSigEntryBytes]), nil
+		}
+	}
+	return nil, errors.New("revocation handle not found in the credential revocation information, the credential has been revoked")
+}
+
+func plainSigFSContribution(sigmaPrime *FP256BN.ECP, t *FP256BN.FP12) []byte {
+	res := make([]byte, ProofBytes[ALG_PLAIN_SIGNATURE])
+	index := appendBytesG1(res, 0, sigmaPrime)
+	t.ToBytes(res[index:])
+	return res
+}
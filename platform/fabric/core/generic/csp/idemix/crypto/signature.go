@@ -266,7 +266,7 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	}()
 
 	// Validate inputs
-	if ipk == nil || revPk == nil {
+	if ipk == nil {
 		return errors.Errorf("cannot verify idemix signature: received nil input")
 	}
 
@@ -278,6 +278,24 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 		return errors.Errorf("Attribute %d is disclosed but is also used as revocation handle, which should remain hidden.", rhIndex)
 	}
 
+	// A nil revocation public key means that revocation is not configured for the verifier:
+	// epochs are not tracked and only signatures that do not use revocation are accepted.
+	// Otherwise, the epoch key must have been certified by the revocation authority for the expected epoch.
+	// This is checked also when no revocation is used, to make sure the revocation authority
+	// indeed certified that no revocation is used in this epoch.
+	if revPk == nil {
+		if RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg) != ALG_NO_REVOCATION {
+			return errors.Errorf("signature invalid: revocation algorithm %d used but revocation is not configured", sig.NonRevocationProof.RevocationAlg)
+		}
+	} else {
+		if sig.Epoch != int64(epoch) {
+			return errors.Errorf("signature invalid: expected epoch %d, got %d", epoch, sig.Epoch)
+		}
+		if err := VerifyEpochPK(revPk, sig.RevocationEpochPk, sig.RevocationPkSig, epoch, RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg)); err != nil {
+			return errors.WithMessage(err, "signature invalid: epoch key not certified")
+		}
+	}
+
 	HiddenIndices := hiddenIndices(Disclosure)
 
 	// Parse signature
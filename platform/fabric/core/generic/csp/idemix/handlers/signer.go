@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package handlers
 
 import (
+	"crypto/ecdsa"
+
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/csp"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/pkg/errors"
@@ -77,9 +79,14 @@ func (v *Verifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.Sign
 		return false, errors.New("invalid options, expected *IdemixSignerOpts")
 	}
 
-	rpk, ok := signerOpts.RevocationPublicKey.(*revocationPublicKey)
-	if !ok {
-		return false, errors.New("invalid options, expected *revocationPublicKey")
+	// a missing revocation public key means that the verifier does not check revocation
+	var pk *ecdsa.PublicKey
+	if signerOpts.RevocationPublicKey != nil {
+		rpk, ok := signerOpts.RevocationPublicKey.(*revocationPublicKey)
+		if !ok {
+			return false, errors.New("invalid options, expected *revocationPublicKey")
+		}
+		pk = rpk.pubKey
 	}
 
 	if len(signature) == 0 {
@@ -92,7 +99,7 @@ func (v *Verifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.Sign
 		digest,
 		signerOpts.Attributes,
 		signerOpts.RhIndex,
-		rpk.pubKey,
+		pk,
 		signerOpts.Epoch,
 	)
 	if err != nil {
@@ -316,15 +316,17 @@ var _ = Describe("Signature", func() {
 			})
 
 			Context("and the option's revocation public key is empty", func() {
-				It("returns error", func() {
+				It("verifies without revocation", func() {
 					valid, err := Verifier.Verify(
 						handlers.NewIssuerPublicKey(nil),
 						[]byte("fake signature"),
 						nil,
 						&csp.IdemixSignerOpts{},
 					)
-					Expect(err).To(MatchError("invalid options, expected *revocationPublicKey"))
-					Expect(valid).To(BeFalse())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(valid).To(BeTrue())
+					_, _, _, _, _, rpk, _ := fakeSignatureScheme.VerifyArgsForCall(0)
+					Expect(rpk).To(BeNil())
 				})
 			})
 
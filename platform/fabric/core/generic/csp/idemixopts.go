@@ -22,6 +22,8 @@ const (
 const (
 	// AlgNoRevocation means no revocation support
 	AlgNoRevocation RevocationAlgorithm = iota
+	// AlgPlainSignature means that the revocation authority signs each unrevoked handle with the epoch key
+	AlgPlainSignature
 )
 
 // IdemixIssuerKeyGenOpts contains the options for the Idemix Issuer key-generation.
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package delivery
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+// BlockStream delivers the full blocks of a channel, in order, as they are received from a peer
+type BlockStream struct {
+	address string
+	client  DeliverClient
+	deliver DeliverFiltered
+	cancel  context.CancelFunc
+	done    bool
+	closed  bool
+}
+
+// NewBlockStream connects to the deliver service of the passed peer and requests the blocks of the channel
+// from the start block to the stop block, included.
+// If wait is true, the stream waits for the blocks not yet committed, otherwise it fails when it reaches them.
+func NewBlockStream(config *grpc.ConnectionConfig, channel string, signingIdentity SigningIdentity, hasher Hasher, start, stop uint64, wait bool) (*BlockStream, error) {
+	if start > stop {
+		return nil, errors.Errorf("invalid range [%d,%d]", start, stop)
+	}
+	client, err := NewDeliverClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deliver, err := client.NewDeliver(ctx)
+	if err != nil {
+		cancel()
+		client.Close()
+		return nil, err
+	}
+
+	behavior := ab.SeekInfo_FAIL_IF_NOT_READY
+	if wait {
+		behavior = ab.SeekInfo_BLOCK_UNTIL_READY
+	}
+	envelope, err := CreateDeliverRangeEnvelope(
+		channel,
+		signingIdentity,
+		client.Certificate(),
+		hasher,
+		seekTo(start),
+		seekTo(stop),
+		behavior,
+	)
+	if err == nil {
+		err = DeliverSend(deliver, config.Address, envelope)
+	}
+	if err != nil {
+		cancel()
+		client.Close()
+		return nil, err
+	}
+
+	return &BlockStream{
+		address: config.Address,
+		client:  client,
+		deliver: deliver,
+		cancel:  cancel,
+	}, nil
+}
+
+// Next returns the next block of the stream, nil when the stop block has been already delivered
+func (s *BlockStream) Next() (*common.Block, error) {
+	if s.closed {
+		return nil, errors.New("this stream was closed")
+	}
+	if s.done {
+		return nil, nil
+	}
+	for {
+		resp, err := s.deliver.Recv()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error receiving deliver response from peer %s", s.address)
+		}
+		switch r := resp.Type.(type) {
+		case *pb.DeliverResponse_Block:
+			if r.Block == nil || r.Block.Header == nil {
+				return nil, errors.Errorf("peer %s delivered an empty block", s.address)
+			}
+			return r.Block, nil
+		case *pb.DeliverResponse_Status:
+			if r.Status != common.Status_SUCCESS {
+				return nil, errors.Errorf("deliver service of peer %s returned status [%s]", s.address, r.Status)
+			}
+			s.done = true
+			return nil, nil
+		default:
+			logger.Debugf("ignoring deliver response of type [%T] from peer %s", r, s.address)
+		}
+	}
+}
+
+// Close stops the stream and closes the connection to the peer
+func (s *BlockStream) Close() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.cancel()
+	if err := s.client.Close(); err != nil {
+		logger.Debugf("failed closing connection to peer %s: [%s]", s.address, err)
+	}
+}
+
+func seekTo(number uint64) *ab.SeekPosition {
+	return &ab.SeekPosition{
+		Type: &ab.SeekPosition_Specified{
+			Specified: &ab.SeekSpecified{
+				Number: number,
+			},
+		},
+	}
+}
@@ -54,8 +54,14 @@ type DeliverClient interface {
 	// NewDeliverFilterd returns a DeliverFiltered
 	NewDeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error)
 
+	// NewDeliver returns a client of the deliver service that delivers full blocks
+	NewDeliver(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error)
+
 	// Certificate returns tls certificate for the deliver client to peer
 	Certificate() *tls.Certificate
+
+	// Close closes the connection to the peer
+	Close() error
 }
 
 // deliverClient implements DeliverClient interface
@@ -108,14 +114,46 @@ func (d *deliverClient) NewDeliverFiltered(ctx context.Context, opts ...grpc.Cal
 	return df, nil
 }
 
+// NewDeliver creates a client of the deliver service that delivers full blocks
+func (d *deliverClient) NewDeliver(ctx context.Context, opts ...grpc.CallOption) (DeliverFiltered, error) {
+	if d.conn == nil {
+		return nil, errors.Errorf("no connection to peer %s", d.peerAddr)
+	}
+	deliver, err := pb.NewDeliverClient(d.conn).Deliver(ctx, opts...)
+	if err != nil {
+		rpcStatus, _ := status.FromError(err)
+		return nil, errors.Wrapf(err, "failed to new a deliver, rpcStatus=%+v", rpcStatus)
+	}
+	return deliver, nil
+}
+
 func (d *deliverClient) Certificate() *tls.Certificate {
 	cert := d.grpcClient.Certificate()
 	return &cert
 }
 
+func (d *deliverClient) Close() error {
+	if d.conn == nil {
+		return nil
+	}
+	return d.conn.Close()
+}
+
 // CreateDeliverEnvelope creates a signed envelope with SeekPosition_Newest for block
 func CreateDeliverEnvelope(channelId string, signingIdentity SigningIdentity, cert *tls.Certificate, hasher Hasher, start *ab.SeekPosition) (*common.Envelope, error) {
-	logger.Debugf("create delivery envelope starting from: [%s]", start.String())
+	stop := &ab.SeekPosition{
+		Type: &ab.SeekPosition_Specified{
+			Specified: &ab.SeekSpecified{
+				Number: math.MaxUint64,
+			},
+		},
+	}
+	return CreateDeliverRangeEnvelope(channelId, signingIdentity, cert, hasher, start, stop, ab.SeekInfo_BLOCK_UNTIL_READY)
+}
+
+// CreateDeliverRangeEnvelope creates a signed envelope requesting the blocks between the passed positions, included
+func CreateDeliverRangeEnvelope(channelId string, signingIdentity SigningIdentity, cert *tls.Certificate, hasher Hasher, start, stop *ab.SeekPosition, behavior ab.SeekInfo_SeekBehavior) (*common.Envelope, error) {
+	logger.Debugf("create delivery envelope from [%s] to [%s]", start.String(), stop.String())
 	creator, err := signingIdentity.Serialize()
 	if err != nil {
 		return nil, err
@@ -132,18 +170,10 @@ func CreateDeliverEnvelope(channelId string, signingIdentity SigningIdentity, ce
 		return nil, err
 	}
 
-	stop := &ab.SeekPosition{
-		Type: &ab.SeekPosition_Specified{
-			Specified: &ab.SeekSpecified{
-				Number: math.MaxUint64,
-			},
-		},
-	}
-
 	seekInfo := &ab.SeekInfo{
 		Start:    start,
 		Stop:     stop,
-		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+		Behavior: behavior,
 	}
 
 	raw, err := proto.Marshal(seekInfo)
@@ -7,6 +7,7 @@ package delivery
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -20,6 +21,7 @@ import (
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 )
 
 var logger = flogging.MustGetLogger("fabric-sdk.delivery")
@@ -30,9 +32,12 @@ type Committer interface {
 
 type Vault interface {
 	GetLastTxID() (string, error)
+	// SnapshotHeight returns the last block of the snapshot the vault was bootstrapped from, if any
+	SnapshotHeight() (uint64, bool, error)
 }
 
 type Network interface {
+	Name() string
 	Channel(name string) (api.Channel, error)
 	Peers() []*grpc.ConnectionConfig
 	LocalMembership() api.LocalMembership
@@ -46,6 +51,15 @@ type delivery struct {
 	peerConnectionConfig *grpc.ConnectionConfig
 	committer            Committer
 	vault                Vault
+	metrics              *Metrics
+	// connected is 1 when the delivery service is connected to the peer and receiving blocks, 0 otherwise
+	connected int32
+	// ctx is cancelled when the delivery service stops
+	ctx    context.Context
+	cancel context.CancelFunc
+	// stopped is closed when the delivery loop returns
+	stopped chan struct{}
+	started int32
 }
 
 func New(
@@ -67,34 +81,75 @@ func New(
 		peerConnectionConfig: network.Peers()[0],
 		committer:            committer,
 		vault:                vault,
+		metrics:              NewMetrics(operations.GetMetricsProvider(sp), network.Name(), channel),
 	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.stopped = make(chan struct{})
 	return d, nil
 }
 
+// HealthCheck returns an error if the delivery service is not connected to the peer
+func (d *delivery) HealthCheck(ctx context.Context) error {
+	if atomic.LoadInt32(&d.connected) == 0 {
+		return errors.Errorf("delivery service [%s:%s] not connected", d.peerConnectionConfig.Address, d.channel)
+	}
+	return nil
+}
+
 func (d *delivery) Start() {
+	atomic.StoreInt32(&d.started, 1)
 	go d.run()
 }
 
+// Stop stops the delivery service. It returns once the block being committed, if any, has been committed.
+func (d *delivery) Stop() {
+	d.cancel()
+	if atomic.LoadInt32(&d.started) == 1 {
+		<-d.stopped
+	}
+}
+
+// wait waits for the passed duration or until the delivery service stops
+func (d *delivery) wait(duration time.Duration) {
+	select {
+	case <-time.After(duration):
+	case <-d.ctx.Done():
+	}
+}
+
 func (d *delivery) run() {
+	defer close(d.stopped)
+
 	var df DeliverFiltered
 	var err error
 	for {
+		if d.ctx.Err() != nil {
+			atomic.StoreInt32(&d.connected, 0)
+			logger.Debugf("deliver service [%s:%s], stopped", d.peerConnectionConfig.Address, d.channel)
+			return
+		}
 		address := d.peerConnectionConfig.Address
 		logger.Debugf("deliver service [%s:%s], next event...", address, d.channel)
 		if df == nil {
+			atomic.StoreInt32(&d.connected, 0)
 			logger.Debugf("deliver service [%s:%s], connecting...", address, d.channel)
 			df, err = d.connect()
 			if err != nil {
 				logger.Errorf("failed connecting to delivery service [%s:%s] [%s]. Wait 10 sec before reconnecting", address, d.channel, err)
-				time.Sleep(10 * time.Second)
+				d.wait(10 * time.Second)
 				logger.Debugf("reconnecting to delivery service [%s:%s]", address, d.channel)
 				continue
 			}
+			atomic.StoreInt32(&d.connected, 1)
 		}
 
 		resp, err := df.Recv()
 		if err != nil {
 			df = nil
+			atomic.StoreInt32(&d.connected, 0)
+			if d.ctx.Err() != nil {
+				continue
+			}
 			logger.Errorf("delivery service [%s:%s], failed receiving response [%s]", address, d.channel, errors.WithMessagef(err, "error receiving deliver response from peer %s", address))
 			continue
 		}
@@ -104,11 +159,12 @@ func (d *delivery) run() {
 			logger.Debugf("delivery service [%s:%s], commit block [%d]", address, d.channel, r.FilteredBlock.Number)
 
 			d.committer.Commit(r.FilteredBlock)
+			d.metrics.BlockHeight.Set(float64(r.FilteredBlock.Number))
 		case *pb.DeliverResponse_Status:
 			if r.Status == common.Status_NOT_FOUND {
 				df = nil
 				logger.Warnf("delivery service [%s:%s] status [%s], wait a few seconds before retrying", address, d.channel, r.Status)
-				time.Sleep(10 * time.Second)
+				d.wait(10 * time.Second)
 			} else {
 				logger.Warnf("delivery service [%s:%s] status [%s]", address, d.channel, r.Status)
 			}
@@ -133,7 +189,7 @@ func (d *delivery) connect() (DeliverFiltered, error) {
 
 	//ctx, cancelFunc = context.WithTimeout(context.Background(), d.waitForEventTimeout)
 	//defer cancelFunc()
-	ctx = context.Background()
+	ctx = d.ctx
 	deliverFiltered, err := deliverClient.NewDeliverFiltered(ctx)
 	if err != nil {
 		return nil, err
@@ -144,16 +200,28 @@ func (d *delivery) connect() (DeliverFiltered, error) {
 		return nil, errors.WithMessagef(err, "failed getting last transaction committed/discarted from the vault")
 	}
 
+	snapshotHeight, fromSnapshot, err := d.vault.SnapshotHeight()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting snapshot height from the vault")
+	}
+
 	start := &ab.SeekPosition{}
-	if len(lastTxID) != 0 {
-		// Retrieve block from Fabric
-		ch, err := d.network.Channel(d.channel)
-		if err != nil {
-			return nil, errors.WithMessagef(err, "failed getting channeln [%s]", d.channel)
+	if len(lastTxID) != 0 || fromSnapshot {
+		var blockNumber uint64
+		if len(lastTxID) != 0 {
+			// Retrieve block from Fabric
+			ch, err := d.network.Channel(d.channel)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed getting channeln [%s]", d.channel)
+			}
+			blockNumber, err = ch.GetBlockNumberByTxID(lastTxID)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed getting block number for transaction [%s]", lastTxID)
+			}
 		}
-		blockNumber, err := ch.GetBlockNumberByTxID(lastTxID)
-		if err != nil {
-			return nil, errors.WithMessagef(err, "failed getting block number for transaction [%s]", lastTxID)
+		if fromSnapshot && snapshotHeight > blockNumber {
+			// the blocks up to the snapshot height are already reflected in the vault
+			blockNumber = snapshotHeight
 		}
 		start.Type = &ab.SeekPosition_Specified{
 			Specified: &ab.SeekSpecified{
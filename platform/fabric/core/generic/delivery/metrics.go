@@ -0,0 +1,31 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package delivery
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	blockHeightGaugeOpts = metrics.GaugeOpts{
+		Namespace:  "fabric",
+		Subsystem:  "delivery",
+		Name:       "block_height",
+		Help:       "Number of the last block received from the delivery service, by network and channel.",
+		LabelNames: []string{"network", "channel"},
+	}
+)
+
+type Metrics struct {
+	BlockHeight metrics.Gauge
+}
+
+// NewMetrics returns the metrics of the delivery service of the passed network and channel
+func NewMetrics(p metrics.Provider, network, channel string) *Metrics {
+	return &Metrics{
+		BlockHeight: p.NewGauge(blockHeightGaugeOpts).With("network", network, "channel", channel),
+	}
+}
@@ -6,10 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 package generic
 
 import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/delivery"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
 )
 
 func (c *channel) NewRWSet(txid string) (api.RWSet, error) {
@@ -28,21 +39,124 @@ func (c *channel) NewQueryExecutor() (api.QueryExecutor, error) {
 	return c.vault.NewQueryExecutor()
 }
 
+func (c *channel) GetHistoryForKey(namespace, key string) ([]*api.HistoryEntry, error) {
+	return c.vault.GetHistoryForKey(namespace, key)
+}
+
+func (c *channel) GetNamespaceAsOf(namespace string, block uint64) ([]*api.HistoryEntry, error) {
+	return c.vault.GetNamespaceAsOf(namespace, block)
+}
+
+func (c *channel) ExportSnapshot(w io.Writer, includePrivate bool) (*api.SnapshotInfo, error) {
+	return c.vault.ExportSnapshot(w, includePrivate)
+}
+
+func (c *channel) GetLocalLog(from uint64) ([]*api.LocalTransaction, error) {
+	return c.vault.GetLocalLog(from)
+}
+
 func (c *channel) GetBlockByNumber(number uint64) (api.Block, error) {
-	res, err := c.Chaincode("qscc").NewInvocation(api.ChaincodeQuery, GetBlockByNumber, c.name, number).WithSignerIdentity(
-		c.network.LocalMembership().DefaultIdentity(),
-	).WithEndorsersByConnConfig(c.network.Peers()...).Call()
+	res, err := c.queryLedger(GetBlockByNumber, number)
 	if err != nil {
 		return nil, err
 	}
 
-	b, err := protoutil.UnmarshalBlock(res.([]byte))
+	b, err := protoutil.UnmarshalBlock(res)
 	if err != nil {
 		return nil, err
 	}
 	return &Block{Block: b}, nil
 }
 
+// GetChainInfo returns the chain info of the first peer that answers, the peers might be at different heights
+func (c *channel) GetChainInfo() (*api.ChainInfo, error) {
+	res, err := c.queryLedger(GetChainInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &common.BlockchainInfo{}
+	if err := proto.Unmarshal(res, info); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling blockchain info")
+	}
+	return &api.ChainInfo{
+		Height:            info.Height,
+		CurrentBlockHash:  info.CurrentBlockHash,
+		PreviousBlockHash: info.PreviousBlockHash,
+	}, nil
+}
+
+func (c *channel) GetBlocks(start, stop uint64) (api.BlockIterator, error) {
+	return c.newBlockIterator(start, stop, false)
+}
+
+func (c *channel) NewBlockStream(start uint64) (api.BlockIterator, error) {
+	return c.newBlockIterator(start, math.MaxUint64, true)
+}
+
+// newBlockIterator streams the blocks from the peers in order, until one accepts the request
+func (c *channel) newBlockIterator(start, stop uint64, wait bool) (api.BlockIterator, error) {
+	var errs []error
+	for _, peer := range c.network.Peers() {
+		stream, err := delivery.NewBlockStream(
+			peer,
+			c.name,
+			c.network.LocalMembership().DefaultSigningIdentity(),
+			hash.GetHasher(c.sp),
+			start,
+			stop,
+			wait,
+		)
+		if err != nil {
+			logger.Warnf("failed requesting blocks [%d,%d] of channel [%s] from [%s]: [%s]", start, stop, c.name, peer.Address, err)
+			errs = append(errs, errors.WithMessagef(err, "peer [%s]", peer.Address))
+			continue
+		}
+		return &blockIterator{stream: stream}, nil
+	}
+	if len(errs) == 0 {
+		return nil, errors.Errorf("failed requesting blocks [%d,%d] of channel [%s]: no peers configured", start, stop, c.name)
+	}
+	return nil, errors.Errorf("failed requesting blocks [%d,%d] of channel [%s]: %v", start, stop, c.name, errs)
+}
+
+// queryLedger invokes the passed function of qscc on the peers in order, until one answers.
+// The peers are not asked together, their answers differ when they are at different heights.
+func (c *channel) queryLedger(function string, args ...interface{}) ([]byte, error) {
+	var errs []error
+	for _, peer := range c.network.Peers() {
+		res, err := c.Chaincode("qscc").NewInvocation(api.ChaincodeQuery, function, append([]interface{}{c.name}, args...)...).WithSignerIdentity(
+			c.network.LocalMembership().DefaultIdentity(),
+		).WithEndorsersByConnConfig(peer).Call()
+		if err != nil {
+			logger.Warnf("failed querying [%s] on channel [%s] from [%s]: [%s]", function, c.name, peer.Address, err)
+			errs = append(errs, errors.WithMessagef(err, "peer [%s]", peer.Address))
+			continue
+		}
+		return res.([]byte), nil
+	}
+	if len(errs) == 0 {
+		return nil, errors.Errorf("failed querying [%s] on channel [%s]: no peers configured", function, c.name)
+	}
+	return nil, errors.Errorf("failed querying [%s] on channel [%s]: %v", function, c.name, errs)
+}
+
+type blockIterator struct {
+	stream *delivery.BlockStream
+}
+
+func (b *blockIterator) Next() (api.Block, error) {
+	block, err := b.stream.Next()
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return &Block{Block: block}, nil
+}
+
+func (b *blockIterator) Close() {
+	b.stream.Close()
+}
+
 type Block struct {
 	*common.Block
 }
@@ -50,3 +164,151 @@ type Block struct {
 func (b *Block) DataAt(i int) []byte {
 	return b.Data.Data[i]
 }
+
+func (b *Block) Number() uint64 {
+	return b.Header.Number
+}
+
+func (b *Block) PreviousHash() []byte {
+	return b.Header.PreviousHash
+}
+
+func (b *Block) DataHash() []byte {
+	return b.Header.DataHash
+}
+
+func (b *Block) Hash() []byte {
+	return protoutil.BlockHeaderHash(b.Header)
+}
+
+func (b *Block) NumTransactions() int {
+	if b.Data == nil {
+		return 0
+	}
+	return len(b.Data.Data)
+}
+
+// TransactionAt decodes the envelope at the passed position, the validation code comes from the
+// transactions filter in the metadata of the block.
+func (b *Block) TransactionAt(i int) (api.ProcessedTransaction, error) {
+	if i < 0 || i >= b.NumTransactions() {
+		return nil, errors.Errorf("block [%d] has no transaction at position [%d]", b.Number(), i)
+	}
+	env, err := protoutil.UnmarshalEnvelope(b.Data.Data[i])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling envelope [%d] of block [%d]", i, b.Number())
+	}
+	vc := int32(peer.TxValidationCode_NOT_VALIDATED)
+	if b.Metadata != nil && len(b.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		filter := b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+		if i < len(filter) {
+			vc = int32(filter[i])
+		}
+	}
+	pt, err := newProcessedTransaction(env, vc)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed decoding transaction [%d] of block [%d]", i, b.Number())
+	}
+	return pt, nil
+}
+
+type processedTransaction struct {
+	env  *common.Envelope
+	vc   int32
+	chdr *common.ChannelHeader
+	shdr *common.SignatureHeader
+	// ue is nil for non-endorser transactions
+	ue *transaction.UnpackedEnvelope
+}
+
+func newProcessedTransaction(env *common.Envelope, vc int32) (*processedTransaction, error) {
+	if env == nil {
+		return nil, errors.New("nil envelope")
+	}
+	payl, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling payload")
+	}
+	if payl.Header == nil {
+		return nil, errors.New("payload has no header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payl.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling channel header")
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(payl.Header.SignatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling signature header")
+	}
+
+	pt := &processedTransaction{env: env, vc: vc, chdr: chdr, shdr: shdr}
+	if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+		pt.ue, err = transaction.UnpackEnvelope(env)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pt, nil
+}
+
+func (p *processedTransaction) TxID() string {
+	return p.chdr.TxId
+}
+
+func (p *processedTransaction) Type() int32 {
+	return p.chdr.Type
+}
+
+func (p *processedTransaction) Timestamp() time.Time {
+	if p.chdr.Timestamp == nil {
+		return time.Time{}
+	}
+	t, err := ptypes.Timestamp(p.chdr.Timestamp)
+	if err != nil {
+		logger.Warnf("invalid timestamp in transaction [%s]: [%s]", p.chdr.TxId, err)
+		return time.Time{}
+	}
+	return t
+}
+
+func (p *processedTransaction) Creator() []byte {
+	return p.shdr.Creator
+}
+
+func (p *processedTransaction) Chaincode() (string, string) {
+	if p.ue == nil {
+		return "", ""
+	}
+	return p.ue.ChaincodeName, p.ue.ChaincodeVersion
+}
+
+func (p *processedTransaction) Function() (string, []string) {
+	if p.ue == nil {
+		return "", nil
+	}
+	return p.ue.Function, p.ue.Args
+}
+
+func (p *processedTransaction) Results() []byte {
+	if p.ue == nil {
+		return nil
+	}
+	return p.ue.Results
+}
+
+func (p *processedTransaction) IsValid() bool {
+	return p.vc == int32(peer.TxValidationCode_VALID)
+}
+
+func (p *processedTransaction) ValidationCode() int32 {
+	return p.vc
+}
+
+func (p *processedTransaction) Envelope() []byte {
+	raw, err := proto.Marshal(p.env)
+	if err != nil {
+		logger.Errorf("failed marshalling envelope of transaction [%s]: [%s]", p.chdr.TxId, err)
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package generic
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockTransactionAt(t *testing.T) {
+	env := &common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(common.HeaderType_CONFIG),
+					ChannelId: "ch",
+					TxId:      "tx1",
+				}),
+				SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: []byte("alice")}),
+			},
+		}),
+	}
+	block := protoutil.NewBlock(3, []byte("previous"))
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(env)}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{byte(peer.TxValidationCode_MVCC_READ_CONFLICT)}
+
+	b := &Block{Block: block}
+	assert.Equal(t, uint64(3), b.Number())
+	assert.Equal(t, []byte("previous"), b.PreviousHash())
+	assert.Equal(t, 1, b.NumTransactions())
+
+	pt, err := b.TransactionAt(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "tx1", pt.TxID())
+	assert.Equal(t, int32(common.HeaderType_CONFIG), pt.Type())
+	assert.Equal(t, []byte("alice"), pt.Creator())
+	assert.Equal(t, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), pt.ValidationCode())
+	assert.False(t, pt.IsValid())
+	assert.Nil(t, pt.Results())
+	name, version := pt.Chaincode()
+	assert.Empty(t, name)
+	assert.Empty(t, version)
+
+	_, err = b.TransactionAt(1)
+	assert.EqualError(t, err, "block [3] has no transaction at position [1]")
+}
@@ -6,7 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package generic
 
 import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
 )
@@ -40,3 +45,27 @@ type mspManager struct {
 func (m *mspManager) DeserializeIdentity(serializedIdentity []byte) (api.MSPIdentity, error) {
 	return m.MSPManager.DeserializeIdentity(serializedIdentity)
 }
+
+// EvaluatePolicy checks that the passed signatures satisfy the passed serialized SignaturePolicyEnvelope
+// against the current channel configuration.
+func (c *channel) EvaluatePolicy(policy []byte, signatures []*api.SignedData) error {
+	spe := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(policy, spe); err != nil {
+		return errors.Wrap(err, "failed unmarshalling signature policy envelope")
+	}
+	pp := &cauthdsl.EnvelopeBasedPolicyProvider{Deserializer: c.Resources().MSPManager()}
+	p, err := pp.NewPolicy(spe)
+	if err != nil {
+		return errors.Wrap(err, "failed compiling policy")
+	}
+
+	var signedData []*protoutil.SignedData
+	for _, signature := range signatures {
+		signedData = append(signedData, &protoutil.SignedData{
+			Data:      signature.Data,
+			Identity:  signature.Identity,
+			Signature: signature.Signature,
+		})
+	}
+	return p.EvaluateSignedData(signedData)
+}
@@ -8,6 +8,8 @@ package idemix
 import (
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
+	m "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/msp"
@@ -60,8 +62,64 @@ func NewDeserializer(ipk []byte) (*idd, error) {
 	}, nil
 }
 
+// NewDeserializerWithRevocation returns a deserializer that also checks that identities and signatures
+// carry a valid proof of non-revocation for the current epoch.
+// The current epoch is set by the credential revocation information passed to UpdateCRI.
+func NewDeserializerWithRevocation(ipk []byte, revocationPK []byte) (*idd, error) {
+	if len(ipk) == 0 || len(revocationPK) == 0 {
+		return nil, errors.New("issuer public key and revocation public key must be set")
+	}
+	d, err := NewDeserializer(ipk)
+	if err != nil {
+		return nil, err
+	}
+	d.revocationPK, err = d.csp.KeyImport(
+		revocationPK,
+		&csp.IdemixRevocationPublicKeyImportOpts{Temporary: true},
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to import revocation public key")
+	}
+	return d, nil
+}
+
+// NewDeserializerFromConfig returns a verification-only deserializer for the passed idemix MSP configuration.
+// When the configuration carries a revocation public key, the deserializer checks revocation once it receives
+// the credential revocation information of an epoch.
+func NewDeserializerFromConfig(conf *m.MSPConfig) (*idd, error) {
+	if conf == nil {
+		return nil, errors.New("setup error: nil conf reference")
+	}
+	idemixConf := &m.IdemixMSPConfig{}
+	if err := proto.Unmarshal(conf.Config, idemixConf); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling idemix msp config")
+	}
+	var d *idd
+	var err error
+	if len(idemixConf.RevocationPk) == 0 {
+		d, err = NewDeserializer(idemixConf.Ipk)
+	} else {
+		d, err = NewDeserializerWithRevocation(idemixConf.Ipk, idemixConf.RevocationPk)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed setting up idemix deserializer [%s]", idemixConf.Name)
+	}
+	d.name = idemixConf.Name
+	return d, nil
+}
+
+// IsVerificationOnly returns true if the passed idemix MSP configuration carries no key material to sign
+func IsVerificationOnly(conf *m.MSPConfig) (bool, error) {
+	idemixConf := &m.IdemixMSPConfig{}
+	if err := proto.Unmarshal(conf.Config, idemixConf); err != nil {
+		return false, errors.Wrap(err, "failed unmarshalling idemix msp config")
+	}
+	return idemixConf.Signer == nil, nil
+}
+
 func (i *idd) DeserializeVerifier(raw []byte) (api.Verifier, error) {
-	r, err := i.Deserialize(raw, false)
+	// When the revocation public key is known, the identity must be valid in the current epoch
+	r, err := i.Deserialize(raw, i.revocationPK != nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +127,8 @@ func (i *idd) DeserializeVerifier(raw []byte) (api.Verifier, error) {
 	return &verifier{
 		idd:          i,
 		nymPublicKey: r.NymPublicKey,
+		ou:           r.ou,
+		role:         r.role,
 	}, nil
 }
 
@@ -104,9 +164,15 @@ func (i *idd) String() string {
 type verifier struct {
 	idd          *idd
 	nymPublicKey bccsp.Key
+	ou           *m.OrganizationUnit
+	role         *m.MSPRole
 }
 
 func (v *verifier) Verify(message, sigma []byte) error {
+	if _, _, alg := v.idd.revocation(); alg != csp.AlgNoRevocation {
+		return v.idd.verifyNonRevoked(v.nymPublicKey, v.ou, v.role, message, sigma)
+	}
+
 	_, err := v.idd.csp.Verify(
 		v.nymPublicKey,
 		sigma,
@@ -58,7 +58,7 @@ func (id *identity) Anonymous() bool {
 }
 
 func (id *identity) ExpiresAt() time.Time {
-	// Idemix MSP currently does not use expiration dates or revocation,
+	// Idemix MSP currently does not use expiration dates, revocation is handled by epochs,
 	// so we return the zero time to indicate this.
 	return time.Time{}
 }
@@ -91,6 +91,10 @@ func (id *identity) Validate() error {
 }
 
 func (id *identity) Verify(msg []byte, sig []byte) error {
+	if _, _, alg := id.support.revocation(); alg != csp.AlgNoRevocation {
+		return id.support.verifyNonRevoked(id.NymPublicKey, id.OU, id.Role, msg, sig)
+	}
+
 	_, err := id.support.csp.Verify(
 		id.NymPublicKey,
 		sig,
@@ -152,7 +156,7 @@ func (id *identity) verifyProof() error {
 		id.associationProof,
 		nil,
 		&csp.IdemixSignerOpts{
-			RevocationPublicKey: id.support.revocationPK,
+			RevocationPublicKey: id.support.verificationRevocationPK(),
 			Attributes: []csp.IdemixAttribute{
 				{Type: csp.IdemixBytesAttribute, Value: []byte(id.OU.OrganizationalUnitIdentifier)},
 				{Type: csp.IdemixIntAttribute, Value: getIdemixRoleFromMSPRole(id.Role)},
@@ -160,7 +164,7 @@ func (id *identity) verifyProof() error {
 				{Type: csp.IdemixHiddenAttribute},
 			},
 			RhIndex: rhIndex,
-			Epoch:   id.support.epoch,
+			Epoch:   id.support.Epoch(),
 		},
 	)
 	if err == nil && !valid {
@@ -181,6 +185,27 @@ type signingIdentity struct {
 func (id *signingIdentity) Sign(msg []byte) ([]byte, error) {
 	// logger.Debugf("Idemix identity %s is signing", id.GetIdentifier())
 
+	if _, cri, alg := id.support.revocation(); alg != csp.AlgNoRevocation {
+		// Prove, together with the signature, that the credential has not been revoked in the current epoch
+		return id.support.csp.Sign(
+			id.UserKey,
+			msg,
+			&csp.IdemixSignerOpts{
+				Credential: id.Cred,
+				Nym:        id.NymKey,
+				IssuerPK:   id.support.issuerPublicKey,
+				Attributes: []csp.IdemixAttribute{
+					{Type: csp.IdemixBytesAttribute},
+					{Type: csp.IdemixIntAttribute},
+					{Type: csp.IdemixHiddenAttribute},
+					{Type: csp.IdemixHiddenAttribute},
+				},
+				RhIndex: rhIndex,
+				CRI:     cri,
+			},
+		)
+	}
+
 	sig, err := id.support.csp.Sign(
 		id.UserKey,
 		msg,
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
@@ -45,7 +46,11 @@ type support struct {
 	csp             bccsp.BCCSP
 	issuerPublicKey bccsp.Key
 	revocationPK    bccsp.Key
-	epoch           int
+
+	revocationLock sync.RWMutex
+	epoch          int
+	cri            []byte
+	revocationAlg  csp.RevocationAlgorithm
 }
 
 func (s *support) Deserialize(raw []byte, checkValidity bool) (*deserialized, error) {
@@ -184,7 +189,7 @@ func NewProvider(conf1 *m.MSPConfig, sp view2.ServiceProvider) (*provider, error
 		return nil, errors.WithMessage(err, "failed importing signer secret key")
 	}
 
-	return &provider{
+	p := &provider{
 		support: &support{
 			name:            conf.Name,
 			csp:             cryptoProvider,
@@ -195,7 +200,13 @@ func NewProvider(conf1 *m.MSPConfig, sp view2.ServiceProvider) (*provider, error
 		userKey: userKey,
 		conf:    conf,
 		sp:      sp,
-	}, nil
+	}
+	if len(conf.Signer.CredentialRevocationInformation) != 0 {
+		if err := p.UpdateCRI(conf.Signer.CredentialRevocationInformation); err != nil {
+			return nil, errors.WithMessage(err, "failed setting up credential revocation information")
+		}
+	}
+	return p, nil
 }
 
 func (p *provider) Identity() (view.Identity, []byte, error) {
@@ -246,7 +257,8 @@ func (p *provider) Identity() (view.Identity, []byte, error) {
 		return nil, nil, errors.WithMessage(err, "Credential is not cryptographically valid")
 	}
 
-	// Create the cryptographic evidence that this identity is valid
+	// Create the cryptographic evidence that this identity is valid in the current epoch
+	_, cri, _ := p.revocation()
 	opts := &csp.IdemixSignerOpts{
 		Nym:        nymKey,
 		IssuerPK:   p.issuerPublicKey,
@@ -258,7 +270,7 @@ func (p *provider) Identity() (view.Identity, []byte, error) {
 			{Type: csp.IdemixHiddenAttribute},
 		},
 		RhIndex: rhIndex,
-		CRI:     p.conf.Signer.CredentialRevocationInformation,
+		CRI:     cri,
 	}
 	proof, err := p.csp.Sign(
 		p.userKey,
@@ -351,7 +363,8 @@ func (p *provider) SignerIdentity() (api.SigningIdentity, error) {
 		return nil, errors.WithMessage(err, "Credential is not cryptographically valid")
 	}
 
-	// Create the cryptographic evidence that this identity is valid
+	// Create the cryptographic evidence that this identity is valid in the current epoch
+	_, cri, _ := p.revocation()
 	proof, err := p.csp.Sign(
 		p.userKey,
 		nil,
@@ -366,7 +379,7 @@ func (p *provider) SignerIdentity() (api.SigningIdentity, error) {
 				{Type: csp.IdemixHiddenAttribute},
 			},
 			RhIndex: rhIndex,
-			CRI:     p.conf.Signer.CredentialRevocationInformation,
+			CRI:     cri,
 		},
 	)
 	if err != nil {
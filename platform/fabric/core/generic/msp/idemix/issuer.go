@@ -0,0 +1,592 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	m "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/csp/idemix/crypto"
+)
+
+const (
+	// IssuerPublicKeyFile and the other file names follow the layout used by the Fabric CA to store the issuer's key material
+	IssuerPublicKeyFile            = "IssuerPublicKey"
+	IssuerSecretKeyFile            = "IssuerSecretKey"
+	IssuerRevocationPublicKeyFile  = "IssuerRevocationPublicKey"
+	IssuerRevocationPrivateKeyFile = "IssuerRevocationPrivateKey"
+
+	// nonceTTL is how long a released nonce can be used to build a credential request
+	nonceTTL = 5 * time.Minute
+	// maxNoncesPerEnrollmentID bounds the nonces an enrollment ID can hold, the oldest is dropped to make room
+	maxNoncesPerEnrollmentID = 8
+	// saltSize is the size of the salt the secrets are hashed with
+	saltSize = 32
+)
+
+// Registration describes an identity that is entitled to receive an idemix credential
+type Registration struct {
+	EnrollmentID string
+	// SecretHash is the SHA256 of SecretSalt followed by the secret
+	SecretHash       []byte
+	SecretSalt       []byte
+	OU               string
+	Role             int
+	RevocationHandle int64
+	Revoked          bool
+}
+
+// RegistrationStore persists the registrations of an issuer
+type RegistrationStore interface {
+	Exists(id string) bool
+	Put(id string, state interface{}) error
+	Get(id string, state interface{}) error
+}
+
+// Issuer issues, renews and revokes idemix credentials.
+// The issuance follows the interactive protocol of the idemix library, the same used by the Fabric CA:
+// the issuer sends a fresh nonce to the user, the user answers with a credential request bound to that nonce,
+// and the issuer signs the user's commitment together with the attributes of the registration.
+// The issuer also plays the role of revocation authority: for each epoch, it publishes a credential revocation
+// information (CRI) that allows the holders of unrevoked credentials to prove they have not been revoked.
+type Issuer struct {
+	mspID         string
+	key           *crypto.IssuerKey
+	revocationKey *ecdsa.PrivateKey
+	store         RegistrationStore
+
+	lock  sync.Mutex
+	epoch int
+	// nonces maps each released nonce to the enrollment ID it has been released to.
+	// A nonce can be used by a single credential request, within nonceTTL from its release.
+	nonces map[string]*releasedNonce
+	// pending lists, oldest first, the nonces released to each enrollment ID and not used yet
+	pending map[string][]string
+	now     func() time.Time
+	// criRaw caches the CRI of the current epoch, it is reset when the set of unrevoked handles changes
+	criRaw []byte
+}
+
+// NewIssuer returns a new issuer for the passed MSP ID, with the passed key material.
+func NewIssuer(mspID string, key *crypto.IssuerKey, revocationKey *ecdsa.PrivateKey, store RegistrationStore) (*Issuer, error) {
+	if key == nil || revocationKey == nil {
+		return nil, errors.New("issuer key material not set")
+	}
+	if store == nil {
+		return nil, errors.New("registration store not set")
+	}
+	var epoch int
+	if store.Exists(epochKey) {
+		if err := store.Get(epochKey, &epoch); err != nil {
+			return nil, errors.Wrap(err, "failed getting current epoch")
+		}
+	}
+	return &Issuer{
+		mspID:         mspID,
+		key:           key,
+		revocationKey: revocationKey,
+		store:         store,
+		epoch:         epoch,
+		nonces:        map[string]*releasedNonce{},
+		pending:       map[string][]string{},
+		now:           time.Now,
+	}, nil
+}
+
+type releasedNonce struct {
+	enrollmentID string
+	released     time.Time
+}
+
+// GenerateIssuerKeys generates a fresh issuer key and revocation key and stores them in the passed folder
+// using the Fabric CA layout.
+func GenerateIssuerKeys(path string) error {
+	rng, err := crypto.GetRand()
+	if err != nil {
+		return errors.Wrap(err, "failed getting PRNG")
+	}
+	key, err := crypto.NewIssuerKey(
+		[]string{msp.AttributeNameOU, msp.AttributeNameRole, msp.AttributeNameEnrollmentId, msp.AttributeNameRevocationHandle},
+		rng,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed generating issuer key")
+	}
+	ipk, err := proto.Marshal(key.Ipk)
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling issuer public key")
+	}
+	revocationKey, err := crypto.GenerateLongTermRevocationKey()
+	if err != nil {
+		return errors.Wrap(err, "failed generating revocation key")
+	}
+	rsk, err := x509.MarshalECPrivateKey(revocationKey)
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling revocation private key")
+	}
+	rpk, err := revocationPublicKeyPEM(revocationKey)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return errors.Wrapf(err, "failed creating [%s]", path)
+	}
+	files := map[string][]byte{
+		IssuerPublicKeyFile:            ipk,
+		IssuerSecretKeyFile:            key.Isk,
+		IssuerRevocationPublicKeyFile:  rpk,
+		IssuerRevocationPrivateKeyFile: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rsk}),
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(path, name), content, 0600); err != nil {
+			return errors.Wrapf(err, "failed writing [%s]", name)
+		}
+	}
+	return nil
+}
+
+// LoadIssuer loads the issuer key material from the passed folder, stored using the Fabric CA layout.
+func LoadIssuer(mspID string, path string, store RegistrationStore) (*Issuer, error) {
+	ipkRaw, err := ioutil.ReadFile(filepath.Join(path, IssuerPublicKeyFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading issuer public key")
+	}
+	isk, err := ioutil.ReadFile(filepath.Join(path, IssuerSecretKeyFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading issuer secret key")
+	}
+	ipk := &crypto.IssuerPublicKey{}
+	if err := proto.Unmarshal(ipkRaw, ipk); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling issuer public key")
+	}
+	if err := ipk.Check(); err != nil {
+		return nil, errors.Wrap(err, "invalid issuer public key")
+	}
+
+	rskRaw, err := ioutil.ReadFile(filepath.Join(path, IssuerRevocationPrivateKeyFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading revocation private key")
+	}
+	block, _ := pem.Decode(rskRaw)
+	if block == nil {
+		return nil, errors.New("failed decoding revocation private key")
+	}
+	revocationKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing revocation private key")
+	}
+
+	return NewIssuer(mspID, &crypto.IssuerKey{Isk: isk, Ipk: ipk}, revocationKey, store)
+}
+
+// MSPID returns the identifier of the MSP the credentials are issued for
+func (i *Issuer) MSPID() string {
+	return i.mspID
+}
+
+// PublicKey returns the serialized issuer public key
+func (i *Issuer) PublicKey() ([]byte, error) {
+	return proto.Marshal(i.key.Ipk)
+}
+
+// RevocationPublicKey returns the PEM encoded revocation public key
+func (i *Issuer) RevocationPublicKey() ([]byte, error) {
+	return revocationPublicKeyPEM(i.revocationKey)
+}
+
+// Register entitles the passed enrollment ID to receive a credential with the passed OU and role.
+// The secret must be presented when asking for a nonce.
+// Role is a bitmask of idemix roles, see GetRoleMaskFromIdemixRole.
+func (i *Issuer) Register(enrollmentID string, secret string, ou string, role int) error {
+	if len(enrollmentID) == 0 || len(secret) == 0 || len(ou) == 0 {
+		return errors.New("enrollment id, secret and ou must be set")
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	k := registrationKey(enrollmentID)
+	if i.store.Exists(k) {
+		return errors.Errorf("enrollment id [%s] already registered", enrollmentID)
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return errors.Wrap(err, "failed generating salt")
+	}
+	return i.store.Put(k, &Registration{EnrollmentID: enrollmentID, SecretHash: secretHash(salt, secret), SecretSalt: salt, OU: ou, Role: role})
+}
+
+// Registration returns the registration of the passed enrollment ID
+func (i *Issuer) Registration(enrollmentID string) (*Registration, error) {
+	r := &Registration{}
+	if err := i.store.Get(registrationKey(enrollmentID), r); err != nil {
+		return nil, errors.Wrapf(err, "enrollment id [%s] not registered", enrollmentID)
+	}
+	return r, nil
+}
+
+// NewNonce authenticates the passed enrollment ID and returns the nonce to be used to build its credential request
+func (i *Issuer) NewNonce(enrollmentID string, secret string) ([]byte, error) {
+	r, err := i.Registration(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(secretHash(r.SecretSalt, secret), r.SecretHash) != 1 {
+		return nil, errors.Errorf("invalid secret for [%s]", enrollmentID)
+	}
+	if r.Revoked {
+		return nil, errors.Errorf("enrollment id [%s] has been revoked", enrollmentID)
+	}
+
+	rng, err := crypto.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting PRNG")
+	}
+	nonce := crypto.BigToBytes(crypto.RandModOrder(rng))
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.expireNonces()
+	if pending := i.pending[enrollmentID]; len(pending) >= maxNoncesPerEnrollmentID {
+		i.forgetNonce(pending[0])
+	}
+	i.nonces[string(nonce)] = &releasedNonce{enrollmentID: enrollmentID, released: i.now()}
+	i.pending[enrollmentID] = append(i.pending[enrollmentID], string(nonce))
+	return nonce, nil
+}
+
+// expireNonces forgets the nonces released more than nonceTTL ago
+func (i *Issuer) expireNonces() {
+	for nonce, released := range i.nonces {
+		if i.now().Sub(released.released) > nonceTTL {
+			i.forgetNonce(nonce)
+		}
+	}
+}
+
+func (i *Issuer) forgetNonce(nonce string) {
+	released, ok := i.nonces[nonce]
+	if !ok {
+		return
+	}
+	delete(i.nonces, nonce)
+	var pending []string
+	for _, n := range i.pending[released.enrollmentID] {
+		if n != nonce {
+			pending = append(pending, n)
+		}
+	}
+	if len(pending) == 0 {
+		delete(i.pending, released.enrollmentID)
+		return
+	}
+	i.pending[released.enrollmentID] = pending
+}
+
+// Issue verifies the passed serialized credential request and issues a credential for the passed enrollment ID.
+// The request must be bound to a nonce released to the enrollment ID and not used yet.
+// If the enrollment ID already holds a credential, the old credential is revoked, starting from the next epoch,
+// and a new one with a fresh revocation handle is issued.
+// Issue returns the serialized credential and the credential revocation information of the current epoch.
+func (i *Issuer) Issue(enrollmentID string, request []byte) ([]byte, []byte, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	credRequest := &crypto.CredRequest{}
+	if err := proto.Unmarshal(request, credRequest); err != nil {
+		return nil, nil, errors.Wrap(err, "failed unmarshalling credential request")
+	}
+	i.expireNonces()
+	released, ok := i.nonces[string(credRequest.IssuerNonce)]
+	if !ok || released.enrollmentID != enrollmentID {
+		return nil, nil, errors.Errorf("credential request not bound to a nonce released to [%s]", enrollmentID)
+	}
+	i.forgetNonce(string(credRequest.IssuerNonce))
+
+	r := &Registration{}
+	if err := i.store.Get(registrationKey(enrollmentID), r); err != nil {
+		return nil, nil, errors.Wrapf(err, "enrollment id [%s] not registered", enrollmentID)
+	}
+	if r.Revoked {
+		return nil, nil, errors.Errorf("enrollment id [%s] has been revoked", enrollmentID)
+	}
+
+	if err := credRequest.Check(i.key.Ipk); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid credential request")
+	}
+
+	rh, err := i.nextRevocationHandle()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rng, err := crypto.GetRand()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed getting PRNG")
+	}
+	attrs := make([]*FP256BN.BIG, 4)
+	attrs[msp.AttributeIndexOU] = crypto.HashModOrder([]byte(r.OU))
+	attrs[msp.AttributeIndexRole] = FP256BN.NewBIGint(r.Role)
+	attrs[msp.AttributeIndexEnrollmentId] = crypto.HashModOrder([]byte(r.EnrollmentID))
+	attrs[msp.AttributeIndexRevocationHandle] = FP256BN.NewBIGint(int(rh))
+	cred, err := crypto.NewCredential(i.key, credRequest, attrs, rng)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed issuing credential")
+	}
+	credRaw, err := proto.Marshal(cred)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed marshalling credential")
+	}
+
+	// store the new handle in the registration first, a failure here leaves the set of unrevoked handles untouched
+	old := r.RevocationHandle
+	r.RevocationHandle = rh
+	if err := i.store.Put(registrationKey(enrollmentID), r); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed storing registration of [%s]", enrollmentID)
+	}
+	if err := i.updateUnrevokedHandles(old, rh); err != nil {
+		return nil, nil, err
+	}
+	criRaw, err := i.cri()
+	if err != nil {
+		return nil, nil, err
+	}
+	return credRaw, criRaw, nil
+}
+
+// Revoke revokes the credentials of the passed enrollment ID and moves to a new epoch.
+// The revoked credential cannot be used to sign once the verifiers have moved to the new epoch,
+// and the revoked enrollment ID cannot renew its credential.
+func (i *Issuer) Revoke(enrollmentID string) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	r := &Registration{}
+	if err := i.store.Get(registrationKey(enrollmentID), r); err != nil {
+		return errors.Wrapf(err, "enrollment id [%s] not registered", enrollmentID)
+	}
+	r.Revoked = true
+	if err := i.store.Put(registrationKey(enrollmentID), r); err != nil {
+		return errors.Wrapf(err, "failed storing registration of [%s]", enrollmentID)
+	}
+	if err := i.updateUnrevokedHandles(r.RevocationHandle, 0); err != nil {
+		return err
+	}
+	return i.nextEpoch()
+}
+
+// NextEpoch moves to a new epoch. Credentials revoked or renewed in the previous epochs cannot be used in the new one.
+func (i *Issuer) NextEpoch() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.nextEpoch()
+}
+
+// Epoch returns the current epoch
+func (i *Issuer) Epoch() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.epoch
+}
+
+// CRI returns the serialized credential revocation information of the current epoch.
+// The holders of an unrevoked credential need it to sign, verifiers need it to learn the current epoch.
+func (i *Issuer) CRI() ([]byte, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.cri()
+}
+
+func (i *Issuer) nextEpoch() error {
+	if err := i.store.Put(epochKey, i.epoch+1); err != nil {
+		return errors.Wrap(err, "failed storing epoch")
+	}
+	i.epoch++
+	i.criRaw = nil
+	logger.Debugf("issuer [%s] moved to epoch [%d]", i.mspID, i.epoch)
+	return nil
+}
+
+// cri returns the serialized credential revocation information for the current epoch
+func (i *Issuer) cri() ([]byte, error) {
+	if i.criRaw != nil {
+		return i.criRaw, nil
+	}
+
+	var unrevoked []int64
+	if i.store.Exists(unrevokedKey) {
+		if err := i.store.Get(unrevokedKey, &unrevoked); err != nil {
+			return nil, errors.Wrap(err, "failed getting unrevoked handles")
+		}
+	}
+	handles := make([]*FP256BN.BIG, len(unrevoked))
+	for j, rh := range unrevoked {
+		handles[j] = FP256BN.NewBIGint(int(rh))
+	}
+	rng, err := crypto.GetRand()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting PRNG")
+	}
+	cri, err := crypto.CreateCRI(i.revocationKey, handles, i.epoch, crypto.ALG_PLAIN_SIGNATURE, rng)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating credential revocation information")
+	}
+	raw, err := proto.Marshal(cri)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling credential revocation information")
+	}
+	i.criRaw = raw
+	return raw, nil
+}
+
+// updateUnrevokedHandles removes the revoked handle, if not zero, from the set of unrevoked handles,
+// and adds the issued one, if not zero
+func (i *Issuer) updateUnrevokedHandles(revoked, issued int64) error {
+	var unrevoked []int64
+	if i.store.Exists(unrevokedKey) {
+		if err := i.store.Get(unrevokedKey, &unrevoked); err != nil {
+			return errors.Wrap(err, "failed getting unrevoked handles")
+		}
+	}
+	var res []int64
+	for _, rh := range unrevoked {
+		if rh != revoked {
+			res = append(res, rh)
+		}
+	}
+	if issued != 0 {
+		res = append(res, issued)
+	}
+	if err := i.store.Put(unrevokedKey, res); err != nil {
+		return errors.Wrap(err, "failed storing unrevoked handles")
+	}
+	i.criRaw = nil
+	return nil
+}
+
+func (i *Issuer) nextRevocationHandle() (int64, error) {
+	k := "fabric-sdk.msp.idemix.issuer.rh"
+	var rh int64
+	if i.store.Exists(k) {
+		if err := i.store.Get(k, &rh); err != nil {
+			return 0, errors.Wrap(err, "failed getting last revocation handle")
+		}
+	}
+	rh++
+	if err := i.store.Put(k, rh); err != nil {
+		return 0, errors.Wrap(err, "failed storing last revocation handle")
+	}
+	return rh, nil
+}
+
+// NewCredentialRequest generates a fresh user secret key and a credential request for the passed issuer public key
+// and nonce. It returns the serialized user secret key and credential request.
+func NewCredentialRequest(ipk []byte, nonce []byte) ([]byte, []byte, error) {
+	issuerPublicKey := &crypto.IssuerPublicKey{}
+	if err := proto.Unmarshal(ipk, issuerPublicKey); err != nil {
+		return nil, nil, errors.Wrap(err, "failed unmarshalling issuer public key")
+	}
+	rng, err := crypto.GetRand()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed getting PRNG")
+	}
+	sk := crypto.RandModOrder(rng)
+	request, err := proto.Marshal(crypto.NewCredRequest(sk, nonce, issuerPublicKey, rng))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed marshalling credential request")
+	}
+	return crypto.BigToBytes(sk), request, nil
+}
+
+// WriteMSPFolder verifies the passed credential and stores it, together with the issuer's public keys, in the passed
+// folder using the layout expected by the Fabric MSP. The resulting folder can be loaded with RegisterIdemixMSP.
+func WriteMSPFolder(path string, ipk, revocationPK []byte, sk, cred, cri []byte, ou string, role int, enrollmentID string) error {
+	issuerPublicKey := &crypto.IssuerPublicKey{}
+	if err := proto.Unmarshal(ipk, issuerPublicKey); err != nil {
+		return errors.Wrap(err, "failed unmarshalling issuer public key")
+	}
+	credential := &crypto.Credential{}
+	if err := proto.Unmarshal(cred, credential); err != nil {
+		return errors.Wrap(err, "failed unmarshalling credential")
+	}
+	if err := credential.Ver(FP256BN.FromBytes(sk), issuerPublicKey); err != nil {
+		return errors.Wrap(err, "invalid credential")
+	}
+
+	signer, err := proto.Marshal(&m.IdemixMSPSignerConfig{
+		Cred:                            cred,
+		Sk:                              sk,
+		OrganizationalUnitIdentifier:    ou,
+		Role:                            int32(role),
+		EnrollmentId:                    enrollmentID,
+		CredentialRevocationInformation: cri,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling signer config")
+	}
+
+	mspDir := filepath.Join(path, msp.IdemixConfigDirMsp)
+	userDir := filepath.Join(path, msp.IdemixConfigDirUser)
+	for _, dir := range []string{mspDir, userDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Wrapf(err, "failed creating [%s]", dir)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(mspDir, msp.IdemixConfigFileIssuerPublicKey), ipk, 0644); err != nil {
+		return errors.Wrap(err, "failed writing issuer public key")
+	}
+	if err := ioutil.WriteFile(filepath.Join(mspDir, msp.IdemixConfigFileRevocationPublicKey), revocationPK, 0644); err != nil {
+		return errors.Wrap(err, "failed writing revocation public key")
+	}
+	if err := ioutil.WriteFile(filepath.Join(userDir, msp.IdemixConfigFileSigner), signer, 0600); err != nil {
+		return errors.Wrap(err, "failed writing signer config")
+	}
+	return nil
+}
+
+func revocationPublicKeyPEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	raw, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling revocation public key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: raw}), nil
+}
+
+const (
+	epochKey     = "fabric-sdk.msp.idemix.issuer.epoch"
+	unrevokedKey = "fabric-sdk.msp.idemix.issuer.unrevoked"
+)
+
+// secretHash returns the SHA256 of the passed salt followed by the passed secret.
+// Registrations stored without salt are hashed with the secret alone.
+func secretHash(salt []byte, secret string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secret))
+	return h.Sum(nil)
+}
+
+func registrationKey(enrollmentID string) string {
+	return "fabric-sdk.msp.idemix.issuer.registration." + enrollmentID
+}
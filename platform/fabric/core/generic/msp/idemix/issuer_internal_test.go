@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapStore map[string][]byte
+
+func (m mapStore) Exists(id string) bool {
+	_, ok := m[id]
+	return ok
+}
+
+func (m mapStore) Put(id string, state interface{}) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	m[id] = raw
+	return nil
+}
+
+func (m mapStore) Get(id string, state interface{}) error {
+	raw, ok := m[id]
+	if !ok {
+		return errors.Errorf("[%s] not found", id)
+	}
+	return json.Unmarshal(raw, state)
+}
+
+func TestIssuerNonces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idemix-issuer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, GenerateIssuerKeys(dir))
+	issuer, err := LoadIssuer("IdemixOrgMSP", dir, mapStore{})
+	assert.NoError(t, err)
+	now := time.Now()
+	issuer.now = func() time.Time { return now }
+
+	// secrets are stored salted
+	assert.NoError(t, issuer.Register("alice", "secret", "OU1", GetRoleMaskFromIdemixRole(MEMBER)))
+	r, err := issuer.Registration("alice")
+	assert.NoError(t, err)
+	assert.Len(t, r.SecretSalt, saltSize)
+	unsalted := sha256.Sum256([]byte("secret"))
+	assert.NotEqual(t, unsalted[:], r.SecretHash)
+	_, err = issuer.NewNonce("alice", "wrong secret")
+	assert.Error(t, err)
+
+	// the nonces of an enrollment id are bounded, the oldest are dropped
+	var nonces [][]byte
+	for j := 0; j < maxNoncesPerEnrollmentID+2; j++ {
+		nonce, err := issuer.NewNonce("alice", "secret")
+		assert.NoError(t, err)
+		nonces = append(nonces, nonce)
+	}
+	assert.Len(t, issuer.nonces, maxNoncesPerEnrollmentID)
+	assert.Len(t, issuer.pending["alice"], maxNoncesPerEnrollmentID)
+	assert.NotContains(t, issuer.nonces, string(nonces[0]))
+	assert.NotContains(t, issuer.nonces, string(nonces[1]))
+	assert.Contains(t, issuer.nonces, string(nonces[2]))
+
+	// nonces expire
+	ipk, err := issuer.PublicKey()
+	assert.NoError(t, err)
+	_, request, err := NewCredentialRequest(ipk, nonces[2])
+	assert.NoError(t, err)
+	now = now.Add(nonceTTL + time.Second)
+	_, _, err = issuer.Issue("alice", request)
+	assert.Error(t, err)
+	assert.Empty(t, issuer.nonces)
+	assert.Empty(t, issuer.pending)
+
+	nonce, err := issuer.NewNonce("alice", "secret")
+	assert.NoError(t, err)
+	_, request, err = NewCredentialRequest(ipk, nonce)
+	assert.NoError(t, err)
+	_, _, err = issuer.Issue("alice", request)
+	assert.NoError(t, err)
+	assert.Empty(t, issuer.nonces)
+	assert.Empty(t, issuer.pending)
+}
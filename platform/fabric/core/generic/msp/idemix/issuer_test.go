@@ -0,0 +1,269 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix_test
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	msp2 "github.com/hyperledger/fabric/msp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/csp/idemix/crypto"
+	idemix2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/idemix"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
+	sig2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/sig"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	registry2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/registry"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+func TestIssuer(t *testing.T) {
+	registry := registry2.New()
+	registry.RegisterService(&fakeProv{typ: "memory"})
+
+	kvss, err := kvs.New("memory", "", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+	sigService := sig2.NewSignService(registry, nil)
+	assert.NoError(t, registry.RegisterService(sigService))
+
+	dir, err := ioutil.TempDir("", "idemix-issuer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, idemix2.GenerateIssuerKeys(filepath.Join(dir, "issuer")))
+	issuer, err := idemix2.LoadIssuer("IdemixOrgMSP", filepath.Join(dir, "issuer"), kvss)
+	assert.NoError(t, err)
+
+	role := idemix2.GetRoleMaskFromIdemixRole(idemix2.MEMBER)
+	assert.NoError(t, issuer.Register("alice", "secret", "OU1", role))
+	assert.Error(t, issuer.Register("alice", "secret", "OU1", role))
+
+	ipk, err := issuer.PublicKey()
+	assert.NoError(t, err)
+	rpk, err := issuer.RevocationPublicKey()
+	assert.NoError(t, err)
+
+	// a request must be bound to a nonce
+	_, request, err := idemix2.NewCredentialRequest(ipk, []byte("a nonce"))
+	assert.NoError(t, err)
+	_, _, err = issuer.Issue("alice", request)
+	assert.Error(t, err)
+
+	_, err = issuer.NewNonce("alice", "wrong secret")
+	assert.Error(t, err)
+	nonce, err := issuer.NewNonce("alice", "secret")
+	assert.NoError(t, err)
+	sk, request, err := idemix2.NewCredentialRequest(ipk, nonce)
+	assert.NoError(t, err)
+	cred, cri, err := issuer.Issue("alice", request)
+	assert.NoError(t, err)
+	r, err := issuer.Registration("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), r.RevocationHandle)
+
+	path := filepath.Join(dir, "alice")
+	assert.NoError(t, idemix2.WriteMSPFolder(path, ipk, rpk, sk, cred, cri, "OU1", role, "alice"))
+
+	config, err := msp2.GetLocalMspConfigWithType(path, nil, "IdemixOrgMSP", "idemix")
+	assert.NoError(t, err)
+	p, err := idemix2.NewProvider(config, registry)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", p.EnrollmentID())
+	id, _, err := p.Identity()
+	assert.NoError(t, err)
+	signer, err := p.DeserializeSigner(id)
+	assert.NoError(t, err)
+	verifier, err := p.DeserializeVerifier(id)
+	assert.NoError(t, err)
+	sigma, err := signer.Sign([]byte("hello world!!!"))
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.Verify([]byte("hello world!!!"), sigma))
+
+	// renewal assigns a fresh revocation handle
+	nonce, err = issuer.NewNonce("alice", "secret")
+	assert.NoError(t, err)
+	_, request, err = idemix2.NewCredentialRequest(ipk, nonce)
+	assert.NoError(t, err)
+	_, _, err = issuer.Issue("alice", request)
+	assert.NoError(t, err)
+	r, err = issuer.Registration("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), r.RevocationHandle)
+
+	// nonces are bound to a single request, concurrent requests do not clobber each other
+	assert.NoError(t, issuer.Register("bob", "secret", "OU1", role))
+	nonce1, err := issuer.NewNonce("alice", "secret")
+	assert.NoError(t, err)
+	nonce2, err := issuer.NewNonce("alice", "secret")
+	assert.NoError(t, err)
+	bobNonce, err := issuer.NewNonce("bob", "secret")
+	assert.NoError(t, err)
+	_, request1, err := idemix2.NewCredentialRequest(ipk, nonce1)
+	assert.NoError(t, err)
+	_, request2, err := idemix2.NewCredentialRequest(ipk, nonce2)
+	assert.NoError(t, err)
+	_, bobRequest, err := idemix2.NewCredentialRequest(ipk, bobNonce)
+	assert.NoError(t, err)
+	_, _, err = issuer.Issue("alice", bobRequest)
+	assert.Error(t, err)
+	_, _, err = issuer.Issue("alice", request2)
+	assert.NoError(t, err)
+	_, _, err = issuer.Issue("alice", request1)
+	assert.NoError(t, err)
+	_, _, err = issuer.Issue("alice", request1)
+	assert.Error(t, err)
+	_, _, err = issuer.Issue("bob", bobRequest)
+	assert.NoError(t, err)
+
+	// revoked enrollment ids cannot renew
+	assert.NoError(t, issuer.Revoke("alice"))
+	_, err = issuer.NewNonce("alice", "secret")
+	assert.Error(t, err)
+}
+
+func TestRevocation(t *testing.T) {
+	registry := registry2.New()
+	registry.RegisterService(&fakeProv{typ: "memory"})
+
+	kvss, err := kvs.New("memory", "", registry)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.RegisterService(kvss))
+	sigService := sig2.NewSignService(registry, nil)
+	assert.NoError(t, registry.RegisterService(sigService))
+
+	dir, err := ioutil.TempDir("", "idemix-revocation")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, idemix2.GenerateIssuerKeys(filepath.Join(dir, "issuer")))
+	issuer, err := idemix2.LoadIssuer("IdemixOrgMSP", filepath.Join(dir, "issuer"), kvss)
+	assert.NoError(t, err)
+	ipk, err := issuer.PublicKey()
+	assert.NoError(t, err)
+	rpk, err := issuer.RevocationPublicKey()
+	assert.NoError(t, err)
+
+	type provider interface {
+		Identity() (view.Identity, []byte, error)
+		DeserializeSigner(raw []byte) (api.Signer, error)
+		UpdateCRI(raw []byte) error
+	}
+	role := idemix2.GetRoleMaskFromIdemixRole(idemix2.MEMBER)
+	enroll := func(eid string) (provider, []byte, []byte) {
+		assert.NoError(t, issuer.Register(eid, "secret", "OU1", role))
+		nonce, err := issuer.NewNonce(eid, "secret")
+		assert.NoError(t, err)
+		sk, request, err := idemix2.NewCredentialRequest(ipk, nonce)
+		assert.NoError(t, err)
+		cred, cri, err := issuer.Issue(eid, request)
+		assert.NoError(t, err)
+		path := filepath.Join(dir, eid)
+		assert.NoError(t, idemix2.WriteMSPFolder(path, ipk, rpk, sk, cred, cri, "OU1", role, eid))
+		config, err := msp2.GetLocalMspConfigWithType(path, nil, "IdemixOrgMSP", "idemix")
+		assert.NoError(t, err)
+		p, err := idemix2.NewProvider(config, registry)
+		assert.NoError(t, err)
+		id, _, err := p.Identity()
+		assert.NoError(t, err)
+		return p, id, cri
+	}
+	alice, aliceID, oldCRI := enroll("alice")
+	bob, bobID, _ := enroll("bob")
+	// alice got the CRI of the current epoch before bob was issued, she must refresh it
+	cri, err := issuer.CRI()
+	assert.NoError(t, err)
+	assert.NoError(t, alice.UpdateCRI(cri))
+
+	// a verification-only msp, as loaded from a configuration with no key material
+	verifierPath := filepath.Join(dir, "verifier")
+	assert.NoError(t, os.MkdirAll(filepath.Join(verifierPath, msp2.IdemixConfigDirMsp), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(verifierPath, msp2.IdemixConfigDirMsp, msp2.IdemixConfigFileIssuerPublicKey), ipk, 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(verifierPath, msp2.IdemixConfigDirMsp, msp2.IdemixConfigFileRevocationPublicKey), rpk, 0644))
+	verifierConfig, err := msp2.GetLocalMspConfigWithType(verifierPath, nil, "IdemixOrgMSP", "idemix")
+	assert.NoError(t, err)
+	verificationOnly, err := idemix2.IsVerificationOnly(verifierConfig)
+	assert.NoError(t, err)
+	assert.True(t, verificationOnly)
+	verifier, err := idemix2.NewDeserializerFromConfig(verifierConfig)
+	assert.NoError(t, err)
+	// until it gets a CRI, the verifier does not check revocation and refuses identities using it
+	_, err = verifier.DeserializeVerifier(aliceID)
+	assert.Error(t, err)
+	assert.NoError(t, verifier.UpdateCRI(cri))
+
+	sign := func(p provider, id []byte) ([]byte, error) {
+		signer, err := p.DeserializeSigner(id)
+		assert.NoError(t, err)
+		return signer.Sign([]byte("hello world!!!"))
+	}
+	verify := func(id []byte, sigma []byte) error {
+		v, err := verifier.DeserializeVerifier(id)
+		if err != nil {
+			return err
+		}
+		return v.Verify([]byte("hello world!!!"), sigma)
+	}
+	aliceSigma, err := sign(alice, aliceID)
+	assert.NoError(t, err)
+	assert.NoError(t, verify(aliceID, aliceSigma))
+	bobSigner, err := bob.DeserializeSigner(bobID)
+	assert.NoError(t, err)
+	bobSigma, err := bobSigner.Sign([]byte("hello world!!!"))
+	assert.NoError(t, err)
+	assert.NoError(t, verify(bobID, bobSigma))
+
+	// revoke bob and move everybody to the next epoch
+	assert.NoError(t, issuer.Revoke("bob"))
+	assert.Equal(t, 1, issuer.Epoch())
+	cri, err = issuer.CRI()
+	assert.NoError(t, err)
+	assert.NoError(t, verifier.UpdateCRI(cri))
+	assert.NoError(t, alice.UpdateCRI(cri))
+	assert.NoError(t, bob.UpdateCRI(cri))
+	assert.Equal(t, 1, verifier.Epoch())
+
+	// identities and signatures of the previous epoch are not valid anymore
+	assert.Error(t, verify(aliceID, aliceSigma))
+	assert.Error(t, verify(bobID, bobSigma))
+
+	// alice can still sign with a fresh identity
+	aliceID, _, err = alice.Identity()
+	assert.NoError(t, err)
+	aliceSigma, err = sign(alice, aliceID)
+	assert.NoError(t, err)
+	assert.NoError(t, verify(aliceID, aliceSigma))
+
+	// bob cannot
+	_, _, err = bob.Identity()
+	assert.Error(t, err)
+	_, err = bobSigner.Sign([]byte("hello world!!!"))
+	assert.Error(t, err)
+
+	// stale CRIs are refused
+	assert.Error(t, verifier.UpdateCRI(oldCRI))
+	assert.Error(t, alice.UpdateCRI(oldCRI))
+
+	// CRIs not signed by the revocation authority are refused
+	assert.NoError(t, issuer.NextEpoch())
+	cri, err = issuer.CRI()
+	assert.NoError(t, err)
+	forged := &crypto.CredentialRevocationInformation{}
+	assert.NoError(t, proto.Unmarshal(cri, forged))
+	otherKey, err := crypto.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	forged.EpochPkSig, err = otherKey.Sign(rand.Reader, []byte("not the epoch key"), nil)
+	assert.NoError(t, err)
+	forgedRaw, err := proto.Marshal(forged)
+	assert.NoError(t, err)
+	assert.Error(t, verifier.UpdateCRI(forgedRaw))
+	assert.Equal(t, 1, verifier.Epoch())
+}
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	m "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/csp"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/csp/idemix/crypto"
+)
+
+// Epoch returns the current revocation epoch
+func (s *support) Epoch() int {
+	s.revocationLock.RLock()
+	defer s.revocationLock.RUnlock()
+	return s.epoch
+}
+
+// UpdateCRI verifies the passed credential revocation information (CRI) against the revocation public key and
+// makes it current. The CRI must not refer to an epoch older than the current one.
+// From then on, identities and signatures are checked against the epoch of the CRI, and, when the CRI uses
+// a revocation algorithm, signatures carry a proof that the signer's credential has not been revoked in that epoch.
+func (s *support) UpdateCRI(raw []byte) error {
+	if s.revocationPK == nil {
+		return errors.New("revocation public key not set")
+	}
+	cri := &crypto.CredentialRevocationInformation{}
+	if err := proto.Unmarshal(raw, cri); err != nil {
+		return errors.Wrap(err, "failed unmarshalling credential revocation information")
+	}
+	valid, err := s.csp.Verify(s.revocationPK, raw, nil, &csp.IdemixCRISignerOpts{
+		Epoch:               int(cri.Epoch),
+		RevocationAlgorithm: csp.RevocationAlgorithm(cri.RevocationAlg),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "invalid credential revocation information")
+	}
+	if !valid {
+		return errors.New("invalid credential revocation information")
+	}
+
+	s.revocationLock.Lock()
+	defer s.revocationLock.Unlock()
+	if int(cri.Epoch) < s.epoch {
+		return errors.Errorf("credential revocation information refers to epoch [%d], current epoch is [%d]", cri.Epoch, s.epoch)
+	}
+	s.epoch = int(cri.Epoch)
+	s.cri = raw
+	s.revocationAlg = csp.RevocationAlgorithm(cri.RevocationAlg)
+	logger.Debugf("idemix msp [%s] moved to epoch [%d] with revocation algorithm [%d]", s.name, s.epoch, s.revocationAlg)
+
+	return nil
+}
+
+// revocation returns the current epoch, credential revocation information, and revocation algorithm
+func (s *support) revocation() (int, []byte, csp.RevocationAlgorithm) {
+	s.revocationLock.RLock()
+	defer s.revocationLock.RUnlock()
+	return s.epoch, s.cri, s.revocationAlg
+}
+
+// verificationRevocationPK returns the revocation public key identities and signatures are verified against.
+// It is nil when revocation is not configured, that is, when no credential revocation information has been set.
+func (s *support) verificationRevocationPK() bccsp.Key {
+	s.revocationLock.RLock()
+	defer s.revocationLock.RUnlock()
+	if s.cri == nil {
+		return nil
+	}
+	return s.revocationPK
+}
+
+// verifyNonRevoked verifies a signature produced by signingIdentity.Sign when revocation is in use.
+// The signature must be bound to the passed pseudonym and prove that the signer's credential
+// has not been revoked in the current epoch.
+func (s *support) verifyNonRevoked(nymPublicKey bccsp.Key, ou *m.OrganizationUnit, role *m.MSPRole, msg, sigma []byte) error {
+	sig := &crypto.Signature{}
+	if err := proto.Unmarshal(sigma, sig); err != nil {
+		return errors.Wrap(err, "failed unmarshalling signature")
+	}
+	nym, err := nymPublicKey.Bytes()
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling nym public key")
+	}
+	if sig.Nym == nil || !bytes.Equal(nym, append(append([]byte{}, sig.Nym.X...), sig.Nym.Y...)) {
+		return errors.New("signature not bound to the identity's pseudonym")
+	}
+
+	_, err = s.csp.Verify(
+		s.issuerPublicKey,
+		sigma,
+		msg,
+		&csp.IdemixSignerOpts{
+			RevocationPublicKey: s.revocationPK,
+			Attributes: []csp.IdemixAttribute{
+				{Type: csp.IdemixBytesAttribute, Value: []byte(ou.OrganizationalUnitIdentifier)},
+				{Type: csp.IdemixIntAttribute, Value: getIdemixRoleFromMSPRole(role)},
+				{Type: csp.IdemixHiddenAttribute},
+				{Type: csp.IdemixHiddenAttribute},
+			},
+			RhIndex: rhIndex,
+			Epoch:   s.Epoch(),
+		},
+	)
+	return err
+}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/csp/idemix/crypto"
+)
+
+// rejectingCSP reports every signature as invalid, without an error
+type rejectingCSP struct {
+	bccsp.BCCSP
+}
+
+func (rejectingCSP) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return false, nil
+}
+
+type fakeKey struct {
+	bccsp.Key
+}
+
+func TestUpdateCRIInvalidSignature(t *testing.T) {
+	raw, err := proto.Marshal(&crypto.CredentialRevocationInformation{Epoch: 1, EpochPkSig: []byte("bad signature")})
+	assert.NoError(t, err)
+	s := &support{name: "IdemixOrgMSP", csp: rejectingCSP{}, revocationPK: fakeKey{}}
+	assert.EqualError(t, s.UpdateCRI(raw), "invalid credential revocation information")
+	assert.Equal(t, 0, s.Epoch())
+	assert.Nil(t, s.cri)
+}
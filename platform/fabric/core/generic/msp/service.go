@@ -17,6 +17,7 @@ import (
 	x5092 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/x509"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
 
+	msp2 "github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
 
@@ -61,6 +62,12 @@ type DeserializerManager interface {
 	AddDeserializer(deserializer sig2.Deserializer)
 }
 
+// revocable is implemented by the MSPs whose identities can be revoked by a revocation authority
+type revocable interface {
+	// UpdateCRI moves the MSP to the epoch of the passed credential revocation information
+	UpdateCRI(raw []byte) error
+}
+
 type Configuration struct {
 	ID      string `yaml:"id"`
 	MSPType string `yaml:"mspType"`
@@ -89,6 +96,7 @@ type service struct {
 	resolversByEnrollmentID  map[string]*Resolver
 	resolversByTypeAndName   map[string]*Resolver
 	bccspResolversByIdentity map[string]*Resolver
+	revocables               []revocable
 }
 
 func NewLocalMSPManager(sp view2.ServiceProvider, config Config, signerService SignerService, binderService BinderService) *service {
@@ -244,10 +252,48 @@ func (s *service) RegisterIdemixMSP(id string, path string, mspID string) error
 
 	s.deserializerManager().AddDeserializer(provider)
 	s.addResolver(id, IdemixMSP, provider.EnrollmentID(), provider.Identity)
+	s.revocables = append(s.revocables, provider)
 
 	return nil
 }
 
+// UpdateRevocationInformation passes the serialized credential revocation information to the idemix MSPs
+// whose revocation authority signed it, moving them to the epoch it refers to.
+// An error is returned if no MSP accepted it.
+func (s *service) UpdateRevocationInformation(cri []byte) error {
+	s.resolversMutex.RLock()
+	defer s.resolversMutex.RUnlock()
+
+	updated := 0
+	for _, r := range s.revocables {
+		if err := r.UpdateCRI(cri); err != nil {
+			logger.Debugf("credential revocation information not accepted: [%s]", err)
+			continue
+		}
+		updated++
+	}
+	if updated == 0 {
+		return errors.New("no msp accepted the credential revocation information")
+	}
+	return nil
+}
+
+// addIdemixVerifier registers a verification-only deserializer, that follows the revocation epochs,
+// if the passed idemix configuration carries no key material to sign. It returns true if it did so.
+func (s *service) addIdemixVerifier(conf *msp2.MSPConfig, dm DeserializerManager) (bool, error) {
+	verificationOnly, err := idemix2.IsVerificationOnly(conf)
+	if err != nil || !verificationOnly {
+		return false, err
+	}
+	verifier, err := idemix2.NewDeserializerFromConfig(conf)
+	if err != nil {
+		return false, err
+	}
+	dm.AddDeserializer(verifier)
+	s.revocables = append(s.revocables, verifier)
+	return true, nil
+}
+
 func (s *service) RegisterX509MSP(id string, path string, mspID string) error {
 	s.resolversMutex.Lock()
 	defer s.resolversMutex.Unlock()
@@ -273,6 +319,7 @@ func (s *service) Refresh() error {
 	s.bccspResolversByIdentity = map[string]*Resolver{}
 	s.resolversByEnrollmentID = map[string]*Resolver{}
 	s.resolversByName = map[string]*Resolver{}
+	s.revocables = nil
 
 	// reload
 	return s.Load()
@@ -389,12 +436,20 @@ func (s *service) loadExtraResolvers() error {
 			if err != nil {
 				return errors.Wrapf(err, "failed reading idemix msp configuration from [%s]", s.config.TranslatePath(config.Path))
 			}
-			provider, err = idemix2.NewProvider(conf, s.sp)
+			verifier, err := s.addIdemixVerifier(conf, dm)
+			if err != nil {
+				return errors.WithMessagef(err, "failed instantiating idemix msp verifier from [%s]", s.config.TranslatePath(config.Path))
+			}
+			if verifier {
+				continue
+			}
+			idemixProvider, err := idemix2.NewProvider(conf, s.sp)
 			if err != nil {
 				return errors.Wrapf(err, "failed instantiating idemix msp provider from [%s]", s.config.TranslatePath(config.Path))
 			}
-			dm.AddDeserializer(provider)
-			s.addResolver(config.ID, config.MSPType, provider.EnrollmentID(), provider.Identity)
+			dm.AddDeserializer(idemixProvider)
+			s.addResolver(config.ID, config.MSPType, idemixProvider.EnrollmentID(), idemixProvider.Identity)
+			s.revocables = append(s.revocables, idemixProvider)
 		case BccspMSP:
 			provider, err = x5092.NewProvider(s.config.TranslatePath(config.Path), config.MSPID, s.signerService)
 			if err != nil {
@@ -423,14 +478,23 @@ func (s *service) loadExtraResolvers() error {
 					logger.Warnf("failed reading idemix msp configuration from [%s]: [%s]", filepath.Join(s.config.TranslatePath(config.Path), id), err)
 					continue
 				}
-				provider, err = idemix2.NewProvider(conf, s.sp)
+				verifier, err := s.addIdemixVerifier(conf, dm)
+				if err != nil {
+					logger.Warnf("failed instantiating idemix msp verifier from [%s]: [%s]", filepath.Join(s.config.TranslatePath(config.Path), id), err)
+					continue
+				}
+				if verifier {
+					continue
+				}
+				idemixProvider, err := idemix2.NewProvider(conf, s.sp)
 				if err != nil {
 					logger.Warnf("failed instantiating idemix msp configuration from [%s]: [%s]", filepath.Join(s.config.TranslatePath(config.Path), id), err)
 					continue
 				}
-				dm.AddDeserializer(provider)
-				logger.Debugf("Adding resolver [%s:%s]", id, provider.EnrollmentID())
-				s.addResolver(id, IdemixMSP, provider.EnrollmentID(), provider.Identity)
+				dm.AddDeserializer(idemixProvider)
+				logger.Debugf("Adding resolver [%s:%s]", id, idemixProvider.EnrollmentID())
+				s.addResolver(id, IdemixMSP, idemixProvider.EnrollmentID(), idemixProvider.Identity)
+				s.revocables = append(s.revocables, idemixProvider)
 			}
 		case BccspMSPFolder:
 			entries, err := ioutil.ReadDir(s.config.TranslatePath(config.Path))
@@ -6,7 +6,9 @@ SPDX-License-Identifier: Apache-2.0
 package generic
 
 import (
+	"fmt"
 	"io/ioutil"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -41,18 +43,26 @@ type network struct {
 	transactionManager api.TransactionManager
 	sigService         api.SigService
 
+	configMutex    sync.RWMutex
 	tlsRootCerts   [][]byte
 	orderers       []*grpc.ConnectionConfig
 	peers          []*grpc.ConnectionConfig
 	defaultChannel string
 	channelDefs    []*Channel
 
-	ordering api.Ordering
+	ordering Ordering
 	channels map[string]api.Channel
 	mutex    sync.Mutex
 	name     string
 }
 
+// Ordering is the ordering service of a network, it tracks the transactions broadcast on each channel
+type Ordering interface {
+	api.Ordering
+	// Tracker returns the tracker of the transactions broadcast on the passed channel, nil if tracking is disabled
+	Tracker(channel string) *ordering.Tracker
+}
+
 func NewNetwork(
 	sp view2.ServiceProvider,
 	name string,
@@ -96,10 +106,14 @@ func (f *network) Channels() []string {
 }
 
 func (f *network) Orderers() []*grpc.ConnectionConfig {
+	f.configMutex.RLock()
+	defer f.configMutex.RUnlock()
 	return f.orderers
 }
 
 func (f *network) Peers() []*grpc.ConnectionConfig {
+	f.configMutex.RLock()
+	defer f.configMutex.RUnlock()
 	return f.peers
 }
 
@@ -138,6 +152,24 @@ func (f *network) Channel(name string) (api.Channel, error) {
 	return ch, nil
 }
 
+// Close closes all the channels opened so far
+func (f *network) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var errs []string
+	for name, ch := range f.channels {
+		logger.Debugf("closing channel [%s:%s]", f.name, name)
+		if err := ch.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("[%s]: %s", name, err))
+		}
+	}
+	if len(errs) != 0 {
+		return errors.Errorf("failed closing channels of [%s]: %s", f.name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 func (f *network) Ledger(name string) (api.Ledger, error) {
 	return f.Channel(name)
 }
@@ -167,9 +199,43 @@ func (f *network) TransactionManager() api.TransactionManager {
 }
 
 func (f *network) GetTLSRootCert(endorser view.Identity) ([][]byte, error) {
+	f.configMutex.RLock()
+	defer f.configMutex.RUnlock()
 	return f.tlsRootCerts, nil
 }
 
+// Reload re-reads the TLS root certificates, the orderers and the peers, and refreshes the local MSPs.
+// The channels keep their connections in place, the new ones use what has been reloaded.
+func (f *network) Reload() error {
+	tlsRootCerts, err := loadFile(f.config.TLSRootCertFile())
+	if err != nil {
+		return errors.Wrap(err, "failed loading tls root certificate")
+	}
+	orderers, err := f.config.Orderers()
+	if err != nil {
+		return errors.Wrap(err, "failed loading orderers")
+	}
+	if len(orderers) == 0 {
+		return errors.New("no orderers configured, keeping the current ones")
+	}
+	peers, err := f.config.Peers()
+	if err != nil {
+		return errors.Wrap(err, "failed loading peers")
+	}
+
+	f.configMutex.Lock()
+	f.tlsRootCerts = [][]byte{tlsRootCerts}
+	f.orderers = orderers
+	f.peers = peers
+	f.configMutex.Unlock()
+	logger.Debugf("Reloaded orderers [%v] and peers [%v]", orderers, peers)
+
+	if err := f.localMembership.Refresh(); err != nil {
+		return errors.WithMessage(err, "failed refreshing local msps")
+	}
+	return nil
+}
+
 func (f *network) Broadcast(blob interface{}) error {
 	return f.ordering.Broadcast(blob)
 }
@@ -212,10 +278,36 @@ func (f *network) init() error {
 		}
 	}
 
-	f.ordering = ordering.NewService(f.sp, f)
+	trackerConfig, err := f.trackerConfig()
+	if err != nil {
+		return err
+	}
+	f.ordering = ordering.NewService(f.sp, f, trackerConfig)
 	return nil
 }
 
+// trackerConfig returns the configuration of the tracking of the transactions broadcast, nil if disabled
+func (f *network) trackerConfig() (*ordering.TrackerConfig, error) {
+	if !f.config.OrderingTrackerEnabled() {
+		return nil, nil
+	}
+	waitBlocks, err := f.config.OrderingTrackerWaitBlocks()
+	if err != nil {
+		return nil, err
+	}
+	if waitBlocks == 0 {
+		waitBlocks = ordering.DefaultTrackerWaitBlocks
+	}
+	maxResubmissions, err := f.config.OrderingTrackerMaxResubmissions()
+	if err != nil {
+		return nil, err
+	}
+	if maxResubmissions == 0 {
+		maxResubmissions = ordering.DefaultTrackerMaxResubmissions
+	}
+	return &ordering.TrackerConfig{WaitBlocks: waitBlocks, MaxResubmissions: maxResubmissions}, nil
+}
+
 func loadFile(path string) ([]byte, error) {
 	if len(path) == 0 {
 		return nil, errors.New("file path must be set")
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package generic
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+type mapConfigService map[string]interface{}
+
+func (m mapConfigService) GetBool(s string) bool              { v, _ := m[s].(bool); return v }
+func (m mapConfigService) GetString(s string) string          { v, _ := m[s].(string); return v }
+func (m mapConfigService) GetDuration(s string) time.Duration { v, _ := m[s].(time.Duration); return v }
+func (m mapConfigService) GetPath(s string) string            { return m.GetString(s) }
+func (m mapConfigService) TranslatePath(path string) string   { return path }
+func (m mapConfigService) UnmarshalKey(s string, i interface{}) error {
+	v, ok := m[s]
+	if !ok {
+		return nil
+	}
+	if err, ok := v.(error); ok {
+		return err
+	}
+	reflect.ValueOf(i).Elem().Set(reflect.ValueOf(v))
+	return nil
+}
+
+type refreshingMembership struct {
+	api.LocalMembership
+	refreshes int
+}
+
+func (r *refreshingMembership) Refresh() error {
+	r.refreshes++
+	return nil
+}
+
+func TestNetworkReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "network-reload")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca1 := filepath.Join(dir, "ca1.pem")
+	assert.NoError(t, ioutil.WriteFile(ca1, []byte("ca1"), 0644))
+	ca2 := filepath.Join(dir, "ca2.pem")
+	assert.NoError(t, ioutil.WriteFile(ca2, []byte("ca2"), 0644))
+
+	conf := mapConfigService{
+		"fabric.tls.rootCertFile": ca1,
+		"fabric.orderers":         []*grpc.ConnectionConfig{{Address: "orderer1:7050"}},
+		"fabric.peers":            []*grpc.ConnectionConfig{{Address: "peer1:7051"}},
+	}
+	lm := &refreshingMembership{}
+	n := &network{config: NewConfig(conf), localMembership: lm}
+	assert.NoError(t, n.Reload())
+	assert.Equal(t, 1, lm.refreshes)
+	certs, err := n.GetTLSRootCert(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("ca1")}, certs)
+
+	// the certificates and the endpoints are swapped
+	conf["fabric.tls.rootCertFile"] = ca2
+	conf["fabric.orderers"] = []*grpc.ConnectionConfig{{Address: "orderer2:7050"}}
+	conf["fabric.peers"] = []*grpc.ConnectionConfig{{Address: "peer2:7051"}, {Address: "peer3:7051"}}
+	assert.NoError(t, n.Reload())
+	assert.Equal(t, 2, lm.refreshes)
+	certs, err = n.GetTLSRootCert(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("ca2")}, certs)
+	assert.Equal(t, []*grpc.ConnectionConfig{{Address: "orderer2:7050"}}, n.Orderers())
+	assert.Equal(t, []*grpc.ConnectionConfig{{Address: "peer2:7051"}, {Address: "peer3:7051"}}, n.Peers())
+
+	// a failing reload leaves the previous configuration in place
+	failures := []struct {
+		key   string
+		value interface{}
+	}{
+		{"fabric.tls.rootCertFile", filepath.Join(dir, "missing.pem")},
+		{"fabric.orderers", []*grpc.ConnectionConfig{}},
+		{"fabric.orderers", errors.New("invalid orderers")},
+		{"fabric.peers", errors.New("invalid peers")},
+	}
+	for _, failure := range failures {
+		conf["fabric.tls.rootCertFile"] = ca1
+		conf["fabric.orderers"] = []*grpc.ConnectionConfig{{Address: "orderer1:7050"}}
+		conf["fabric.peers"] = []*grpc.ConnectionConfig{{Address: "peer1:7051"}}
+		conf[failure.key] = failure.value
+
+		assert.Error(t, n.Reload(), "reloading with [%s] set to [%v]", failure.key, failure.value)
+		assert.Equal(t, 2, lm.refreshes)
+		certs, err = n.GetTLSRootCert(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("ca2")}, certs)
+		assert.Equal(t, []*grpc.ConnectionConfig{{Address: "orderer2:7050"}}, n.Orderers())
+		assert.Equal(t, []*grpc.ConnectionConfig{{Address: "peer2:7051"}, {Address: "peer3:7051"}}, n.Peers())
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package ordering
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	broadcastDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:  "fabric",
+		Subsystem:  "ordering",
+		Name:       "broadcast_duration",
+		Help:       "Time in seconds to broadcast an envelope to the ordering service and get its response, by network and status.",
+		Buckets:    []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		LabelNames: []string{"network", "status"},
+	}
+
+	resubmittedTransactionsCounterOpts = metrics.CounterOpts{
+		Namespace:  "fabric",
+		Subsystem:  "ordering",
+		Name:       "resubmitted_transactions",
+		Help:       "Transactions resubmitted to the ordering service because they did not appear in time, by network and channel.",
+		LabelNames: []string{"network", "channel"},
+	}
+
+	abandonedTransactionsCounterOpts = metrics.CounterOpts{
+		Namespace:  "fabric",
+		Subsystem:  "ordering",
+		Name:       "abandoned_transactions",
+		Help:       "Transactions discarded after exceeding the resubmission limit, by network and channel.",
+		LabelNames: []string{"network", "channel"},
+	}
+)
+
+type Metrics struct {
+	BroadcastDuration       metrics.Histogram
+	ResubmittedTransactions metrics.Counter
+	AbandonedTransactions   metrics.Counter
+}
+
+func NewMetrics(p metrics.Provider, network string) *Metrics {
+	return &Metrics{
+		BroadcastDuration:       p.NewHistogram(broadcastDurationHistogramOpts).With("network", network),
+		ResubmittedTransactions: p.NewCounter(resubmittedTransactionsCounterOpts).With("network", network),
+		AbandonedTransactions:   p.NewCounter(abandonedTransactionsCounterOpts).With("network", network),
+	}
+}
@@ -10,11 +10,15 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"sync"
+	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/transaction"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 
 	"github.com/golang/protobuf/proto"
 	common2 "github.com/hyperledger/fabric-protos-go/common"
@@ -49,6 +53,7 @@ type Configuration interface {
 
 type Network interface {
 	Configuration
+	Name() string
 	Peers() []*grpc.ConnectionConfig
 	LocalMembership() api.LocalMembership
 	// Broadcast sends the passed blob to the ordering service to be ordered
@@ -69,13 +74,39 @@ type Transaction interface {
 type service struct {
 	sp      view2.ServiceProvider
 	network Network
+	metrics *Metrics
+
+	// trackerConfig is nil if the transactions broadcast are not tracked
+	trackerConfig *TrackerConfig
+	trackersLock  sync.Mutex
+	trackers      map[string]*Tracker
 }
 
-func NewService(sp view2.ServiceProvider, network Network) *service {
+// NewService returns the ordering service of the passed network.
+// If trackerConfig is not nil, the transactions broadcast are tracked until they appear in a block.
+func NewService(sp view2.ServiceProvider, network Network, trackerConfig *TrackerConfig) *service {
 	return &service{
-		sp:      sp,
-		network: network,
+		sp:            sp,
+		network:       network,
+		metrics:       NewMetrics(operations.GetMetricsProvider(sp), network.Name()),
+		trackerConfig: trackerConfig,
+		trackers:      map[string]*Tracker{},
+	}
+}
+
+// Tracker returns the tracker of the transactions broadcast on the passed channel, nil if tracking is disabled
+func (o *service) Tracker(channel string) *Tracker {
+	if o.trackerConfig == nil {
+		return nil
+	}
+	o.trackersLock.Lock()
+	defer o.trackersLock.Unlock()
+	t, ok := o.trackers[channel]
+	if !ok {
+		t = newTracker(channel, o.network, *o.trackerConfig, o.broadcastEnvelopeTo, o.metrics, kvs.GetService(o.sp))
+		o.trackers[channel] = t
 	}
+	return t
 }
 
 func (o *service) Broadcast(blob interface{}) error {
@@ -98,7 +129,50 @@ func (o *service) Broadcast(blob interface{}) error {
 		return errors.Errorf("invalid blob's type, got [%T]", blob)
 	}
 
-	return o.broadcastEnvelope(env)
+	orderers := o.network.Orderers()
+	if len(orderers) == 0 {
+		return errors.New("no orderers configured")
+	}
+	tracker, txid, err := o.track(env)
+	if err != nil {
+		return err
+	}
+	// the orderers are tried in order, until one accepts the envelope
+	var errs []error
+	for i, orderer := range orderers {
+		err := o.broadcastEnvelopeTo(orderer, env)
+		if err == nil {
+			if tracker != nil && i != 0 {
+				tracker.SentTo(txid, i)
+			}
+			return nil
+		}
+		logger.Warnf("failed broadcasting to [%s]: [%s]", orderer.Address, err)
+		errs = append(errs, errors.WithMessagef(err, "orderer [%s]", orderer.Address))
+	}
+	if tracker != nil {
+		tracker.Forget(txid)
+	}
+	return errors.Errorf("failed broadcasting: %v", errs)
+}
+
+// track starts tracking the passed envelope, if it carries an endorser transaction and tracking is enabled
+func (o *service) track(env *common2.Envelope) (*Tracker, string, error) {
+	if o.trackerConfig == nil {
+		return nil, "", nil
+	}
+	chdr, err := protoutil.ChannelHeader(env)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed getting channel header")
+	}
+	if common2.HeaderType(chdr.Type) != common2.HeaderType_ENDORSER_TRANSACTION {
+		return nil, "", nil
+	}
+	tracker := o.Tracker(chdr.ChannelId)
+	if err := tracker.Track(chdr.TxId, env, 0); err != nil {
+		return nil, "", errors.WithMessagef(err, "failed tracking transaction [%s]", chdr.TxId)
+	}
+	return tracker, chdr.TxId, nil
 }
 
 func (o *service) createFabricEndorseTransactionEnvelope(tx Transaction) (*common2.Envelope, error) {
@@ -143,8 +217,15 @@ func (o *service) castFabricEndorseTransactionEnvelope(blob []byte) (*common2.En
 	return env, nil
 }
 
-func (o *service) broadcastEnvelope(env *common2.Envelope) error {
-	OrdererConfig := o.network.Orderers()[0]
+func (o *service) broadcastEnvelopeTo(OrdererConfig *grpc.ConnectionConfig, env *common2.Envelope) (err error) {
+	start := time.Now()
+	defer func() {
+		status := "SUCCESS"
+		if err != nil {
+			status = "FAILURE"
+		}
+		o.metrics.BroadcastDuration.With("status", status).Observe(time.Since(start).Seconds())
+	}()
 
 	ordererClient, err := NewOrdererClient(OrdererConfig)
 	if err != nil {
@@ -152,10 +233,10 @@ func (o *service) broadcastEnvelope(env *common2.Envelope) error {
 	}
 
 	broadcastClient, err := ordererClient.NewBroadcast(context.Background())
-	defer broadcastClient.CloseSend()
 	if err != nil {
 		return err
 	}
+	defer broadcastClient.CloseSend()
 
 	// send the envelope for ordering
 	err = BroadcastSend(broadcastClient, OrdererConfig.Address, env)
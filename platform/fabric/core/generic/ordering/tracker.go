@@ -0,0 +1,297 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package ordering
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	common2 "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+)
+
+const (
+	// DefaultTrackerWaitBlocks is the number of blocks a transaction has to appear within, if not configured otherwise
+	DefaultTrackerWaitBlocks = 10
+	// DefaultTrackerMaxResubmissions is the number of times a transaction is resubmitted, if not configured otherwise
+	DefaultTrackerMaxResubmissions = 3
+)
+
+// TrackerConfig tells how the transactions broadcast are tracked
+type TrackerConfig struct {
+	// WaitBlocks is the number of blocks a transaction has to appear within, before being resubmitted
+	WaitBlocks uint64
+	// MaxResubmissions is the number of times a transaction is resubmitted, before being discarded
+	MaxResubmissions uint64
+}
+
+type broadcastFunc func(orderer *grpc.ConnectionConfig, env *common2.Envelope) error
+
+type submission struct {
+	TxID string `json:"txid"`
+	// Orderer is the index of the orderer the transaction was last sent to
+	Orderer int `json:"orderer"`
+	// Blocks is the number of blocks delivered since the transaction was last sent
+	Blocks        uint64 `json:"blocks"`
+	Resubmissions uint64 `json:"resubmissions"`
+}
+
+// SubmissionStore persists the submissions tracked, for the tracker to resume watching them after a restart
+type SubmissionStore interface {
+	Exists(id string) bool
+	Put(id string, state interface{}) error
+	Get(id string, state interface{}) error
+}
+
+// Tracker watches the transactions broadcast on a channel until they appear in a block.
+// The orderers might accept a transaction and then drop it, during a leader change for instance,
+// leaving its state in the vault busy. If a transaction does not appear within the configured number of blocks,
+// the tracker resubmits its envelope to the next orderer. Once the resubmissions are exhausted,
+// the transaction is discarded from the vault, unless the ledger knows it.
+// The envelopes are persisted with the EnvelopeService of the channel, the submissions with the passed store, if any.
+type Tracker struct {
+	channel   string
+	network   Network
+	config    TrackerConfig
+	broadcast broadcastFunc
+	metrics   *Metrics
+	store     SubmissionStore
+	storeKey  string
+
+	lock    sync.Mutex
+	pending map[string]*submission
+}
+
+func newTracker(channel string, network Network, config TrackerConfig, broadcast broadcastFunc, metrics *Metrics, store SubmissionStore) *Tracker {
+	if config.WaitBlocks == 0 {
+		config.WaitBlocks = DefaultTrackerWaitBlocks
+	}
+	t := &Tracker{
+		channel:   channel,
+		network:   network,
+		config:    config,
+		broadcast: broadcast,
+		metrics:   metrics,
+		store:     store,
+		pending:   map[string]*submission{},
+	}
+	if store != nil {
+		key, err := kvs.CreateCompositeKey("tracker", []string{network.Name(), channel})
+		if err != nil {
+			logger.Errorf("failed creating the key of the transactions tracked on [%s], they are not persisted: [%s]", channel, err)
+			t.store = nil
+			return t
+		}
+		t.storeKey = key
+		if store.Exists(key) {
+			if err := store.Get(key, &t.pending); err != nil {
+				logger.Errorf("failed loading the transactions tracked on [%s], they are not watched anymore: [%s]", channel, err)
+				t.pending = map[string]*submission{}
+			}
+			logger.Infof("resuming tracking [%d] transactions on [%s]", len(t.pending), channel)
+		}
+	}
+	return t
+}
+
+// Track persists the envelope of the passed transaction, sent to the orderer with the passed index,
+// and watches for the transaction to appear in a block.
+func (t *Tracker) Track(txid string, env *common2.Envelope, orderer int) error {
+	ch, err := t.network.Channel(t.channel)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting channel [%s]", t.channel)
+	}
+	es := ch.EnvelopeService()
+	if !es.Exists(txid) {
+		raw, err := proto.Marshal(env)
+		if err != nil {
+			return errors.Wrapf(err, "failed marshalling envelope of [%s]", txid)
+		}
+		if err := es.StoreEnvelope(txid, raw); err != nil {
+			return errors.WithMessagef(err, "failed storing envelope of [%s]", txid)
+		}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.pending[txid] = &submission{TxID: txid, Orderer: orderer}
+	return t.persist()
+}
+
+// SentTo records that the passed transaction has been sent to the orderer with the passed index,
+// the orderers before it failed
+func (t *Tracker) SentTo(txid string, orderer int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if s, ok := t.pending[txid]; ok {
+		s.Orderer = orderer
+		t.logPersistErr()
+	}
+}
+
+// Forget stops watching the passed transaction
+func (t *Tracker) Forget(txid string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.pending, txid)
+	t.logPersistErr()
+}
+
+// Pending returns the transactions that have not appeared in a block yet, sorted
+func (t *Tracker) Pending() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	res := make([]string, 0, len(t.pending))
+	for txid := range t.pending {
+		res = append(res, txid)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Commit stops watching the transactions of the passed block, valid or not, and deals with
+// the ones that have not appeared within the configured number of blocks.
+// The block is expected to be committed to the vault already.
+func (t *Tracker) Commit(block *pb.FilteredBlock) {
+	resubmit, abandon := t.expired(block)
+	if len(resubmit) == 0 && len(abandon) == 0 {
+		return
+	}
+	// do not hold the delivery of the blocks while talking to the orderers
+	go t.handle(resubmit, abandon)
+}
+
+// expired returns the submissions to resubmit and the ones to abandon, after the passed block
+func (t *Tracker) expired(block *pb.FilteredBlock) ([]submission, []submission) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if len(t.pending) == 0 {
+		return nil, nil
+	}
+
+	for _, tx := range block.FilteredTransactions {
+		delete(t.pending, tx.Txid)
+	}
+	var resubmit, abandon []submission
+	for txid, s := range t.pending {
+		s.Blocks++
+		if s.Blocks < t.config.WaitBlocks {
+			continue
+		}
+		if s.Resubmissions >= t.config.MaxResubmissions {
+			delete(t.pending, txid)
+			abandon = append(abandon, *s)
+			continue
+		}
+		s.Blocks = 0
+		s.Resubmissions++
+		s.Orderer++
+		resubmit = append(resubmit, *s)
+	}
+	t.logPersistErr()
+	return resubmit, abandon
+}
+
+// persist stores the pending submissions, the lock is expected to be held
+func (t *Tracker) persist() error {
+	if t.store == nil {
+		return nil
+	}
+	if err := t.store.Put(t.storeKey, t.pending); err != nil {
+		return errors.WithMessagef(err, "failed persisting the transactions tracked on [%s]", t.channel)
+	}
+	return nil
+}
+
+func (t *Tracker) logPersistErr() {
+	if err := t.persist(); err != nil {
+		logger.Errorf("%s", err)
+	}
+}
+
+func (t *Tracker) handle(resubmit, abandon []submission) {
+	ch, err := t.network.Channel(t.channel)
+	if err != nil {
+		logger.Errorf("failed getting channel [%s]: [%s]", t.channel, err)
+		return
+	}
+	for _, s := range abandon {
+		if t.committed(ch, s.TxID) {
+			continue
+		}
+		if t.inLedger(ch, s.TxID) {
+			// the committer applies it when its block gets delivered
+			logger.Warnf("transaction [%s] did not appear in the blocks watched but the ledger knows it, not discarding it", s.TxID)
+			continue
+		}
+		logger.Warnf("transaction [%s] did not appear after [%d] resubmissions, discarding it", s.TxID, s.Resubmissions)
+		if err := ch.DiscardTx(s.TxID); err != nil {
+			logger.Errorf("failed discarding transaction [%s]: [%s]", s.TxID, err)
+			continue
+		}
+		t.metrics.AbandonedTransactions.With("channel", t.channel).Add(1)
+	}
+	for _, s := range resubmit {
+		if t.committed(ch, s.TxID) {
+			t.Forget(s.TxID)
+			continue
+		}
+		if err := t.resubmit(ch, s); err != nil {
+			// the next expiration tries with yet another orderer
+			logger.Warnf("failed resubmitting transaction [%s]: [%s]", s.TxID, err)
+			continue
+		}
+		t.metrics.ResubmittedTransactions.With("channel", t.channel).Add(1)
+	}
+}
+
+func (t *Tracker) resubmit(ch api.Channel, s submission) error {
+	raw, err := ch.EnvelopeService().LoadEnvelope(s.TxID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed loading envelope of [%s]", s.TxID)
+	}
+	env := &common2.Envelope{}
+	if err := proto.Unmarshal(raw, env); err != nil {
+		return errors.Wrapf(err, "failed unmarshalling envelope of [%s]", s.TxID)
+	}
+	orderers := t.network.Orderers()
+	if len(orderers) == 0 {
+		return errors.New("no orderers configured")
+	}
+	orderer := orderers[s.Orderer%len(orderers)]
+	logger.Infof("transaction [%s] did not appear, resubmitting it to [%s] [%d/%d]", s.TxID, orderer.Address, s.Resubmissions, t.config.MaxResubmissions)
+	return t.broadcast(orderer, env)
+}
+
+// inLedger returns true if a peer returns the passed transaction.
+// If the peers cannot be reached, the transaction is considered unknown. Should it be committed as valid
+// after being discarded, the committer applies it anyway.
+func (t *Tracker) inLedger(ch api.Channel, txid string) bool {
+	pt, err := ch.GetTransactionByID(txid)
+	if err != nil {
+		logger.Debugf("transaction [%s] not found in the ledger: [%s]", txid, err)
+		return false
+	}
+	logger.Debugf("transaction [%s] found in the ledger, valid [%v]", txid, pt.IsValid())
+	return true
+}
+
+// committed returns true if the vault knows the final status of the passed transaction,
+// it might have been committed while the tracker was not watching
+func (t *Tracker) committed(ch api.Channel, txid string) bool {
+	vc, _, err := ch.Status(txid)
+	if err != nil {
+		logger.Errorf("failed getting status of [%s]: [%s]", txid, err)
+		return false
+	}
+	return vc == api.Valid || vc == api.Invalid
+}
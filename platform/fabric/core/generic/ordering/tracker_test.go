@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package ordering
+
+import (
+	"encoding/json"
+	"testing"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func block(txids ...string) *pb.FilteredBlock {
+	res := &pb.FilteredBlock{}
+	for _, txid := range txids {
+		res.FilteredTransactions = append(res.FilteredTransactions, &pb.FilteredTransaction{Txid: txid})
+	}
+	return res
+}
+
+func TestTrackerExpired(t *testing.T) {
+	tracker := newTracker("ch", nil, TrackerConfig{WaitBlocks: 2, MaxResubmissions: 1}, nil, nil, nil)
+	tracker.pending["a"] = &submission{TxID: "a"}
+	tracker.pending["b"] = &submission{TxID: "b"}
+	tracker.pending["c"] = &submission{TxID: "c"}
+	assert.Equal(t, []string{"a", "b", "c"}, tracker.Pending())
+
+	// b appears, a and c keep waiting
+	resubmit, abandon := tracker.expired(block("b", "x"))
+	assert.Empty(t, resubmit)
+	assert.Empty(t, abandon)
+	assert.Equal(t, []string{"a", "c"}, tracker.Pending())
+
+	// a and c did not appear within two blocks, they are resubmitted to the next orderer
+	resubmit, abandon = tracker.expired(block())
+	assert.Len(t, resubmit, 2)
+	assert.Empty(t, abandon)
+	for _, s := range resubmit {
+		assert.Equal(t, 1, s.Orderer)
+		assert.Equal(t, uint64(1), s.Resubmissions)
+	}
+	assert.Equal(t, []string{"a", "c"}, tracker.Pending())
+
+	// a appears, c does not and is abandoned
+	resubmit, abandon = tracker.expired(block("a"))
+	assert.Empty(t, resubmit)
+	assert.Empty(t, abandon)
+	resubmit, abandon = tracker.expired(block())
+	assert.Empty(t, resubmit)
+	assert.Equal(t, []submission{{TxID: "c", Orderer: 1, Blocks: 2, Resubmissions: 1}}, abandon)
+	assert.Empty(t, tracker.Pending())
+}
+
+type network struct {
+	Network
+}
+
+func (n *network) Name() string {
+	return "default"
+}
+
+// store keeps the JSON representation of the values put, as the kvs does
+type store map[string][]byte
+
+func (s store) Exists(id string) bool {
+	_, ok := s[id]
+	return ok
+}
+
+func (s store) Put(id string, state interface{}) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	s[id] = raw
+	return nil
+}
+
+func (s store) Get(id string, state interface{}) error {
+	return json.Unmarshal(s[id], state)
+}
+
+func TestTrackerPersistence(t *testing.T) {
+	st := store{}
+	tracker := newTracker("ch", &network{}, TrackerConfig{WaitBlocks: 1, MaxResubmissions: 2}, nil, nil, st)
+	tracker.lock.Lock()
+	tracker.pending["a"] = &submission{TxID: "a"}
+	tracker.pending["b"] = &submission{TxID: "b"}
+	assert.NoError(t, tracker.persist())
+	tracker.lock.Unlock()
+	tracker.SentTo("b", 1)
+	resubmit, _ := tracker.expired(block())
+	assert.Len(t, resubmit, 2)
+	tracker.Forget("a")
+
+	// the submissions survive a restart
+	restarted := newTracker("ch", &network{}, TrackerConfig{WaitBlocks: 1, MaxResubmissions: 2}, nil, nil, st)
+	assert.Equal(t, []string{"b"}, restarted.Pending())
+	assert.Equal(t, submission{TxID: "b", Orderer: 2, Resubmissions: 1}, *restarted.pending["b"])
+
+	// the trackers of other channels do not see them
+	assert.Empty(t, newTracker("other", &network{}, TrackerConfig{}, nil, nil, st).Pending())
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	pcommon "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -90,6 +91,7 @@ type Transaction struct {
 	TParameters       [][]byte
 
 	RWSet      []byte
+	PvtRWSet   []byte
 	TTransient api.TransientMap
 
 	TProposal          *pb.Proposal
@@ -163,6 +165,7 @@ func (t *Transaction) From(tx api.Transaction) (err error) {
 	t.TFunction = payload.TFunction
 	t.TParameters = payload.TParameters
 	t.RWSet = payload.RWSet
+	t.PvtRWSet = payload.PvtRWSet
 	t.TProposal = payload.TProposal
 	t.TSignedProposal = payload.TSignedProposal
 	if payload.TSignedProposal != nil {
@@ -323,6 +326,12 @@ func (t *Transaction) SetRWSet() error {
 			return err
 		}
 	}
+	if len(t.PvtRWSet) != 0 {
+		logger.Debugf("append private rwset")
+		if err := t.rwset.AppendPrivateRWSet(t.PvtRWSet); err != nil {
+			return errors.WithMessagef(err, "failed appending private rwset")
+		}
+	}
 	logger.Debugf("rws set [%s]", t.rwset.String())
 	return nil
 }
@@ -342,6 +351,10 @@ func (t *Transaction) Done() error {
 		if err != nil {
 			return errors.Wrapf(err, "failed marshalling rws")
 		}
+		t.PvtRWSet, err = t.rwset.PrivateBytes()
+		if err != nil {
+			return errors.Wrapf(err, "failed marshalling private rws")
+		}
 		logger.Debugf("terminated simulation with [%s][len:%d]", t.rwset.Namespaces(), len(t.RWSet))
 	}
 	return nil
@@ -361,6 +374,10 @@ func (t *Transaction) Raw() ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
+		t.PvtRWSet, err = t.rwset.PrivateBytes()
+		if err != nil {
+			return nil, err
+		}
 	}
 	return json.Marshal(t)
 }
@@ -396,6 +413,27 @@ func (t *Transaction) BytesNoTransient() ([]byte, error) {
 
 }
 
+// BytesWithCollections marshals the transaction keeping only the preimages of the collections
+// for which keep returns true. The hashes of all the private writes stay in the read-write set.
+func (t *Transaction) BytesWithCollections(noTransient bool, keep func(namespace, collection string) bool) ([]byte, error) {
+	if err := t.Done(); err != nil {
+		return nil, err
+	}
+	temp := &Transaction{}
+	if err := temp.From(t); err != nil {
+		return nil, err
+	}
+	if noTransient {
+		temp.ResetTransient()
+	}
+	var err error
+	temp.PvtRWSet, err = filterPvtRWSet(t.PvtRWSet, keep)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(temp)
+}
+
 func (t *Transaction) Endorse() error {
 	return t.EndorseWithIdentity(t.Creator())
 }
@@ -675,3 +713,37 @@ func (s *signerWrapper) Sign(message []byte) ([]byte, error) {
 func (s *signerWrapper) Serialize() ([]byte, error) {
 	return s.creator, nil
 }
+
+// filterPvtRWSet returns the passed private read-write set with the collections for which keep returns true.
+// It returns nil if no collection is left.
+func filterPvtRWSet(raw []byte, keep func(namespace, collection string) bool) ([]byte, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(raw, txPvtRWSet); err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling private rwset")
+	}
+	var nsPvtRWSets []*rwset.NsPvtReadWriteSet
+	for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwset {
+		var colls []*rwset.CollectionPvtReadWriteSet
+		for _, coll := range nsPvtRWSet.CollectionPvtRwset {
+			if keep(nsPvtRWSet.Namespace, coll.CollectionName) {
+				colls = append(colls, coll)
+			}
+		}
+		if len(colls) != 0 {
+			nsPvtRWSets = append(nsPvtRWSets, &rwset.NsPvtReadWriteSet{
+				Namespace:          nsPvtRWSet.Namespace,
+				CollectionPvtRwset: colls,
+			})
+		}
+	}
+	if len(nsPvtRWSets) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(&rwset.TxPvtReadWriteSet{
+		DataModel:  txPvtRWSet.DataModel,
+		NsPvtRwset: nsPvtRWSets,
+	})
+}
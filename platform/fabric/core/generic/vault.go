@@ -6,23 +6,28 @@ SPDX-License-Identifier: Apache-2.0
 package generic
 
 import (
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/vault"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/vault/txidstore"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 )
 
 type Badger struct {
 	Path string
 }
 
-func NewVault(config *Config, channel string, sp view.ServiceProvider) (*vault.Vault, *txidstore.TXIDStore, error) {
+// OpenVaultPersistence opens the store of the vault of the passed channel, as configured
+func OpenVaultPersistence(config *Config, channel string) (driver.VersionedPersistence, error) {
 	var persistence driver.VersionedPersistence
 	pType := config.VaultPersistenceType()
 	switch pType {
@@ -30,25 +35,33 @@ func NewVault(config *Config, channel string, sp view.ServiceProvider) (*vault.V
 		opts := &Badger{}
 		err := config.VaultPersistenceOpts(opts)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "failed getting opts for vault")
+			return nil, errors.Wrapf(err, "failed getting opts for vault")
 		}
 		opts.Path = filepath.Join(opts.Path, channel)
 		err = os.MkdirAll(opts.Path, 0755)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "failed creating folders for vault [%s]", opts.Path)
+			return nil, errors.Wrapf(err, "failed creating folders for vault [%s]", opts.Path)
 		}
 		persistence, err = db.OpenVersioned("badger", opts.Path)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 	case "memory":
 		var err error
 		persistence, err = db.OpenVersioned("memory", "")
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 	default:
-		return nil, nil, errors.Errorf("invalid persistence type, expected one of [file,memory], got [%s]", pType)
+		return nil, errors.Errorf("invalid persistence type, expected one of [file,memory], got [%s]", pType)
+	}
+	return persistence, nil
+}
+
+func NewVault(config *Config, network, channel string, sp view.ServiceProvider) (*vault.Vault, *txidstore.TXIDStore, error) {
+	persistence, err := OpenVaultPersistence(config, channel)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	txidstore, err := txidstore.NewTXIDStore(db.Unversioned(persistence))
@@ -56,5 +69,138 @@ func NewVault(config *Config, channel string, sp view.ServiceProvider) (*vault.V
 		return nil, nil, err
 	}
 
-	return vault.New(persistence, txidstore), txidstore, nil
+	v := vault.NewWithMetrics(
+		persistence,
+		txidstore,
+		vault.NewMetrics(operations.GetMetricsProvider(sp), network, channel),
+	)
+	if config.VaultHistoryEnabled() {
+		v.EnableHistory()
+	}
+	localNamespaces, err := config.VaultLocalNamespaces()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed loading local namespaces")
+	}
+	v.EnableLocalNamespaces(localNamespaces...)
+	// the history retention counts from the last block committed, before the node was restarted too
+	if err := v.LoadLastBlock(); err != nil {
+		return nil, nil, err
+	}
+	return v, txidstore, nil
+}
+
+// ImportVaultSnapshot bootstraps the vault of the passed channel from the snapshot stored at the passed path.
+// The snapshot is verified first. The vault must be empty and not in use, the node must not be running.
+func ImportVaultSnapshot(config *Config, channel, path string) (*api.SnapshotInfo, error) {
+	if config.VaultPersistenceType() != "file" {
+		return nil, errors.Errorf("cannot import a snapshot into a vault of type [%s]", config.VaultPersistenceType())
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening snapshot [%s]", path)
+	}
+	defer f.Close()
+	if _, err := vault.VerifySnapshot(f); err != nil {
+		return nil, errors.WithMessagef(err, "failed verifying snapshot [%s]", path)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrapf(err, "failed rewinding snapshot [%s]", path)
+	}
+
+	persistence, err := OpenVaultPersistence(config, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer persistence.Close()
+	info, err := vault.ImportSnapshot(persistence, f)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed importing snapshot [%s] into the vault of [%s]", path, channel)
+	}
+	return info, nil
+}
+
+// deliveryVault tells the delivery service where to resume from
+type deliveryVault struct {
+	txIDStore *txidstore.TXIDStore
+	vault     *vault.Vault
+}
+
+func (d *deliveryVault) GetLastTxID() (string, error) {
+	return d.txIDStore.GetLastTxID()
+}
+
+func (d *deliveryVault) SnapshotHeight() (uint64, bool, error) {
+	return d.vault.SnapshotHeight()
+}
+
+const defaultHistoryPruneInterval = 10 * time.Minute
+
+// historyPruner periodically removes from the history of the vault the versions older than the retention
+type historyPruner struct {
+	vault     *vault.Vault
+	channel   string
+	retention uint64
+	interval  time.Duration
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// newHistoryPruner returns a pruner for the vault of the passed channel, nil if the history is disabled
+// or kept forever
+func newHistoryPruner(config *Config, channel string, v *vault.Vault) (*historyPruner, error) {
+	if !config.VaultHistoryEnabled() {
+		return nil, nil
+	}
+	retention, err := config.VaultHistoryRetention()
+	if err != nil {
+		return nil, err
+	}
+	if retention == 0 {
+		return nil, nil
+	}
+	interval := config.VaultHistoryPruneInterval()
+	if interval <= 0 {
+		interval = defaultHistoryPruneInterval
+	}
+	return &historyPruner{
+		vault:     v,
+		channel:   channel,
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}, nil
+}
+
+func (p *historyPruner) Start() {
+	go p.run()
+}
+
+// Stop stops the pruner and waits for the pruning in progress, if any, to complete
+func (p *historyPruner) Stop() {
+	close(p.stop)
+	<-p.stopped
+}
+
+func (p *historyPruner) run() {
+	defer close(p.stopped)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			last := p.vault.LastBlock()
+			if last <= p.retention {
+				continue
+			}
+			pruned, err := p.vault.PruneHistory(last - p.retention)
+			if err != nil {
+				logger.Errorf("failed pruning history of channel [%s]: [%s]", p.channel, err)
+				continue
+			}
+			logger.Debugf("pruned [%d] versions from the history of channel [%s] below block [%d]", pruned, p.channel, last-p.retention)
+		}
+	}
 }
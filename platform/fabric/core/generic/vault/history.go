@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+)
+
+// historyNamespace holds the history index. Namespaces and keys cannot contain '$',
+// the keys of the index are namespace$key$version, where the version sorts as the height does.
+const (
+	historyNamespace = "$$h"
+	historySeparator = "$"
+	// historyEnd follows all the keys of the index, namespaces are made of printable ASCII characters
+	historyEnd    = "\x7f"
+	versionLength = 32
+)
+
+type historyValue struct {
+	Value   []byte
+	TxID    string
+	Deleted bool `json:",omitempty"`
+}
+
+func historyKey(ns, key string, block, txnum uint64) string {
+	return ns + historySeparator + key + historySeparator + fmt.Sprintf("%016x%016x", block, txnum)
+}
+
+// historyPrefixRange returns the range of the index keys starting with the passed prefix
+func historyPrefixRange(prefix string) (string, string) {
+	// the separator is followed by '%' in the ASCII table
+	return prefix + historySeparator, prefix + "%"
+}
+
+// parseHistoryKey splits an index key into the namespace, the key, the block and the transaction number
+func parseHistoryKey(hk string) (string, string, uint64, uint64, error) {
+	sep := strings.Index(hk, historySeparator)
+	if sep < 0 || len(hk) < sep+2+versionLength {
+		return "", "", 0, 0, errors.Errorf("invalid history key [%s]", hk)
+	}
+	version := hk[len(hk)-versionLength:]
+	block, err := strconv.ParseUint(version[:versionLength/2], 16, 64)
+	if err != nil {
+		return "", "", 0, 0, errors.Wrapf(err, "invalid history key [%s]", hk)
+	}
+	txnum, err := strconv.ParseUint(version[versionLength/2:], 16, 64)
+	if err != nil {
+		return "", "", 0, 0, errors.Wrapf(err, "invalid history key [%s]", hk)
+	}
+	return hk[:sep], hk[sep+1 : len(hk)-versionLength-1], block, txnum, nil
+}
+
+// EnableHistory makes the vault record, in the history index, the versions of the keys
+// written by the committed transactions. Private data is not recorded.
+func (db *Vault) EnableHistory() {
+	db.history = true
+}
+
+// LoadLastBlock loads, from the store, the highest block committed before the vault was opened.
+// It is the highest block of the entries of the store, or the height of the snapshot the vault was bootstrapped from.
+// It must be called once the vault is opened, before the commits start.
+func (db *Vault) LoadLastBlock() error {
+	db.storeLock.RLock()
+	defer db.storeLock.RUnlock()
+
+	lastBlock, err := db.highestBlock()
+	if err != nil {
+		return errors.WithMessage(err, "failed loading last block")
+	}
+	if lastBlock > db.lastBlock.Load() {
+		db.lastBlock.Store(lastBlock)
+	}
+	return nil
+}
+
+// LastBlock returns the highest block committed, as loaded by LoadLastBlock and updated by the commits since
+func (db *Vault) LastBlock() uint64 {
+	return db.lastBlock.Load()
+}
+
+// recordHistory adds the writes of the passed transaction to the history index, as part of the ongoing update
+func (db *Vault) recordHistory(txid string, writes writes, block, txnum uint64) error {
+	for ns, keyMap := range writes {
+		for key, v := range keyMap {
+			raw, err := json.Marshal(&historyValue{Value: v, TxID: txid, Deleted: len(v) == 0})
+			if err != nil {
+				return errors.Wrapf(err, "failed marshalling history of [%s:%s]", ns, key)
+			}
+			if err := db.store.SetState(historyNamespace, historyKey(ns, key, block, txnum), raw, block, txnum); err != nil {
+				return errors.WithMessagef(err, "failed recording history of [%s:%s]", ns, key)
+			}
+		}
+	}
+	return nil
+}
+
+// GetHistoryForKey returns the versions of the passed key recorded in the history index, the oldest first
+func (db *Vault) GetHistoryForKey(ns, key string) ([]*api.HistoryEntry, error) {
+	if !db.history {
+		return nil, errors.New("history is not enabled")
+	}
+	db.storeLock.RLock()
+	defer db.storeLock.RUnlock()
+
+	var res []*api.HistoryEntry
+	start, end := historyPrefixRange(ns + historySeparator + key)
+	err := db.scanHistory(start, end, func(_ string, e *api.HistoryEntry) {
+		res = append(res, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetNamespaceAsOf returns the keys of the namespace, with their values, as they were
+// once the passed block was committed. Keys deleted by then are not returned.
+func (db *Vault) GetNamespaceAsOf(ns string, block uint64) ([]*api.HistoryEntry, error) {
+	if !db.history {
+		return nil, errors.New("history is not enabled")
+	}
+	db.storeLock.RLock()
+	defer db.storeLock.RUnlock()
+
+	var res []*api.HistoryEntry
+	var last *api.HistoryEntry
+	flush := func() {
+		if last != nil && !last.Deleted {
+			res = append(res, last)
+		}
+		last = nil
+	}
+	start, end := historyPrefixRange(ns)
+	err := db.scanHistory(start, end, func(_ string, e *api.HistoryEntry) {
+		if last != nil && last.Key != e.Key {
+			flush()
+		}
+		if e.Block <= block {
+			last = e
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	flush()
+	return res, nil
+}
+
+// PruneHistory removes from the history index the versions overwritten before the passed block.
+// For each key, the last version committed before that block is kept, unless it is a deletion,
+// so that the state as of any block from the passed one on can still be read.
+// It returns the number of versions removed.
+func (db *Vault) PruneHistory(block uint64) (int, error) {
+	if !db.history {
+		return 0, errors.New("history is not enabled")
+	}
+	db.storeLock.Lock()
+	defer db.storeLock.Unlock()
+
+	var pruned []string
+	var prev *api.HistoryEntry
+	var prevNs string
+	prune := func(sameKey bool, next *api.HistoryEntry) {
+		if prev == nil || prev.Block >= block {
+			return
+		}
+		if prev.Deleted || (sameKey && next.Block < block) {
+			pruned = append(pruned, historyKey(prevNs, prev.Key, prev.Block, prev.TxNum))
+		}
+	}
+	err := db.scanHistory("", historyEnd, func(ns string, e *api.HistoryEntry) {
+		prune(prev != nil && prevNs == ns && prev.Key == e.Key, e)
+		prev, prevNs = e, ns
+	})
+	if err != nil {
+		return 0, err
+	}
+	prune(false, nil)
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	if err := db.store.BeginUpdate(); err != nil {
+		return 0, errors.WithMessage(err, "begin update for history pruning failed")
+	}
+	for _, hk := range pruned {
+		if err := db.store.DeleteState(historyNamespace, hk); err != nil {
+			if err1 := db.store.Discard(); err1 != nil {
+				logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+			}
+			return 0, errors.WithMessagef(err, "failed pruning history key [%s]", hk)
+		}
+	}
+	if err := db.store.Commit(); err != nil {
+		return 0, errors.WithMessage(err, "committing history pruning failed")
+	}
+	return len(pruned), nil
+}
+
+// scanHistory passes the entries of the index in the passed range, in order, to the callback
+func (db *Vault) scanHistory(start, end string, callback func(ns string, e *api.HistoryEntry)) error {
+	itr, err := db.store.GetStateRangeScanIterator(historyNamespace, start, end)
+	if err != nil {
+		return errors.WithMessage(err, "failed scanning history")
+	}
+	defer itr.Close()
+
+	for {
+		read, err := itr.Next()
+		if err != nil {
+			return errors.WithMessage(err, "failed scanning history")
+		}
+		if read == nil {
+			return nil
+		}
+		ns, key, block, txnum, err := parseHistoryKey(read.Key)
+		if err != nil {
+			return err
+		}
+		hv := &historyValue{}
+		if err := json.Unmarshal(read.Raw, hv); err != nil {
+			return errors.Wrapf(err, "failed unmarshalling history value [%s]", read.Key)
+		}
+		callback(ns, &api.HistoryEntry{
+			Key:     key,
+			Value:   hv.Value,
+			Block:   block,
+			TxNum:   txnum,
+			TxID:    hv.TxID,
+			Deleted: hv.Deleted,
+		})
+	}
+}
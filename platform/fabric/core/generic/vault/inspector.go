@@ -7,6 +7,8 @@ package vault
 
 import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +30,10 @@ func newInspector() *Inspector {
 			metaWriteSet: metaWriteSet{
 				metawrites: namespaceKeyedMetaWrites{},
 			},
+			rangeQuerySet: rangeQuerySet{
+				ranges: map[string][]*kvrwset.RangeQueryInfo{},
+			},
+			private: newPrivateSet(),
 		},
 	}
 }
@@ -48,6 +54,34 @@ func (i *Inspector) DeleteState(namespace string, key string) error {
 	panic("programming error: the rwset inspector is read-only")
 }
 
+func (i *Inspector) SetPrivateState(namespace, collection, key string, value []byte) error {
+	panic("programming error: the rwset inspector is read-only")
+}
+
+func (i *Inspector) GetPrivateState(namespace, collection, key string, opts ...api.GetStateOpt) ([]byte, error) {
+	return i.rws.private.get(namespace, collection, key), nil
+}
+
+func (i *Inspector) DeletePrivateState(namespace, collection, key string) error {
+	panic("programming error: the rwset inspector is read-only")
+}
+
+func (i *Inspector) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	panic("programming error: unexpected call")
+}
+
+func (i *Inspector) Collections(ns string) []string {
+	return i.rws.private.collections(ns)
+}
+
+func (i *Inspector) AppendPrivateRWSet(raw []byte) error {
+	return i.rws.private.addPreimages(raw)
+}
+
+func (i *Inspector) PrivateBytes() ([]byte, error) {
+	panic("programming error: unexpected call")
+}
+
 func (i *Inspector) GetStateMetadata(namespace, key string, opts ...api.GetStateOpt) (map[string][]byte, error) {
 	return i.rws.metaWriteSet.get(namespace, key), nil
 }
@@ -105,6 +139,17 @@ func (i *Inspector) Namespaces() []string {
 	for ns := range i.rws.writes {
 		mergedMaps[ns] = struct{}{}
 	}
+	for ns := range i.rws.private.writes {
+		mergedMaps[ns] = struct{}{}
+	}
+	for ns := range i.rws.private.hashed {
+		mergedMaps[ns] = struct{}{}
+	}
+	for ns, rqis := range i.rws.ranges {
+		if len(rqis) != 0 {
+			mergedMaps[ns] = struct{}{}
+		}
+	}
 
 	namespaces := make([]string, 0, len(mergedMaps))
 	for ns := range mergedMaps {
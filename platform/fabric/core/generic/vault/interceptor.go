@@ -8,6 +8,8 @@ package vault
 import (
 	"encoding/json"
 
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/keys"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
 
 	"github.com/golang/protobuf/proto"
@@ -21,6 +23,7 @@ import (
 type QueryExecutor interface {
 	GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error)
 	GetState(namespace, key string) ([]byte, uint64, uint64, error)
+	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error)
 	Done()
 }
 
@@ -30,6 +33,8 @@ type Interceptor struct {
 	rws       readWriteSet
 	closed    bool
 	txid      string
+	// itrs are the range iterators opened on this read-write set, kept to check they have been recorded
+	itrs []*rangeIterator
 }
 
 func newInterceptor(qe QueryExecutor, txidStore TXIDStoreReader, txid string) *Interceptor {
@@ -51,11 +56,18 @@ func newInterceptor(qe QueryExecutor, txidStore TXIDStoreReader, txid string) *I
 			metaWriteSet: metaWriteSet{
 				metawrites: namespaceKeyedMetaWrites{},
 			},
+			rangeQuerySet: rangeQuerySet{
+				ranges: map[string][]*kvrwset.RangeQueryInfo{},
+			},
+			private: newPrivateSet(),
 		},
 	}
 }
 
 func (i *Interceptor) IsValid() error {
+	if err := i.rangeErr(); err != nil {
+		return err
+	}
 	code, err := i.txidStore.Get(i.txid)
 	if err != nil {
 		return err
@@ -77,6 +89,29 @@ func (i *Interceptor) IsValid() error {
 		}
 	}
 
+	for ns, nsMap := range i.rws.private.reads {
+		for coll, collMap := range nsMap {
+			for k, v := range collMap {
+				_, b, t, err := i.qe.GetState(privateNamespace(ns, coll), k)
+				if err != nil {
+					return err
+				}
+
+				if b != v.block || t != v.txnum {
+					return errors.Errorf("invalid private read: vault at version %s:%s:%s %d:%d, read-write set at version %d:%d", ns, coll, k, b, t, v.block, v.txnum)
+				}
+			}
+		}
+	}
+
+	for ns, rqis := range i.rws.ranges {
+		for _, rqi := range rqis {
+			if err := validateRange(i.qe, ns, rqi); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -88,6 +123,8 @@ func (i *Interceptor) Clear(ns string) error {
 	i.rws.readSet.clear(ns)
 	i.rws.writeSet.clear(ns)
 	i.rws.metaWriteSet.clear(ns)
+	i.rws.rangeQuerySet.clear(ns)
+	i.rws.private.clear(ns)
 
 	return nil
 }
@@ -153,6 +190,20 @@ func (i *Interceptor) Namespaces() []string {
 	for ns := range i.rws.writes {
 		mergedMaps[ns] = struct{}{}
 	}
+	for ns := range i.rws.private.writes {
+		mergedMaps[ns] = struct{}{}
+	}
+	for ns := range i.rws.private.reads {
+		mergedMaps[ns] = struct{}{}
+	}
+	for ns := range i.rws.private.hashed {
+		mergedMaps[ns] = struct{}{}
+	}
+	for ns, rqis := range i.rws.ranges {
+		if len(rqis) != 0 {
+			mergedMaps[ns] = struct{}{}
+		}
+	}
 
 	namespaces := make([]string, 0, len(mergedMaps))
 	for ns := range mergedMaps {
@@ -299,7 +350,7 @@ func (i *Interceptor) AppendRWSet(raw []byte, nss ...string) error {
 		return errors.Wrap(err, "provided invalid read-write set bytes, TxRwSetFromProtoMsg failed")
 	}
 
-	for _, nsrws := range rws.NsRwSets {
+	for idx, nsrws := range rws.NsRwSets {
 		ns := nsrws.NameSpace
 		if len(nss) != 0 {
 			found := false
@@ -354,40 +405,168 @@ func (i *Interceptor) AppendRWSet(raw []byte, nss ...string) error {
 				return err
 			}
 		}
+
+		for _, rqi := range nsrws.KvRwSet.RangeQueriesInfo {
+			if err := i.rws.rangeQuerySet.add(ns, rqi); err != nil {
+				return err
+			}
+		}
+
+		if err := i.rws.private.addHashed(ns, txRWSet.NsRwset[idx].CollectionHashedRwset); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// Bytes returns the public read-write set, the private data is replaced by its hashes
 func (i *Interceptor) Bytes() ([]byte, error) {
-	rwsb := rwsetutil.NewRWSetBuilder()
-
-	for ns, keyMap := range i.rws.reads {
-		for key, v := range keyMap {
-			if v.block != 0 || v.txnum != 0 {
-				rwsb.AddToReadSet(ns, key, rwsetutil.NewVersion(&kvrwset.Version{BlockNum: v.block, TxNum: v.txnum}))
-			} else {
-				rwsb.AddToReadSet(ns, key, nil)
+	if err := i.rangeErr(); err != nil {
+		return nil, err
+	}
+	pub, _, err := i.rws.simulationResults()
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(pub)
+}
+
+// PrivateBytes returns the private read-write set, the preimages of the hashed private writes.
+// It is nil if the read-write set has no private writes or none of their preimages is known.
+func (i *Interceptor) PrivateBytes() ([]byte, error) {
+	if err := i.rangeErr(); err != nil {
+		return nil, err
+	}
+	_, pvt, err := i.rws.simulationResults()
+	if err != nil {
+		return nil, err
+	}
+	if pvt == nil {
+		return nil, nil
+	}
+
+	return proto.Marshal(pvt)
+}
+
+// AppendPrivateRWSet adds the preimages of the private writes of the passed private read-write set.
+// The hashed read-write set of each collection must be already there and match its preimages.
+func (i *Interceptor) AppendPrivateRWSet(raw []byte) error {
+	if i.closed {
+		return errors.New("this instance was closed")
+	}
+
+	return i.rws.private.addPreimages(raw)
+}
+
+func (i *Interceptor) SetPrivateState(namespace, collection, key string, value []byte) error {
+	if i.closed {
+		return errors.New("this instance was closed")
+	}
+	logger.Debugf("SetPrivateState [%s,%s,%s,%s]", namespace, collection, key, hash.Hashable(value).String())
+
+	return i.rws.private.add(namespace, collection, key, value)
+}
+
+func (i *Interceptor) DeletePrivateState(namespace, collection, key string) error {
+	if i.closed {
+		return errors.New("this instance was closed")
+	}
+
+	return i.SetPrivateState(namespace, collection, key, nil)
+}
+
+// GetPrivateState returns the value of the passed key in the passed collection.
+// The vault holds the private data of the collections this node received the preimages of,
+// the value is nil for the others.
+func (i *Interceptor) GetPrivateState(namespace, collection, key string, opts ...api.GetStateOpt) ([]byte, error) {
+	if i.closed {
+		return nil, errors.New("this instance was closed")
+	}
+
+	if len(opts) > 1 {
+		return nil, errors.Errorf("a single getoption is supported, %d provided", len(opts))
+	}
+
+	opt := api.FromStorage
+	if len(opts) == 1 {
+		opt = opts[0]
+	}
+
+	switch opt {
+	case api.FromStorage:
+		val, block, txnum, err := i.qe.GetState(privateNamespace(namespace, collection), key)
+		if err != nil {
+			return nil, err
+		}
+
+		b, t, in := i.rws.private.getRead(namespace, collection, key)
+		if in {
+			if b != block || t != txnum {
+				return nil, errors.Errorf("invalid private read [%s:%s:%s]: previous value returned at version %d:%d, current value at version %d:%d", namespace, collection, key, b, t, block, txnum)
 			}
+		} else if err := i.rws.private.addRead(namespace, collection, key, block, txnum); err != nil {
+			return nil, err
 		}
-	}
-	for ns, keyMap := range i.rws.writes {
-		for key, v := range keyMap {
-			rwsb.AddToWriteSet(ns, key, v)
+
+		return val, nil
+
+	case api.FromIntermediate:
+		return i.rws.private.get(namespace, collection, key), nil
+
+	case api.FromBoth:
+		val, err := i.GetPrivateState(namespace, collection, key, api.FromIntermediate)
+		if err != nil || val != nil || i.rws.private.in(namespace, collection, key) {
+			return val, err
 		}
+
+		return i.GetPrivateState(namespace, collection, key, api.FromStorage)
+
+	default:
+		return nil, errors.Errorf("invalid get option %+v", opts)
 	}
-	for ns, keyMap := range i.rws.metawrites {
-		for key, v := range keyMap {
-			rwsb.AddToMetadataWriteSet(ns, key, v)
-		}
+}
+
+// GetStateRangeScanIterator returns an iterator over the keys of the passed namespace in the range [startKey, endKey).
+// When the iterator is closed, the range and the keys returned, or their merkle summary if they are many, are added
+// to the read-write set. This way, the validation detects the phantom reads.
+func (i *Interceptor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	if i.closed {
+		return nil, errors.New("this instance was closed")
+	}
+	if err := keys.ValidateNs(namespace); err != nil {
+		return nil, err
 	}
 
-	simRes, err := rwsb.GetTxSimulationResults()
+	itr, err := i.qe.GetStateRangeScanIterator(namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	ri, err := newRangeIterator(itr, namespace, startKey, endKey, i.rws.rangeQuerySet.add)
 	if err != nil {
+		itr.Close()
 		return nil, err
 	}
+	i.itrs = append(i.itrs, ri)
+
+	return ri, nil
+}
+
+// rangeErr returns the first failure to record the range scanned by a closed iterator.
+// The read-write set misses that range and cannot be used.
+func (i *Interceptor) rangeErr() error {
+	for _, itr := range i.itrs {
+		if err := itr.Err(); err != nil {
+			return errors.WithMessagef(err, "invalid read-write set [%s]", i.txid)
+		}
+	}
+	return nil
+}
 
-	return simRes.GetPubSimulationBytes()
+// Collections returns the collections of the passed namespace with private reads or writes
+func (i *Interceptor) Collections(ns string) []string {
+	return i.rws.private.collections(ns)
 }
 
 func (i *Interceptor) Equals(other interface{}, nss ...string) error {
@@ -405,6 +584,12 @@ func (i *Interceptor) Equals(other interface{}, nss ...string) error {
 	if err := i.rws.metawrites.equals(o.rws.metawrites, nss...); err != nil {
 		return errors.Wrap(err, "meta writes do not match")
 	}
+	if err := i.rws.rangeQuerySet.equals(&o.rws.rangeQuerySet, nss...); err != nil {
+		return errors.Wrap(err, "range queries do not match")
+	}
+	if err := i.rws.private.equals(&o.rws.private, nss...); err != nil {
+		return errors.Wrap(err, "private data does not match")
+	}
 
 	return nil
 }
@@ -417,6 +602,10 @@ func (i *Interceptor) String() string {
 func (i *Interceptor) Done() {
 	logger.Debugf("Done with [%s], closed [%v]", i.txid, i.closed)
 	if !i.closed {
+		// the ranges scanned by the iterators still open are part of the read-write set
+		for _, itr := range i.itrs {
+			itr.Close()
+		}
 		i.closed = true
 		i.qe.Done()
 	}
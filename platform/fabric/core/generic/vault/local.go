@@ -0,0 +1,246 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+)
+
+// Local transactions are committed to the vault only, without going through Fabric. They are meant for the
+// namespaces only the FSC nodes use. A sequencer assigns them increasing sequence numbers, and their writes
+// are stored at version 0:seq. The genesis block carries no state, so these versions never clash with the ones
+// of the transactions committed by Fabric, and do not move the height of the vault.
+// The local namespace holds the last sequence number, the status of the local transactions and their audit log.
+const (
+	localNamespace   = "$$l"
+	localSequenceKey = "seq"
+	localTxPrefix    = "tx" + historySeparator
+	localLog         = "log"
+)
+
+type localStatus struct {
+	Seq   uint64
+	Valid bool
+}
+
+func localLogKey(seq uint64) string {
+	return localLog + historySeparator + fmt.Sprintf("%016x", seq)
+}
+
+// EnableLocalNamespaces allows the transactions writing only the passed namespaces to be committed locally
+func (db *Vault) EnableLocalNamespaces(nss ...string) {
+	for _, ns := range nss {
+		db.localNamespaces[ns] = true
+	}
+}
+
+// CommitLocalTX commits the passed transaction to the vault only, at the next sequence number.
+// The transaction is rejected, and marked as invalid, if it writes namespaces that are not local
+// or if what it read has changed since, because of another transaction committed in the meantime.
+// Either way, the outcome is recorded in the audit log.
+func (db *Vault) CommitLocalTX(txid string) (uint64, error) {
+	i, err := db.unmapInterceptor(txid)
+	if err != nil {
+		return 0, err
+	}
+
+	db.storeLock.Lock()
+	defer db.storeLock.Unlock()
+
+	seq, err := db.localSequence()
+	if err != nil {
+		return 0, err
+	}
+	seq++
+
+	if err := db.validateLocal(i); err != nil {
+		if err1 := db.rejectLocal(txid, seq, err); err1 != nil {
+			return 0, errors.WithMessagef(err1, "failed rejecting local transaction [%s] for [%s]", txid, err)
+		}
+		db.metrics.DiscardedTransactions.Add(1)
+		return 0, errors.WithMessagef(err, "local transaction [%s] rejected", txid)
+	}
+
+	if err := db.store.BeginUpdate(); err != nil {
+		return 0, errors.WithMessagef(err, "begin update for txid '%s' failed", txid)
+	}
+	if err := db.storeRWSet(txid, i, 0, seq); err != nil {
+		return 0, err
+	}
+	if err := db.recordLocal(&api.LocalTransaction{Seq: seq, TxID: txid, Valid: true, Writes: localWrites(i.rws.writes)}); err != nil {
+		return 0, err
+	}
+	if err := db.store.Commit(); err != nil {
+		return 0, errors.WithMessagef(err, "committing local tx for txid '%s' failed", txid)
+	}
+	db.metrics.CommittedTransactions.Add(1)
+
+	return seq, nil
+}
+
+// GetLocalLog returns the audit log of the local transactions, from the passed sequence number on
+func (db *Vault) GetLocalLog(from uint64) ([]*api.LocalTransaction, error) {
+	db.storeLock.RLock()
+	defer db.storeLock.RUnlock()
+
+	_, end := historyPrefixRange(localLog)
+	itr, err := db.store.GetStateRangeScanIterator(localNamespace, localLogKey(from), end)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed scanning local log")
+	}
+	defer itr.Close()
+
+	var res []*api.LocalTransaction
+	for {
+		read, err := itr.Next()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed scanning local log")
+		}
+		if read == nil {
+			return res, nil
+		}
+		entry := &api.LocalTransaction{}
+		if err := json.Unmarshal(read.Raw, entry); err != nil {
+			return nil, errors.Wrapf(err, "failed unmarshalling local log entry [%s]", read.Key)
+		}
+		res = append(res, entry)
+	}
+}
+
+// validateLocal checks that the passed read-write set can be committed locally
+func (db *Vault) validateLocal(i *Interceptor) error {
+	code, err := db.localStatus(i.txid)
+	if err != nil {
+		return err
+	}
+	if code != api.Unknown {
+		return errors.Errorf("duplicate txid %s", i.txid)
+	}
+
+	if len(db.localNamespaces) == 0 {
+		return errors.New("no local namespaces configured")
+	}
+	for ns := range i.rws.writes {
+		if !db.localNamespaces[ns] {
+			return errors.Errorf("namespace [%s] is not local", ns)
+		}
+	}
+	for ns := range i.rws.metawrites {
+		if !db.localNamespaces[ns] {
+			return errors.Errorf("namespace [%s] is not local", ns)
+		}
+	}
+	if len(i.rws.private.writes) != 0 {
+		return errors.New("private data cannot be committed locally")
+	}
+
+	// the transactions committed since the read-write set was assembled might have changed what it read
+	return i.IsValid()
+}
+
+// rejectLocal marks the passed transaction as invalid and records why in the audit log
+func (db *Vault) rejectLocal(txid string, seq uint64, reason error) error {
+	if err := db.store.BeginUpdate(); err != nil {
+		return errors.WithMessagef(err, "begin update for txid '%s' failed", txid)
+	}
+	if err := db.recordLocal(&api.LocalTransaction{Seq: seq, TxID: txid, Reason: reason.Error()}); err != nil {
+		return err
+	}
+	if err := db.store.Commit(); err != nil {
+		return errors.WithMessagef(err, "committing local tx for txid '%s' failed", txid)
+	}
+	return nil
+}
+
+// recordLocal stores the sequence number, the status and the audit log entry of the passed transaction,
+// as part of the ongoing update. On error, the update is discarded.
+func (db *Vault) recordLocal(entry *api.LocalTransaction) error {
+	entry.Timestamp = time.Now().UTC()
+	status, err := json.Marshal(&localStatus{Seq: entry.Seq, Valid: entry.Valid})
+	if err != nil {
+		return errors.Wrapf(err, "failed marshalling status of local transaction [%s]", entry.TxID)
+	}
+	log, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrapf(err, "failed marshalling log entry of local transaction [%s]", entry.TxID)
+	}
+
+	for _, kv := range []struct {
+		key   string
+		value []byte
+	}{
+		{localSequenceKey, []byte(strconv.FormatUint(entry.Seq, 10))},
+		{localTxPrefix + entry.TxID, status},
+		{localLogKey(entry.Seq), log},
+	} {
+		if err := db.store.SetState(localNamespace, kv.key, kv.value, 0, entry.Seq); err != nil {
+			if err1 := db.store.Discard(); err1 != nil {
+				logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+			}
+			return errors.WithMessagef(err, "failed recording local transaction [%s]", entry.TxID)
+		}
+	}
+	return nil
+}
+
+// localSequence returns the last sequence number assigned to a local transaction, 0 if none
+func (db *Vault) localSequence() (uint64, error) {
+	raw, _, _, err := db.store.GetState(localNamespace, localSequenceKey)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed reading local sequence number")
+	}
+	if len(raw) == 0 {
+		return 0, nil
+	}
+	seq, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid local sequence number [%s]", string(raw))
+	}
+	return seq, nil
+}
+
+// localStatus returns the status of the passed transaction, if it was committed or rejected locally
+func (db *Vault) localStatus(txid string) (api.ValidationCode, error) {
+	raw, _, _, err := db.store.GetState(localNamespace, localTxPrefix+txid)
+	if err != nil {
+		return api.Unknown, errors.WithMessagef(err, "failed reading status of local transaction [%s]", txid)
+	}
+	if len(raw) == 0 {
+		return api.Unknown, nil
+	}
+	status := &localStatus{}
+	if err := json.Unmarshal(raw, status); err != nil {
+		return api.Unknown, errors.Wrapf(err, "failed unmarshalling status of local transaction [%s]", txid)
+	}
+	if status.Valid {
+		return api.Valid, nil
+	}
+	return api.Invalid, nil
+}
+
+func localWrites(w writes) []*api.LocalWrite {
+	var res []*api.LocalWrite
+	for ns, keyMap := range w {
+		for key, v := range keyMap {
+			res = append(res, &api.LocalWrite{Namespace: ns, Key: key, Deleted: len(v) == 0})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Namespace != res[j].Namespace {
+			return res[i].Namespace < res[j].Namespace
+		}
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
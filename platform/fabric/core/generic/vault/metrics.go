@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package vault
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+)
+
+var (
+	committedTransactionsCounterOpts = metrics.CounterOpts{
+		Namespace:  "fabric",
+		Subsystem:  "vault",
+		Name:       "committed_transactions",
+		Help:       "Transactions committed to the vault, by network and channel.",
+		LabelNames: []string{"network", "channel"},
+	}
+
+	discardedTransactionsCounterOpts = metrics.CounterOpts{
+		Namespace:  "fabric",
+		Subsystem:  "vault",
+		Name:       "discarded_transactions",
+		Help:       "Transactions discarded by the vault, by network and channel.",
+		LabelNames: []string{"network", "channel"},
+	}
+)
+
+type Metrics struct {
+	CommittedTransactions metrics.Counter
+	DiscardedTransactions metrics.Counter
+}
+
+// NewMetrics returns the metrics of the vault of the passed network and channel
+func NewMetrics(p metrics.Provider, network, channel string) *Metrics {
+	return &Metrics{
+		CommittedTransactions: p.NewCounter(committedTransactionsCounterOpts).With("network", network, "channel", channel),
+		DiscardedTransactions: p.NewCounter(discardedTransactionsCounterOpts).With("network", network, "channel", channel),
+	}
+}
+
+func newDisabledMetrics() *Metrics {
+	return NewMetrics(&disabled.Provider{}, "", "")
+}
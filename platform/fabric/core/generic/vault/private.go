@@ -0,0 +1,413 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package vault
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cmp "github.com/google/go-cmp/cmp"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/keys"
+)
+
+// privateNamespace returns the namespace, in the vault's store, of the private data of the passed collection.
+// This is the side store of the vault, it holds the preimages of the private writes of the committed
+// transactions, for the collections this node received the private data of.
+func privateNamespace(ns, coll string) string {
+	return ns + privateSeparator + coll
+}
+
+// privateSeparator separates the namespace from the collection in the namespaces of the private data
+const privateSeparator = "$$p"
+
+type privateVersion struct {
+	block uint64
+	txnum uint64
+}
+
+// privateSet holds the private reads and writes of a transaction, by namespace and collection.
+// The collections simulated locally are hashed when the read-write set is marshalled, those received with
+// the read-write set keep their hashed read-write set, the preimages, if any, are verified against it.
+type privateSet struct {
+	// writes are the preimages of the private writes, a nil value deletes the key
+	writes map[string]map[string]map[string][]byte
+	// reads are the versions of the private keys read during the simulation
+	reads map[string]map[string]map[string]privateVersion
+	// hashed are the hashed read-write sets of the collections received with the read-write set
+	hashed map[string]map[string]*rwset.CollectionHashedReadWriteSet
+	// preimages are the received private read-write sets, as marshalled by the node that simulated them
+	preimages map[string]map[string][]byte
+}
+
+func newPrivateSet() privateSet {
+	return privateSet{
+		writes:    map[string]map[string]map[string][]byte{},
+		reads:     map[string]map[string]map[string]privateVersion{},
+		hashed:    map[string]map[string]*rwset.CollectionHashedReadWriteSet{},
+		preimages: map[string]map[string][]byte{},
+	}
+}
+
+func (p *privateSet) validate(ns, coll, key string) error {
+	if err := keys.ValidateNs(ns); err != nil {
+		return err
+	}
+	if err := keys.ValidateNs(coll); err != nil {
+		return errors.Errorf("collection '%s' is invalid", coll)
+	}
+	return keys.ValidateKey(key)
+}
+
+func (p *privateSet) add(ns, coll, key string, value []byte) error {
+	if err := p.validate(ns, coll, key); err != nil {
+		return err
+	}
+	if _, in := p.hashed[ns][coll]; in {
+		return errors.Errorf("the read-write set of collection [%s:%s] has been received, it cannot be extended", ns, coll)
+	}
+	p.put(ns, coll, key, value)
+	return nil
+}
+
+func (p *privateSet) put(ns, coll, key string, value []byte) {
+	nsMap, in := p.writes[ns]
+	if !in {
+		nsMap = map[string]map[string][]byte{}
+		p.writes[ns] = nsMap
+	}
+	collMap, in := nsMap[coll]
+	if !in {
+		collMap = map[string][]byte{}
+		nsMap[coll] = collMap
+	}
+	if len(value) == 0 {
+		collMap[key] = nil
+		return
+	}
+	collMap[key] = append([]byte(nil), value...)
+}
+
+func (p *privateSet) get(ns, coll, key string) []byte {
+	return p.writes[ns][coll][key]
+}
+
+func (p *privateSet) in(ns, coll, key string) bool {
+	_, in := p.writes[ns][coll][key]
+	return in
+}
+
+func (p *privateSet) addRead(ns, coll, key string, block, txnum uint64) error {
+	if err := p.validate(ns, coll, key); err != nil {
+		return err
+	}
+	if _, in := p.hashed[ns][coll]; in {
+		return errors.Errorf("the read-write set of collection [%s:%s] has been received, it cannot be extended", ns, coll)
+	}
+	nsMap, in := p.reads[ns]
+	if !in {
+		nsMap = map[string]map[string]privateVersion{}
+		p.reads[ns] = nsMap
+	}
+	collMap, in := nsMap[coll]
+	if !in {
+		collMap = map[string]privateVersion{}
+		nsMap[coll] = collMap
+	}
+	collMap[key] = privateVersion{block: block, txnum: txnum}
+	return nil
+}
+
+func (p *privateSet) getRead(ns, coll, key string) (block, txnum uint64, in bool) {
+	v, in := p.reads[ns][coll][key]
+	return v.block, v.txnum, in
+}
+
+func (p *privateSet) clear(ns string) {
+	delete(p.writes, ns)
+	delete(p.reads, ns)
+	delete(p.hashed, ns)
+	delete(p.preimages, ns)
+}
+
+// collections returns the collections of the passed namespace with private reads or writes
+func (p *privateSet) collections(ns string) []string {
+	merged := map[string]struct{}{}
+	for coll := range p.writes[ns] {
+		merged[coll] = struct{}{}
+	}
+	for coll := range p.reads[ns] {
+		merged[coll] = struct{}{}
+	}
+	for coll := range p.hashed[ns] {
+		merged[coll] = struct{}{}
+	}
+	res := make([]string, 0, len(merged))
+	for coll := range merged {
+		res = append(res, coll)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// addHashed adds the hashed read-write sets of the collections of the passed namespace
+func (p *privateSet) addHashed(ns string, colls []*rwset.CollectionHashedReadWriteSet) error {
+	for _, coll := range colls {
+		if _, in := p.hashed[ns][coll.CollectionName]; in {
+			return errors.Errorf("duplicate hashed read-write set for collection [%s:%s]", ns, coll.CollectionName)
+		}
+		if _, in := p.writes[ns][coll.CollectionName]; in {
+			return errors.Errorf("duplicate read-write set for collection [%s:%s]", ns, coll.CollectionName)
+		}
+		if _, in := p.reads[ns][coll.CollectionName]; in {
+			return errors.Errorf("duplicate read-write set for collection [%s:%s]", ns, coll.CollectionName)
+		}
+		nsMap, in := p.hashed[ns]
+		if !in {
+			nsMap = map[string]*rwset.CollectionHashedReadWriteSet{}
+			p.hashed[ns] = nsMap
+		}
+		nsMap[coll.CollectionName] = coll
+	}
+	return nil
+}
+
+// addPreimages adds the preimages of the private writes contained in the passed private read-write set.
+// The preimages of a collection must match the hash of its hashed read-write set.
+func (p *privateSet) addPreimages(raw []byte) error {
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(raw, txPvtRWSet); err != nil {
+		return errors.Wrap(err, "provided invalid private read-write set bytes, unmarshal failed")
+	}
+
+	for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwset {
+		ns := nsPvtRWSet.Namespace
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			coll := collPvtRWSet.CollectionName
+			hashed, in := p.hashed[ns][coll]
+			if !in {
+				return errors.Errorf("no hashed read-write set for collection [%s:%s]", ns, coll)
+			}
+			if !bytes.Equal(util.ComputeHash(collPvtRWSet.Rwset), hashed.PvtRwsetHash) {
+				return errors.Errorf("private read-write set of collection [%s:%s] does not match its hash", ns, coll)
+			}
+			if _, in := p.preimages[ns][coll]; in {
+				continue
+			}
+
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(collPvtRWSet.Rwset, kvRWSet); err != nil {
+				return errors.Wrapf(err, "provided invalid private read-write set for collection [%s:%s]", ns, coll)
+			}
+			for _, write := range kvRWSet.Writes {
+				if err := p.validate(ns, coll, write.Key); err != nil {
+					return err
+				}
+				if write.IsDelete {
+					p.put(ns, coll, write.Key, nil)
+					continue
+				}
+				p.put(ns, coll, write.Key, write.Value)
+			}
+
+			nsMap, in := p.preimages[ns]
+			if !in {
+				nsMap = map[string][]byte{}
+				p.preimages[ns] = nsMap
+			}
+			nsMap[coll] = append([]byte(nil), collPvtRWSet.Rwset...)
+		}
+	}
+	return nil
+}
+
+// addTo adds the private reads and writes simulated locally to the passed builder
+func (p *privateSet) addTo(rwsb *rwsetutil.RWSetBuilder) {
+	for ns, nsMap := range p.reads {
+		for coll, collMap := range nsMap {
+			for key, v := range collMap {
+				if v.block != 0 || v.txnum != 0 {
+					rwsb.AddToHashedReadSet(ns, coll, key, rwsetutil.NewVersion(&kvrwset.Version{BlockNum: v.block, TxNum: v.txnum}))
+				} else {
+					rwsb.AddToHashedReadSet(ns, coll, key, nil)
+				}
+			}
+		}
+	}
+	for ns, nsMap := range p.writes {
+		for coll, collMap := range nsMap {
+			if _, in := p.hashed[ns][coll]; in {
+				// received preimages, the hashed read-write set is already there
+				continue
+			}
+			for key, v := range collMap {
+				rwsb.AddToPvtAndHashedWriteSet(ns, coll, key, v)
+			}
+		}
+	}
+}
+
+// mergeHashed adds the received hashed read-write sets to the passed public read-write set
+func (p *privateSet) mergeHashed(txRWSet *rwset.TxReadWriteSet) error {
+	for ns, nsMap := range p.hashed {
+		var nsRWSet *rwset.NsReadWriteSet
+		for _, candidate := range txRWSet.NsRwset {
+			if candidate.Namespace == ns {
+				nsRWSet = candidate
+				break
+			}
+		}
+		if nsRWSet == nil {
+			raw, err := proto.Marshal(&kvrwset.KVRWSet{})
+			if err != nil {
+				return err
+			}
+			nsRWSet = &rwset.NsReadWriteSet{Namespace: ns, Rwset: raw}
+			txRWSet.NsRwset = append(txRWSet.NsRwset, nsRWSet)
+		}
+		for _, coll := range nsMap {
+			nsRWSet.CollectionHashedRwset = append(nsRWSet.CollectionHashedRwset, coll)
+		}
+		sort.Slice(nsRWSet.CollectionHashedRwset, func(i, j int) bool {
+			return nsRWSet.CollectionHashedRwset[i].CollectionName < nsRWSet.CollectionHashedRwset[j].CollectionName
+		})
+	}
+	sort.Slice(txRWSet.NsRwset, func(i, j int) bool {
+		return txRWSet.NsRwset[i].Namespace < txRWSet.NsRwset[j].Namespace
+	})
+	return nil
+}
+
+// mergePreimages adds the received preimages to the passed private read-write set
+func (p *privateSet) mergePreimages(txPvtRWSet *rwset.TxPvtReadWriteSet) {
+	for ns, nsMap := range p.preimages {
+		var nsPvtRWSet *rwset.NsPvtReadWriteSet
+		for _, candidate := range txPvtRWSet.NsPvtRwset {
+			if candidate.Namespace == ns {
+				nsPvtRWSet = candidate
+				break
+			}
+		}
+		if nsPvtRWSet == nil {
+			nsPvtRWSet = &rwset.NsPvtReadWriteSet{Namespace: ns}
+			txPvtRWSet.NsPvtRwset = append(txPvtRWSet.NsPvtRwset, nsPvtRWSet)
+		}
+		for coll, raw := range nsMap {
+			nsPvtRWSet.CollectionPvtRwset = append(nsPvtRWSet.CollectionPvtRwset, &rwset.CollectionPvtReadWriteSet{
+				CollectionName: coll,
+				Rwset:          raw,
+			})
+		}
+		sort.Slice(nsPvtRWSet.CollectionPvtRwset, func(i, j int) bool {
+			return nsPvtRWSet.CollectionPvtRwset[i].CollectionName < nsPvtRWSet.CollectionPvtRwset[j].CollectionName
+		})
+	}
+	sort.Slice(txPvtRWSet.NsPvtRwset, func(i, j int) bool {
+		return txPvtRWSet.NsPvtRwset[i].Namespace < txPvtRWSet.NsPvtRwset[j].Namespace
+	})
+}
+
+// simulationResults returns the public read-write set, with the hashes of the private data,
+// and the private read-write set, nil if there is no private data
+func (rws *readWriteSet) simulationResults() (*rwset.TxReadWriteSet, *rwset.TxPvtReadWriteSet, error) {
+	rwsb := rwsetutil.NewRWSetBuilder()
+
+	for ns, keyMap := range rws.reads {
+		for key, v := range keyMap {
+			if v.block != 0 || v.txnum != 0 {
+				rwsb.AddToReadSet(ns, key, rwsetutil.NewVersion(&kvrwset.Version{BlockNum: v.block, TxNum: v.txnum}))
+			} else {
+				rwsb.AddToReadSet(ns, key, nil)
+			}
+		}
+	}
+	for ns, keyMap := range rws.writes {
+		for key, v := range keyMap {
+			rwsb.AddToWriteSet(ns, key, v)
+		}
+	}
+	for ns, keyMap := range rws.metawrites {
+		for key, v := range keyMap {
+			rwsb.AddToMetadataWriteSet(ns, key, v)
+		}
+	}
+	rws.rangeQuerySet.addTo(rwsb)
+	rws.private.addTo(rwsb)
+
+	simRes, err := rwsb.GetTxSimulationResults()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := rws.private.mergeHashed(simRes.PubSimulationResults); err != nil {
+		return nil, nil, err
+	}
+	pvt := simRes.PvtSimulationResults
+	if len(rws.private.preimages) != 0 {
+		if pvt == nil {
+			pvt = &rwset.TxPvtReadWriteSet{DataModel: rwset.TxReadWriteSet_KV}
+		}
+		rws.private.mergePreimages(pvt)
+	}
+	return simRes.PubSimulationResults, pvt, nil
+}
+
+func (p *privateSet) equals(o *privateSet, nss ...string) error {
+	for _, ns := range namespacesOf(p, o, nss...) {
+		colls := p.collections(ns)
+		if diff := cmp.Diff(colls, o.collections(ns)); len(diff) != 0 {
+			return errors.Errorf("collections of namespace [%s] do not match [%s]", ns, diff)
+		}
+		for _, coll := range colls {
+			if err := namespaceWrites(p.writes[ns][coll]).Equals(o.writes[ns][coll]); err != nil {
+				return errors.Wrapf(err, "writes of collection [%s:%s] do not match", ns, coll)
+			}
+			if diff := cmp.Diff(p.reads[ns][coll], o.reads[ns][coll], cmp.AllowUnexported(privateVersion{})); len(diff) != 0 {
+				return errors.Errorf("reads of collection [%s:%s] do not match [%s]", ns, coll, diff)
+			}
+			if !proto.Equal(p.hashed[ns][coll], o.hashed[ns][coll]) {
+				return errors.Errorf("hashed read-write sets of collection [%s:%s] do not match", ns, coll)
+			}
+		}
+	}
+	return nil
+}
+
+// namespacesOf returns the namespaces with private data in any of the passed sets, restricted to nss if not empty
+func namespacesOf(p, o *privateSet, nss ...string) []string {
+	merged := map[string]struct{}{}
+	for _, s := range []*privateSet{p, o} {
+		for ns := range s.writes {
+			merged[ns] = struct{}{}
+		}
+		for ns := range s.reads {
+			merged[ns] = struct{}{}
+		}
+		for ns := range s.hashed {
+			merged[ns] = struct{}{}
+		}
+	}
+	var res []string
+	for ns := range merged {
+		if len(nss) == 0 {
+			res = append(res, ns)
+			continue
+		}
+		for _, s := range nss {
+			if s == ns {
+				res = append(res, ns)
+				break
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -26,6 +26,13 @@ func (q *directQueryExecutor) GetState(namespace string, key string) ([]byte, er
 	return v, err
 }
 
+// GetPrivateState returns the value of the passed key in the side store of the passed collection
+func (q *directQueryExecutor) GetPrivateState(namespace, collection, key string) ([]byte, error) {
+	logger.Debugf("Get Private State [%s,%s,%s]", namespace, collection, key)
+	v, _, _, err := q.vault.store.GetState(privateNamespace(namespace, collection), key)
+	return v, err
+}
+
 func (q *directQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
 	return q.vault.store.GetStateRangeScanIterator(namespace, startKey, endKey)
 }
@@ -55,3 +62,7 @@ func (i *interceptorQueryExecutor) GetStateMetadata(namespace, key string) (map[
 func (i *interceptorQueryExecutor) GetState(namespace, key string) ([]byte, uint64, uint64, error) {
 	return i.store.GetState(namespace, key)
 }
+
+func (i *interceptorQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (driver.VersionedResultsIterator, error) {
+	return i.store.GetStateRangeScanIterator(namespace, startKey, endKey)
+}
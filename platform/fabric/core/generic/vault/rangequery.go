@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package vault
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/keys"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
+)
+
+// RangeQueryMaxDegree is the maximum degree of the merkle trees summarizing the results of range queries.
+// Ranges with up to RangeQueryMaxDegree results carry the raw reads. The default is the one of the peer.
+var RangeQueryMaxDegree uint32 = 50
+
+type rangeQuerySet struct {
+	ranges map[string][]*kvrwset.RangeQueryInfo
+}
+
+func (r *rangeQuerySet) add(ns string, rqi *kvrwset.RangeQueryInfo) error {
+	if err := keys.ValidateNs(ns); err != nil {
+		return err
+	}
+
+	r.ranges[ns] = append(r.ranges[ns], rqi)
+
+	return nil
+}
+
+func (r *rangeQuerySet) clear(ns string) {
+	r.ranges[ns] = nil
+}
+
+func (r *rangeQuerySet) addTo(rwsb *rwsetutil.RWSetBuilder) {
+	for ns, rqis := range r.ranges {
+		for _, rqi := range rqis {
+			rwsb.AddToRangeQuerySet(ns, rqi)
+		}
+	}
+}
+
+func (r *rangeQuerySet) equals(o *rangeQuerySet, nss ...string) error {
+	for _, ns := range rangeNamespacesOf(r, o, nss...) {
+		rqis, orqis := r.ranges[ns], o.ranges[ns]
+		if len(rqis) != len(orqis) {
+			return errors.Errorf("number of range queries in [%s] do not match [%d]!=[%d]", ns, len(rqis), len(orqis))
+		}
+		for i := range rqis {
+			if !proto.Equal(rqis[i], orqis[i]) {
+				return errors.Errorf("range queries [%d] in [%s] do not match [%s,%s]!=[%s,%s]", i, ns, rqis[i].StartKey, rqis[i].EndKey, orqis[i].StartKey, orqis[i].EndKey)
+			}
+		}
+	}
+	return nil
+}
+
+func rangeNamespacesOf(r, o *rangeQuerySet, nss ...string) []string {
+	set := map[string]struct{}{}
+	for _, m := range []map[string][]*kvrwset.RangeQueryInfo{r.ranges, o.ranges} {
+		for ns, rqis := range m {
+			if len(rqis) != 0 {
+				set[ns] = struct{}{}
+			}
+		}
+	}
+	var res []string
+	for ns := range set {
+		if len(nss) == 0 {
+			res = append(res, ns)
+			continue
+		}
+		for _, s := range nss {
+			if s == ns {
+				res = append(res, ns)
+				break
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// rangeIterator records the results returned by a range scan, the range query info is added
+// to the read-write set when the iterator is closed.
+type rangeIterator struct {
+	itr    driver.VersionedResultsIterator
+	ns     string
+	endKey string
+	rqi    *kvrwset.RangeQueryInfo
+	helper *rwsetutil.RangeQueryResultsHelper
+	read   bool
+	closed bool
+	// err is the failure to record the range query info on close, it invalidates the read-write set
+	err     error
+	onClose func(ns string, rqi *kvrwset.RangeQueryInfo) error
+}
+
+func newRangeIterator(itr driver.VersionedResultsIterator, ns, startKey, endKey string, onClose func(ns string, rqi *kvrwset.RangeQueryInfo) error) (*rangeIterator, error) {
+	helper, err := rwsetutil.NewRangeQueryResultsHelper(true, RangeQueryMaxDegree, hash.SHA256)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating range query results helper")
+	}
+	return &rangeIterator{
+		itr:     itr,
+		ns:      ns,
+		endKey:  endKey,
+		rqi:     &kvrwset.RangeQueryInfo{StartKey: startKey},
+		helper:  helper,
+		onClose: onClose,
+	}, nil
+}
+
+// Next returns the next result and updates the range query info, as the peer does.
+// The end key is the last key returned until the iterator gets exhausted, the caller might not call Next again.
+func (r *rangeIterator) Next() (*driver.VersionedRead, error) {
+	if r.closed {
+		return nil, errors.New("this iterator was closed")
+	}
+	read, err := r.itr.Next()
+	if err != nil {
+		return nil, err
+	}
+	r.read = true
+	if read == nil {
+		r.rqi.ItrExhausted = true
+		r.rqi.EndKey = r.endKey
+		return nil, nil
+	}
+	if err := r.helper.AddResult(newKVRead(read.Key, read.Block, uint64(read.IndexInBlock))); err != nil {
+		return nil, errors.Wrapf(err, "failed recording range query result [%s:%s]", r.ns, read.Key)
+	}
+	r.rqi.EndKey = read.Key
+	return read, nil
+}
+
+// Close closes the underlying iterator and records the range query info.
+// A failure to record it is kept, see Err, and fails the read-write set the iterator belongs to.
+func (r *rangeIterator) Close() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.itr.Close()
+
+	if !r.read {
+		// nothing has been observed
+		return
+	}
+	if r.err = r.record(); r.err != nil {
+		logger.Errorf("closing range iterator: [%s]", r.err)
+	}
+}
+
+// Err returns the failure to record the range query info, if any
+func (r *rangeIterator) Err() error {
+	return r.err
+}
+
+func (r *rangeIterator) record() error {
+	results, summary, err := r.helper.Done()
+	if err != nil {
+		return errors.Wrapf(err, "failed summarizing range query [%s:%s,%s]", r.ns, r.rqi.StartKey, r.rqi.EndKey)
+	}
+	if results != nil {
+		rwsetutil.SetRawReads(r.rqi, results)
+	}
+	if summary != nil {
+		rwsetutil.SetMerkelSummary(r.rqi, summary)
+	}
+	return errors.WithMessagef(r.onClose(r.ns, r.rqi), "failed recording range query [%s:%s,%s]", r.ns, r.rqi.StartKey, r.rqi.EndKey)
+}
+
+// validateRange runs again the passed range query against the vault and checks that the results did not change.
+// This detects phantom reads, keys added to or removed from the range, as well as updates of the keys in the range.
+func validateRange(qe QueryExecutor, ns string, rqi *kvrwset.RangeQueryInfo) error {
+	endKey := rqi.EndKey
+	if !rqi.ItrExhausted {
+		// the range ends with the last key read, included
+		endKey += "\x00"
+	}
+
+	maxDegree := RangeQueryMaxDegree
+	summary := rqi.GetReadsMerkleHashes()
+	if summary != nil {
+		maxDegree = summary.MaxDegree
+	}
+	helper, err := rwsetutil.NewRangeQueryResultsHelper(summary != nil, maxDegree, hash.SHA256)
+	if err != nil {
+		return errors.Wrap(err, "failed creating range query results helper")
+	}
+
+	itr, err := qe.GetStateRangeScanIterator(ns, rqi.StartKey, endKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed scanning range [%s:%s,%s]", ns, rqi.StartKey, rqi.EndKey)
+	}
+	defer itr.Close()
+	for {
+		read, err := itr.Next()
+		if err != nil {
+			return errors.Wrapf(err, "failed scanning range [%s:%s,%s]", ns, rqi.StartKey, rqi.EndKey)
+		}
+		if read == nil {
+			break
+		}
+		if err := helper.AddResult(newKVRead(read.Key, read.Block, uint64(read.IndexInBlock))); err != nil {
+			return errors.Wrapf(err, "failed recording range query result [%s:%s]", ns, read.Key)
+		}
+	}
+	results, hashes, err := helper.Done()
+	if err != nil {
+		return errors.Wrapf(err, "failed summarizing range [%s:%s,%s]", ns, rqi.StartKey, rqi.EndKey)
+	}
+
+	if !proto.Equal(&kvrwset.QueryReads{KvReads: results}, &kvrwset.QueryReads{KvReads: rqi.GetRawReads().GetKvReads()}) ||
+		(hashes == nil) != (summary == nil) || (hashes != nil && !proto.Equal(hashes, summary)) {
+		return errors.Errorf("invalid range query: the results of [%s:%s,%s] changed", ns, rqi.StartKey, rqi.EndKey)
+	}
+	return nil
+}
+
+func newKVRead(key string, block, txnum uint64) *kvrwset.KVRead {
+	if block == 0 && txnum == 0 {
+		return &kvrwset.KVRead{Key: key}
+	}
+	return &kvrwset.KVRead{Key: key, Version: &kvrwset.Version{BlockNum: block, TxNum: txnum}}
+}
@@ -21,6 +21,8 @@ type readWriteSet struct {
 	readSet
 	writeSet
 	metaWriteSet
+	rangeQuerySet
+	private privateSet
 }
 
 func (rws *readWriteSet) populate(rwsetBytes []byte, txid string) error {
@@ -35,9 +37,13 @@ func (rws *readWriteSet) populate(rwsetBytes []byte, txid string) error {
 		return errors.Wrapf(err, "provided invalid read-write set bytes for txid %s, TxRwSetFromProtoMsg failed", txid)
 	}
 
-	for _, nsrws := range rwsIn.NsRwSets {
+	for i, nsrws := range rwsIn.NsRwSets {
 		ns := nsrws.NameSpace
 
+		if err := rws.private.addHashed(ns, txRWSet.NsRwset[i].CollectionHashedRwset); err != nil {
+			return err
+		}
+
 		for _, read := range nsrws.KvRwSet.Reads {
 			bn := uint64(0)
 			txn := uint64(0)
@@ -64,6 +70,12 @@ func (rws *readWriteSet) populate(rwsetBytes []byte, txid string) error {
 				return err
 			}
 		}
+
+		for _, rqi := range nsrws.KvRwSet.RangeQueriesInfo {
+			if err := rws.rangeQuerySet.add(ns, rqi); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,317 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package vault
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/db/driver"
+)
+
+// A snapshot is a stream of JSON records, one per line: a header, the entries of the store, in order,
+// and a trailer carrying the number of entries and the SHA-256 of the lines preceding it.
+// The store of the vault holds the state, the metadata, the private data, the history and the txidstore.
+const (
+	SnapshotVersion = 1
+
+	snapshotNamespace = "$$s"
+	snapshotHeightKey = "height"
+	// snapshotBatchSize is the number of entries imported per update, to keep the transactions of the store small
+	snapshotBatchSize = 1000
+)
+
+type snapshotHeader struct {
+	Version   int
+	LastBlock uint64
+}
+
+type snapshotTrailer struct {
+	Entries  uint64
+	Checksum string
+}
+
+type snapshotRecord struct {
+	Header  *snapshotHeader        `json:",omitempty"`
+	Entry   *driver.VersionedEntry `json:",omitempty"`
+	Trailer *snapshotTrailer       `json:",omitempty"`
+}
+
+// snapshotWriter writes the records of a snapshot, hashing them
+type snapshotWriter struct {
+	w       *bufio.Writer
+	hash    hash.Hash
+	entries uint64
+}
+
+func (s *snapshotWriter) write(r *snapshotRecord) error {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "failed marshalling snapshot record")
+	}
+	raw = append(raw, '\n')
+	if r.Trailer == nil {
+		s.hash.Write(raw)
+	}
+	if _, err := s.w.Write(raw); err != nil {
+		return errors.Wrap(err, "failed writing snapshot")
+	}
+	return nil
+}
+
+// ExportSnapshot writes the content of the vault to the passed writer.
+// Unless includePrivate is set, the entries private to this node are left out: the private data of the collections,
+// and the status and the audit log of the local transactions. Set it only for a node of the same organization,
+// the snapshot would otherwise disclose the private data of collections the receiving node is not a member of.
+// Commits are blocked until the export completes, so that the snapshot is consistent.
+// The last block of the snapshot is the highest block of the entries, or of the commits, whichever is higher. The node importing the snapshot resumes the delivery of the blocks from there.
+func (db *Vault) ExportSnapshot(w io.Writer, includePrivate bool) (*api.SnapshotInfo, error) {
+	db.storeLock.RLock()
+	defer db.storeLock.RUnlock()
+
+	// the highest block is known only once all the entries are read, it goes in the trailer too
+	lastBlock, err := db.highestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if last := db.lastBlock.Load(); last > lastBlock {
+		lastBlock = last
+	}
+
+	sw := &snapshotWriter{w: bufio.NewWriter(w), hash: sha256.New()}
+	if err := sw.write(&snapshotRecord{Header: &snapshotHeader{Version: SnapshotVersion, LastBlock: lastBlock}}); err != nil {
+		return nil, err
+	}
+	itr, err := db.store.GetEntriesIterator()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed iterating over the vault")
+	}
+	defer itr.Close()
+	for {
+		e, err := itr.Next()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed iterating over the vault")
+		}
+		if e == nil {
+			break
+		}
+		if e.Namespace == snapshotNamespace {
+			// the height of the snapshot the vault was bootstrapped from is superseded by this one
+			continue
+		}
+		if !includePrivate && isPrivateEntry(e) {
+			continue
+		}
+		if err := sw.write(&snapshotRecord{Entry: e}); err != nil {
+			return nil, err
+		}
+		sw.entries++
+	}
+	info := &api.SnapshotInfo{
+		LastBlock: lastBlock,
+		Entries:   sw.entries,
+		Checksum:  hex.EncodeToString(sw.hash.Sum(nil)),
+	}
+	if err := sw.write(&snapshotRecord{Trailer: &snapshotTrailer{Entries: info.Entries, Checksum: info.Checksum}}); err != nil {
+		return nil, err
+	}
+	if err := sw.w.Flush(); err != nil {
+		return nil, errors.Wrap(err, "failed writing snapshot")
+	}
+	return info, nil
+}
+
+// isPrivateEntry returns true if the passed entry is private to this node: the private data of a collection,
+// or the local transactions, their status and audit log.
+func isPrivateEntry(e *driver.VersionedEntry) bool {
+	return e.Namespace == localNamespace || strings.Contains(e.Namespace, privateSeparator)
+}
+
+// VerifySnapshot reads the passed snapshot and checks its format and checksum
+func VerifySnapshot(r io.Reader) (*api.SnapshotInfo, error) {
+	return readSnapshot(r, func(*driver.VersionedEntry) error { return nil })
+}
+
+// ImportSnapshot loads the passed snapshot into the store of a vault, that must be empty.
+// The store must not be in use, the vault and its txidstore are to be created afterwards.
+// The snapshot is imported in batches: if it turns out to be corrupted, the store is left partially
+// populated and has to be discarded. Verify the snapshot first with VerifySnapshot to avoid that.
+func ImportSnapshot(store driver.VersionedPersistence, r io.Reader) (*api.SnapshotInfo, error) {
+	itr, err := store.GetEntriesIterator()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed iterating over the vault")
+	}
+	e, err := itr.Next()
+	itr.Close()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed iterating over the vault")
+	}
+	if e != nil {
+		return nil, errors.Errorf("the vault is not empty, found [%s:%s]", e.Namespace, e.Key)
+	}
+
+	inBatch := 0
+	commit := func() error {
+		if inBatch == 0 {
+			return nil
+		}
+		inBatch = 0
+		return store.Commit()
+	}
+	info, err := readSnapshot(r, func(e *driver.VersionedEntry) error {
+		if inBatch == 0 {
+			if err := store.BeginUpdate(); err != nil {
+				return errors.WithMessage(err, "begin update for snapshot import failed")
+			}
+		}
+		inBatch++
+		if err := store.SetState(e.Namespace, e.Key, e.Raw, e.Block, e.TxNum); err != nil {
+			return errors.WithMessagef(err, "failed importing [%s:%s]", e.Namespace, e.Key)
+		}
+		if len(e.Metadata) != 0 {
+			if err := store.SetStateMetadata(e.Namespace, e.Key, e.Metadata, e.Block, e.TxNum); err != nil {
+				return errors.WithMessagef(err, "failed importing metadata of [%s:%s]", e.Namespace, e.Key)
+			}
+		}
+		if inBatch == snapshotBatchSize {
+			return commit()
+		}
+		return nil
+	})
+	if err != nil {
+		if inBatch != 0 {
+			if err1 := store.Discard(); err1 != nil {
+				logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+			}
+		}
+		return nil, err
+	}
+	if err := commit(); err != nil {
+		return nil, errors.WithMessage(err, "committing snapshot import failed")
+	}
+
+	// the height goes last, its presence tells that the import completed
+	if err := store.BeginUpdate(); err != nil {
+		return nil, errors.WithMessage(err, "begin update for snapshot import failed")
+	}
+	if err := store.SetState(snapshotNamespace, snapshotHeightKey, []byte(strconv.FormatUint(info.LastBlock, 10)), 0, 0); err != nil {
+		if err1 := store.Discard(); err1 != nil {
+			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+		}
+		return nil, errors.WithMessage(err, "failed storing snapshot height")
+	}
+	if err := store.Commit(); err != nil {
+		return nil, errors.WithMessage(err, "committing snapshot import failed")
+	}
+	return info, nil
+}
+
+// SnapshotHeight returns the last block of the snapshot the vault was bootstrapped from, if any.
+// The delivery of the blocks resumes from there.
+func (db *Vault) SnapshotHeight() (uint64, bool, error) {
+	db.storeLock.RLock()
+	defer db.storeLock.RUnlock()
+
+	return db.snapshotHeight()
+}
+
+func (db *Vault) snapshotHeight() (uint64, bool, error) {
+	raw, _, _, err := db.store.GetState(snapshotNamespace, snapshotHeightKey)
+	if err != nil {
+		return 0, false, errors.WithMessage(err, "failed reading snapshot height")
+	}
+	if len(raw) == 0 {
+		return 0, false, nil
+	}
+	height, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "invalid snapshot height [%s]", string(raw))
+	}
+	return height, true, nil
+}
+
+// highestBlock returns the highest block of the entries of the store, or the height of the snapshot
+// the vault was bootstrapped from, whichever is higher
+func (db *Vault) highestBlock() (uint64, error) {
+	lastBlock, _, err := db.snapshotHeight()
+	if err != nil {
+		return 0, err
+	}
+	itr, err := db.store.GetEntriesIterator()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed iterating over the vault")
+	}
+	defer itr.Close()
+	for {
+		e, err := itr.Next()
+		if err != nil {
+			return 0, errors.WithMessage(err, "failed iterating over the vault")
+		}
+		if e == nil {
+			return lastBlock, nil
+		}
+		if e.Block > lastBlock {
+			lastBlock = e.Block
+		}
+	}
+}
+
+// readSnapshot passes the entries of the snapshot to the callback, in order, and checks the trailer
+func readSnapshot(r io.Reader, callback func(*driver.VersionedEntry) error) (*api.SnapshotInfo, error) {
+	br := bufio.NewReader(r)
+	h := sha256.New()
+	var header *snapshotHeader
+	var entries uint64
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			return nil, errors.New("invalid snapshot: truncated, no trailer found")
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading snapshot")
+		}
+		record := &snapshotRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, errors.Wrapf(err, "invalid snapshot: malformed record after [%d] entries", entries)
+		}
+
+		switch {
+		case record.Header != nil:
+			if header != nil {
+				return nil, errors.New("invalid snapshot: duplicate header")
+			}
+			if record.Header.Version != SnapshotVersion {
+				return nil, errors.Errorf("invalid snapshot: unsupported version [%d]", record.Header.Version)
+			}
+			header = record.Header
+		case header == nil:
+			return nil, errors.New("invalid snapshot: no header found")
+		case record.Entry != nil:
+			if err := callback(record.Entry); err != nil {
+				return nil, err
+			}
+			entries++
+		case record.Trailer != nil:
+			checksum := hex.EncodeToString(h.Sum(nil))
+			if record.Trailer.Entries != entries || record.Trailer.Checksum != checksum {
+				return nil, errors.Errorf("invalid snapshot: expected [%d] entries with checksum [%s], got [%d] with [%s]", record.Trailer.Entries, record.Trailer.Checksum, entries, checksum)
+			}
+			return &api.SnapshotInfo{LastBlock: header.LastBlock, Entries: entries, Checksum: checksum}, nil
+		default:
+			return nil, errors.Errorf("invalid snapshot: empty record after [%d] entries", entries)
+		}
+		h.Write(line)
+	}
+}
@@ -6,8 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 package vault
 
 import (
+	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 	"github.com/pkg/errors"
@@ -20,6 +22,14 @@ import (
 
 var logger = flogging.MustGetLogger("fabric-sdk.vault")
 
+const (
+	healthCheckNamespace = "_healthz"
+	healthCheckKey       = "vault"
+)
+
+// closeTimeout is how long Close waits for the open query executors and rw sets to be done
+var closeTimeout = 10 * time.Second
+
 type TXIDStoreReader interface {
 	Get(txid string) (api.ValidationCode, error)
 }
@@ -46,16 +56,57 @@ type Vault struct {
 	// * an exclusive lock is held when Commit is called.
 	store     driver.VersionedPersistence
 	storeLock sync.RWMutex
+
+	metrics *Metrics
+
+	// history is true if the versions of the keys are recorded in the history index
+	history   bool
+	lastBlock atomic.Uint64
+	// localNamespaces are the namespaces the transactions committed locally can write
+	localNamespaces map[string]bool
 }
 
 func New(store driver.VersionedPersistence, txidStore TXIDStore) *Vault {
+	return NewWithMetrics(store, txidStore, newDisabledMetrics())
+}
+
+// NewWithMetrics returns a new vault that reports committed and discarded transactions to the passed metrics
+func NewWithMetrics(store driver.VersionedPersistence, txidStore TXIDStore, metrics *Metrics) *Vault {
 	return &Vault{
-		interceptors: make(map[string]*Interceptor),
-		store:        store,
-		txidStore:    txidStore,
+		interceptors:    make(map[string]*Interceptor),
+		store:           store,
+		txidStore:       txidStore,
+		metrics:         metrics,
+		localNamespaces: map[string]bool{},
 	}
 }
 
+// HealthCheck returns an error if the vault's store cannot be read
+func (db *Vault) HealthCheck(ctx context.Context) error {
+	if _, _, _, err := db.store.GetState(healthCheckNamespace, healthCheckKey); err != nil {
+		return errors.WithMessage(err, "failed reading from the vault")
+	}
+	return nil
+}
+
+// Close closes the vault's store once the commits in progress, and the open query executors and RW sets,
+// are done. If they are not done within closeTimeout, for example because an executor has been leaked,
+// the store is closed anyway. The vault cannot be used afterwards.
+func (db *Vault) Close() error {
+	locked := make(chan struct{}, 1)
+	go func() {
+		// once acquired, the lock is never released, the vault is closed
+		db.storeLock.Lock()
+		locked <- struct{}{}
+	}()
+	select {
+	case <-locked:
+	case <-time.After(closeTimeout):
+		logger.Warnf("[%d] query executors or rw sets still open after [%s], closing the vault anyway", db.counter.Load(), closeTimeout)
+	}
+	return db.store.Close()
+}
+
 func (db *Vault) NewQueryExecutor() (api.QueryExecutor, error) {
 	logger.Debugf("getting lock for query executor")
 	db.counter.Inc()
@@ -89,9 +140,16 @@ func (db *Vault) unmapInterceptor(txid string) (*Interceptor, error) {
 func (db *Vault) Status(txid string) (api.ValidationCode, error) {
 	code, err := db.txidStore.Get(txid)
 	if err != nil {
-		return 0, nil
+		return api.Unknown, errors.WithMessagef(err, "failed getting status of [%s]", txid)
 	}
 
+	if code != api.Unknown {
+		return code, nil
+	}
+	code, err = db.localStatus(txid)
+	if err != nil {
+		return api.Unknown, err
+	}
 	if code != api.Unknown {
 		return code, nil
 	}
@@ -126,6 +184,7 @@ func (db *Vault) DiscardTx(txid string) error {
 	if err != nil {
 		return errors.WithMessagef(err, "committing tx for txid '%s' failed", txid)
 	}
+	db.metrics.DiscardedTransactions.Add(1)
 
 	return nil
 }
@@ -149,13 +208,42 @@ func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 		return errors.WithMessagef(err, "begin update for txid '%s' failed", txid)
 	}
 
+	if err := db.storeRWSet(txid, i, block, uint64(indexInBloc)); err != nil {
+		return err
+	}
+
+	logger.Debugf("set state to valid [%s]", txid)
+	err = db.txidStore.Set(txid, api.Valid)
+	if err != nil {
+		if err1 := db.store.Discard(); err1 != nil {
+			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+		}
+
+		return err
+	}
+
+	err = db.store.Commit()
+	if err != nil {
+		return errors.WithMessagef(err, "committing tx for txid '%s' failed", txid)
+	}
+	db.metrics.CommittedTransactions.Add(1)
+	if block > db.lastBlock.Load() {
+		db.lastBlock.Store(block)
+	}
+
+	return nil
+}
+
+// storeRWSet stores the writes of the passed read-write set at the passed version, as part of the ongoing update.
+// On error, the update is discarded.
+func (db *Vault) storeRWSet(txid string, i *Interceptor, block, txnum uint64) error {
 	logger.Debugf("parse writes [%s]", txid)
 	for ns, keyMap := range i.rws.writes {
 		for key, v := range keyMap {
 			logger.Debugf("store write [%s,%s,%v]", ns, key, hash.Hashable(v).String())
 			var err error
 			if len(v) != 0 {
-				err = db.store.SetState(ns, key, v, block, uint64(indexInBloc))
+				err = db.store.SetState(ns, key, v, block, txnum)
 			} else {
 				err = db.store.DeleteState(ns, key)
 			}
@@ -165,7 +253,7 @@ func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 					logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
 				}
 
-				return errors.Errorf("failed to commit operation on %s:%s at height %d:%d", ns, key, block, indexInBloc)
+				return errors.Errorf("failed to commit operation on %s:%s at height %d:%d", ns, key, block, txnum)
 			}
 		}
 	}
@@ -175,30 +263,50 @@ func (db *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 		for key, v := range keyMap {
 			logger.Debugf("store meta write [%s,%s]", ns, key)
 
-			err := db.store.SetStateMetadata(ns, key, v, block, uint64(indexInBloc))
+			err := db.store.SetStateMetadata(ns, key, v, block, txnum)
 			if err != nil {
 				if err1 := db.store.Discard(); err1 != nil {
 					logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
 				}
 
-				return errors.Errorf("failed to commit metadata operation on %s:%s at height %d:%d", ns, key, block, indexInBloc)
+				return errors.Errorf("failed to commit metadata operation on %s:%s at height %d:%d", ns, key, block, txnum)
 			}
 		}
 	}
 
-	logger.Debugf("set state to valid [%s]", txid)
-	err = db.txidStore.Set(txid, api.Valid)
-	if err != nil {
-		if err1 := db.store.Discard(); err1 != nil {
-			logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
-		}
+	logger.Debugf("parse private writes [%s]", txid)
+	for ns, collMap := range i.rws.private.writes {
+		for coll, keyMap := range collMap {
+			pns := privateNamespace(ns, coll)
+			for key, v := range keyMap {
+				logger.Debugf("store private write [%s,%s,%s,%v]", ns, coll, key, hash.Hashable(v).String())
+				var err error
+				if len(v) != 0 {
+					err = db.store.SetState(pns, key, v, block, txnum)
+				} else {
+					err = db.store.DeleteState(pns, key)
+				}
 
-		return err
+				if err != nil {
+					if err1 := db.store.Discard(); err1 != nil {
+						logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+					}
+
+					return errors.Errorf("failed to commit private operation on %s:%s:%s at height %d:%d", ns, coll, key, block, txnum)
+				}
+			}
+		}
 	}
 
-	err = db.store.Commit()
-	if err != nil {
-		return errors.WithMessagef(err, "committing tx for txid '%s' failed", txid)
+	if db.history {
+		logger.Debugf("record history [%s]", txid)
+		if err := db.recordHistory(txid, i.rws.writes, block, txnum); err != nil {
+			if err1 := db.store.Discard(); err1 != nil {
+				logger.Errorf("got error %s; discarding caused %s", err.Error(), err1.Error())
+			}
+
+			return err
+		}
 	}
 
 	return nil
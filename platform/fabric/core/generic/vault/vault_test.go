@@ -6,16 +6,20 @@ SPDX-License-Identifier: Apache-2.0
 package vault
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
@@ -653,6 +657,14 @@ func TestVaultErr(t *testing.T) {
 	code, err := vault1.Status("unknown-txid")
 	assert.NoError(t, err)
 	assert.Equal(t, api.Unknown, code)
+
+	// a status that cannot be read is reported
+	assert.NoError(t, ddb.BeginUpdate())
+	assert.NoError(t, ddb.SetState(localNamespace, localTxPrefix+"corrupted", []byte("{"), 0, 0))
+	assert.NoError(t, ddb.Commit())
+	_, err = vault1.Status("corrupted")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed unmarshalling status of local transaction [corrupted]")
 }
 
 func TestInterceptorErr(t *testing.T) {
@@ -816,6 +828,29 @@ func TestQueryExecutor(t *testing.T) {
 	}, res)
 }
 
+func TestCloseWithLeakedExecutor(t *testing.T) {
+	defer func(timeout time.Duration) { closeTimeout = timeout }(closeTimeout)
+	closeTimeout = 100 * time.Millisecond
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	vault := New(ddb, tidstore)
+
+	// the executor is never done, close does not hang
+	_, err = vault.NewQueryExecutor()
+	assert.NoError(t, err)
+	done := make(chan error, 1)
+	go func() { done <- vault.Close() }()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("close hangs on a leaked query executor")
+	}
+}
+
 func TestShardLikeCommit(t *testing.T) {
 	ns := "namespace"
 	k1 := "key1"
@@ -938,3 +973,494 @@ func TestMain(m *testing.M) {
 
 	m.Run()
 }
+
+func TestPrivateData(t *testing.T) {
+	ns, coll := "namespace", "collection"
+
+	newVault := func() (*Vault, driver.VersionedPersistence) {
+		ddb, err := db.OpenVersioned("memory", "")
+		assert.NoError(t, err)
+		tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+		assert.NoError(t, err)
+		return New(ddb, tidstore), ddb
+	}
+	creator, _ := newVault()
+	member, _ := newVault()
+	nonMember, nonMemberDB := newVault()
+
+	// the creator simulates a transaction writing public and private data
+	rws, err := creator.NewRWSet("txid1")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetState(ns, "k1", []byte("public")))
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k2", []byte("private")))
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k3", []byte("deleted")))
+	assert.NoError(t, rws.DeletePrivateState(ns, coll, "k3"))
+	v, err := rws.GetPrivateState(ns, coll, "k2", api.FromBoth)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("private"), v)
+	assert.Equal(t, []string{coll}, rws.Collections(ns))
+	pub, err := rws.Bytes()
+	assert.NoError(t, err)
+	pvt, err := rws.PrivateBytes()
+	assert.NoError(t, err)
+	assert.NotNil(t, pvt)
+	rws.Done()
+
+	// the public read-write set carries the hashes of the private writes only
+	txRWSet := &rwset.TxReadWriteSet{}
+	assert.NoError(t, proto.Unmarshal(pub, txRWSet))
+	rwsIn, err := rwsetutil.TxRwSetFromProtoMsg(txRWSet)
+	assert.NoError(t, err)
+	assert.Len(t, rwsIn.NsRwSets, 1)
+	assert.Len(t, rwsIn.NsRwSets[0].CollHashedRwSets, 1)
+	assert.Len(t, rwsIn.NsRwSets[0].CollHashedRwSets[0].HashedRwSet.HashedWrites, 2)
+	assert.NotContains(t, string(pub), "private")
+
+	// a member gets the preimages, the read-write set marshals the same
+	rws, err = member.GetRWSet("txid1", pub)
+	assert.NoError(t, err)
+	assert.NoError(t, rws.AppendPrivateRWSet(pvt))
+	raw, err := rws.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, pub, raw)
+	raw, err = rws.PrivateBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, pvt, raw)
+	v, err = rws.GetPrivateState(ns, coll, "k2", api.FromIntermediate)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("private"), v)
+	assert.Error(t, rws.SetPrivateState(ns, coll, "k4", []byte("extended")))
+	rws.Done()
+
+	// a non-member gets the hashes only
+	rws, err = nonMember.GetRWSet("txid1", pub)
+	assert.NoError(t, err)
+	raw, err = rws.Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, pub, raw)
+	raw, err = rws.PrivateBytes()
+	assert.NoError(t, err)
+	assert.Nil(t, raw)
+	rws.Done()
+
+	// tampered preimages are rejected
+	other, err := creator.NewRWSet("txid-other")
+	assert.NoError(t, err)
+	assert.NoError(t, other.SetPrivateState(ns, coll, "k2", []byte("tampered")))
+	tampered, err := other.PrivateBytes()
+	assert.NoError(t, err)
+	other.Done()
+	rws, err = creator.GetRWSet("txid-tampered", pub)
+	assert.NoError(t, err)
+	assert.EqualError(t, rws.AppendPrivateRWSet(tampered), "private read-write set of collection [namespace:collection] does not match its hash")
+	rws.Done()
+
+	// once committed, the private data is in the side store of the members
+	assert.NoError(t, creator.CommitTX("txid1", 35, 2))
+	assert.NoError(t, member.CommitTX("txid1", 35, 2))
+	assert.NoError(t, nonMember.CommitTX("txid1", 35, 2))
+	for _, vault := range []*Vault{creator, member} {
+		qe, err := vault.NewQueryExecutor()
+		assert.NoError(t, err)
+		v, err := qe.(*directQueryExecutor).GetPrivateState(ns, coll, "k2")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("private"), v)
+		v, err = qe.(*directQueryExecutor).GetPrivateState(ns, coll, "k3")
+		assert.NoError(t, err)
+		assert.Nil(t, v)
+		qe.Done()
+	}
+	v, _, _, err = nonMemberDB.GetState(privateNamespace(ns, coll), "k2")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	// private reads are hashed and checked at validation time
+	rws, err = member.NewRWSet("txid2")
+	assert.NoError(t, err)
+	v, err = rws.GetPrivateState(ns, coll, "k2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("private"), v)
+	assert.NoError(t, rws.SetPrivateState(ns, coll, "k2", []byte("updated")))
+	pub, err = rws.Bytes()
+	assert.NoError(t, err)
+	assert.NoError(t, rws.IsValid())
+	rws.Done()
+	assert.NoError(t, proto.Unmarshal(pub, txRWSet))
+	rwsIn, err = rwsetutil.TxRwSetFromProtoMsg(txRWSet)
+	assert.NoError(t, err)
+	hashedReads := rwsIn.NsRwSets[0].CollHashedRwSets[0].HashedRwSet.HashedReads
+	assert.Len(t, hashedReads, 1)
+	assert.Equal(t, &kvrwset.Version{BlockNum: 35, TxNum: 2}, hashedReads[0].Version)
+
+	// a private read becomes invalid once the key is updated
+	rws, err = member.NewRWSet("txid3")
+	assert.NoError(t, err)
+	_, err = rws.GetPrivateState(ns, coll, "k2")
+	assert.NoError(t, err)
+	rws.Done()
+	assert.NoError(t, member.CommitTX("txid2", 36, 0))
+	assert.EqualError(t, rws.IsValid(), "invalid private read: vault at version namespace:collection:k2 36:0, read-write set at version 35:2")
+	assert.NoError(t, member.DiscardTx("txid3"))
+}
+
+func TestRangeQuery(t *testing.T) {
+	ns := "namespace"
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	vault := New(ddb, tidstore)
+
+	commit := func(txid string, block uint64, writes ...string) {
+		rws, err := vault.NewRWSet(txid)
+		assert.NoError(t, err)
+		for _, key := range writes {
+			assert.NoError(t, rws.SetState(ns, key, []byte(txid)))
+		}
+		rws.Done()
+		assert.NoError(t, vault.CommitTX(txid, block, 0))
+	}
+	scan := func(txid, startKey, endKey string, n int) (api.RWSet, []string) {
+		rws, err := vault.NewRWSet(txid)
+		assert.NoError(t, err)
+		itr, err := rws.GetStateRangeScanIterator(ns, startKey, endKey)
+		assert.NoError(t, err)
+		var keys []string
+		for n < 0 || len(keys) < n {
+			read, err := itr.Next()
+			assert.NoError(t, err)
+			if read == nil {
+				break
+			}
+			keys = append(keys, read.Key)
+		}
+		itr.Close()
+		return rws, keys
+	}
+	commit("txid0", 1, "k1", "k3", "k5", "k7")
+
+	// the range is recorded in the read-write set and survives marshalling
+	rws, keys := scan("txid1", "k2", "k6", -1)
+	assert.Equal(t, []string{"k3", "k5"}, keys)
+	raw, err := rws.Bytes()
+	assert.NoError(t, err)
+	assert.NoError(t, rws.IsValid())
+	rws.Done()
+	txRWSet := &rwset.TxReadWriteSet{}
+	assert.NoError(t, proto.Unmarshal(raw, txRWSet))
+	rwsIn, err := rwsetutil.TxRwSetFromProtoMsg(txRWSet)
+	assert.NoError(t, err)
+	assert.Len(t, rwsIn.NsRwSets[0].KvRwSet.RangeQueriesInfo, 1)
+	rqi := rwsIn.NsRwSets[0].KvRwSet.RangeQueriesInfo[0]
+	assert.Equal(t, "k2", rqi.StartKey)
+	assert.Equal(t, "k6", rqi.EndKey)
+	assert.True(t, rqi.ItrExhausted)
+	assert.Len(t, rqi.GetRawReads().KvReads, 2)
+	received, err := vault.GetRWSet("txid1", raw)
+	assert.NoError(t, err)
+	assert.NoError(t, received.Equals(rws))
+	received.Done()
+	assert.NoError(t, vault.DiscardTx("txid1"))
+
+	// a range not fully scanned ends with the last key returned
+	partial, keys := scan("txid2", "k2", "k6", 1)
+	assert.Equal(t, []string{"k3"}, keys)
+	partial.Done()
+
+	// iterators still open when the simulation ends are recorded too
+	open, err := vault.NewRWSet("txid3")
+	assert.NoError(t, err)
+	itr, err := open.GetStateRangeScanIterator(ns, "", "")
+	assert.NoError(t, err)
+	_, err = itr.Next()
+	assert.NoError(t, err)
+	open.Done()
+	raw, err = open.Bytes()
+	assert.NoError(t, err)
+	assert.NoError(t, proto.Unmarshal(raw, txRWSet))
+	rwsIn, err = rwsetutil.TxRwSetFromProtoMsg(txRWSet)
+	assert.NoError(t, err)
+	assert.Len(t, rwsIn.NsRwSets[0].KvRwSet.RangeQueriesInfo, 1)
+
+	// a phantom, a key added to the range, invalidates the scan of the whole range only
+	rws, _ = scan("txid1", "k2", "k6", -1)
+	rws.Done()
+	commit("txid4", 2, "k4")
+	assert.EqualError(t, rws.IsValid(), "invalid range query: the results of [namespace:k2,k6] changed")
+	assert.NoError(t, partial.IsValid())
+	assert.NoError(t, vault.DiscardTx("txid1"))
+	assert.NoError(t, vault.DiscardTx("txid3"))
+
+	// so does the update of a key in the range
+	assert.NoError(t, partial.IsValid())
+	commit("txid5", 3, "k3")
+	assert.EqualError(t, partial.IsValid(), "invalid range query: the results of [namespace:k2,k3] changed")
+	assert.NoError(t, vault.DiscardTx("txid2"))
+
+	// large ranges carry the merkle summary of the results
+	defer func(maxDegree uint32) { RangeQueryMaxDegree = maxDegree }(RangeQueryMaxDegree)
+	RangeQueryMaxDegree = 2
+	rws, keys = scan("txid6", "", "", -1)
+	assert.Len(t, keys, 5)
+	raw, err = rws.Bytes()
+	assert.NoError(t, err)
+	rws.Done()
+	assert.NoError(t, proto.Unmarshal(raw, txRWSet))
+	rwsIn, err = rwsetutil.TxRwSetFromProtoMsg(txRWSet)
+	assert.NoError(t, err)
+	rqi = rwsIn.NsRwSets[0].KvRwSet.RangeQueriesInfo[0]
+	assert.Nil(t, rqi.GetRawReads())
+	assert.NotNil(t, rqi.GetReadsMerkleHashes())
+	assert.NoError(t, rws.IsValid())
+	commit("txid7", 4, "k7")
+	assert.EqualError(t, rws.IsValid(), "invalid range query: the results of [namespace:,] changed")
+	assert.NoError(t, vault.DiscardTx("txid6"))
+
+	// a range that cannot be recorded fails the read-write set
+	failed, err := vault.NewRWSet("txid8")
+	assert.NoError(t, err)
+	itr, err = failed.GetStateRangeScanIterator(ns, "", "")
+	assert.NoError(t, err)
+	_, err = itr.Next()
+	assert.NoError(t, err)
+	itr.(*rangeIterator).onClose = func(string, *kvrwset.RangeQueryInfo) error { return errors.New("no space left") }
+	itr.Close()
+	_, err = failed.Bytes()
+	assert.EqualError(t, err, "invalid read-write set [txid8]: failed recording range query [namespace:,k1]: no space left")
+	assert.Error(t, failed.IsValid())
+	failed.Done()
+	_, err = failed.Bytes()
+	assert.Error(t, err)
+}
+
+func TestHistory(t *testing.T) {
+	ns := "namespace"
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	vault := New(ddb, tidstore)
+
+	_, err = vault.GetHistoryForKey(ns, "k1")
+	assert.EqualError(t, err, "history is not enabled")
+	vault.EnableHistory()
+
+	commit := func(txid string, block uint64, writes map[string]string) {
+		rws, err := vault.NewRWSet(txid)
+		assert.NoError(t, err)
+		for key, value := range writes {
+			if len(value) == 0 {
+				assert.NoError(t, rws.DeleteState(ns, key))
+				continue
+			}
+			assert.NoError(t, rws.SetState(ns, key, []byte(value)))
+		}
+		rws.Done()
+		assert.NoError(t, vault.CommitTX(txid, block, 0))
+	}
+	commit("txid1", 1, map[string]string{"k1": "v1", "k2": "a"})
+	commit("txid2", 2, map[string]string{"k1": "v2", "k2": ""})
+	commit("txid3", 3, map[string]string{"k1": "v3"})
+	assert.Equal(t, uint64(3), vault.LastBlock())
+
+	// the last block survives a restart
+	reopened := New(ddb, tidstore)
+	assert.Equal(t, uint64(0), reopened.LastBlock())
+	assert.NoError(t, reopened.LoadLastBlock())
+	assert.Equal(t, uint64(3), reopened.LastBlock())
+
+	history, err := vault.GetHistoryForKey(ns, "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.HistoryEntry{
+		{Key: "k1", Value: []byte("v1"), Block: 1, TxID: "txid1"},
+		{Key: "k1", Value: []byte("v2"), Block: 2, TxID: "txid2"},
+		{Key: "k1", Value: []byte("v3"), Block: 3, TxID: "txid3"},
+	}, history)
+	history, err = vault.GetHistoryForKey(ns, "k2")
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.HistoryEntry{
+		{Key: "k2", Value: []byte("a"), Block: 1, TxID: "txid1"},
+		{Key: "k2", Block: 2, TxID: "txid2", Deleted: true},
+	}, history)
+
+	state, err := vault.GetNamespaceAsOf(ns, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.HistoryEntry{
+		{Key: "k1", Value: []byte("v1"), Block: 1, TxID: "txid1"},
+		{Key: "k2", Value: []byte("a"), Block: 1, TxID: "txid1"},
+	}, state)
+	state, err = vault.GetNamespaceAsOf(ns, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.HistoryEntry{{Key: "k1", Value: []byte("v2"), Block: 2, TxID: "txid2"}}, state)
+
+	// the state as of block 2 survives pruning below block 3
+	pruned, err := vault.PruneHistory(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pruned)
+	state2, err := vault.GetNamespaceAsOf(ns, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, state, state2)
+	history, err = vault.GetHistoryForKey(ns, "k1")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	history, err = vault.GetHistoryForKey(ns, "k2")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestSnapshot(t *testing.T) {
+	ns := "namespace"
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	vault := New(ddb, tidstore)
+
+	rws, err := vault.NewRWSet("txid1")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetState(ns, "k1", []byte("v1")))
+	assert.NoError(t, rws.SetStateMetadata(ns, "k1", map[string][]byte{"m": []byte("v")}))
+	rws.Done()
+	assert.NoError(t, vault.CommitTX("txid1", 7, 2))
+	// private data and local transactions, left out unless explicitly included
+	assert.NoError(t, ddb.BeginUpdate())
+	assert.NoError(t, ddb.SetState(privateNamespace(ns, "coll"), "k2", []byte("private"), 7, 2))
+	assert.NoError(t, ddb.SetState(localNamespace, localTxPrefix+"ltx", []byte("local"), 0, 0))
+	assert.NoError(t, ddb.Commit())
+
+	var buf bytes.Buffer
+	info, err := vault.ExportSnapshot(&buf, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), info.LastBlock)
+	raw := buf.Bytes()
+	assert.NotContains(t, string(raw), privateNamespace(ns, "coll"))
+	assert.NotContains(t, string(raw), localNamespace)
+
+	var full bytes.Buffer
+	fullInfo, err := vault.ExportSnapshot(&full, true)
+	assert.NoError(t, err)
+	assert.Equal(t, info.Entries+2, fullInfo.Entries)
+	assert.Contains(t, full.String(), privateNamespace(ns, "coll"))
+	assert.Contains(t, full.String(), localNamespace)
+
+	verified, err := VerifySnapshot(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, info, verified)
+
+	// a corrupted snapshot is rejected
+	corrupted := bytes.Replace(raw, []byte(base64.StdEncoding.EncodeToString([]byte("v1"))), []byte(base64.StdEncoding.EncodeToString([]byte("v2"))), 1)
+	_, err = VerifySnapshot(bytes.NewReader(corrupted))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid snapshot: expected [")
+	_, err = VerifySnapshot(bytes.NewReader(raw[:len(raw)-10]))
+	assert.Error(t, err)
+
+	// the imported vault has the same state, the same txidstore and resumes from the snapshot height
+	ddb2, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	imported, err := ImportSnapshot(ddb2, bytes.NewReader(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, info, imported)
+	_, err = ImportSnapshot(ddb2, bytes.NewReader(raw))
+	assert.EqualError(t, err, "the vault is not empty, found [$$s:height]")
+
+	tidstore2, err := txidstore.NewTXIDStore(db.Unversioned(ddb2))
+	assert.NoError(t, err)
+	vault2 := New(ddb2, tidstore2)
+	height, found, err := vault2.SnapshotHeight()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(7), height)
+	assert.NoError(t, vault2.LoadLastBlock())
+	assert.Equal(t, uint64(7), vault2.LastBlock())
+	code, err := vault2.Status("txid1")
+	assert.NoError(t, err)
+	assert.Equal(t, api.Valid, code)
+	lastTxID, err := tidstore2.GetLastTxID()
+	assert.NoError(t, err)
+	assert.Equal(t, "txid1", lastTxID)
+
+	qe, err := vault2.NewQueryExecutor()
+	assert.NoError(t, err)
+	v, err := qe.GetState(ns, "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), v)
+	m, block, txnum, err := qe.GetStateMetadata(ns, "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"m": []byte("v")}, m)
+	assert.Equal(t, uint64(7), block)
+	assert.Equal(t, uint64(2), txnum)
+	qe.Done()
+}
+
+func TestLocalCommit(t *testing.T) {
+	ns := "local"
+
+	ddb, err := db.OpenVersioned("memory", "")
+	assert.NoError(t, err)
+	tidstore, err := txidstore.NewTXIDStore(db.Unversioned(ddb))
+	assert.NoError(t, err)
+	vault := New(ddb, tidstore)
+
+	newRWSet := func(txid string, writes map[string]string) *Interceptor {
+		rws, err := vault.NewRWSet(txid)
+		assert.NoError(t, err)
+		_, err = rws.GetState(ns, "k1")
+		assert.NoError(t, err)
+		for key, value := range writes {
+			assert.NoError(t, rws.SetState(ns, key, []byte(value)))
+		}
+		rws.Done()
+		return rws
+	}
+
+	newRWSet("txid0", map[string]string{"k1": "v0"})
+	_, err = vault.CommitLocalTX("txid0")
+	assert.EqualError(t, err, "local transaction [txid0] rejected: no local namespaces configured")
+	vault.EnableLocalNamespaces(ns)
+
+	// txid1 and txid2 read k1 concurrently, the second one to commit is rejected
+	newRWSet("txid1", map[string]string{"k1": "v1", "k2": "v1"})
+	newRWSet("txid2", map[string]string{"k1": "v2"})
+	seq, err := vault.CommitLocalTX("txid1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), seq)
+	_, err = vault.CommitLocalTX("txid2")
+	assert.EqualError(t, err, "local transaction [txid2] rejected: invalid read: vault at version local:k1 0:2, read-write set at version 0:0")
+
+	for txid, expected := range map[string]api.ValidationCode{"txid0": api.Invalid, "txid1": api.Valid, "txid2": api.Invalid} {
+		vc, err := vault.Status(txid)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, vc, txid)
+	}
+	v, block, txnum, err := ddb.GetState(ns, "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), v)
+	assert.Equal(t, uint64(0), block)
+	assert.Equal(t, uint64(2), txnum)
+
+	// only local namespaces can be written
+	rws, err := vault.NewRWSet("txid4")
+	assert.NoError(t, err)
+	assert.NoError(t, rws.SetState("fabric", "k1", []byte("v")))
+	rws.Done()
+	_, err = vault.CommitLocalTX("txid4")
+	assert.EqualError(t, err, "local transaction [txid4] rejected: namespace [fabric] is not local")
+
+	log, err := vault.GetLocalLog(2)
+	assert.NoError(t, err)
+	assert.Len(t, log, 3)
+	for _, e := range log {
+		assert.False(t, e.Timestamp.IsZero())
+		e.Timestamp = time.Time{}
+	}
+	assert.Equal(t, []*api.LocalTransaction{
+		{Seq: 2, TxID: "txid1", Valid: true, Writes: []*api.LocalWrite{{Namespace: ns, Key: "k1"}, {Namespace: ns, Key: "k2"}}},
+		{Seq: 3, TxID: "txid2", Reason: "invalid read: vault at version local:k1 0:2, read-write set at version 0:0"},
+		{Seq: 4, TxID: "txid4", Reason: "namespace [fabric] is not local"},
+	}, log)
+	assert.Equal(t, uint64(0), vault.LastBlock())
+}
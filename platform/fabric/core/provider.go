@@ -7,6 +7,7 @@ package core
 
 import (
 	"reflect"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -19,8 +20,14 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server"
 )
 
+// reloadable is implemented by the fabric network services that can reload their configuration
+type reloadable interface {
+	Reload() error
+}
+
 type fnsProvider struct {
 	sp       view.ServiceProvider
+	mutex    sync.Mutex
 	networks map[string]api.FabricNetworkService
 }
 
@@ -37,6 +44,9 @@ func (m *fnsProvider) FabricNetworkService(network string) (api.FabricNetworkSer
 		network = "default"
 	}
 
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	net, ok := m.networks[network]
 	if !ok {
 		var err error
@@ -49,6 +59,36 @@ func (m *fnsProvider) FabricNetworkService(network string) (api.FabricNetworkSer
 	return net, nil
 }
 
+// Close closes all the fabric network services created so far
+func (m *fnsProvider) Close() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for name, network := range m.networks {
+		if err := network.Close(); err != nil {
+			return errors.WithMessagef(err, "failed closing fabric network service [%s]", name)
+		}
+	}
+	return nil
+}
+
+// Reload reloads the configuration of the fabric network services created so far
+func (m *fnsProvider) Reload() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for name, network := range m.networks {
+		r, ok := network.(reloadable)
+		if !ok {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			return errors.WithMessagef(err, "failed reloading fabric network service [%s]", name)
+		}
+	}
+	return nil
+}
+
 func (m *fnsProvider) newFNS(network string) (api.FabricNetworkService, error) {
 	config := generic.NewConfig(view.GetConfigService(m.sp))
 	sigService := generic.NewSigService(m.sp)
@@ -79,11 +79,20 @@ func (n *NetworkService) SigService() *SigService {
 }
 
 func GetFabricNetworkService(sp view2.ServiceProvider, id string) *NetworkService {
-	fns, err := core.GetFabricNetworkServiceProvider(sp).FabricNetworkService(id)
+	fns, err := FabricNetworkService(sp, id)
 	if err != nil {
 		panic(err)
 	}
-	return &NetworkService{sp: sp, fns: fns}
+	return fns
+}
+
+// FabricNetworkService returns the fns for the passed id, or an error if the network is not known
+func FabricNetworkService(sp view2.ServiceProvider, id string) (*NetworkService, error) {
+	fns, err := core.GetFabricNetworkServiceProvider(sp).FabricNetworkService(id)
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkService{sp: sp, fns: fns}, nil
 }
 
 func GetDefaultNetwork(sp view2.ServiceProvider) *NetworkService {
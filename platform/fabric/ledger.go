@@ -6,29 +6,165 @@ SPDX-License-Identifier: Apache-2.0
 package fabric
 
 import (
+	"time"
+
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
 )
 
+// ChainInfo describes the chain of a channel
+type ChainInfo struct {
+	// Height is the number of blocks in the chain
+	Height uint64
+	// CurrentBlockHash is the hash of the last block of the chain
+	CurrentBlockHash []byte
+	// PreviousBlockHash is the hash of the block preceding the last one
+	PreviousBlockHash []byte
+}
+
 type Block struct {
 	b api.Block
 }
 
 func (b *Block) DataAt(i int) []byte {
-	return b.DataAt(i)
+	return b.b.DataAt(i)
+}
+
+// Number returns the number of the block
+func (b *Block) Number() uint64 {
+	return b.b.Number()
+}
+
+// PreviousHash returns the hash of the previous block
+func (b *Block) PreviousHash() []byte {
+	return b.b.PreviousHash()
+}
+
+// DataHash returns the hash of the data of the block
+func (b *Block) DataHash() []byte {
+	return b.b.DataHash()
+}
+
+// Hash returns the hash of the header of the block
+func (b *Block) Hash() []byte {
+	return b.b.Hash()
+}
+
+// NumTransactions returns the number of transactions in the block
+func (b *Block) NumTransactions() int {
+	return b.b.NumTransactions()
+}
+
+// TransactionAt decodes the transaction at the passed position in the block
+func (b *Block) TransactionAt(i int) (*ProcessedTransaction, error) {
+	pt, err := b.b.TransactionAt(i)
+	if err != nil {
+		return nil, err
+	}
+	return &ProcessedTransaction{pt: pt}, nil
+}
+
+// Transactions decodes all the transactions of the block, in order
+func (b *Block) Transactions() ([]*ProcessedTransaction, error) {
+	var res []*ProcessedTransaction
+	for i := 0; i < b.NumTransactions(); i++ {
+		pt, err := b.TransactionAt(i)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pt)
+	}
+	return res, nil
+}
+
+// BlockIterator iterates over the blocks of a channel
+type BlockIterator struct {
+	it api.BlockIterator
+}
+
+// Next returns the next block, nil if there are no more blocks
+func (b *BlockIterator) Next() (*Block, error) {
+	block, err := b.it.Next()
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return &Block{b: block}, nil
+}
+
+// Close releases the resources held by the iterator
+func (b *BlockIterator) Close() {
+	b.it.Close()
 }
 
 type ProcessedTransaction struct {
 	pt api.ProcessedTransaction
 }
 
+// TxID returns the transaction id
+func (pt *ProcessedTransaction) TxID() string {
+	return pt.pt.TxID()
+}
+
+// Type returns the type of the transaction, as in Fabric's common.HeaderType
+func (pt *ProcessedTransaction) Type() int32 {
+	return pt.pt.Type()
+}
+
+// Timestamp returns the time the transaction was created
+func (pt *ProcessedTransaction) Timestamp() time.Time {
+	return pt.pt.Timestamp()
+}
+
+// Creator returns the serialized identity of the creator of the transaction
+func (pt *ProcessedTransaction) Creator() []byte {
+	return pt.pt.Creator()
+}
+
+// Chaincode returns the name and the version of the invoked chaincode, empty for non-endorser transactions
+func (pt *ProcessedTransaction) Chaincode() (string, string) {
+	return pt.pt.Chaincode()
+}
+
+// Function returns the invoked function and its arguments, empty for non-endorser transactions
+func (pt *ProcessedTransaction) Function() (string, []string) {
+	return pt.pt.Function()
+}
+
 func (pt *ProcessedTransaction) Results() []byte {
 	return pt.pt.Results()
 }
 
+// ValidationCode returns the validation code assigned by the committing peer, as in Fabric's peer.TxValidationCode
+func (pt *ProcessedTransaction) ValidationCode() int32 {
+	return pt.pt.ValidationCode()
+}
+
+// IsValid returns true if the transaction has been committed as valid
+func (pt *ProcessedTransaction) IsValid() bool {
+	return pt.pt.IsValid()
+}
+
+// Envelope returns the marshalled envelope of the transaction
+func (pt *ProcessedTransaction) Envelope() []byte {
+	return pt.pt.Envelope()
+}
+
 type Ledger struct {
 	ch *Channel
 }
 
+// GetChainInfo returns the height of the chain and the hashes of its last blocks
+func (l *Ledger) GetChainInfo() (*ChainInfo, error) {
+	info, err := l.ch.ch.GetChainInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &ChainInfo{
+		Height:            info.Height,
+		CurrentBlockHash:  info.CurrentBlockHash,
+		PreviousBlockHash: info.PreviousBlockHash,
+	}, nil
+}
+
 func (l *Ledger) GetBlockNumberByTxID(txID string) (uint64, error) {
 	return l.ch.ch.GetBlockNumberByTxID(txID)
 }
@@ -48,3 +184,23 @@ func (l *Ledger) GetBlockByNumber(number uint64) (*Block, error) {
 	}
 	return &Block{b: b}, nil
 }
+
+// GetBlocks returns an iterator over the blocks from start to stop, included.
+// The iterator fails when it reaches a block that has not been committed yet.
+func (l *Ledger) GetBlocks(start, stop uint64) (*BlockIterator, error) {
+	it, err := l.ch.ch.GetBlocks(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockIterator{it: it}, nil
+}
+
+// NewBlockStream returns an iterator over the blocks from start on, waiting for the new ones to be committed.
+// The caller must close the iterator when done.
+func (l *Ledger) NewBlockStream(start uint64) (*BlockIterator, error) {
+	it, err := l.ch.ch.NewBlockStream(start)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockIterator{it: it}, nil
+}
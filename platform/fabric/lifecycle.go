@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package fabric
+
+import (
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// ChaincodeDefinition is the definition of a chaincode the organizations approve and then commit to a channel
+type ChaincodeDefinition struct {
+	Name     string
+	Version  string
+	Sequence int64
+	// EndorsementPolicy is the signature policy of the chaincode, e.g. "AND('Org1MSP.member','Org2MSP.member')"
+	EndorsementPolicy string
+	// ChannelConfigPolicy is the channel configuration policy the chaincode refers to, instead of a signature policy,
+	// e.g. "/Channel/Application/Endorsement". If neither is set, the default endorsement policy of the channel applies.
+	ChannelConfigPolicy string
+	EndorsementPlugin   string
+	ValidationPlugin    string
+	// Collections is the marshalled CollectionConfigPackage of the chaincode, if any
+	Collections  []byte
+	InitRequired bool
+}
+
+func (d *ChaincodeDefinition) toAPI() *api.ChaincodeDefinition {
+	if d == nil {
+		return nil
+	}
+	return &api.ChaincodeDefinition{
+		Name:                d.Name,
+		Version:             d.Version,
+		Sequence:            d.Sequence,
+		EndorsementPolicy:   d.EndorsementPolicy,
+		ChannelConfigPolicy: d.ChannelConfigPolicy,
+		EndorsementPlugin:   d.EndorsementPlugin,
+		ValidationPlugin:    d.ValidationPlugin,
+		Collections:         d.Collections,
+		InitRequired:        d.InitRequired,
+	}
+}
+
+// InstalledChaincode is a chaincode package installed on the peers
+type InstalledChaincode struct {
+	PackageID string
+	Label     string
+}
+
+// Lifecycle manages the chaincodes of a channel through the _lifecycle system chaincode.
+// The peers of the network are expected to belong to the organization of the node.
+type Lifecycle struct {
+	l api.Lifecycle
+}
+
+// Install installs the passed chaincode package on the peers of the network, it succeeds if it is already installed
+func (l *Lifecycle) Install(pkg []byte) (*InstalledChaincode, error) {
+	installed, err := l.l.Install(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return &InstalledChaincode{PackageID: installed.PackageID, Label: installed.Label}, nil
+}
+
+// QueryInstalled returns the chaincode packages installed on the first peer of the network
+func (l *Lifecycle) QueryInstalled() ([]*InstalledChaincode, error) {
+	installed, err := l.l.QueryInstalled()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*InstalledChaincode, len(installed))
+	for i, cc := range installed {
+		res[i] = &InstalledChaincode{PackageID: cc.PackageID, Label: cc.Label}
+	}
+	return res, nil
+}
+
+// Approve approves the passed definition for the organization of the node.
+// The package ID refers to the package the peers run the chaincode with, it can be empty if they don't.
+func (l *Lifecycle) Approve(definition *ChaincodeDefinition, packageID string) error {
+	return l.l.Approve(definition.toAPI(), packageID)
+}
+
+// CheckCommitReadiness returns, for each organization of the channel, whether it approved the passed definition
+func (l *Lifecycle) CheckCommitReadiness(definition *ChaincodeDefinition) (map[string]bool, error) {
+	return l.l.CheckCommitReadiness(definition.toAPI())
+}
+
+// Commit commits the passed definition to the channel. The proposal is endorsed by the passed endorsers,
+// or by endorsers discovered to satisfy the lifecycle endorsement policy of the channel if none is passed.
+func (l *Lifecycle) Commit(definition *ChaincodeDefinition, endorsers ...view.Identity) error {
+	return l.l.Commit(definition.toAPI(), endorsers...)
+}
+
+// QueryCommitted returns the definition of the passed chaincode committed to the channel, and the organizations
+// that approved it. The policies of the definition are not reported.
+func (l *Lifecycle) QueryCommitted(name string) (*ChaincodeDefinition, map[string]bool, error) {
+	d, approvals, err := l.l.QueryCommitted(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ChaincodeDefinition{
+		Name:              d.Name,
+		Version:           d.Version,
+		Sequence:          d.Sequence,
+		EndorsementPlugin: d.EndorsementPlugin,
+		ValidationPlugin:  d.ValidationPlugin,
+		Collections:       d.Collections,
+		InitRequired:      d.InitRequired,
+	}, approvals, nil
+}
+
+// WithSignerIdentity sets the identity the lifecycle operations are signed with,
+// the default identity of the node is used otherwise
+func (l *Lifecycle) WithSignerIdentity(id view.Identity) *Lifecycle {
+	l.l.WithSignerIdentity(id)
+	return l
+}
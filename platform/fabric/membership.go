@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package fabric
 
 import (
+	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/api"
@@ -89,6 +90,12 @@ func (s *LocalMembership) Refresh() error {
 	return s.network.LocalMembership().Refresh()
 }
 
+// UpdateRevocationInformation moves the idemix MSPs to the epoch of the passed credential revocation information.
+// From then on, idemix identities and signatures of the previous epochs are rejected.
+func (s *LocalMembership) UpdateRevocationInformation(cri []byte) error {
+	return s.network.LocalMembership().UpdateRevocationInformation(cri)
+}
+
 // Verifier is an interface which wraps the Verify method.
 type Verifier interface {
 	// Verify verifies the signature over the passed message.
@@ -115,6 +122,31 @@ func (c *MSPManager) GetMSPIdentifier(sid []byte) (string, error) {
 	return id.GetMSPIdentifier(), nil
 }
 
+// SatisfiesPrincipal returns true if the passed identity satisfies the passed principal, a role in an organization
+// for instance, as defined by the current channel configuration.
+// An error is returned if the MSPs of the channel cannot deserialize the identity.
+func (c *MSPManager) SatisfiesPrincipal(identity view.Identity, principal *msp.MSPPrincipal) (bool, error) {
+	id, err := c.ch.MSPManager().DeserializeIdentity(identity)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed deserializing identity [%s]", identity.UniqueID())
+	}
+	return id.SatisfiesPrincipal(principal) == nil, nil
+}
+
 func (c *MSPManager) GetVerifier(identity view.Identity) (Verifier, error) {
 	return c.ch.GetVerifier(identity)
 }
+
+// EvaluateEndorsementPolicy checks that the endorsements carried by the passed proposal responses
+// satisfy the passed serialized SignaturePolicyEnvelope.
+func (c *MSPManager) EvaluateEndorsementPolicy(policy []byte, responses []*ProposalResponse) error {
+	var signatures []*api.SignedData
+	for _, response := range responses {
+		signatures = append(signatures, &api.SignedData{
+			Data:      append(append([]byte{}, response.Payload()...), response.Endorser()...),
+			Identity:  response.Endorser(),
+			Signature: response.EndorserSignature(),
+		})
+	}
+	return c.ch.EvaluatePolicy(policy, signatures)
+}
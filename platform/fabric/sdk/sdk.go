@@ -11,16 +11,22 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/id"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
 
 	fabric2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/admin"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/crypto"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
 	endpoint2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endpoint"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/state"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/state/impl"
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/assert"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/tracker"
 )
 
@@ -32,6 +38,11 @@ type Registry interface {
 	RegisterService(service interface{}) error
 }
 
+// reloadable is implemented by the fabric network service providers that can reload their configuration
+type reloadable interface {
+	Reload() error
+}
+
 type p struct {
 	registry Registry
 }
@@ -75,11 +86,17 @@ func (p *p) Install() error {
 	}
 	assert.NoError(p.registry.RegisterService(idProvider))
 
+	// endorser metrics, shared by the endorser views
+	assert.NoError(p.registry.RegisterService(endorser.NewMetrics(operations.GetMetricsProvider(p.registry))))
+
 	// TODO: remove this
 	assert.NoError(p.registry.RegisterService(tracker.NewTracker()))
 	// TODO: change this
 	assert.NoError(p.registry.RegisterService(impl.NewWorldStateService(p.registry)))
 
+	// admin views
+	assert.NoError(admin.RegisterViews(api.GetRegistry(p.registry)), "failed registering fabric admin views")
+
 	return nil
 }
 
@@ -96,3 +113,58 @@ func (p *p) Start(ctx context.Context) error {
 
 	return nil
 }
+
+// Reload reloads the TLS root certificates, the orderer and peer endpoints, and the local MSPs
+// of the fabric network services
+func (p *p) Reload() error {
+	if !view2.GetConfigService(p.registry).GetBool("fabric.enabled") {
+		return nil
+	}
+	r, ok := core.GetFabricNetworkServiceProvider(p.registry).(reloadable)
+	if !ok {
+		return errors.New("fabric network service provider cannot reload")
+	}
+	return r.Reload()
+}
+
+// WatchedPaths returns the TLS material used to connect to fabric and the local MSP folders
+func (p *p) WatchedPaths() []string {
+	configService := view2.GetConfigService(p.registry)
+	if !configService.GetBool("fabric.enabled") {
+		return nil
+	}
+
+	paths := []string{
+		configService.GetPath("fabric.tls.rootCertFile"),
+		configService.GetPath("fabric.tls.clientKey.file"),
+		configService.GetPath("fabric.tls.clientCert.file"),
+		configService.GetPath("fabric.mspConfigPath"),
+	}
+	var msps []*msp.Configuration
+	if err := configService.UnmarshalKey("fabric.msps", &msps); err == nil {
+		for _, m := range msps {
+			paths = append(paths, configService.TranslatePath(m.Path))
+		}
+	}
+	var endpoints []*grpc.ConnectionConfig
+	if err := configService.UnmarshalKey("fabric.orderers", &endpoints); err == nil {
+		for _, e := range endpoints {
+			paths = append(paths, e.TLSRootCertFile)
+		}
+	}
+	endpoints = nil
+	if err := configService.UnmarshalKey("fabric.peers", &endpoints); err == nil {
+		for _, e := range endpoints {
+			paths = append(paths, e.TLSRootCertFile)
+		}
+	}
+	return paths
+}
+
+// Stop closes the fabric network services, stopping the delivery of blocks and closing the vaults
+func (p *p) Stop() error {
+	if !view2.GetConfigService(p.registry).GetBool("fabric.enabled") {
+		return nil
+	}
+	return core.GetFabricNetworkServiceProvider(p.registry).Close()
+}
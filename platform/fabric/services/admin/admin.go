@@ -0,0 +1,15 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package admin contains the admin views of the fabric platform.
+// As any admin view, they can be called only by the admin identities of the node.
+package admin
+
+import (
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+)
+
+var logger = flogging.MustGetLogger("fabric-sdk.admin")
@@ -0,0 +1,304 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/admin"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const (
+	// VaultTxViewID is the identifier of the view that returns the status and the RW set of a transaction
+	VaultTxViewID = admin.Prefix + "vault.tx"
+	// VaultExportViewID is the identifier of the view that returns the content of the vault for the given namespaces
+	VaultExportViewID = admin.Prefix + "vault.export"
+	// VaultSnapshotViewID is the identifier of the view that writes a snapshot of the vault to a file of the node
+	VaultSnapshotViewID = admin.Prefix + "vault.snapshot"
+)
+
+// RegisterViews registers the factories of the admin views of the fabric platform
+func RegisterViews(r admin.Registry) error {
+	if err := r.RegisterFactory(VaultTxViewID, &VaultTxViewFactory{}); err != nil {
+		return err
+	}
+	if err := r.RegisterFactory(VaultExportViewID, &VaultExportViewFactory{}); err != nil {
+		return err
+	}
+	return r.RegisterFactory(VaultSnapshotViewID, &VaultSnapshotViewFactory{})
+}
+
+// VaultTxRequest identifies a transaction in the vault of a channel.
+// Empty Network and Channel select the defaults.
+type VaultTxRequest struct {
+	Network string
+	Channel string
+	TxID    string
+}
+
+// KeyValue is an entry of a RW set
+type KeyValue struct {
+	Key   string
+	Value []byte `json:",omitempty"`
+}
+
+// NamespaceRWSet is the portion of a RW set that refers to a namespace
+type NamespaceRWSet struct {
+	Namespace string
+	Reads     []KeyValue
+	Writes    []KeyValue
+}
+
+// VaultTx describes a transaction known to the vault
+type VaultTx struct {
+	TxID         string
+	Status       string
+	Dependencies []string `json:",omitempty"`
+	// RWSet is available only if the transaction can be retrieved from the ledger
+	RWSet []NamespaceRWSet `json:",omitempty"`
+}
+
+type VaultTxView struct {
+	*VaultTxRequest
+}
+
+func (v *VaultTxView) Call(context view.Context) (interface{}, error) {
+	ch, err := getChannel(context, v.Network, v.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	code, deps, err := ch.Vault().Status(v.TxID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting status of [%s]", v.TxID)
+	}
+	res := &VaultTx{TxID: v.TxID, Status: validationCodeString(code), Dependencies: deps}
+
+	pt, err := ch.Ledger().GetTransactionByID(v.TxID)
+	if err != nil {
+		logger.Debugf("transaction [%s] not found in the ledger [%s], skipping rw set", v.TxID, err)
+		return res, nil
+	}
+	rws, err := ch.Vault().GetEphemeralRWSet(pt.Results())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling rw set of [%s]", v.TxID)
+	}
+	defer rws.Done()
+	for _, ns := range rws.Namespaces() {
+		nsRWSet := NamespaceRWSet{Namespace: ns}
+		for i := 0; i < rws.NumReads(ns); i++ {
+			key, err := rws.GetReadKeyAt(ns, i)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed getting read [%s:%d] of [%s]", ns, i, v.TxID)
+			}
+			nsRWSet.Reads = append(nsRWSet.Reads, KeyValue{Key: key})
+		}
+		for i := 0; i < rws.NumWrites(ns); i++ {
+			key, value, err := rws.GetWriteAt(ns, i)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed getting write [%s:%d] of [%s]", ns, i, v.TxID)
+			}
+			nsRWSet.Writes = append(nsRWSet.Writes, KeyValue{Key: key, Value: value})
+		}
+		res.RWSet = append(res.RWSet, nsRWSet)
+	}
+	return res, nil
+}
+
+type VaultTxViewFactory struct{}
+
+func (v *VaultTxViewFactory) NewView(in []byte) (view.View, error) {
+	f := &VaultTxView{VaultTxRequest: &VaultTxRequest{}}
+	if err := json.Unmarshal(in, f.VaultTxRequest); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling input")
+	}
+	if len(f.TxID) == 0 {
+		return nil, errors.New("transaction id not specified")
+	}
+	return f, nil
+}
+
+// VaultExportRequest selects the namespaces of the vault of a channel to export.
+// Empty Network and Channel select the defaults.
+type VaultExportRequest struct {
+	Network    string
+	Channel    string
+	Namespaces []string
+}
+
+// VaultEntry is a key stored in the vault
+type VaultEntry struct {
+	Namespace    string
+	Key          string
+	Value        []byte
+	Block        uint64
+	IndexInBlock int
+}
+
+type VaultExportView struct {
+	*VaultExportRequest
+}
+
+func (v *VaultExportView) Call(context view.Context) (interface{}, error) {
+	ch, err := getChannel(context, v.Network, v.Channel)
+	if err != nil {
+		return nil, err
+	}
+	qe, err := ch.Vault().NewQueryExecutor()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting query executor")
+	}
+	defer qe.Done()
+
+	var res []VaultEntry
+	for _, ns := range v.Namespaces {
+		it, err := qe.GetStateRangeScanIterator(ns, "", "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed scanning namespace [%s]", ns)
+		}
+		for {
+			read, err := it.Next()
+			if err != nil {
+				it.Close()
+				return nil, errors.Wrapf(err, "failed scanning namespace [%s]", ns)
+			}
+			if read == nil {
+				break
+			}
+			res = append(res, VaultEntry{
+				Namespace:    ns,
+				Key:          read.Key,
+				Value:        read.Raw,
+				Block:        read.Block,
+				IndexInBlock: read.IndexInBlock,
+			})
+		}
+		it.Close()
+	}
+	return res, nil
+}
+
+type VaultExportViewFactory struct{}
+
+func (v *VaultExportViewFactory) NewView(in []byte) (view.View, error) {
+	f := &VaultExportView{VaultExportRequest: &VaultExportRequest{}}
+	if err := json.Unmarshal(in, f.VaultExportRequest); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling input")
+	}
+	if len(f.Namespaces) == 0 {
+		return nil, errors.New("no namespace specified")
+	}
+	return f, nil
+}
+
+// VaultSnapshotRequest asks for a snapshot of the vault of a channel to be written to the passed path, on the node.
+// Empty Network and Channel select the defaults.
+type VaultSnapshotRequest struct {
+	Network string
+	Channel string
+	Path    string
+	// IncludePrivate includes the private data of the collections and the local transactions.
+	// The snapshot must then be handed to nodes of the same organization only.
+	IncludePrivate bool
+}
+
+// VaultSnapshot describes the snapshot written by the node
+type VaultSnapshot struct {
+	Path string
+	*fabric.SnapshotInfo
+}
+
+type VaultSnapshotView struct {
+	*VaultSnapshotRequest
+}
+
+func (v *VaultSnapshotView) Call(context view.Context) (interface{}, error) {
+	ch, err := getChannel(context, v.Network, v.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// the snapshot appears at the path only once complete
+	f, err := ioutil.TempFile(filepath.Dir(v.Path), filepath.Base(v.Path)+".*.tmp")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed creating snapshot file in [%s]", filepath.Dir(v.Path))
+	}
+	defer os.Remove(f.Name())
+	info, err := ch.Vault().ExportSnapshot(f, v.IncludePrivate)
+	if err != nil {
+		f.Close()
+		return nil, errors.WithMessage(err, "failed exporting snapshot")
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed closing snapshot file [%s]", f.Name())
+	}
+	if err := os.Rename(f.Name(), v.Path); err != nil {
+		return nil, errors.Wrapf(err, "failed moving snapshot to [%s]", v.Path)
+	}
+	logger.Infof("vault snapshot written to [%s], last block [%d], [%d] entries", v.Path, info.LastBlock, info.Entries)
+	return &VaultSnapshot{Path: v.Path, SnapshotInfo: info}, nil
+}
+
+type VaultSnapshotViewFactory struct{}
+
+func (v *VaultSnapshotViewFactory) NewView(in []byte) (view.View, error) {
+	f := &VaultSnapshotView{VaultSnapshotRequest: &VaultSnapshotRequest{}}
+	if err := json.Unmarshal(in, f.VaultSnapshotRequest); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling input")
+	}
+	if len(f.Path) == 0 {
+		return nil, errors.New("no path specified")
+	}
+	return f, nil
+}
+
+func getChannel(sp view2.ServiceProvider, network, channel string) (*fabric.Channel, error) {
+	fns, err := getNetwork(sp, network)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := fns.Channel(channel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting channel [%s:%s]", network, channel)
+	}
+	return ch, nil
+}
+
+func getNetwork(sp view2.ServiceProvider, network string) (fns *fabric.NetworkService, err error) {
+	// GetFabricNetworkService panics if the network does not exist
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("failed getting fabric network service [%s]: %s", network, r)
+		}
+	}()
+	return fabric.GetFabricNetworkService(sp, network), nil
+}
+
+func validationCodeString(code fabric.ValidationCode) string {
+	switch code {
+	case fabric.Valid:
+		return "Valid"
+	case fabric.Invalid:
+		return "Invalid"
+	case fabric.Busy:
+		return "Busy"
+	case fabric.Unknown:
+		return "Unknown"
+	case fabric.HasDependencies:
+		return "HasDependencies"
+	default:
+		return "Undefined"
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package channelconfig
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+var logger = flogging.MustGetLogger("fabric-sdk.channelconfig")
+
+// ConfigUpdateRequest is the config update the admins of the other organizations are asked to sign
+type ConfigUpdateRequest struct {
+	Network string
+	Channel string
+	// Update is the marshalled ConfigUpdate
+	Update []byte
+}
+
+type collectSignaturesView struct {
+	request *ConfigUpdateRequest
+	parties []view.Identity
+}
+
+// NewCollectSignaturesView returns a view that signs the passed config update with the identity of this node,
+// and then asks the passed parties, the nodes of the admins of other organizations, to sign it too.
+// The view returns the marshalled ConfigSignatures, [][]byte.
+func NewCollectSignaturesView(request *ConfigUpdateRequest, parties ...view.Identity) *collectSignaturesView {
+	return &collectSignaturesView{request: request, parties: parties}
+}
+
+func (c *collectSignaturesView) Call(context view.Context) (interface{}, error) {
+	cm, err := configManager(context, c.request.Network, c.request.Channel)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := cm.SignConfigUpdate(c.request.Update, context.Me())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed signing config update")
+	}
+	signatures := [][]byte{signature}
+	signers := map[string]bool{context.Me().UniqueID(): true}
+
+	raw, err := json.Marshal(c.request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling config update request")
+	}
+	for _, party := range c.parties {
+		if context.IsMe(party) {
+			continue
+		}
+		session, err := context.GetSession(context.Initiator(), party)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed getting session with [%s]", party)
+		}
+		ch := session.Receive()
+		if err := session.Send(raw); err != nil {
+			return nil, errors.Wrapf(err, "failed sending config update to [%s]", party)
+		}
+		var msg *view.Message
+		select {
+		case msg = <-ch:
+		case <-time.After(60 * time.Second):
+			return nil, errors.Errorf("timeout waiting for the signature of [%s]", party)
+		}
+		if msg.Status == view.ERROR {
+			return nil, errors.Errorf("party [%s] did not sign: [%s]", party, string(msg.Payload))
+		}
+		signer, err := cm.VerifyConfigSignature(c.request.Update, msg.Payload)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid config signature from [%s]", party)
+		}
+		if signers[signer.UniqueID()] {
+			return nil, errors.Errorf("party [%s] returned a signature by [%s], already collected", party, signer)
+		}
+		signers[signer.UniqueID()] = true
+		logger.Debugf("config update signed by [%s]", party)
+		signatures = append(signatures, msg.Payload)
+	}
+	return signatures, nil
+}
+
+// ConfigUpdateCheck inspects the config update another node asks to sign, and refuses it by returning an error.
+// The update is the decoded ConfigUpdate of the request: its write set holds what the update changes.
+type ConfigUpdateCheck func(request *ConfigUpdateRequest, update *cb.ConfigUpdate) error
+
+type signResponderView struct {
+	check ConfigUpdateCheck
+}
+
+// NewSignResponderView returns a view that signs, with the identity of this node, the config update
+// another node asks for, once the passed check accepts it. Without a check, every update is refused.
+// The identity of this node must be an admin of its organization for the signature to count.
+func NewSignResponderView(check ConfigUpdateCheck) *signResponderView {
+	return &signResponderView{check: check}
+}
+
+func (s *signResponderView) Call(context view.Context) (interface{}, error) {
+	session := context.Session()
+	var msg *view.Message
+	select {
+	case msg = <-session.Receive():
+	case <-time.After(60 * time.Second):
+		return nil, errors.New("timeout waiting for config update")
+	}
+	if msg.Status == view.ERROR {
+		return nil, errors.New(string(msg.Payload))
+	}
+
+	request := &ConfigUpdateRequest{}
+	if err := json.Unmarshal(msg.Payload, request); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling config update request")
+	}
+	signature, err := s.sign(context, request)
+	if err != nil {
+		if err1 := session.SendError([]byte(err.Error())); err1 != nil {
+			logger.Errorf("failed sending error [%s]: [%s]", err, err1)
+		}
+		return nil, err
+	}
+	if err := session.Send(signature); err != nil {
+		return nil, errors.Wrap(err, "failed sending config signature")
+	}
+	return request, nil
+}
+
+func (s *signResponderView) sign(context view.Context, request *ConfigUpdateRequest) ([]byte, error) {
+	if s.check == nil {
+		return nil, errors.Errorf("config update of [%s] refused: no check set", request.Channel)
+	}
+	cm, err := configManager(context, request.Network, request.Channel)
+	if err != nil {
+		return nil, err
+	}
+	update := &cb.ConfigUpdate{}
+	if err := proto.Unmarshal(request.Update, update); err != nil {
+		return nil, errors.Wrap(err, "invalid config update")
+	}
+	if update.ChannelId != request.Channel {
+		return nil, errors.Errorf("config update for channel [%s], expected [%s]", update.ChannelId, request.Channel)
+	}
+	if err := s.check(request, update); err != nil {
+		return nil, errors.WithMessagef(err, "config update of [%s] refused", request.Channel)
+	}
+	return cm.SignConfigUpdate(request.Update, context.Me())
+}
+
+func configManager(context view.Context, network, channel string) (*fabric.ConfigManager, error) {
+	fns, err := fabric.FabricNetworkService(context, network)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unknown network [%s]", network)
+	}
+	ch, err := fns.Channel(channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", network, channel)
+	}
+	return ch.ConfigManager(), nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package channelconfig
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type updateView struct {
+	network string
+	channel string
+	updated []byte
+	parties []view.Identity
+}
+
+// NewUpdateView returns a view that updates the configuration of the channel to the passed marshalled
+// common.Config. The update is signed by this node and by the passed parties, the nodes of the admins of the
+// organizations the modification policies require, and then submitted by this node.
+// The view returns the new *fabric.ChannelConfig.
+func NewUpdateView(network, channel string, updated []byte, parties ...view.Identity) *updateView {
+	return &updateView{network: network, channel: channel, updated: updated, parties: parties}
+}
+
+func (u *updateView) Call(context view.Context) (interface{}, error) {
+	cm, err := configManager(context, u.network, u.channel)
+	if err != nil {
+		return nil, err
+	}
+	update, err := cm.NewConfigUpdate(u.updated)
+	if err != nil {
+		return nil, err
+	}
+	res, err := context.RunView(NewCollectSignaturesView(&ConfigUpdateRequest{
+		Network: u.network,
+		Channel: u.channel,
+		Update:  update,
+	}, u.parties...))
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.SubmitConfigUpdate(update, res.([][]byte), context.Me()); err != nil {
+		return nil, err
+	}
+	return cm.Config()
+}
+
+// AddOrganization returns the passed marshalled common.Config with the passed organization added
+// to its application group. The organization is a marshalled ConfigGroup, as configtxgen prints it
+// with -printOrg once encoded with configtxlator.
+func AddOrganization(config []byte, name string, org []byte) ([]byte, error) {
+	c := &cb.Config{}
+	if err := proto.Unmarshal(config, c); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+	group := &cb.ConfigGroup{}
+	if err := proto.Unmarshal(org, group); err != nil {
+		return nil, errors.Wrap(err, "invalid organization")
+	}
+	application, ok := c.GetChannelGroup().GetGroups()[channelconfig.ApplicationGroupKey]
+	if !ok {
+		return nil, errors.New("no application group in config")
+	}
+	if _, ok := application.Groups[name]; ok {
+		return nil, errors.Errorf("organization [%s] already in config", name)
+	}
+	if application.Groups == nil {
+		application.Groups = map[string]*cb.ConfigGroup{}
+	}
+	application.Groups[name] = group
+	raw, err := proto.Marshal(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling config")
+	}
+	return raw, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package endorser
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// principalChecker tells whether an identity satisfies a principal, as the MSP manager of a channel does
+type principalChecker interface {
+	SatisfiesPrincipal(identity view.Identity, principal *msp.MSPPrincipal) (bool, error)
+}
+
+// collectionMembers are the principals of the member organizations policy of a private data collection
+type collectionMembers struct {
+	principals []*msp.MSPPrincipal
+}
+
+func newCollectionMembers(policy *common.SignaturePolicyEnvelope) (*collectionMembers, error) {
+	if policy == nil {
+		return nil, errors.New("no signature policy")
+	}
+	if len(policy.Identities) == 0 {
+		return nil, errors.New("no principals in signature policy")
+	}
+	return &collectionMembers{principals: policy.Identities}, nil
+}
+
+// contains returns true if the passed party satisfies one of the principals of the members, roles included.
+// An error is returned if the party cannot be resolved by the passed checker.
+func (m *collectionMembers) contains(party view.Identity, checker principalChecker) (bool, error) {
+	for _, principal := range m.principals {
+		ok, err := checker.SatisfiesPrincipal(party, principal)
+		if err != nil {
+			return false, errors.WithMessagef(err, "failed checking collection membership of [%s]", party)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package endorser
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/x509"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type mspChecker struct {
+	msp.MSP
+}
+
+func (m *mspChecker) SatisfiesPrincipal(identity view.Identity, principal *mspproto.MSPPrincipal) (bool, error) {
+	id, err := m.DeserializeIdentity(identity)
+	if err != nil {
+		return false, err
+	}
+	return id.SatisfiesPrincipal(principal) == nil, nil
+}
+
+func rolePrincipal(t *testing.T, mspID string, role mspproto.MSPRole_MSPRoleType) *mspproto.MSPPrincipal {
+	raw, err := proto.Marshal(&mspproto.MSPRole{MspIdentifier: mspID, Role: role})
+	assert.NoError(t, err)
+	return &mspproto.MSPPrincipal{PrincipalClassification: mspproto.MSPPrincipal_ROLE, Principal: raw}
+}
+
+func TestCollectionMembers(t *testing.T) {
+	m, err := x509.LoadLocalMSPAt("../../core/generic/msp/x509/testdata/msp", "Org1MSP", "bccsp")
+	assert.NoError(t, err)
+	signer, err := m.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	// the party is a member of Org1MSP, not one of its admins
+	party, err := signer.Serialize()
+	assert.NoError(t, err)
+	checker := &mspChecker{MSP: m}
+
+	_, err = newCollectionMembers(nil)
+	assert.Error(t, err)
+
+	members, err := newCollectionMembers(&common.SignaturePolicyEnvelope{
+		Identities: []*mspproto.MSPPrincipal{rolePrincipal(t, "Org1MSP", mspproto.MSPRole_MEMBER)},
+	})
+	assert.NoError(t, err)
+	ok, err := members.contains(party, checker)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the role of the principal must be satisfied, not just the msp id
+	members, err = newCollectionMembers(&common.SignaturePolicyEnvelope{
+		Identities: []*mspproto.MSPPrincipal{rolePrincipal(t, "Org1MSP", mspproto.MSPRole_ADMIN)},
+	})
+	assert.NoError(t, err)
+	ok, err = members.contains(party, checker)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	members, err = newCollectionMembers(&common.SignaturePolicyEnvelope{
+		Identities: []*mspproto.MSPPrincipal{rolePrincipal(t, "Org2MSP", mspproto.MSPRole_MEMBER)},
+	})
+	assert.NoError(t, err)
+	ok, err = members.contains(party, checker)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// parties that cannot be resolved are reported
+	_, err = members.contains(view.Identity("a view identity"), checker)
+	assert.Error(t, err)
+}
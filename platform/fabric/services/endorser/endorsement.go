@@ -25,7 +25,9 @@ type collectEndorsementsView struct {
 	deleteTransient bool
 }
 
-func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error) {
+func (c *collectEndorsementsView) Call(context view.Context) (res interface{}, err error) {
+	defer func(start time.Time) { observe(getMetrics(context).EndorsementDuration, start, err) }(time.Now())
+
 	tracker, err := tracker.GetViewTracker(context)
 	if err != nil {
 		return nil, err
@@ -34,7 +36,7 @@ func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error
 
 	signService := c.tx.FabricNetworkService().SigService()
 
-	res, err := c.tx.Results()
+	results, err := c.tx.Results()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed getting tx results")
 	}
@@ -52,17 +54,9 @@ func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error
 			continue
 		}
 
-		var txRaw []byte
-		if c.deleteTransient {
-			txRaw, err = c.tx.BytesNoTransient()
-			if err != nil {
-				return nil, errors.Wrap(err, "failed marshalling transaction content")
-			}
-		} else {
-			txRaw, err = c.tx.Bytes()
-			if err != nil {
-				return nil, errors.Wrap(err, "failed marshalling transaction content")
-			}
+		txRaw, err := c.tx.bytesFor(party, c.deleteTransient)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed marshalling transaction content")
 		}
 
 		tracker.Report(fmt.Sprintf("collectEndorsementsView: collect signature from %s", party))
@@ -124,7 +118,7 @@ func (c *collectEndorsementsView) Call(context view.Context) (interface{}, error
 			}
 			// Check the content of the response
 			// Now results can be equal to what this node has proposed or different
-			if !bytes.Equal(res, proposalResponse.Results()) {
+			if !bytes.Equal(results, proposalResponse.Results()) {
 				return nil, errors.Errorf("received different results")
 			}
 
@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package endorser
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
@@ -23,15 +25,17 @@ type answer struct {
 	party   view.Identity
 }
 
-func (c *parallelCollectEndorsementsOnProposalView) Call(context view.Context) (interface{}, error) {
-	// send Transaction to each party and wait for their responses
-	stateRaw, err := c.tx.Bytes()
-	if err != nil {
-		return nil, err
-	}
+func (c *parallelCollectEndorsementsOnProposalView) Call(context view.Context) (res interface{}, err error) {
+	defer func(start time.Time) { observe(getMetrics(context).EndorsementDuration, start, err) }(time.Now())
+
+	// send Transaction to each party, with the private data it is entitled to, and wait for their responses
 	answerChannel := make(chan *answer, len(c.parties))
 	for _, party := range c.parties {
-		go c.callView(context, party, stateRaw, answerChannel)
+		txRaw, err := c.tx.BytesFor(party)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed marshalling transaction content for [%s]", party)
+		}
+		go c.callView(context, party, txRaw, answerChannel)
 	}
 
 	tm := fabric.GetFabricNetworkService(context, c.tx.Network()).TransactionManager()
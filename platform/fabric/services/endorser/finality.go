@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package endorser
 
 import (
+	"time"
+
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
@@ -15,7 +17,9 @@ type finalityView struct {
 	endpoints []view.Identity
 }
 
-func (f *finalityView) Call(context view.Context) (interface{}, error) {
+func (f *finalityView) Call(context view.Context) (res interface{}, err error) {
+	defer func(start time.Time) { observe(getMetrics(context).FinalityDuration, start, err) }(time.Now())
+
 	ch := fabric.GetChannelDefaultNetwork(context, f.tx.Channel())
 	if len(f.endpoints) != 0 {
 		return nil, ch.Finality().IsFinalForParties(f.tx.ID(), f.endpoints...)
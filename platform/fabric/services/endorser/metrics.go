@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package endorser
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+)
+
+var (
+	endorsementDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:  "fabric",
+		Subsystem:  "endorser",
+		Name:       "endorsement_duration",
+		Help:       "Time in seconds to collect the endorsements of a transaction from the other parties, by status.",
+		Buckets:    []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		LabelNames: []string{"status"},
+	}
+
+	finalityDurationHistogramOpts = metrics.HistogramOpts{
+		Namespace:  "fabric",
+		Subsystem:  "endorser",
+		Name:       "finality_duration",
+		Help:       "Time in seconds to wait for a transaction to become final, by status.",
+		Buckets:    []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		LabelNames: []string{"status"},
+	}
+)
+
+type Metrics struct {
+	EndorsementDuration metrics.Histogram
+	FinalityDuration    metrics.Histogram
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		EndorsementDuration: p.NewHistogram(endorsementDurationHistogramOpts),
+		FinalityDuration:    p.NewHistogram(finalityDurationHistogramOpts),
+	}
+}
+
+// disabledMetrics are used when no endorser metrics are registered, e.g. the fabric platform is not installed
+var disabledMetrics = NewMetrics(&disabled.Provider{})
+
+// getMetrics returns the endorser metrics registered in the passed service provider
+func getMetrics(sp view2.ServiceProvider) *Metrics {
+	s, err := sp.GetService(&Metrics{})
+	if err != nil {
+		return disabledMetrics
+	}
+	return s.(*Metrics)
+}
+
+// observe records in the passed histogram the time elapsed since start, labelled by the outcome
+func observe(h metrics.Histogram, start time.Time, err error) {
+	status := "SUCCESS"
+	if err != nil {
+		status = "FAILURE"
+	}
+	h.With("status", status).Observe(time.Since(start).Seconds())
+}
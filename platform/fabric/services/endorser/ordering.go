@@ -24,7 +24,10 @@ func (o *orderingView) Call(context view.Context) (interface{}, error) {
 		return nil, err
 	}
 	if o.finality {
-		if err := fabric.GetChannelDefaultNetwork(context, tx.Channel()).Finality().IsFinal(tx.ID()); err != nil {
+		start := time.Now()
+		err := fabric.GetChannelDefaultNetwork(context, tx.Channel()).Finality().IsFinal(tx.ID())
+		observe(getMetrics(context).FinalityDuration, start, err)
+		if err != nil {
 			return nil, err
 		}
 	}
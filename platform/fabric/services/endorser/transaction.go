@@ -8,6 +8,8 @@ package endorser
 import (
 	"bytes"
 
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
@@ -20,6 +22,9 @@ type Transaction struct {
 	view2.ServiceProvider
 
 	Transaction *fabric.Transaction
+
+	// collections caches the member organizations of the collections, indexed by namespace and collection
+	collections map[string]*collectionMembers
 }
 
 func (t *Transaction) ID() string {
@@ -100,6 +105,91 @@ func (t *Transaction) BytesNoTransient() ([]byte, error) {
 	return t.Transaction.BytesNoTransient()
 }
 
+// BytesFor marshals the transaction to be sent to the passed party, the private data of the collections
+// the party is not a member of is left out
+func (t *Transaction) BytesFor(party view.Identity) ([]byte, error) {
+	return t.bytesFor(party, false)
+}
+
+// bytesFor marshals the transaction with the private data of the collections the passed party is a member of.
+// Membership is given by the member organizations policy of the collections, as committed on the channel.
+// The party is resolved through the MSPs of the channel, an error is returned if they cannot deserialize it.
+func (t *Transaction) bytesFor(party view.Identity, noTransient bool) ([]byte, error) {
+	var memberErr error
+	raw, err := t.Transaction.BytesWithCollections(noTransient, func(namespace, collection string) bool {
+		if memberErr != nil {
+			return false
+		}
+		members, err := t.collectionMembers(namespace, collection)
+		if err != nil {
+			memberErr = err
+			return false
+		}
+		ch, err := t.channel()
+		if err != nil {
+			memberErr = err
+			return false
+		}
+		ok, err := members.contains(party, ch.MSPManager())
+		if err != nil {
+			memberErr = errors.WithMessagef(err, "collection [%s:%s]", namespace, collection)
+			return false
+		}
+		return ok
+	})
+	if memberErr != nil {
+		return nil, memberErr
+	}
+	return raw, err
+}
+
+// collectionMembers returns the members of the passed collection, as defined by the collection
+// configuration of the chaincode committed on the channel
+func (t *Transaction) collectionMembers(namespace, collection string) (*collectionMembers, error) {
+	if members, ok := t.collections[namespace+"$"+collection]; ok {
+		return members, nil
+	}
+	ch, err := t.channel()
+	if err != nil {
+		return nil, err
+	}
+	definition, _, err := ch.Lifecycle().QueryCommitted(namespace)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed querying the definition of chaincode [%s]", namespace)
+	}
+	configs := &pb.CollectionConfigPackage{}
+	if err := proto.Unmarshal(definition.Collections, configs); err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling the collections of chaincode [%s]", namespace)
+	}
+	if t.collections == nil {
+		t.collections = map[string]*collectionMembers{}
+	}
+	for _, config := range configs.Config {
+		static := config.GetStaticCollectionConfig()
+		if static == nil {
+			continue
+		}
+		members, err := newCollectionMembers(static.MemberOrgsPolicy.GetSignaturePolicy())
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid member policy for collection [%s:%s]", namespace, static.Name)
+		}
+		t.collections[namespace+"$"+static.Name] = members
+	}
+	members, ok := t.collections[namespace+"$"+collection]
+	if !ok {
+		return nil, errors.Errorf("collection [%s:%s] not defined", namespace, collection)
+	}
+	return members, nil
+}
+
+func (t *Transaction) channel() (*fabric.Channel, error) {
+	ch, err := fabric.GetFabricNetworkService(t.ServiceProvider, t.Network()).Channel(t.Channel())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s]", t.Channel())
+	}
+	return ch, nil
+}
+
 func (t *Transaction) Raw() ([]byte, error) {
 	return t.Transaction.Raw()
 }
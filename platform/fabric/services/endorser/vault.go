@@ -6,7 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package endorser
 
 import (
-	"hash/fnv"
+	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 
@@ -17,20 +17,22 @@ type commitInVault struct {
 	transaction *Transaction
 }
 
+// Call commits the transaction to the vault only, without going through Fabric.
+// It returns the sequence number assigned to the transaction.
 func (c commitInVault) Call(context view.Context) (interface{}, error) {
 	vault := fabric.GetChannelDefaultNetwork(context, c.transaction.Channel()).Vault()
 
-	h := fnv.New64()
-	h.Write([]byte(c.transaction.ID()))
-	block := h.Sum64()
-
-	// TODO: Change this
-	if err := vault.CommitTX(c.transaction.ID(), block, 0); err != nil {
-		return nil, err
+	seq, err := vault.CommitLocalTX(c.transaction.ID())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed committing transaction [%s] locally", c.transaction.ID())
 	}
-	return nil, nil
+	return seq, nil
 }
 
+// NewCommitInVault returns a view that commits the passed transaction to the vault only.
+// The transaction must write only the namespaces listed under fabric.vault.local.namespaces in the configuration,
+// it is rejected otherwise. Earlier versions committed any transaction, at a version derived from its id,
+// the nodes using this view have to list the namespaces it writes.
 func NewCommitInVault(transaction *Transaction) *commitInVault {
 	return &commitInVault{transaction: transaction}
 }
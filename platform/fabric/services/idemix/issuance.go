@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	idemix2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/idemix"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+var logger = flogging.MustGetLogger("fabric-sdk.idemix")
+
+// EnrollmentRequest is sent by a node to the issuer to start the issuance of a credential
+type EnrollmentRequest struct {
+	EnrollmentID string
+	Secret       string
+}
+
+// NonceResponse carries the issuer's nonce and public parameters
+type NonceResponse struct {
+	MSPID               string
+	Nonce               []byte
+	IssuerPublicKey     []byte
+	RevocationPublicKey []byte
+}
+
+// CredentialRequest carries the credential request bound to the issuer's nonce
+type CredentialRequest struct {
+	Request []byte
+}
+
+// CredentialResponse carries the credential issued by the issuer
+type CredentialResponse struct {
+	Credential                      []byte
+	CredentialRevocationInformation []byte
+	OU                              string
+	Role                            int
+}
+
+// Enrollment describes the credential a node wants to get from an issuer
+type Enrollment struct {
+	// Issuer is the identity of the FSC node playing the issuer role
+	Issuer view.Identity
+	// Network is the fabric network the credential is registered for
+	Network string
+	// ID is the label under which the credential is registered in the local membership
+	ID string
+	// Path is the folder where the credential is stored
+	Path         string
+	EnrollmentID string
+	Secret       string
+}
+
+type enrollView struct {
+	*Enrollment
+}
+
+// NewEnrollView returns a view that obtains an idemix credential from the issuer, stores it in the enrollment path,
+// and registers it in the local membership of the fabric network.
+// Running the view again renews the credential.
+func NewEnrollView(enrollment *Enrollment) *enrollView {
+	return &enrollView{Enrollment: enrollment}
+}
+
+func (e *enrollView) Call(context view.Context) (interface{}, error) {
+	session, err := context.GetSession(context.Initiator(), e.Issuer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting session with issuer [%s]", e.Issuer)
+	}
+
+	// Step 1: ask for a nonce
+	if err := send(session, &EnrollmentRequest{EnrollmentID: e.EnrollmentID, Secret: e.Secret}); err != nil {
+		return nil, errors.Wrap(err, "failed sending enrollment request")
+	}
+	nonce := &NonceResponse{}
+	if err := receive(session, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed receiving nonce")
+	}
+
+	// Step 2: send the credential request
+	sk, request, err := idemix2.NewCredentialRequest(nonce.IssuerPublicKey, nonce.Nonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating credential request")
+	}
+	if err := send(session, &CredentialRequest{Request: request}); err != nil {
+		return nil, errors.Wrap(err, "failed sending credential request")
+	}
+	cred := &CredentialResponse{}
+	if err := receive(session, cred); err != nil {
+		return nil, errors.Wrap(err, "failed receiving credential")
+	}
+
+	// Step 3: store and register the credential
+	if err := idemix2.WriteMSPFolder(
+		e.Path,
+		nonce.IssuerPublicKey, nonce.RevocationPublicKey,
+		sk, cred.Credential, cred.CredentialRevocationInformation,
+		cred.OU, cred.Role, e.EnrollmentID,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed storing credential for [%s]", e.EnrollmentID)
+	}
+	if err := fabric.GetFabricNetworkService(context, e.Network).LocalMembership().RegisterIdemixMSP(e.ID, e.Path, nonce.MSPID); err != nil {
+		return nil, errors.Wrapf(err, "failed registering idemix msp [%s]", e.ID)
+	}
+	logger.Debugf("credential for [%s] registered as [%s]", e.EnrollmentID, e.ID)
+
+	return nil, nil
+}
+
+// IssuerView is the responder run by the FSC node playing the issuer role
+type IssuerView struct {
+	issuer *idemix2.Issuer
+}
+
+func NewIssuerView(issuer *idemix2.Issuer) *IssuerView {
+	return &IssuerView{issuer: issuer}
+}
+
+func (i *IssuerView) Call(context view.Context) (interface{}, error) {
+	session := context.Session()
+
+	// Step 1: authenticate and release a nonce
+	request := &EnrollmentRequest{}
+	if err := receive(session, request); err != nil {
+		return nil, errors.Wrap(err, "failed receiving enrollment request")
+	}
+	nonce, err := i.issuer.NewNonce(request.EnrollmentID, request.Secret)
+	if err != nil {
+		session.SendError([]byte(err.Error()))
+		return nil, errors.Wrapf(err, "failed authenticating [%s]", request.EnrollmentID)
+	}
+	ipk, err := i.issuer.PublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting issuer public key")
+	}
+	rpk, err := i.issuer.RevocationPublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting revocation public key")
+	}
+	if err := send(session, &NonceResponse{
+		MSPID:               i.issuer.MSPID(),
+		Nonce:               nonce,
+		IssuerPublicKey:     ipk,
+		RevocationPublicKey: rpk,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed sending nonce")
+	}
+
+	// Step 2: issue the credential
+	credRequest := &CredentialRequest{}
+	if err := receive(session, credRequest); err != nil {
+		return nil, errors.Wrap(err, "failed receiving credential request")
+	}
+	cred, cri, err := i.issuer.Issue(request.EnrollmentID, credRequest.Request)
+	if err != nil {
+		session.SendError([]byte(err.Error()))
+		return nil, errors.Wrapf(err, "failed issuing credential for [%s]", request.EnrollmentID)
+	}
+	r, err := i.issuer.Registration(request.EnrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := send(session, &CredentialResponse{
+		Credential:                      cred,
+		CredentialRevocationInformation: cri,
+		OU:                              r.OU,
+		Role:                            r.Role,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed sending credential")
+	}
+	logger.Debugf("credential issued for [%s]", request.EnrollmentID)
+
+	return nil, nil
+}
+
+func send(session view.Session, msg interface{}) error {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return session.Send(raw)
+}
+
+func receive(session view.Session, msg interface{}) error {
+	select {
+	case m := <-session.Receive():
+		if m.Status == view.ERROR {
+			return errors.New(string(m.Payload))
+		}
+		return json.Unmarshal(m.Payload, msg)
+	case <-time.After(60 * time.Second):
+		return errors.New("timeout reading from session")
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package idemix
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	idemix2 "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/idemix"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// RevocationInformationRequest is sent by a node to the issuer to get the credential revocation information
+// of the current epoch
+type RevocationInformationRequest struct{}
+
+// RevocationInformationResponse carries the credential revocation information of the current epoch
+type RevocationInformationResponse struct {
+	Epoch                           int
+	CredentialRevocationInformation []byte
+}
+
+type updateRevocationInformationView struct {
+	issuer  view.Identity
+	network string
+}
+
+// NewUpdateRevocationInformationView returns a view that fetches the credential revocation information of the
+// current epoch from the issuer and moves the idemix MSPs of the local membership to that epoch.
+// Nodes, either signing or verifying, should run it whenever the issuer moves to a new epoch, for instance
+// after a revocation. The issuer must register RevocationInformationResponderView as responder of this view.
+func NewUpdateRevocationInformationView(issuer view.Identity, network string) *updateRevocationInformationView {
+	return &updateRevocationInformationView{issuer: issuer, network: network}
+}
+
+func (u *updateRevocationInformationView) Call(context view.Context) (interface{}, error) {
+	session, err := context.GetSession(context.Initiator(), u.issuer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting session with issuer [%s]", u.issuer)
+	}
+	if err := send(session, &RevocationInformationRequest{}); err != nil {
+		return nil, errors.Wrap(err, "failed sending revocation information request")
+	}
+	response := &RevocationInformationResponse{}
+	if err := receive(session, response); err != nil {
+		return nil, errors.Wrap(err, "failed receiving revocation information")
+	}
+
+	if err := fabric.GetFabricNetworkService(context, u.network).LocalMembership().UpdateRevocationInformation(
+		response.CredentialRevocationInformation,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed moving to epoch [%d]", response.Epoch)
+	}
+	logger.Debugf("moved to epoch [%d]", response.Epoch)
+
+	return response.Epoch, nil
+}
+
+// RevocationInformationResponderView is run by the FSC node playing the issuer role
+// to release the credential revocation information of the current epoch
+type RevocationInformationResponderView struct {
+	issuer *idemix2.Issuer
+}
+
+func NewRevocationInformationResponderView(issuer *idemix2.Issuer) *RevocationInformationResponderView {
+	return &RevocationInformationResponderView{issuer: issuer}
+}
+
+func (r *RevocationInformationResponderView) Call(context view.Context) (interface{}, error) {
+	session := context.Session()
+
+	request := &RevocationInformationRequest{}
+	if err := receive(session, request); err != nil {
+		return nil, errors.Wrap(err, "failed receiving revocation information request")
+	}
+	cri, err := r.issuer.CRI()
+	if err != nil {
+		session.SendError([]byte(err.Error()))
+		return nil, errors.Wrap(err, "failed getting credential revocation information")
+	}
+	if err := send(session, &RevocationInformationResponse{
+		Epoch:                           r.issuer.Epoch(),
+		CredentialRevocationInformation: cri,
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed sending revocation information")
+	}
+
+	return nil, nil
+}
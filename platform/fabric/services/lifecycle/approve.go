@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package lifecycle
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// approvalTimeout bounds the wait for the approval of a party, that has to be ordered and committed
+const approvalTimeout = 2 * time.Minute
+
+// ApprovalRequest is what the nodes of the other organizations are asked to approve
+type ApprovalRequest struct {
+	Network    string
+	Channel    string
+	Definition *fabric.ChaincodeDefinition
+	// PackageID is the package the peers run the chaincode with, it must be installed on the peers of each party
+	PackageID string
+}
+
+type approveView struct {
+	request *ApprovalRequest
+	parties []view.Identity
+}
+
+// NewApproveView returns a view that approves the passed definition for the organization of this node,
+// and then asks the passed parties, nodes of other organizations, to do the same for theirs.
+// The view returns the approvals of the organizations of the channel, as reported by CheckCommitReadiness.
+func NewApproveView(request *ApprovalRequest, parties ...view.Identity) *approveView {
+	return &approveView{request: request, parties: parties}
+}
+
+func (a *approveView) Call(context view.Context) (interface{}, error) {
+	ch, err := fabric.GetFabricNetworkService(context, a.request.Network).Channel(a.request.Channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", a.request.Network, a.request.Channel)
+	}
+	lc := ch.Lifecycle().WithSignerIdentity(context.Me())
+	if err := lc.Approve(a.request.Definition, a.request.PackageID); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(a.request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling approval request")
+	}
+	for _, party := range a.parties {
+		if context.IsMe(party) {
+			continue
+		}
+		session, err := context.GetSession(context.Initiator(), party)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed getting session with [%s]", party)
+		}
+		ch := session.Receive()
+		if err := session.Send(raw); err != nil {
+			return nil, errors.Wrapf(err, "failed sending approval request to [%s]", party)
+		}
+		var msg *view.Message
+		select {
+		case msg = <-ch:
+		case <-time.After(approvalTimeout):
+			return nil, errors.Errorf("timeout waiting for the approval of [%s]", party)
+		}
+		if msg.Status == view.ERROR {
+			return nil, errors.Errorf("party [%s] did not approve: [%s]", party, string(msg.Payload))
+		}
+		logger.Debugf("party [%s] approved [%s:%d]", party, a.request.Definition.Name, a.request.Definition.Sequence)
+	}
+
+	return lc.CheckCommitReadiness(a.request.Definition)
+}
+
+// ApprovalCheck inspects the definition another node asks to approve, its policies, collections and init flag
+// included, and refuses it by returning an error
+type ApprovalCheck func(request *ApprovalRequest, definition *fabric.ChaincodeDefinition) error
+
+type approveResponderView struct {
+	check ApprovalCheck
+}
+
+// NewApproveResponderView returns a view that approves, for the organization of this node, the definition
+// another node asks for, once the passed check accepts it. The check is mandatory: without it, every request
+// is refused. If the request carries a package ID, the package must be installed on the peers of the network.
+func NewApproveResponderView(check ApprovalCheck) *approveResponderView {
+	return &approveResponderView{check: check}
+}
+
+func (a *approveResponderView) Call(context view.Context) (interface{}, error) {
+	session := context.Session()
+	var msg *view.Message
+	select {
+	case msg = <-session.Receive():
+	case <-time.After(60 * time.Second):
+		return nil, errors.New("timeout waiting for approval request")
+	}
+	if msg.Status == view.ERROR {
+		return nil, errors.New(string(msg.Payload))
+	}
+
+	request := &ApprovalRequest{}
+	if err := json.Unmarshal(msg.Payload, request); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling approval request")
+	}
+	if err := a.approve(context, request); err != nil {
+		if err1 := session.SendError([]byte(err.Error())); err1 != nil {
+			logger.Errorf("failed sending error [%s]: [%s]", err, err1)
+		}
+		return nil, err
+	}
+	if err := session.Send([]byte("approved")); err != nil {
+		return nil, errors.Wrap(err, "failed sending approval")
+	}
+	return request, nil
+}
+
+func (a *approveResponderView) approve(context view.Context, request *ApprovalRequest) error {
+	if request.Definition == nil {
+		return errors.New("no chaincode definition specified")
+	}
+	if a.check == nil {
+		return errors.Errorf("approval of [%s:%d] refused: no check set", request.Definition.Name, request.Definition.Sequence)
+	}
+	if err := a.check(request, request.Definition); err != nil {
+		return errors.WithMessagef(err, "approval of [%s:%d] refused", request.Definition.Name, request.Definition.Sequence)
+	}
+	fns, err := fabric.FabricNetworkService(context, request.Network)
+	if err != nil {
+		return errors.WithMessagef(err, "unknown network [%s]", request.Network)
+	}
+	ch, err := fns.Channel(request.Channel)
+	if err != nil {
+		return errors.WithMessagef(err, "failed getting channel [%s:%s]", request.Network, request.Channel)
+	}
+	lc := ch.Lifecycle().WithSignerIdentity(context.Me())
+	if len(request.PackageID) != 0 {
+		installed, err := lc.QueryInstalled()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, cc := range installed {
+			if cc.PackageID == request.PackageID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("package [%s] not installed", request.PackageID)
+		}
+	}
+	return lc.Approve(request.Definition, request.PackageID)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package lifecycle
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+type commitView struct {
+	network    string
+	channel    string
+	definition *fabric.ChaincodeDefinition
+	required   []string
+	endorsers  []view.Identity
+}
+
+// NewCommitView returns a view that commits the passed definition to the channel, once the passed organizations
+// have approved it. If no organization is passed, all the organizations of the channel must have approved it.
+func NewCommitView(network, channel string, definition *fabric.ChaincodeDefinition, required ...string) *commitView {
+	return &commitView{network: network, channel: channel, definition: definition, required: required}
+}
+
+// WithEndorsers sets the peers that endorse the commit, they are discovered otherwise
+func (c *commitView) WithEndorsers(ids ...view.Identity) *commitView {
+	c.endorsers = ids
+	return c
+}
+
+func (c *commitView) Call(context view.Context) (interface{}, error) {
+	ch, err := fabric.GetFabricNetworkService(context, c.network).Channel(c.channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", c.network, c.channel)
+	}
+	lc := ch.Lifecycle().WithSignerIdentity(context.Me())
+	approvals, err := lc.CheckCommitReadiness(c.definition)
+	if err != nil {
+		return nil, err
+	}
+	if missing := missingApprovals(approvals, c.required); len(missing) != 0 {
+		return nil, errors.Errorf("[%s:%d] not approved yet by %v", c.definition.Name, c.definition.Sequence, missing)
+	}
+	if err := lc.Commit(c.definition, c.endorsers...); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// missingApprovals returns the required organizations that have not approved, sorted.
+// If no organization is required, all are.
+func missingApprovals(approvals map[string]bool, required []string) []string {
+	if len(required) == 0 {
+		for org := range approvals {
+			required = append(required, org)
+		}
+	}
+	var missing []string
+	for _, org := range required {
+		if !approvals[org] {
+			missing = append(missing, org)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
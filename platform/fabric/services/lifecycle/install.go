@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package lifecycle
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+var logger = flogging.MustGetLogger("fabric-sdk.lifecycle")
+
+type installView struct {
+	network string
+	channel string
+	pkg     []byte
+}
+
+// NewInstallView returns a view that installs the passed chaincode package on the peers of the network
+// of the passed channel. The view returns the *fabric.InstalledChaincode.
+func NewInstallView(network, channel string, pkg []byte) *installView {
+	return &installView{network: network, channel: channel, pkg: pkg}
+}
+
+func (i *installView) Call(context view.Context) (interface{}, error) {
+	ch, err := fabric.GetFabricNetworkService(context, i.network).Channel(i.channel)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting channel [%s:%s]", i.network, i.channel)
+	}
+	installed, err := ch.Lifecycle().WithSignerIdentity(context.Me()).Install(i.pkg)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("package [%s] installed", installed.PackageID)
+	return installed, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+)
+
+// cborEncMode encodes deterministically, map keys sorted, so that all the endorsers
+// of a transaction produce the same bytes for the same state
+var cborEncMode cbor.EncMode
+
+func init() {
+	var err error
+	cborEncMode, err = cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// CBORCodec encodes states in CBOR, that keeps binary fields as byte strings
+// and big integers as bignums. States implementing Serializable and State are encoded using their own methods.
+type CBORCodec struct{}
+
+func (c *CBORCodec) Marshal(v interface{}) ([]byte, error) {
+	if s, ok := v.(Serializable); ok {
+		return s.Bytes()
+	}
+	raw, err := cborEncMode.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot marshal [%T]", v)
+	}
+	return raw, nil
+}
+
+func (c *CBORCodec) Unmarshal(data []byte, v interface{}) error {
+	if s, ok := v.(State); ok {
+		return s.SetFromBytes(data)
+	}
+	if err := cbor.Unmarshal(data, v); err != nil {
+		return errors.Wrapf(err, "cannot unmarshal into [%T]", v)
+	}
+	return nil
+}
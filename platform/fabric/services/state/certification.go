@@ -10,18 +10,18 @@ import (
 
 	"github.com/pkg/errors"
 
-	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
-	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/rwset"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/assert"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
 const (
-	CertificationType string = "CertificationType"
-	Certification     string = "Certification"
+	CertificationType      string = "CertificationType"
+	CertificationTypeParam string = "CertificationTypeParam"
+	Certification          string = "Certification"
 
 	ChaincodeCertification string = "ChaincodesCertification"
+	NotaryCertification    string = "NotaryCertification"
 
 	CertificationFnc string = "state_certification"
 )
@@ -31,16 +31,19 @@ type TxTransientStore interface {
 	GetTransient(key string) []byte
 }
 
+// SetCertificationType sets the certification type to be used to certify the inputs of the passed transaction,
+// together with its parameters. The certification type must have a registered Certifier.
 func SetCertificationType(tx TxTransientStore, typ string, value []byte) error {
-	switch typ {
-	case ChaincodeCertification:
-		if err := tx.SetTransient(CertificationType, []byte(ChaincodeCertification)); err != nil {
-			return errors.Wrap(err, "failed appending certification type")
-		}
-		return nil
-	default:
-		return errors.Errorf("certification type [%s] not recognized", typ)
+	if _, err := GetCertifier(typ); err != nil {
+		return err
 	}
+	if err := tx.SetTransient(CertificationType, []byte(typ)); err != nil {
+		return errors.Wrap(err, "failed appending certification type")
+	}
+	if err := tx.SetTransient(CertificationTypeParam, value); err != nil {
+		return errors.Wrap(err, "failed appending certification type parameters")
+	}
+	return nil
 }
 
 func GetCertificationType(tx TxTransientStore) (string, []byte, error) {
@@ -50,12 +53,10 @@ func GetCertificationType(tx TxTransientStore) (string, []byte, error) {
 	}
 
 	typ := string(ctt)
-	switch typ {
-	case ChaincodeCertification:
-		return typ, nil, nil
-	default:
-		return "", nil, errors.Errorf("certification type [%s] not recognized", typ)
+	if _, err := GetCertifier(typ); err != nil {
+		return "", nil, err
 	}
+	return typ, tx.GetTransient(CertificationTypeParam), nil
 }
 
 func SetCertification(tx TxTransientStore, id string, value []byte) error {
@@ -96,109 +97,66 @@ func certificationKey(key string) (string, error) {
 }
 
 func (n *Namespace) VerifyInputCertificationAt(index int, key string) error {
-	typ, _, err := GetCertificationType(n.tx)
+	typ, params, err := GetCertificationType(n.tx)
 	if err != nil {
 		return errors.Wrapf(err, "failed getting certification type")
 	}
 	if len(typ) == 0 {
-		return errors.Wrapf(err, "no certification type found")
-	}
-	switch typ {
-	case ChaincodeCertification:
-		rwSet, err := n.tx.RWSet()
-		if err != nil {
-			return errors.Wrap(err, "filed getting rw set")
-		}
-		id, err := rwSet.GetReadKeyAt(n.namespace(), index)
-		if err != nil {
-			return errors.Wrapf(err, "failed getting state [%s, %d]", n.namespace(), index)
-		}
-
-		raw, err := GetCertification(n.tx, id)
-		if err != nil {
-			return errors.Wrapf(err, "failed setting certification from [%s, %d]", n.namespace(), index)
-		}
-
-		// raw is an envelope, it must be signed by enough endorsers
-		cn, cv := n.tx.Chaincode()
-		ch, err := fabric.GetFabricNetworkService(n.tx.ServiceProvider, n.tx.Network()).Channel(n.tx.Channel())
-		if err != nil {
-			return errors.Wrapf(err, "failed getting channel [%s:%s]", n.tx.Network(), n.tx.Channel())
-		}
-		endorsers, err := ch.Chaincode(cn).Discover().Call()
-		if err != nil {
-			return errors.Wrapf(err, "failed asking endorsers for to [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-		_, certTx, err := endorser.NewTransactionFromEnvelopeBytes(n.tx.ServiceProvider, raw)
-		if err != nil {
-			return errors.Wrapf(err, "failed parsing certification [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-
-		// Check input
-		fn, params := certTx.FunctionAndParameters()
-		if fn != CertificationFnc || len(params) != 2 || params[0] != key || params[1] != n.tx.ID() {
-			return errors.Errorf("invalid certification, expected [CertificationFnc,%s,%s], got [%s,%v]", n.tx.ID(), key, fn, params)
-		}
-
-		// Check endorsements
-		if err := certTx.HasBeenEndorsedBy(endorsers...); err != nil {
-			return errors.Wrapf(err, "failed validating certification [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-
-		// Extract the content
-		rws, err := certTx.RWSet()
-		if err != nil {
-			return errors.Wrapf(err, "failed getting rws [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-		defer rws.Done()
-		k, v, err := rws.GetWriteAt(n.namespace(), 0)
-		if err != nil {
-			return errors.Wrapf(err, "failed getting rws write at 0 [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-		if k != key {
-			return errors.Errorf("invalid certification, expected key [%s], got [%s]", key, k)
-		}
-		n.certifiedInputs[k] = v
-
-		return nil
-	default:
-		return errors.Errorf("certification type [%s] not recognized", typ)
+		return errors.New("no certification type found")
+	}
+	// the certification type is chosen by the proposer, check that this node accepts it
+	if err := checkCertificationType(n.tx.ServiceProvider, typ); err != nil {
+		return err
+	}
+	certifier, err := GetCertifier(typ)
+	if err != nil {
+		return err
 	}
+
+	rwSet, err := n.tx.RWSet()
+	if err != nil {
+		return errors.Wrap(err, "failed getting rw set")
+	}
+	id, err := rwSet.GetReadKeyAt(n.namespace(), index)
+	if err != nil {
+		return errors.Wrapf(err, "failed getting state [%s, %d]", n.namespace(), index)
+	}
+
+	raw, err := GetCertification(n.tx, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed setting certification from [%s, %d]", n.namespace(), index)
+	}
+
+	v, err := certifier.Verify(n.tx, n.namespace(), key, params, raw)
+	if err != nil {
+		return errors.Wrapf(err, "failed verifying certification [%s] of [%s, %d]", typ, n.namespace(), index)
+	}
+	n.certifiedInputs[key] = v
+
+	return nil
 }
 
 func (n *Namespace) certifyInput(id string) error {
-	typ, _, err := GetCertificationType(n.tx)
+	typ, params, err := GetCertificationType(n.tx)
 	if err != nil {
 		return errors.Wrapf(err, "failed getting certification type")
 	}
 	if len(typ) == 0 {
-		return errors.Wrapf(err, "no certification type found")
-	}
-	switch typ {
-	case ChaincodeCertification:
-		// Invoke chaincode
-		cn, cv := n.tx.Chaincode()
-		ch, err := fabric.GetFabricNetworkService(n.tx.ServiceProvider, n.tx.Network()).Channel(n.tx.Channel())
-		if err != nil {
-			return errors.Wrapf(err, "failed getting channel [%s:%s]", n.tx.Network(), n.tx.Channel())
-		}
-		env, err := ch.Chaincode(cn).Endorse(CertificationFnc, id, n.tx.ID()).WithInvokerIdentity(
-			fabric.GetFabricNetworkService(n.tx.ServiceProvider, n.tx.Network()).IdentityProvider().DefaultIdentity(),
-		).Call()
-		if err != nil {
-			return errors.Wrapf(err, "failed asking certification to [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-		rawEnv, err := env.Bytes()
-		if err != nil {
-			return errors.Wrapf(err, "failed marshalling tx env [%s,%s,%s] for [%s]", n.tx.Channel(), cn, cv, id)
-		}
-		if err := SetCertification(n.tx, id, rawEnv); err != nil {
-			return errors.Wrapf(err, "failed setting certification from [%s,%s,%s] of [%s]", n.tx.Channel(), cn, cv, id)
-		}
-		return nil
-	default:
-		return errors.Errorf("certification type [%s] not recognized", typ)
+		return errors.New("no certification type found")
 	}
+	certifier, err := GetCertifier(typ)
+	if err != nil {
+		return err
+	}
+
+	certification, err := certifier.Certify(n.tx, n.namespace(), id, params)
+	if err != nil {
+		return errors.Wrapf(err, "failed certifying [%s] with [%s]", id, typ)
+	}
+	if err := SetCertification(n.tx, id, certification); err != nil {
+		return errors.Wrapf(err, "failed setting certification [%s] of [%s]", typ, id)
+	}
+	return nil
 }
 
 type CertificationRequest struct {
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
+)
+
+// Certifier produces and verifies certifications of the states read by a transaction.
+// A certification allows the parties of a transaction to trust the value of an input
+// without having access to the vault it is stored in.
+type Certifier interface {
+	// Certify returns a certification of the state stored under the passed namespace and key, to be read by the passed transaction.
+	// Params are the parameters that have been associated with the certification type of the transaction.
+	Certify(tx *endorser.Transaction, namespace string, key string, params []byte) ([]byte, error)
+	// Verify checks that the passed certification is valid for the passed namespace and key, and for the passed transaction,
+	// and returns the certified value.
+	Verify(tx *endorser.Transaction, namespace string, key string, params []byte, certification []byte) ([]byte, error)
+}
+
+var (
+	certifiersLock sync.RWMutex
+	certifiers     = map[string]Certifier{
+		ChaincodeCertification: &chaincodeCertifier{},
+		NotaryCertification:    &notaryCertifier{},
+	}
+)
+
+// RegisterCertifier registers the certifier for the passed certification type
+func RegisterCertifier(typ string, certifier Certifier) {
+	certifiersLock.Lock()
+	defer certifiersLock.Unlock()
+
+	certifiers[typ] = certifier
+}
+
+// GetCertifier returns the certifier registered for the passed certification type
+func GetCertifier(typ string) (Certifier, error) {
+	certifiersLock.RLock()
+	defer certifiersLock.RUnlock()
+
+	certifier, ok := certifiers[typ]
+	if !ok {
+		return nil, errors.Errorf("certification type [%s] not recognized", typ)
+	}
+	return certifier, nil
+}
+
+// chaincodeCertifier certifies states by asking the endorsers of the namespace's chaincode
+// to endorse the invocation of the certification function
+type chaincodeCertifier struct{}
+
+func (c *chaincodeCertifier) Certify(tx *endorser.Transaction, namespace string, key string, params []byte) ([]byte, error) {
+	// Invoke chaincode
+	cn, cv := tx.Chaincode()
+	ch, err := fabric.GetFabricNetworkService(tx.ServiceProvider, tx.Network()).Channel(tx.Channel())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting channel [%s:%s]", tx.Network(), tx.Channel())
+	}
+	env, err := ch.Chaincode(cn).Endorse(CertificationFnc, key, tx.ID()).WithInvokerIdentity(
+		fabric.GetFabricNetworkService(tx.ServiceProvider, tx.Network()).IdentityProvider().DefaultIdentity(),
+	).Call()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed asking certification to [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+	rawEnv, err := env.Bytes()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed marshalling tx env [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+	return rawEnv, nil
+}
+
+func (c *chaincodeCertifier) Verify(tx *endorser.Transaction, namespace string, key string, params []byte, certification []byte) ([]byte, error) {
+	// certification is an envelope, it must be signed by enough endorsers
+	cn, cv := tx.Chaincode()
+	ch, err := fabric.GetFabricNetworkService(tx.ServiceProvider, tx.Network()).Channel(tx.Channel())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting channel [%s:%s]", tx.Network(), tx.Channel())
+	}
+	endorsers, err := ch.Chaincode(cn).Discover().Call()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed asking endorsers for to [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+	_, certTx, err := endorser.NewTransactionFromEnvelopeBytes(tx.ServiceProvider, certification)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing certification [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+
+	// Check input
+	fn, fnParams := certTx.FunctionAndParameters()
+	if fn != CertificationFnc || len(fnParams) != 2 || fnParams[0] != key || fnParams[1] != tx.ID() {
+		return nil, errors.Errorf("invalid certification, expected [CertificationFnc,%s,%s], got [%s,%v]", tx.ID(), key, fn, fnParams)
+	}
+
+	// Check endorsements
+	if err := certTx.HasBeenEndorsedBy(endorsers...); err != nil {
+		return nil, errors.Wrapf(err, "failed validating certification [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+
+	// Extract the content
+	rws, err := certTx.RWSet()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting rws [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+	defer rws.Done()
+	k, v, err := rws.GetWriteAt(namespace, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting rws write at 0 [%s,%s,%s] for [%s]", tx.Channel(), cn, cv, key)
+	}
+	if k != key {
+		return nil, errors.Errorf("invalid certification, expected key [%s], got [%s]", key, k)
+	}
+	return v, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
+)
+
+type transientStore map[string][]byte
+
+func (t transientStore) SetTransient(key string, raw []byte) error {
+	t[key] = raw
+	return nil
+}
+
+func (t transientStore) GetTransient(key string) []byte {
+	return t[key]
+}
+
+type mockCertifier struct{}
+
+func (m *mockCertifier) Certify(tx *endorser.Transaction, namespace string, key string, params []byte) ([]byte, error) {
+	return params, nil
+}
+
+func (m *mockCertifier) Verify(tx *endorser.Transaction, namespace string, key string, params []byte, certification []byte) ([]byte, error) {
+	return certification, nil
+}
+
+func TestCertificationType(t *testing.T) {
+	tx := transientStore{}
+
+	typ, _, err := GetCertificationType(tx)
+	assert.NoError(t, err)
+	assert.Empty(t, typ)
+
+	assert.NoError(t, SetCertificationType(tx, ChaincodeCertification, nil))
+	typ, params, err := GetCertificationType(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, ChaincodeCertification, typ)
+	assert.Empty(t, params)
+
+	assert.NoError(t, SetCertificationType(tx, NotaryCertification, []byte("notary")))
+	typ, params, err = GetCertificationType(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, NotaryCertification, typ)
+	assert.Equal(t, []byte("notary"), params)
+
+	assert.EqualError(t, SetCertificationType(tx, "Merkle", nil), "certification type [Merkle] not recognized")
+	RegisterCertifier("Merkle", &mockCertifier{})
+	assert.NoError(t, SetCertificationType(tx, "Merkle", []byte("root")))
+	typ, params, err = GetCertificationType(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Merkle", typ)
+	assert.Equal(t, []byte("root"), params)
+}
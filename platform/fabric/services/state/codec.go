@@ -5,6 +5,10 @@ SPDX-License-Identifier: Apache-2.0
 */
 package state
 
+import (
+	"sync"
+)
+
 type Marshaller interface {
 	Marshal(v interface{}) ([]byte, error)
 }
@@ -18,6 +22,53 @@ type Codec interface {
 	Unmarshaller
 }
 
+// CodecProvider can be implemented by a state to select the codec used to encode and decode it.
+// The codec returned by a state takes precedence over the one selected for the namespace.
+type CodecProvider interface {
+	Codec() Codec
+}
+
+var (
+	codecsLock      sync.RWMutex
+	namespaceCodecs = map[string]Codec{}
+)
+
+// RegisterCodec selects the codec to be used for the states stored in the passed namespace.
+// Namespaces with no registered codec use the JSONCodec.
+func RegisterCodec(namespace string, codec Codec) {
+	codecsLock.Lock()
+	defer codecsLock.Unlock()
+
+	if codec == nil {
+		delete(namespaceCodecs, namespace)
+		return
+	}
+	namespaceCodecs[namespace] = codec
+}
+
+// GetCodec returns the codec registered for the passed namespace, or the JSONCodec if none was registered.
+func GetCodec(namespace string) Codec {
+	codecsLock.RLock()
+	defer codecsLock.RUnlock()
+
+	codec, ok := namespaceCodecs[namespace]
+	if !ok {
+		return &JSONCodec{}
+	}
+	return codec
+}
+
+// codecOf returns the codec selected by the passed state, or the JSONCodec if the state selects none.
+// It is used where states are exchanged outside of a namespace.
+func codecOf(s interface{}) Codec {
+	if p, ok := s.(CodecProvider); ok {
+		if codec := p.Codec(); codec != nil {
+			return codec
+		}
+	}
+	return &JSONCodec{}
+}
+
 func Unmarshal(unmarshaller Unmarshaller, data []byte, v interface{}) error {
 	return unmarshaller.Unmarshal(data, v)
 }
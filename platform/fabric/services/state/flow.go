@@ -17,8 +17,15 @@ type receiveView struct {
 	state        interface{}
 }
 
+// NewReceiveView returns a view that receives the passed state, decoded with the codec the state selects
 func NewReceiveView(state interface{}) *receiveView {
-	return &receiveView{state: state, unmarshaller: &JSONCodec{}}
+	return &receiveView{state: state, unmarshaller: codecOf(state)}
+}
+
+// WithCodec sets the codec the state is decoded with, e.g. the one registered for its namespace
+func (s *receiveView) WithCodec(codec Codec) *receiveView {
+	s.unmarshaller = codec
+	return s
 }
 
 func (s receiveView) Call(context view.Context) (interface{}, error) {
@@ -70,8 +77,10 @@ func (s payloadReceiveView) Call(context view.Context) (interface{}, error) {
 type sendReceiveView struct {
 	sendState    interface{}
 	receiveState interface{}
-	coded        Codec
-	party        view.Identity
+	// codec, if set, encodes the sent state and decodes the received one,
+	// otherwise the codecs the states select are used
+	codec Codec
+	party view.Identity
 }
 
 func (s *sendReceiveView) Call(context view.Context) (interface{}, error) {
@@ -83,7 +92,11 @@ func (s *sendReceiveView) Call(context view.Context) (interface{}, error) {
 	ch := session.Receive()
 
 	// Send a state
-	sendStateRaw, err := s.coded.Marshal(s.sendState)
+	marshaller, unmarshaller := s.codec, s.codec
+	if s.codec == nil {
+		marshaller, unmarshaller = codecOf(s.sendState), codecOf(s.receiveState)
+	}
+	sendStateRaw, err := marshaller.Marshal(s.sendState)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +112,7 @@ func (s *sendReceiveView) Call(context view.Context) (interface{}, error) {
 			return nil, errors.New(string(msg.Payload))
 		}
 
-		err = s.coded.Unmarshal(msg.Payload, s.receiveState)
+		err = unmarshaller.Unmarshal(msg.Payload, s.receiveState)
 		if err != nil {
 			return nil, err
 		}
@@ -114,10 +127,15 @@ func NewSendReceiveView(sendState, receiveState interface{}, party view.Identity
 		sendState:    sendState,
 		receiveState: receiveState,
 		party:        party,
-		coded:        &JSONCodec{},
 	}
 }
 
+// WithCodec sets the codec the states are encoded and decoded with, e.g. the one registered for their namespace
+func (s *sendReceiveView) WithCodec(codec Codec) *sendReceiveView {
+	s.codec = codec
+	return s
+}
+
 type replyView struct {
 	state      interface{}
 	marshaller Marshaller
@@ -139,6 +157,13 @@ func (s *replyView) Call(context view.Context) (interface{}, error) {
 	return nil, nil
 }
 
+// NewReplyView returns a view that sends back the passed state, encoded with the codec the state selects
 func NewReplyView(state interface{}) *replyView {
-	return &replyView{state: state, marshaller: &JSONCodec{}}
+	return &replyView{state: state, marshaller: codecOf(state)}
+}
+
+// WithCodec sets the codec the state is encoded with, e.g. the one registered for its namespace
+func (s *replyView) WithCodec(codec Codec) *replyView {
+	s.marshaller = codec
+	return s
 }
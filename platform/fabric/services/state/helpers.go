@@ -11,6 +11,7 @@ import (
 	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
 )
 
 func GetWorldStateService(ctx view2.ServiceProvider) WorldStateService {
@@ -42,3 +43,11 @@ func GetWorldStateForChannel(ctx view2.ServiceProvider, channel string) WorldSta
 	}
 	return ws
 }
+
+func GetWorldStateForTx(ctx view2.ServiceProvider, tx *endorser.Transaction) WorldState {
+	ws, err := GetWorldStateService(ctx).GetWorldStateForTx(tx)
+	if err != nil {
+		panic(err)
+	}
+	return ws
+}
@@ -22,6 +22,8 @@ type wss struct {
 	network          string
 	channel          string
 	NewQueryExecutor NewQueryExecutorFunc
+	// rws is the read-write set of the transaction the world state is seen by, if any
+	rws *fabric.RWSet
 }
 
 func NewWorldState(sp view2.ServiceProvider, network, channel string, NewQueryExecutor func() (*fabric.QueryExecutor, error)) *wss {
@@ -33,14 +35,18 @@ func NewWorldState(sp view2.ServiceProvider, network, channel string, NewQueryEx
 	}
 }
 
-func (f *wss) GetState(namespace string, id string, state interface{}) error {
-	q, err := f.NewQueryExecutor()
-	if err != nil {
-		return errors.Wrap(err, "failed getting query executor")
+// NewWorldStateForRWSet returns the world state as seen by the transaction the passed read-write set belongs to
+func NewWorldStateForRWSet(sp view2.ServiceProvider, network, channel string, rws *fabric.RWSet) *wss {
+	return &wss{
+		sp:      sp,
+		network: network,
+		channel: channel,
+		rws:     rws,
 	}
-	defer q.Done()
+}
 
-	raw, err := q.GetState(namespace, id)
+func (f *wss) GetState(namespace string, id string, state interface{}) error {
+	raw, err := f.getState(namespace, id)
 	if err != nil {
 		return err
 	}
@@ -62,6 +68,15 @@ func (f *wss) GetStateByPartialCompositeID(ns string, prefix string, attrs []str
 	}
 	endKey := startKey + string(state.MaxUnicodeRuneValue)
 
+	if f.rws != nil {
+		// the range is recorded in the read-write set, to let the validation detect phantom reads
+		it, err := f.rws.GetStateRangeScanIterator(ns, startKey, endKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed getting state iterator")
+		}
+		return &ListStateQueryIteratorInterface{it: it}, nil
+	}
+
 	q, err := f.NewQueryExecutor()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed getting query executor")
@@ -75,6 +90,18 @@ func (f *wss) GetStateByPartialCompositeID(ns string, prefix string, attrs []str
 	return &ListStateQueryIteratorInterface{it: it}, nil
 }
 
+func (f *wss) getState(namespace string, id string) ([]byte, error) {
+	if f.rws != nil {
+		return f.rws.GetState(namespace, id)
+	}
+	q, err := f.NewQueryExecutor()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting query executor")
+	}
+	defer q.Done()
+	return q.GetState(namespace, id)
+}
+
 func (f *wss) GetStateCertification(namespace string, key string) ([]byte, error) {
 	_, tx, err := endorser.NewTransactionWith(
 		f.sp,
@@ -132,6 +159,14 @@ func (w *worldStateService) GetWorldState(network string, channel string) (state
 	), nil
 }
 
+func (w *worldStateService) GetWorldStateForTx(tx *endorser.Transaction) (state.WorldState, error) {
+	rws, err := tx.RWSet()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting rwset of transaction [%s]", tx.ID())
+	}
+	return NewWorldStateForRWSet(w.sp, tx.Network(), tx.Channel(), rws), nil
+}
+
 type ListStateQueryIteratorInterface struct {
 	it   *fabric.ResultsIterator
 	next *fabric.Read
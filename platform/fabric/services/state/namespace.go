@@ -51,8 +51,7 @@ type Namespace struct {
 
 func NewNamespace(tx *endorser.Transaction, forceSBE bool) *Namespace {
 	return &Namespace{
-		tx:    tx,
-		codec: &JSONCodec{},
+		tx: tx,
 		metaHandlers: []MetaHandler{
 			&sbeMetaHandler{forceSBE: forceSBE},
 			&contractMetaHandler{},
@@ -63,9 +62,8 @@ func NewNamespace(tx *endorser.Transaction, forceSBE bool) *Namespace {
 
 func NewNamespaceForName(tx *endorser.Transaction, ns string, forceSBE bool) *Namespace {
 	return &Namespace{
-		tx:    tx,
-		ns:    ns,
-		codec: &JSONCodec{},
+		tx: tx,
+		ns: ns,
 		metaHandlers: []MetaHandler{
 			&sbeMetaHandler{forceSBE: forceSBE},
 			&contractMetaHandler{},
@@ -89,6 +87,12 @@ func (n *Namespace) Present() bool {
 	return false
 }
 
+// SetCodec sets the codec used to encode and decode the states of this namespace.
+// If no codec is set, the codec registered for the namespace via RegisterCodec is used.
+func (n *Namespace) SetCodec(codec Codec) {
+	n.codec = codec
+}
+
 func (n *Namespace) SetNamespace(ns string) {
 	n.tx.SetProposal(ns, "Version-0.0", "_state")
 }
@@ -151,7 +155,7 @@ func (n *Namespace) AddInputByLinearID(id string, state interface{}, opts ...Add
 	}
 
 	logger.Debugf("AddInputByLinearID [%ss,%s] [%s]", n.namespace(), id, base64.StdEncoding.EncodeToString(raw))
-	err = n.codec.Unmarshal(raw, state)
+	err = n.codecFor(state).Unmarshal(raw, state)
 	if err != nil {
 		return errors.Wrapf(err, "failed unmarshalling state [%s, %s] [%s]", n.namespace(), id, string(raw))
 	}
@@ -208,7 +212,7 @@ func (n *Namespace) AddOutput(st interface{}, opts ...AddOutputOption) error {
 	}
 
 	// Encode
-	raw, err := n.codec.Marshal(st)
+	raw, err := n.codecFor(st).Marshal(st)
 	if err != nil {
 		return err
 	}
@@ -272,7 +276,7 @@ func (n *Namespace) GetOutputAt(index int, state interface{}) error {
 	}
 
 	logger.Debugf("GetOutputAt [%s,%d] [%s]", n.namespace(), index, string(raw))
-	err = n.codec.Unmarshal(raw, state)
+	err = n.codecFor(state).Unmarshal(raw, state)
 	if err != nil {
 		return errors.Wrapf(err, "failed unmarshalling state [%s, %d] [%s]", n.namespace(), index, string(raw))
 	}
@@ -319,7 +323,7 @@ func (n *Namespace) GetInputAt(index int, state interface{}) error {
 	}
 
 	logger.Debugf("GetInputAt [%s,%d] [%s]", n.namespace(), index, string(raw))
-	err = n.codec.Unmarshal(raw, state)
+	err = n.codecFor(state).Unmarshal(raw, state)
 	if err != nil {
 		return errors.Wrapf(err, "failed unmarshalling state [%s, %d] [%s]", n.namespace(), index, string(raw))
 	}
@@ -479,6 +483,18 @@ func (n *Namespace) setMeta(s interface{}, namespace string, key string, options
 	return nil
 }
 
+func (n *Namespace) codecFor(s interface{}) Codec {
+	if p, ok := s.(CodecProvider); ok {
+		if codec := p.Codec(); codec != nil {
+			return codec
+		}
+	}
+	if n.codec != nil {
+		return n.codec
+	}
+	return GetCodec(n.namespace())
+}
+
 func (n *Namespace) namespace() string {
 	if len(n.ns) == 0 {
 		chaincode, _ := n.tx.Chaincode()
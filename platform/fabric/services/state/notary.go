@@ -0,0 +1,314 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/core/generic/msp/x509"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const (
+	// NotariesConfigKey is the configuration key listing the notaries this node trusts
+	NotariesConfigKey = "fabric.state.certification.notaries"
+	// CertificationTypesConfigKey is the configuration key listing the certification types this node accepts.
+	// When it is not set, any registered certification type is accepted.
+	CertificationTypesConfigKey = "fabric.state.certification.types"
+)
+
+// NotaryConfig describes a trusted notary in the local configuration
+type NotaryConfig struct {
+	// Name is the label under which the endpoint service knows the FSC node of the notary
+	Name string `yaml:"name"`
+	// MSPID is the MSP of the Fabric identity the notary signs its certifications with
+	MSPID string `yaml:"mspID"`
+	// Cert is the path of the PEM certificate of the Fabric identity the notary signs its certifications with
+	Cert string `yaml:"cert"`
+}
+
+// NotaryCertificationRequest is sent to a notary to ask the certification of a state
+type NotaryCertificationRequest struct {
+	Network   string
+	Channel   string
+	Namespace string
+	Key       string
+	TxID      string
+}
+
+// NotaryCertificate is the certification of a state released by a notary
+type NotaryCertificate struct {
+	Notary    view.Identity
+	Value     []byte
+	Signature []byte
+}
+
+// message returns the message signed by the notary to certify the passed value
+func (r *NotaryCertificationRequest) message(value []byte) ([]byte, error) {
+	return json.Marshal(&struct {
+		Request *NotaryCertificationRequest
+		Value   []byte
+	}{Request: r, Value: value})
+}
+
+type trustedNotary struct {
+	name   string
+	signer view.Identity
+}
+
+// trustedNotaries returns the notaries listed in the local configuration.
+// Identities supplied by the transaction are never trusted.
+func trustedNotaries(sp view2.ServiceProvider) ([]*trustedNotary, error) {
+	cs := view2.GetConfigService(sp)
+	if !cs.IsSet(NotariesConfigKey) {
+		return nil, errors.Errorf("no trusted notaries configured at [%s]", NotariesConfigKey)
+	}
+	var configs []*NotaryConfig
+	if err := cs.UnmarshalKey(NotariesConfigKey, &configs); err != nil {
+		return nil, errors.Wrapf(err, "failed loading trusted notaries")
+	}
+	var res []*trustedNotary
+	for _, c := range configs {
+		signer, err := x509.Serialize(c.MSPID, cs.TranslatePath(c.Cert))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed loading identity of notary [%s]", c.Name)
+		}
+		res = append(res, &trustedNotary{name: c.Name, signer: signer})
+	}
+	if len(res) == 0 {
+		return nil, errors.Errorf("no trusted notaries configured at [%s]", NotariesConfigKey)
+	}
+	return res, nil
+}
+
+// checkCertificationType checks that the local configuration accepts the passed certification type
+func checkCertificationType(sp view2.ServiceProvider, typ string) error {
+	cs := view2.GetConfigService(sp)
+	if !cs.IsSet(CertificationTypesConfigKey) {
+		return nil
+	}
+	for _, accepted := range cs.GetStringSlice(CertificationTypesConfigKey) {
+		if accepted == typ {
+			return nil
+		}
+	}
+	return errors.Errorf("certification type [%s] not accepted by this node", typ)
+}
+
+// notaryCertifier certifies states by asking a trusted FSC node, the notary, to sign the value stored in its vault.
+// The notaries are taken from the local configuration. The parameters of the certification type, if set,
+// select by name which of the trusted notaries to ask.
+type notaryCertifier struct{}
+
+func (n *notaryCertifier) Certify(tx *endorser.Transaction, namespace string, key string, params []byte) ([]byte, error) {
+	notaries, err := trustedNotaries(tx.ServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	notary := notaries[0]
+	if len(params) != 0 {
+		notary = nil
+		for _, trusted := range notaries {
+			if trusted.name == string(params) {
+				notary = trusted
+				break
+			}
+		}
+		if notary == nil {
+			return nil, errors.Errorf("notary [%s] is not trusted", string(params))
+		}
+	}
+	node, err := view2.GetEndpointService(tx.ServiceProvider).GetIdentity(notary.name, nil)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed resolving notary [%s]", notary.name)
+	}
+	request := &NotaryCertificationRequest{
+		Network:   tx.Network(),
+		Channel:   tx.Channel(),
+		Namespace: namespace,
+		Key:       key,
+		TxID:      tx.ID(),
+	}
+	certification, err := view2.GetManager(tx.ServiceProvider).InitiateView(
+		NewNotaryCertificationView(node, request),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed asking certification of [%s:%s] to notary", namespace, key)
+	}
+	raw, ok := certification.([]byte)
+	if !ok {
+		return nil, errors.Errorf("invalid certification of [%s:%s], expected bytes, got [%T]", namespace, key, certification)
+	}
+	return raw, nil
+}
+
+// Verify ignores the passed parameters, the certification must be signed by one of the trusted notaries
+func (n *notaryCertifier) Verify(tx *endorser.Transaction, namespace string, key string, params []byte, certification []byte) ([]byte, error) {
+	cert := &NotaryCertificate{}
+	if err := json.Unmarshal(certification, cert); err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling certification of [%s:%s]", namespace, key)
+	}
+	notaries, err := trustedNotaries(tx.ServiceProvider)
+	if err != nil {
+		return nil, err
+	}
+	var notary *trustedNotary
+	for _, trusted := range notaries {
+		if trusted.signer.Equal(cert.Notary) {
+			notary = trusted
+			break
+		}
+	}
+	if notary == nil {
+		return nil, errors.Errorf("certification of [%s:%s] not signed by a trusted notary", namespace, key)
+	}
+
+	request := &NotaryCertificationRequest{
+		Network:   tx.Network(),
+		Channel:   tx.Channel(),
+		Namespace: namespace,
+		Key:       key,
+		TxID:      tx.ID(),
+	}
+	msg, err := request.message(cert.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed marshalling certification message of [%s:%s]", namespace, key)
+	}
+	ch, err := fabric.GetFabricNetworkService(tx.ServiceProvider, tx.Network()).Channel(tx.Channel())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting channel [%s:%s]", tx.Network(), tx.Channel())
+	}
+	verifier, err := ch.MSPManager().GetVerifier(notary.signer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting verifier for notary [%s]", notary.name)
+	}
+	if err := verifier.Verify(msg, cert.Signature); err != nil {
+		return nil, errors.Wrapf(err, "invalid notary signature on certification of [%s:%s]", namespace, key)
+	}
+	return cert.Value, nil
+}
+
+type notaryCertificationView struct {
+	notary  view.Identity
+	request *NotaryCertificationRequest
+}
+
+// NewNotaryCertificationView returns a view that asks the passed notary to certify a state.
+// The notary must register NotaryCertificationResponderView as responder of this view.
+func NewNotaryCertificationView(notary view.Identity, request *NotaryCertificationRequest) *notaryCertificationView {
+	return &notaryCertificationView{notary: notary, request: request}
+}
+
+func (n *notaryCertificationView) Call(context view.Context) (interface{}, error) {
+	session, err := context.GetSession(context.Initiator(), n.notary)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting session with notary [%s]", n.notary)
+	}
+	ch := session.Receive()
+
+	raw, err := json.Marshal(n.request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling certification request")
+	}
+	if err := session.Send(raw); err != nil {
+		return nil, errors.Wrap(err, "failed sending certification request")
+	}
+
+	select {
+	case msg := <-ch:
+		if msg.Status == view.ERROR {
+			return nil, errors.New(string(msg.Payload))
+		}
+		return msg.Payload, nil
+	case <-time.After(30 * time.Second):
+		return nil, errors.New("timeout reading from session")
+	}
+}
+
+// NotaryCertificationResponderView is run by a notary to certify the states stored in its vault
+type NotaryCertificationResponderView struct{}
+
+func (n *NotaryCertificationResponderView) Call(context view.Context) (interface{}, error) {
+	session := context.Session()
+	received := session.Receive()
+
+	var msg *view.Message
+	select {
+	case msg = <-received:
+		if msg.Status == view.ERROR {
+			return nil, errors.New(string(msg.Payload))
+		}
+	case <-time.After(30 * time.Second):
+		return nil, errors.New("timeout reading from session")
+	}
+
+	raw, err := n.certify(context, msg.Payload)
+	if err != nil {
+		// let the initiator know why, instead of waiting for its timeout
+		if err1 := session.SendError([]byte(err.Error())); err1 != nil {
+			logger.Errorf("failed sending error [%s]: [%s]", err, err1)
+		}
+		return nil, err
+	}
+	if err := session.Send(raw); err != nil {
+		return nil, errors.Wrap(err, "failed sending certification")
+	}
+	return nil, nil
+}
+
+// certify returns the marshalled NotaryCertificate of the state the passed request asks for
+func (n *NotaryCertificationResponderView) certify(context view.Context, payload []byte) ([]byte, error) {
+	request := &NotaryCertificationRequest{}
+	if err := json.Unmarshal(payload, request); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshalling certification request")
+	}
+
+	fns, err := fabric.FabricNetworkService(context, request.Network)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unknown network [%s]", request.Network)
+	}
+	ch, err := fns.Channel(request.Channel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting channel [%s:%s]", request.Network, request.Channel)
+	}
+	qe, err := ch.Vault().NewQueryExecutor()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting query executor")
+	}
+	value, err := qe.GetState(request.Namespace, request.Key)
+	qe.Done()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting state [%s:%s]", request.Namespace, request.Key)
+	}
+	if len(value) == 0 {
+		return nil, errors.Errorf("state [%s:%s] not found", request.Namespace, request.Key)
+	}
+
+	sigma, err := request.message(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling certification message")
+	}
+	signer := fns.LocalMembership().DefaultSigningIdentity()
+	notary, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed serializing notary identity")
+	}
+	sigma, err = signer.Sign(sigma)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed signing certification")
+	}
+	raw, err := json.Marshal(&NotaryCertificate{Notary: notary, Value: value, Signature: sigma})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshalling certification")
+	}
+	return raw, nil
+}
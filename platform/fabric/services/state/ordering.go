@@ -6,10 +6,64 @@ SPDX-License-Identifier: Apache-2.0
 package state
 
 import (
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
+type orderingView struct {
+	tx *Transaction
+}
+
+func (o *orderingView) Call(context view.Context) (interface{}, error) {
+	if err := o.tx.CheckEndorsementPolicies(); err != nil {
+		return nil, errors.Wrapf(err, "transaction [%s] does not satisfy the state-based endorsement policies", o.tx.ID())
+	}
+	return context.RunView(endorser.NewOrderingView(o.tx.tx))
+}
+
+// NewOrderingView returns a view that checks locally the state-based endorsement policies of the transaction
+// and then submits it to the ordering service
 func NewOrderingView(tx *Transaction) view.View {
-	return endorser.NewOrderingView(tx.tx)
+	return &orderingView{tx: tx}
+}
+
+// CheckEndorsementPolicies checks that the endorsements collected so far satisfy the key-level endorsement policies
+// of the committed states this transaction writes. This way, transactions that would be invalidated by the committing
+// peers fail before being ordered.
+// Keys with no key-level endorsement policy are subject to the chaincode endorsement policy and are not checked.
+func (t *Transaction) CheckEndorsementPolicies() error {
+	rws, err := t.Namespace.RWSet()
+	if err != nil {
+		return errors.Wrap(err, "failed getting rw set")
+	}
+	ch, err := t.FabricNetworkService().Channel(t.Channel())
+	if err != nil {
+		return errors.Wrapf(err, "failed getting channel [%s]", t.Channel())
+	}
+	responses := t.Transaction.Transaction.ProposalResponses()
+
+	for _, ns := range rws.Namespaces() {
+		for i := 0; i < rws.NumWrites(ns); i++ {
+			key, _, err := rws.GetWriteAt(ns, i)
+			if err != nil {
+				return errors.Wrapf(err, "failed getting write [%s:%d]", ns, i)
+			}
+			meta, err := rws.GetStateMetadata(ns, key, fabric.FromStorage)
+			if err != nil {
+				return errors.Wrapf(err, "failed getting metadata [%s:%s]", ns, key)
+			}
+			policy, ok := meta[peer.MetaDataKeys_VALIDATION_PARAMETER.String()]
+			if !ok || len(policy) == 0 {
+				continue
+			}
+			if err := ch.MSPManager().EvaluateEndorsementPolicy(policy, responses); err != nil {
+				return errors.Wrapf(err, "endorsement policy of [%s:%s] not satisfied", ns, key)
+			}
+		}
+	}
+	return nil
 }
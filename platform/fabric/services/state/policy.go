@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// PolicyProvider can be implemented by a state to declare its own key-level endorsement policy.
+// When a state implements PolicyProvider, the returned policy replaces the default one
+// requiring the signatures of all the owners of the state.
+type PolicyProvider interface {
+	// EndorsementPolicy returns the key-level endorsement policy of this state
+	EndorsementPolicy() (*Policy, error)
+}
+
+// Policy is a composable key-level endorsement policy.
+// An invalid policy carries the error that made it so, returned by Envelope and Bytes,
+// and passed along by the policies that combine it.
+type Policy struct {
+	rule       *common.SignaturePolicy
+	principals []*msp.MSPPrincipal
+	err        error
+}
+
+// PolicyFromString parses a policy expressed in the Fabric policy language,
+// for example "AND('Org1MSP.member', OutOf(1, 'Org2MSP.peer', 'Org3MSP.admin'))".
+// This can be used to combine the chaincode endorsement policy with the owners of a state.
+func PolicyFromString(policy string) (*Policy, error) {
+	spe, err := policydsl.FromString(policy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing policy [%s]", policy)
+	}
+	return &Policy{rule: spe.Rule, principals: spe.Identities}, nil
+}
+
+// SignedByIdentity requires the signature of the passed identity
+func SignedByIdentity(id view.Identity) *Policy {
+	if id.IsNone() {
+		return invalidPolicy(errors.New("no identity specified"))
+	}
+	return signedByPrincipal(&msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_IDENTITY,
+		Principal:               id,
+	})
+}
+
+// SignedByRole requires the signature of an identity with the passed role in the passed MSP
+func SignedByRole(mspID string, role msp.MSPRole_MSPRoleType) *Policy {
+	if len(mspID) == 0 {
+		return invalidPolicy(errors.New("no msp id specified"))
+	}
+	if _, ok := msp.MSPRole_MSPRoleType_name[int32(role)]; !ok {
+		return invalidPolicy(errors.Errorf("invalid role [%d]", role))
+	}
+	principal, err := proto.Marshal(&msp.MSPRole{MspIdentifier: mspID, Role: role})
+	if err != nil {
+		return invalidPolicy(errors.Wrap(err, "failed marshalling msp role"))
+	}
+	return signedByPrincipal(&msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               principal,
+	})
+}
+
+// SignedByOU requires the signature of an identity belonging to the passed organizational unit of the passed MSP
+func SignedByOU(mspID string, ou string) *Policy {
+	if len(mspID) == 0 || len(ou) == 0 {
+		return invalidPolicy(errors.New("msp id and organizational unit must be specified"))
+	}
+	principal, err := proto.Marshal(&msp.OrganizationUnit{MspIdentifier: mspID, OrganizationalUnitIdentifier: ou})
+	if err != nil {
+		return invalidPolicy(errors.Wrap(err, "failed marshalling organization unit"))
+	}
+	return signedByPrincipal(&msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+		Principal:               principal,
+	})
+}
+
+// SignedByOwners requires the signature of n out of the passed owners
+func SignedByOwners(n int, owners Identities) *Policy {
+	var policies []*Policy
+	for _, owner := range owners {
+		policies = append(policies, SignedByIdentity(owner))
+	}
+	return NOutOf(n, policies...)
+}
+
+// AllOf requires all the passed policies to be satisfied
+func AllOf(policies ...*Policy) *Policy {
+	return NOutOf(len(policies), policies...)
+}
+
+// AnyOf requires at least one of the passed policies to be satisfied
+func AnyOf(policies ...*Policy) *Policy {
+	return NOutOf(1, policies...)
+}
+
+// NOutOf requires n out of the passed policies to be satisfied
+func NOutOf(n int, policies ...*Policy) *Policy {
+	if n <= 0 || n > len(policies) {
+		return invalidPolicy(errors.Errorf("invalid threshold [%d] for [%d] policies", n, len(policies)))
+	}
+	res := &Policy{}
+	var rules []*common.SignaturePolicy
+	for i, p := range policies {
+		if p == nil {
+			return invalidPolicy(errors.Errorf("policy [%d] is nil", i))
+		}
+		if p.err != nil {
+			return invalidPolicy(p.err)
+		}
+		rule, err := shiftRule(p.rule, int32(len(res.principals)))
+		if err != nil {
+			return invalidPolicy(err)
+		}
+		rules = append(rules, rule)
+		res.principals = append(res.principals, p.principals...)
+	}
+	res.rule = policydsl.NOutOf(int32(n), rules)
+	return res
+}
+
+// Envelope returns the SignaturePolicyEnvelope corresponding to this policy
+func (p *Policy) Envelope() (*common.SignaturePolicyEnvelope, error) {
+	if p == nil {
+		return nil, errors.New("nil policy")
+	}
+	if p.err != nil {
+		return nil, errors.WithMessage(p.err, "invalid policy")
+	}
+	return &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       p.rule,
+		Identities: p.principals,
+	}, nil
+}
+
+// Bytes returns the serialized SignaturePolicyEnvelope corresponding to this policy
+func (p *Policy) Bytes() ([]byte, error) {
+	spe, err := p.Envelope()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(spe)
+}
+
+func signedByPrincipal(principal *msp.MSPPrincipal) *Policy {
+	return &Policy{
+		rule:       policydsl.SignedBy(0),
+		principals: []*msp.MSPPrincipal{principal},
+	}
+}
+
+func invalidPolicy(err error) *Policy {
+	return &Policy{err: err}
+}
+
+// shiftRule returns a copy of the passed rule whose principal indexes are shifted by the passed offset
+func shiftRule(rule *common.SignaturePolicy, offset int32) (*common.SignaturePolicy, error) {
+	switch t := rule.GetType().(type) {
+	case *common.SignaturePolicy_SignedBy:
+		return policydsl.SignedBy(t.SignedBy + offset), nil
+	case *common.SignaturePolicy_NOutOf_:
+		var rules []*common.SignaturePolicy
+		for _, r := range t.NOutOf.Rules {
+			shifted, err := shiftRule(r, offset)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, shifted)
+		}
+		return policydsl.NOutOf(t.NOutOf.N, rules), nil
+	default:
+		return nil, errors.Errorf("unknown signature policy type [%T]", t)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/test-go/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+func TestPolicyComposition(t *testing.T) {
+	cc, err := PolicyFromString("OR('Org1MSP.peer', 'Org2MSP.peer')")
+	assert.NoError(t, err)
+
+	owners := Identities{view.Identity("alice"), view.Identity("bob"), view.Identity("charlie")}
+	p := AllOf(cc, SignedByOwners(2, owners), SignedByOU("Org1MSP", "auditors"))
+	spe, err := p.Envelope()
+	assert.NoError(t, err)
+
+	// 2 principals from the chaincode policy, 3 owners, 1 OU
+	assert.Len(t, spe.Identities, 6)
+	assert.Equal(t, msp.MSPPrincipal_IDENTITY, spe.Identities[2].PrincipalClassification)
+	assert.Equal(t, []byte("alice"), spe.Identities[2].Principal)
+	assert.Equal(t, msp.MSPPrincipal_ORGANIZATION_UNIT, spe.Identities[5].PrincipalClassification)
+
+	rules := spe.Rule.GetNOutOf()
+	assert.Equal(t, int32(3), rules.N)
+	assert.Len(t, rules.Rules, 3)
+
+	// the owners' rule must refer to the shifted principals
+	ownersRule := rules.Rules[1].GetNOutOf()
+	assert.Equal(t, int32(2), ownersRule.N)
+	var indexes []int32
+	for _, r := range ownersRule.Rules {
+		indexes = append(indexes, r.Type.(*common.SignaturePolicy_SignedBy).SignedBy)
+	}
+	assert.Equal(t, []int32{2, 3, 4}, indexes)
+	assert.Equal(t, int32(5), rules.Rules[2].GetSignedBy())
+
+	_, err = p.Bytes()
+	assert.NoError(t, err)
+}
+
+type house2 struct {
+	House
+}
+
+func (h *house2) EndorsementPolicy() (*Policy, error) {
+	return SignedByRole("Org1MSP", msp.MSPRole_ADMIN), nil
+}
+
+func TestSBEPolicyProvider(t *testing.T) {
+	h := &sbeMetaHandler{}
+
+	// Ownable states require all the owners
+	raw, err := h.policy(&House{Owner: []byte("alice")})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, raw)
+
+	// PolicyProvider takes precedence
+	raw2, err := h.policy(&house2{House: House{Owner: []byte("alice")}})
+	assert.NoError(t, err)
+	expected, err := SignedByRole("Org1MSP", msp.MSPRole_ADMIN).Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, raw2)
+
+	// Other states have no policy
+	raw, err = h.policy(&Asset{})
+	assert.NoError(t, err)
+	assert.Empty(t, raw)
+}
+
+type house3 struct {
+	House
+	policy *Policy
+}
+
+func (h *house3) EndorsementPolicy() (*Policy, error) {
+	return h.policy, nil
+}
+
+func TestInvalidPolicies(t *testing.T) {
+	for _, p := range []*Policy{
+		SignedByRole("", msp.MSPRole_ADMIN),
+		SignedByRole("Org1MSP", msp.MSPRole_MSPRoleType(42)),
+		SignedByOU("Org1MSP", ""),
+		SignedByIdentity(nil),
+		NOutOf(2, SignedByRole("Org1MSP", msp.MSPRole_ADMIN)),
+		AnyOf(),
+		AllOf(SignedByRole("Org1MSP", msp.MSPRole_ADMIN), nil),
+		// errors propagate through the combinations
+		AnyOf(SignedByRole("Org1MSP", msp.MSPRole_ADMIN), AllOf(SignedByOU("", "auditors"))),
+		AnyOf(&Policy{principals: []*msp.MSPPrincipal{{}}}),
+	} {
+		_, err := p.Bytes()
+		assert.Error(t, err)
+	}
+
+	// a nil policy is reported, not dereferenced
+	h := &sbeMetaHandler{}
+	_, err := h.policy(&house3{})
+	assert.Error(t, err)
+	_, err = h.policy(&house3{policy: SignedByOU("", "")})
+	assert.Error(t, err)
+}
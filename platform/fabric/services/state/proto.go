@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ProtoCodec encodes states that are protobuf messages.
+// States implementing Serializable and State are encoded using their own methods.
+type ProtoCodec struct{}
+
+func (p *ProtoCodec) Marshal(v interface{}) ([]byte, error) {
+	if s, ok := v.(Serializable); ok {
+		return s.Bytes()
+	}
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, errors.Errorf("cannot marshal [%T], not a proto message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (p *ProtoCodec) Unmarshal(data []byte, v interface{}) error {
+	if s, ok := v.(State); ok {
+		return s.SetFromBytes(data)
+	}
+	m, ok := v.(proto.Message)
+	if !ok {
+		return errors.Errorf("cannot unmarshal into [%T], not a proto message", v)
+	}
+	return proto.Unmarshal(data, m)
+}
@@ -33,34 +33,24 @@ func (s2 *sbeMetaHandler) StoreMeta(ns *Namespace, s interface{}, namespace stri
 		return nil
 	}
 
-	os, ok := s.(Ownable)
-	if !ok {
-		// Nothing to set
-		return nil
-	}
-
-	// parse policy
-	ep, err := newStateEP(nil)
+	policyBytes, err := s2.policy(s)
 	if err != nil {
-		return errors.Wrap(err, "failed creating new EP state")
-	}
-	for _, owner := range os.Owners() {
-		ep.addOwner(owner)
+		return err
 	}
-	policyBytes, err := ep.Policy()
-	if err != nil {
-		return errors.Wrap(err, "failed creating policy")
+	if len(policyBytes) == 0 {
+		// Nothing to set
+		return nil
 	}
 
 	// update meta
 	rws, err := ns.RWSet()
 	if err != nil {
-		return errors.Wrap(err, "filed getting rw set")
+		return errors.Wrap(err, "failed getting rw set")
 	}
 
 	meta, err := rws.GetStateMetadata(namespace, key, fabric.FromIntermediate)
 	if err != nil {
-		return errors.Wrap(err, "filed getting metadata")
+		return errors.Wrap(err, "failed getting metadata")
 	}
 	if len(meta) == 0 {
 		meta = map[string][]byte{}
@@ -74,6 +64,42 @@ func (s2 *sbeMetaHandler) StoreMeta(ns *Namespace, s interface{}, namespace stri
 	return nil
 }
 
+// policy returns the key-level endorsement policy of the passed state.
+// States implementing PolicyProvider declare their own policy, Ownable states require the signatures of all the owners.
+func (s2 *sbeMetaHandler) policy(s interface{}) ([]byte, error) {
+	if pp, ok := s.(PolicyProvider); ok {
+		policy, err := pp.EndorsementPolicy()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed getting endorsement policy")
+		}
+		if policy == nil {
+			return nil, errors.Errorf("state [%T] returned no endorsement policy", s)
+		}
+		policyBytes, err := policy.Bytes()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed marshalling policy")
+		}
+		return policyBytes, nil
+	}
+
+	os, ok := s.(Ownable)
+	if !ok {
+		return nil, nil
+	}
+	ep, err := newStateEP(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating new EP state")
+	}
+	for _, owner := range os.Owners() {
+		ep.addOwner(owner)
+	}
+	policyBytes, err := ep.Policy()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating policy")
+	}
+	return policyBytes, nil
+}
+
 // stateEP implements the KeyEndorsementPolicy
 type stateEP struct {
 	orgs       map[string]msp.MSPRole_MSPRoleType
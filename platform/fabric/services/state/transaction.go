@@ -139,7 +139,7 @@ func (f *sendTransactionView) Call(context view.Context) (interface{}, error) {
 			continue
 		}
 
-		txRaw, err := f.tx.Bytes()
+		txRaw, err := f.tx.BytesFor(party)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed marshalling transaction content")
 		}
@@ -167,13 +167,12 @@ func NewSendTransactionBackView(tx *Transaction) *sendTransactionBackView {
 }
 
 func (f *sendTransactionBackView) Call(context view.Context) (interface{}, error) {
-	txRaw, err := f.tx.Bytes()
+	session := context.Session()
+	txRaw, err := f.tx.BytesFor(session.Info().Caller)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed marshalling transaction content")
 	}
 
-	session := context.Session()
-
 	// Send transaction
 	err = session.Send(txRaw)
 	if err != nil {
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// versionTag prefixes the values encoded by a VersionedCodec. It is followed by the length of the schema version,
+// the schema version as uvarint, and a check byte over the header.
+// The leading zero byte cannot start a value encoded by the codecs of this package: it is the reserved field
+// number 0 in protobuf, it is not valid JSON, and in CBOR it is the whole encoding of the integer 0.
+var versionTag = []byte{0x00, 0xf5, 0xc5}
+
+// Migration upgrades the payload of a state encoded with schema version `from` to schema version `from+1`.
+type Migration func(raw []byte) ([]byte, error)
+
+// VersionedCodec wraps a codec and embeds the schema version of the states in the stored values.
+// When a value stored with an older schema version is unmarshalled, the registered migrations are applied
+// in sequence to bring the payload to the current version before passing it to the wrapped codec.
+// Values without a version tag are considered to be at version 0.
+type VersionedCodec struct {
+	Codec      Codec
+	Version    uint64
+	migrations map[uint64]Migration
+}
+
+func NewVersionedCodec(codec Codec, version uint64) *VersionedCodec {
+	return &VersionedCodec{
+		Codec:      codec,
+		Version:    version,
+		migrations: map[uint64]Migration{},
+	}
+}
+
+// AddMigration registers the migration to upgrade payloads from schema version `from` to `from+1`.
+func (v *VersionedCodec) AddMigration(from uint64, migration Migration) *VersionedCodec {
+	if v.migrations == nil {
+		v.migrations = map[uint64]Migration{}
+	}
+	v.migrations[from] = migration
+	return v
+}
+
+func (v *VersionedCodec) Marshal(s interface{}) ([]byte, error) {
+	raw, err := v.Codec.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(versionTag)+1+binary.MaxVarintLen64+1)
+	copy(header, versionTag)
+	n := binary.PutUvarint(header[len(versionTag)+1:], v.Version)
+	header[len(versionTag)] = byte(n)
+	end := len(versionTag) + 1 + n
+	header[end] = checkByte(header[:end])
+	return append(header[:end+1], raw...), nil
+}
+
+func (v *VersionedCodec) Unmarshal(data []byte, s interface{}) error {
+	version, payload, err := SplitVersion(data)
+	if err != nil {
+		return err
+	}
+	if version > v.Version {
+		return errors.Errorf("state version [%d] is newer than supported version [%d]", version, v.Version)
+	}
+	for ; version < v.Version; version++ {
+		migration, ok := v.migrations[version]
+		if !ok {
+			return errors.Errorf("no migration registered from version [%d]", version)
+		}
+		payload, err = migration(payload)
+		if err != nil {
+			return errors.Wrapf(err, "failed migrating state from version [%d]", version)
+		}
+	}
+	return v.Codec.Unmarshal(payload, s)
+}
+
+// SplitVersion returns the schema version and the payload of a value encoded by a VersionedCodec.
+// Values without the version tag are at version 0, values with a malformed header are rejected.
+func SplitVersion(data []byte) (uint64, []byte, error) {
+	if !bytes.HasPrefix(data, versionTag) {
+		return 0, data, nil
+	}
+	if len(data) < len(versionTag)+1 {
+		return 0, nil, errors.New("invalid state version tag, header truncated")
+	}
+	l := int(data[len(versionTag)])
+	end := len(versionTag) + 1 + l
+	if l == 0 || l > binary.MaxVarintLen64 || len(data) < end+1 {
+		return 0, nil, errors.New("invalid state version tag, invalid length")
+	}
+	version, n := binary.Uvarint(data[len(versionTag)+1 : end])
+	if n != l {
+		return 0, nil, errors.New("invalid state version tag, invalid version")
+	}
+	if data[end] != checkByte(data[:end]) {
+		return 0, nil, errors.New("invalid state version tag, check byte mismatch")
+	}
+	return version, data[end+1:], nil
+}
+
+// checkByte returns the check byte of the passed header
+func checkByte(header []byte) byte {
+	c := byte(0xa5)
+	for _, b := range header {
+		c = c<<1 | c>>7
+		c ^= b
+	}
+	return c
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package state
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/test-go/testify/assert"
+)
+
+type HouseV0 struct {
+	Address  string
+	Value    uint64
+	LinearID string
+}
+
+func TestVersionedCodec(t *testing.T) {
+	h := &House{
+		Address:   "Universe Drive",
+		Valuation: 1000,
+		LinearID:  "An ID",
+		Owner:     []byte("Apple"),
+	}
+	codec := NewVersionedCodec(&JSONCodec{}, 1)
+	raw, err := codec.Marshal(h)
+	assert.NoError(t, err)
+
+	version, _, err := SplitVersion(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), version)
+
+	h2 := &House{}
+	assert.NoError(t, codec.Unmarshal(raw, h2))
+	assert.Equal(t, h, h2)
+
+	// states written with a newer schema cannot be read
+	raw, err = NewVersionedCodec(&JSONCodec{}, 2).Marshal(h)
+	assert.NoError(t, err)
+	assert.Error(t, codec.Unmarshal(raw, h2))
+}
+
+func TestVersionedCodecMigration(t *testing.T) {
+	// legacy values have no version tag
+	raw, err := json.Marshal(&HouseV0{Address: "Universe Drive", Value: 1000, LinearID: "An ID"})
+	assert.NoError(t, err)
+
+	codec := NewVersionedCodec(&JSONCodec{}, 1)
+	h := &House{}
+	assert.Error(t, codec.Unmarshal(raw, h))
+
+	codec.AddMigration(0, func(raw []byte) ([]byte, error) {
+		v0 := &HouseV0{}
+		if err := json.Unmarshal(raw, v0); err != nil {
+			return nil, err
+		}
+		return json.Marshal(&House{Address: v0.Address, Valuation: v0.Value, LinearID: v0.LinearID})
+	})
+	assert.NoError(t, codec.Unmarshal(raw, h))
+	assert.Equal(t, &House{Address: "Universe Drive", Valuation: 1000, LinearID: "An ID"}, h)
+}
+
+func TestVersionedCodecProto(t *testing.T) {
+	// legacy protobuf values are not mistaken for tagged ones, whatever their first field
+	for _, field := range []uint64{30, 3166} {
+		b := proto.NewBuffer(nil)
+		assert.NoError(t, b.EncodeVarint(field<<3|proto.WireFixed32))
+		assert.NoError(t, b.EncodeFixed32(42))
+		assert.NoError(t, b.EncodeVarint(1<<3|proto.WireBytes))
+		assert.NoError(t, b.EncodeRawBytes([]byte("payload")))
+		raw := b.Bytes()
+		assert.Equal(t, byte(0xf5), raw[0])
+
+		version, payload, err := SplitVersion(raw)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), version)
+		assert.Equal(t, raw, payload)
+
+		env := &common.Envelope{}
+		assert.NoError(t, NewVersionedCodec(&ProtoCodec{}, 0).Unmarshal(raw, env))
+		assert.Equal(t, []byte("payload"), env.Payload)
+	}
+
+	codec := NewVersionedCodec(&ProtoCodec{}, 1)
+	raw, err := codec.Marshal(&common.Envelope{Payload: []byte("payload")})
+	assert.NoError(t, err)
+	env := &common.Envelope{}
+	assert.NoError(t, codec.Unmarshal(raw, env))
+	assert.Equal(t, []byte("payload"), env.Payload)
+
+	// a corrupted header is rejected
+	raw[len(versionTag)+1]++
+	_, _, err = SplitVersion(raw)
+	assert.Error(t, err)
+}
+
+func TestVersionedCodecZeroValue(t *testing.T) {
+	codec := &VersionedCodec{Codec: &JSONCodec{}, Version: 1}
+	codec.AddMigration(0, func(raw []byte) ([]byte, error) { return raw, nil })
+	h := &House{}
+	assert.NoError(t, codec.Unmarshal([]byte(`{"Address":"Universe Drive"}`), h))
+	assert.Equal(t, "Universe Drive", h.Address)
+}
+
+type Bond struct {
+	ID        string
+	Principal *big.Int
+	Coupon    []byte
+	Holders   map[string]uint64
+}
+
+func TestCBORCodec(t *testing.T) {
+	principal, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.True(t, ok)
+	b := &Bond{ID: "bond", Principal: principal, Coupon: []byte{0, 1, 2}, Holders: map[string]uint64{"alice": 1, "bob": 2, "charlie": 3}}
+
+	codec := NewVersionedCodec(&CBORCodec{}, 1)
+	raw, err := codec.Marshal(b)
+	assert.NoError(t, err)
+	b2 := &Bond{}
+	assert.NoError(t, codec.Unmarshal(raw, b2))
+	assert.Equal(t, 0, b.Principal.Cmp(b2.Principal))
+	assert.Equal(t, b.Coupon, b2.Coupon)
+	assert.Equal(t, b.Holders, b2.Holders)
+
+	// the encoding is deterministic, the endorsers produce the same bytes
+	for i := 0; i < 10; i++ {
+		raw2, err := codec.Marshal(b)
+		assert.NoError(t, err)
+		assert.Equal(t, raw, raw2)
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	assert.IsType(t, &JSONCodec{}, GetCodec("ns"))
+	RegisterCodec("ns", &ProtoCodec{})
+	assert.IsType(t, &ProtoCodec{}, GetCodec("ns"))
+	RegisterCodec("ns", nil)
+	assert.IsType(t, &JSONCodec{}, GetCodec("ns"))
+}
@@ -5,6 +5,8 @@ SPDX-License-Identifier: Apache-2.0
 */
 package state
 
+import "github.com/hyperledger-labs/fabric-smart-client/platform/fabric/services/endorser"
+
 type CommonIteratorInterface interface {
 	// HasNext returns true if the range query iterator contains additional keys
 	// and values.
@@ -36,4 +38,8 @@ type WorldState interface {
 type WorldStateService interface {
 	// GetWorldState returns the world state for the passed channel.
 	GetWorldState(network string, channel string) (WorldState, error)
+
+	// GetWorldStateForTx returns the world state as seen by the passed transaction.
+	// The reads, range scans included, are recorded in the read-write set of the transaction.
+	GetWorldStateForTx(tx *endorser.Transaction) (WorldState, error)
 }
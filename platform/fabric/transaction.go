@@ -310,6 +310,12 @@ func (t *Transaction) BytesNoTransient() ([]byte, error) {
 	return t.tx.BytesNoTransient()
 }
 
+// BytesWithCollections marshals the transaction with the private data of the collections for which keep returns true.
+// The hashes of the private writes of all collections are always there.
+func (t *Transaction) BytesWithCollections(noTransient bool, keep func(namespace, collection string) bool) ([]byte, error) {
+	return t.tx.BytesWithCollections(noTransient, keep)
+}
+
 func (t *Transaction) FabricNetworkService() *NetworkService {
 	return t.fns
 }
@@ -7,6 +7,8 @@ package fabric
 
 import (
 	"encoding/json"
+	"io"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -105,6 +107,52 @@ func (r *RWSet) SetStateMetadata(namespace, key string, metadata map[string][]by
 	return r.rws.SetStateMetadata(namespace, key, metadata)
 }
 
+// GetStateRangeScanIterator returns an iterator over the keys of the given namespace in the range [startKey, endKey).
+// The range is recorded in this rwset when the iterator is closed, to let the validation detect phantom reads.
+func (r *RWSet) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (*ResultsIterator, error) {
+	ri, err := r.rws.GetStateRangeScanIterator(namespace, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultsIterator{ri: ri}, nil
+}
+
+// SetPrivateState sets the given value for the given key in the given collection of the given namespace.
+// Only the hashes of the private writes end up in the transaction, the preimages are shared with the
+// members of the collection.
+func (r *RWSet) SetPrivateState(namespace, collection, key string, value []byte) error {
+	return r.rws.SetPrivateState(namespace, collection, key, value)
+}
+
+// GetPrivateState returns the value of the given key in the given collection of the given namespace.
+// The value is nil if this node has not received the private data of the collection.
+func (r *RWSet) GetPrivateState(namespace, collection, key string, opts ...GetStateOpt) ([]byte, error) {
+	var o []api.GetStateOpt
+	for _, opt := range opts {
+		o = append(o, api.GetStateOpt(opt))
+	}
+	return r.rws.GetPrivateState(namespace, collection, key, o...)
+}
+
+func (r *RWSet) DeletePrivateState(namespace, collection, key string) error {
+	return r.rws.DeletePrivateState(namespace, collection, key)
+}
+
+// Collections returns the collections of the passed namespace with private reads or writes
+func (r *RWSet) Collections(ns string) []string {
+	return r.rws.Collections(ns)
+}
+
+// AppendPrivateRWSet adds the preimages of the private writes in the passed private read-write set
+func (r *RWSet) AppendPrivateRWSet(raw []byte) error {
+	return r.rws.AppendPrivateRWSet(raw)
+}
+
+// PrivateBytes returns the private read-write set, the preimages of the private writes known to this node
+func (r *RWSet) PrivateBytes() ([]byte, error) {
+	return r.rws.PrivateBytes()
+}
+
 func (r *RWSet) GetReadKeyAt(ns string, i int) (string, error) {
 	return r.rws.GetReadKeyAt(ns, i)
 }
@@ -204,6 +252,12 @@ func (qe *QueryExecutor) GetStateMetadata(namespace, key string) (map[string][]b
 	return qe.qe.GetStateMetadata(namespace, key)
 }
 
+// GetPrivateState returns the value of the key in the collection of the namespace, nil if this node
+// is not a member of the collection
+func (qe *QueryExecutor) GetPrivateState(namespace, collection, key string) ([]byte, error) {
+	return qe.qe.GetPrivateState(namespace, collection, key)
+}
+
 func (qe *QueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (*ResultsIterator, error) {
 	ri, err := qe.qe.GetStateRangeScanIterator(namespace, startKey, endKey)
 	if err != nil {
@@ -262,6 +316,64 @@ func (t *TxIDIterator) Close() {
 	t.TxidIterator.Close()
 }
 
+// HistoryEntry is a version of a key recorded in the history of the vault
+type HistoryEntry struct {
+	Key   string
+	Value []byte
+	Block uint64
+	TxNum uint64
+	// TxID is the id of the transaction that wrote this version
+	TxID string
+	// Deleted is true if the transaction deleted the key
+	Deleted bool
+}
+
+func historyEntries(entries []*api.HistoryEntry) []*HistoryEntry {
+	res := make([]*HistoryEntry, len(entries))
+	for i, e := range entries {
+		res[i] = &HistoryEntry{
+			Key:     e.Key,
+			Value:   e.Value,
+			Block:   e.Block,
+			TxNum:   e.TxNum,
+			TxID:    e.TxID,
+			Deleted: e.Deleted,
+		}
+	}
+	return res
+}
+
+// SnapshotInfo describes a snapshot of the vault
+type SnapshotInfo struct {
+	// LastBlock is the block the delivery resumes from on the nodes bootstrapped from the snapshot
+	LastBlock uint64
+	// Entries is the number of entries of the snapshot
+	Entries uint64
+	// Checksum is the hex-encoded SHA-256 of the content of the snapshot
+	Checksum string
+}
+
+// LocalTransaction is an entry of the audit log of the transactions committed locally, without going through Fabric
+type LocalTransaction struct {
+	// Seq is the sequence number assigned to the transaction, its writes are at version 0:Seq
+	Seq       uint64
+	TxID      string
+	Timestamp time.Time
+	// Valid is false if the transaction was rejected
+	Valid bool
+	// Reason tells why the transaction was rejected
+	Reason string
+	Writes []*LocalWrite
+}
+
+// LocalWrite is a key written by a transaction committed locally
+type LocalWrite struct {
+	Namespace string
+	Key       string
+	// Deleted is true if the transaction deleted the key
+	Deleted bool
+}
+
 type Vault struct {
 	ch api.Channel
 }
@@ -305,6 +417,37 @@ func (c *Vault) CommitTX(txid string, block uint64, indexInBloc int) error {
 	return c.ch.CommitTX(txid, block, indexInBloc)
 }
 
+// CommitLocalTX commits the transaction with the passed id to the vault only, without going through Fabric.
+// The transaction must write only the namespaces configured as local, and is rejected if what it read has changed
+// in the meantime. CommitLocalTX returns the sequence number assigned to the transaction.
+func (c *Vault) CommitLocalTX(txid string) (uint64, error) {
+	return c.ch.CommitLocalTX(txid)
+}
+
+// GetLocalLog returns the audit log of the transactions committed locally, from the passed sequence number on
+func (c *Vault) GetLocalLog(from uint64) ([]*LocalTransaction, error) {
+	entries, err := c.ch.GetLocalLog(from)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*LocalTransaction, len(entries))
+	for i, e := range entries {
+		writes := make([]*LocalWrite, len(e.Writes))
+		for j, w := range e.Writes {
+			writes[j] = &LocalWrite{Namespace: w.Namespace, Key: w.Key, Deleted: w.Deleted}
+		}
+		res[i] = &LocalTransaction{
+			Seq:       e.Seq,
+			TxID:      e.TxID,
+			Timestamp: e.Timestamp,
+			Valid:     e.Valid,
+			Reason:    e.Reason,
+			Writes:    writes,
+		}
+	}
+	return res, nil
+}
+
 func (c *Vault) NewQueryExecutor() (*QueryExecutor, error) {
 	qe, err := c.ch.NewQueryExecutor()
 	if err != nil {
@@ -337,6 +480,42 @@ func (c *Vault) GetEphemeralRWSet(rwset []byte) (*RWSet, error) {
 	return &RWSet{rws: rws}, nil
 }
 
+// GetHistoryForKey returns the versions of the passed key recorded in the history of the vault, the oldest first.
+// The history must be enabled in the configuration.
+func (c *Vault) GetHistoryForKey(namespace, key string) ([]*HistoryEntry, error) {
+	entries, err := c.ch.GetHistoryForKey(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	return historyEntries(entries), nil
+}
+
+// GetNamespaceAsOf returns the keys of the namespace, with their values, as they were once the passed block was committed.
+// The history must be enabled in the configuration.
+func (c *Vault) GetNamespaceAsOf(namespace string, block uint64) ([]*HistoryEntry, error) {
+	entries, err := c.ch.GetNamespaceAsOf(namespace, block)
+	if err != nil {
+		return nil, err
+	}
+	return historyEntries(entries), nil
+}
+
+// ExportSnapshot writes the content of the vault to the passed writer.
+// The snapshot can be imported to bootstrap the vault of another node, whose delivery resumes from the last block of the snapshot.
+// The private data of the collections and the local transactions are included only if includePrivate is set:
+// set it only when the snapshot is for a node of the same organization.
+func (c *Vault) ExportSnapshot(w io.Writer, includePrivate bool) (*SnapshotInfo, error) {
+	info, err := c.ch.ExportSnapshot(w, includePrivate)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotInfo{
+		LastBlock: info.LastBlock,
+		Entries:   info.Entries,
+		Checksum:  info.Checksum,
+	}, nil
+}
+
 func (c *Vault) StoreEnvelope(id string, env []byte) error {
 	return c.ch.EnvelopeService().StoreEnvelope(id, env)
 }
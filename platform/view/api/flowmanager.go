@@ -8,16 +8,31 @@ package api
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
+// RunningView describes a view currently running on this node
+type RunningView struct {
+	ContextID string
+	View      string
+	// Role is either initiator or responder
+	Role  string
+	Since time.Time
+}
+
 type ViewManager interface {
 	NewView(id string, in []byte) (view.View, error)
 	Context(contextID string) (view.Context, error)
 	InitiateView(view view.View) (interface{}, error)
 	InitiateContext(view view.View) (view.Context, error)
 	Start(ctx context.Context)
+	// RunningViews returns the views currently running on this node
+	RunningViews() []RunningView
+	// Drain stops accepting new views and waits for the running ones to complete,
+	// or for the passed context to be done
+	Drain(ctx context.Context) error
 }
 
 func GetViewManager(sp ServiceProvider) ViewManager {
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,14 +27,25 @@ const (
 
 const OfficialPath = "/etc/hyperledger-labs/fabric-smart-client-node"
 
+// memoryDir is the memory-backed file system secrets are written to, if available
+const memoryDir = "/dev/shm"
+
 var (
 	logger    = flogging.MustGetLogger("view-sdk.config")
 	logOutput = os.Stderr
 )
 
+// provider is a viper-backed configuration provider.
+// The string values of the keys declared as secrets can reference secrets, resolved when the configuration
+// is loaded, as in env:NAME, file:PATH, exec:COMMAND or local:NAME. See SecretResolver and DefaultSecretKeys.
 type provider struct {
 	confPath string
-	v        *viper.Viper
+
+	mutex       sync.RWMutex
+	v           *viper.Viper
+	secrets     map[string]*secret
+	secretsDir  string
+	loggingSpec string
 }
 
 func NewProvider(confPath string) (*provider, error) {
@@ -46,37 +58,57 @@ func NewProvider(confPath string) (*provider, error) {
 }
 
 func (p *provider) GetDuration(key string) time.Duration {
-	return p.v.GetDuration(key)
+	return p.viper().GetDuration(key)
 }
 
 func (p *provider) GetBool(key string) bool {
-	logger.Debugf("Getting bool %s:%v", key, p.v.Get(key))
-	return p.v.GetBool(key)
+	logger.Debugf("Getting bool %s:%v", key, p.viper().Get(key))
+	return p.viper().GetBool(key)
 }
 
 func (p *provider) GetStringSlice(key string) []string {
-	return p.v.GetStringSlice(key)
+	return p.viper().GetStringSlice(key)
 }
 
 func (p *provider) AddDecodeHook(f api.DecodeHookFuncType) error {
 	return nil
 }
 
+// UnmarshalKey unmarshals the value of the key, the references to secrets it contains are replaced
+// by the secrets themselves, as returned by GetString
 func (p *provider) UnmarshalKey(key string, rawVal interface{}) error {
-	return viperutil.EnhancedExactUnmarshal(p.v, key, rawVal)
+	p.mutex.RLock()
+	v, secrets := p.v, p.secrets
+	p.mutex.RUnlock()
+	return viperutil.EnhancedExactUnmarshalValue(withSecrets(key, v.Get(key), secrets), rawVal)
 }
 
 func (p *provider) IsSet(key string) bool {
-	return p.v.IsSet(key)
+	return p.viper().IsSet(key)
 }
 
+// GetPath returns the path the key points to. If the key references a secret, the path is that of
+// a file, readable by the owner only, holding the secret, the referenced file for file: references.
+// The files are written in memory, if possible, and removed by Close.
 func (p *provider) GetPath(key string) string {
-	path := p.v.GetString(key)
+	if s := p.secret(key); s != nil {
+		if s.scheme == FileScheme {
+			return s.ref
+		}
+		path, err := p.materialize(key, s)
+		if err != nil {
+			logger.Errorf("failed materializing secret of [%s]: [%s]", key, err)
+			return ""
+		}
+		return path
+	}
+
+	path := p.viper().GetString(key)
 	if path == "" {
 		return ""
 	}
 
-	return TranslatePath(filepath.Dir(p.v.ConfigFileUsed()), path)
+	return TranslatePath(filepath.Dir(p.viper().ConfigFileUsed()), path)
 }
 
 func (p *provider) TranslatePath(path string) string {
@@ -84,36 +116,93 @@ func (p *provider) TranslatePath(path string) string {
 		return ""
 	}
 
-	return TranslatePath(filepath.Dir(p.v.ConfigFileUsed()), path)
+	return TranslatePath(filepath.Dir(p.viper().ConfigFileUsed()), path)
 }
 
+// GetString returns the value of the key. If the key references a secret, the secret is returned
+// without trailing newlines.
 func (p *provider) GetString(key string) string {
-	return p.v.GetString(key)
+	if s := p.secret(key); s != nil {
+		return s.String()
+	}
+	return p.viper().GetString(key)
 }
 
 func (p *provider) ConfigFileUsed() string {
-	return p.v.ConfigFileUsed()
+	return p.viper().ConfigFileUsed()
 }
 
-func (p *provider) load() error {
-	p.v = viper.New()
-	err := p.initViper(p.v, CmdRoot)
+// Reload re-reads the configuration file, resolves again the secrets it references,
+// and activates the logging spec, if it changed.
+// If the file cannot be read, the configuration in place is kept.
+func (p *provider) Reload() error {
+	v, secrets, err := p.read()
 	if err != nil {
 		return err
 	}
+	spec := loggingSpec(v)
+
+	p.mutex.Lock()
+	p.v = v
+	p.secrets = secrets
+	changed := spec != p.loggingSpec
+	p.loggingSpec = spec
+	p.mutex.Unlock()
+
+	if changed {
+		logger.Infof("Activating logging spec [%s]", spec)
+		flogging.ActivateSpec(spec)
+	}
+	return nil
+}
 
-	err = p.v.ReadInConfig() // Find and read the config file
-	if err != nil {          // Handle errors reading the config file
-		// The version of Viper we use claims the config type isn't supported when in fact the file hasn't been found
-		// Display a more helpful message to avoid confusing the user.
-		if strings.Contains(fmt.Sprint(err), "Unsupported Config Type") {
-			return errors.Errorf("Could not find config file. "+
-				"Please make sure that FSCNODE_CFG_PATH is set to a path "+
-				"which contains %s.yaml", CmdRoot)
-		} else {
-			return errors.WithMessagef(err, "error when reading %s config file", CmdRoot)
+func (p *provider) viper() *viper.Viper {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.v
+}
+
+func (p *provider) secret(key string) *secret {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.secrets[strings.ToLower(key)]
+}
+
+// materialize writes the passed secret in a file under a private temporary directory, created on first use
+func (p *provider) materialize(key string, s *secret) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.secretsDir) == 0 {
+		dir, err := newSecretsDir()
+		if err != nil {
+			return "", err
 		}
+		p.secretsDir = dir
 	}
+	return materialize(p.secretsDir, strings.ToLower(key), s.value)
+}
+
+// Close removes the files the secrets have been written to by GetPath
+func (p *provider) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.secretsDir) == 0 {
+		return nil
+	}
+	if err := os.RemoveAll(p.secretsDir); err != nil {
+		return errors.Wrapf(err, "failed removing secrets directory [%s]", p.secretsDir)
+	}
+	p.secretsDir = ""
+	return nil
+}
+
+func (p *provider) load() error {
+	v, secrets, err := p.read()
+	if err != nil {
+		return err
+	}
+	p.v = v
+	p.secrets = secrets
 
 	// read in the legacy logging level settings and, if set,
 	// notify users of the FSCNODE_LOGGING_SPEC env variable
@@ -127,22 +216,50 @@ func (p *provider) load() error {
 		logger.Warning("CORE_LOGGING_LEVEL is no longer supported, please use the FSCNODE_LOGGING_SPEC environment variable")
 	}
 
-	loggingSpec := os.Getenv("FSCNODE_LOGGING_SPEC")
-	loggingFormat := os.Getenv("FSCNODE_LOGGING_FORMAT")
-
-	if len(loggingSpec) == 0 {
-		loggingSpec = p.v.GetString("logging.spec")
-	}
-
+	p.loggingSpec = loggingSpec(p.v)
 	flogging.Init(flogging.Config{
-		Format:  loggingFormat,
+		Format:  os.Getenv("FSCNODE_LOGGING_FORMAT"),
 		Writer:  logOutput,
-		LogSpec: loggingSpec,
+		LogSpec: p.loggingSpec,
 	})
 
 	return nil
 }
 
+func (p *provider) read() (*viper.Viper, map[string]*secret, error) {
+	v := viper.New()
+	err := p.initViper(v, CmdRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = v.ReadInConfig() // Find and read the config file
+	if err != nil {        // Handle errors reading the config file
+		// The version of Viper we use claims the config type isn't supported when in fact the file hasn't been found
+		// Display a more helpful message to avoid confusing the user.
+		if strings.Contains(fmt.Sprint(err), "Unsupported Config Type") {
+			return nil, nil, errors.Errorf("Could not find config file. "+
+				"Please make sure that FSCNODE_CFG_PATH is set to a path "+
+				"which contains %s.yaml", CmdRoot)
+		} else {
+			return nil, nil, errors.WithMessagef(err, "error when reading %s config file", CmdRoot)
+		}
+	}
+	secrets, err := resolveSecrets(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, secrets, nil
+}
+
+// loggingSpec returns the logging spec, FSCNODE_LOGGING_SPEC takes precedence over the configuration
+func loggingSpec(v *viper.Viper) string {
+	if spec := os.Getenv("FSCNODE_LOGGING_SPEC"); len(spec) != 0 {
+		return spec
+	}
+	return v.GetString("logging.spec")
+}
+
 //----------------------------------------------------------------------------------
 // InitViper()
 //----------------------------------------------------------------------------------
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+)
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer flogging.Reset()
+
+	path := filepath.Join(dir, "core.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("logging:\n  spec: info\nfsc:\n  id: alice\n"), 0644))
+	p, err := NewProvider(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", p.GetString("fsc.id"))
+	assert.Equal(t, "info", flogging.LoggerLevel("view-sdk.config.test"))
+
+	// the new values and the new logging spec are picked up
+	assert.NoError(t, ioutil.WriteFile(path, []byte("logging:\n  spec: view-sdk.config.test=debug:info\nfsc:\n  id: bob\n"), 0644))
+	assert.NoError(t, p.Reload())
+	assert.Equal(t, "bob", p.GetString("fsc.id"))
+	assert.Equal(t, "debug", flogging.LoggerLevel("view-sdk.config.test"))
+
+	// a broken file leaves the configuration in place
+	assert.NoError(t, ioutil.WriteFile(path, []byte("fsc: [\n"), 0644))
+	assert.Error(t, p.Reload())
+	assert.Equal(t, "bob", p.GetString("fsc.id"))
+}
+
+func TestSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer flogging.Reset()
+
+	assert.NoError(t, os.Setenv("FSC_TEST_PASSWORD", "secret"))
+	defer os.Unsetenv("FSC_TEST_PASSWORD")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key.pem"), []byte("key\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "secrets.yaml"), []byte("alice/key: alice's key\n"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "core.yaml"), []byte(`
+fsc:
+  secrets:
+    local:
+      file: secrets.yaml
+    keys:
+    - fsc.password
+    - fsc.peers.*.password
+  password: env:FSC_TEST_PASSWORD
+  command: exec:echo not a secret
+  endpoint: http://localhost:9000
+  peers:
+  - address: localhost:7051
+    password: env:FSC_TEST_PASSWORD
+  tls:
+    key:
+      file: file:key.pem
+    clientKey:
+      file: local:alice/key
+  identity:
+    key:
+      file: exec:echo exec key
+`), 0644))
+
+	p, err := NewProvider(dir)
+	assert.NoError(t, err)
+	defer p.Close()
+
+	assert.Equal(t, "secret", p.GetString("fsc.password"))
+	assert.Equal(t, "http://localhost:9000", p.GetString("fsc.endpoint"))
+	// only the keys declared as secrets are resolved
+	assert.Equal(t, "exec:echo not a secret", p.GetString("fsc.command"))
+	assert.Equal(t, "key", p.GetString("fsc.tls.key.file"))
+	assert.Equal(t, "exec key", p.GetString("fsc.identity.key.file"))
+
+	// file: references point to the file itself, the other secrets are written in private files
+	assert.Equal(t, filepath.Join(dir, "key.pem"), p.GetPath("fsc.tls.key.file"))
+	path := p.GetPath("fsc.tls.clientKey.file")
+	raw, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice's key", string(raw))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	info, err = os.Stat(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	// unmarshalled values carry the secrets, list elements included
+	var peers []struct {
+		Address  string
+		Password string
+	}
+	assert.NoError(t, p.UnmarshalKey("fsc.peers", &peers))
+	assert.Len(t, peers, 1)
+	assert.Equal(t, "localhost:7051", peers[0].Address)
+	assert.Equal(t, "secret", peers[0].Password)
+
+	// closing removes the written secrets
+	assert.NoError(t, p.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// secrets are resolved again on reload
+	assert.NoError(t, os.Setenv("FSC_TEST_PASSWORD", "new secret"))
+	assert.NoError(t, p.Reload())
+	assert.Equal(t, "new secret", p.GetString("fsc.password"))
+
+	// unresolvable secrets fail the loading
+	assert.NoError(t, os.Unsetenv("FSC_TEST_PASSWORD"))
+	assert.Error(t, p.Reload())
+	assert.Equal(t, "new secret", p.GetString("fsc.password"))
+	_, err = NewProvider(dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "FSC_TEST_PASSWORD")
+}
@@ -0,0 +1,350 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// SecretResolver resolves references to secrets, env:NAME for instance, to the secret itself.
+// Vault-like stores plug in by registering a resolver under their own scheme.
+type SecretResolver interface {
+	// Resolve returns the secret the passed reference, stripped of the scheme, points to
+	Resolve(ref string) ([]byte, error)
+}
+
+// SecretResolverFunc adapts a function to a SecretResolver
+type SecretResolverFunc func(ref string) ([]byte, error)
+
+func (f SecretResolverFunc) Resolve(ref string) ([]byte, error) {
+	return f(ref)
+}
+
+const (
+	// EnvScheme resolves env:NAME to the value of the environment variable NAME
+	EnvScheme = "env"
+	// FileScheme resolves file:PATH to the content of the file at PATH, relative to the config file
+	FileScheme = "file"
+	// ExecScheme resolves exec:COMMAND ARGS... to the standard output of the command
+	ExecScheme = "exec"
+	// LocalScheme resolves local:NAME to the secret NAME in the local store set by fsc.secrets.local.file
+	LocalScheme = "local"
+)
+
+// ExecTimeout bounds the time the commands of exec: references can take
+var ExecTimeout = 30 * time.Second
+
+// DefaultSecretKeys are the keys always declared as secrets, the private keys of the node.
+// More keys are declared by fsc.secrets.keys. A * matches any single segment of a key,
+// a map key or a list index, as in fabric.networks.*.tls.key.file.
+var DefaultSecretKeys = []string{
+	"fsc.identity.key.file",
+	"fsc.tls.key.file",
+	"fsc.tls.clientKey.file",
+	"fsc.operations.tls.key.file",
+	"fabric.tls.key.file",
+	"fabric.tls.clientKey.file",
+	"fabric.networks.*.tls.key.file",
+	"fabric.networks.*.tls.clientKey.file",
+}
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]SecretResolver{
+		EnvScheme:  SecretResolverFunc(resolveEnv),
+		FileScheme: SecretResolverFunc(ioutil.ReadFile),
+		ExecScheme: SecretResolverFunc(resolveExec),
+	}
+)
+
+// RegisterSecretResolver makes a secret resolver available under the provided scheme.
+// If RegisterSecretResolver is called twice with the same scheme or if resolver is nil,
+// it panics.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	if resolver == nil {
+		panic("RegisterSecretResolver resolver is nil")
+	}
+	if scheme == LocalScheme {
+		panic("RegisterSecretResolver called for reserved scheme " + scheme)
+	}
+	if _, dup := resolvers[scheme]; dup {
+		panic("RegisterSecretResolver called twice for scheme " + scheme)
+	}
+	resolvers[scheme] = resolver
+}
+
+// SecretSchemes returns a sorted list of the schemes of the registered secret resolvers.
+func SecretSchemes() []string {
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	list := make([]string, 0, len(resolvers))
+	for scheme := range resolvers {
+		list = append(list, scheme)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func secretResolver(scheme string) (SecretResolver, bool) {
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	r, ok := resolvers[scheme]
+	return r, ok
+}
+
+// LocalStore is a stand-in for a vault-like store, it serves the secrets listed in a yaml file
+// as a map from names to values. It is meant for development and tests, the secrets are in the clear.
+type LocalStore struct {
+	secrets map[string]string
+}
+
+// NewLocalStore loads the secrets in the passed yaml file
+func NewLocalStore(path string) (*LocalStore, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading secret store [%s]", path)
+	}
+	secrets := map[string]string{}
+	if err := yaml.Unmarshal(raw, &secrets); err != nil {
+		return nil, errors.Wrapf(err, "failed unmarshalling secret store [%s]", path)
+	}
+	return &LocalStore{secrets: secrets}, nil
+}
+
+func (s *LocalStore) Resolve(name string) ([]byte, error) {
+	secret, ok := s.secrets[name]
+	if !ok {
+		return nil, errors.Errorf("secret [%s] not found", name)
+	}
+	return []byte(secret), nil
+}
+
+func resolveEnv(name string) ([]byte, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, errors.Errorf("environment variable [%s] not set", name)
+	}
+	return []byte(value), nil
+}
+
+func resolveExec(command string) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ExecTimeout)
+	defer cancel()
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed running [%s]: [%s]", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// splitSecretRef splits a secret reference in its scheme and the reference proper.
+// It returns false if the passed value does not start with a scheme followed by a colon.
+func splitSecretRef(value string) (string, string, bool) {
+	i := strings.IndexByte(value, ':')
+	if i <= 0 {
+		return "", "", false
+	}
+	return value[:i], value[i+1:], true
+}
+
+// secret is a configuration value resolved from a secret reference
+type secret struct {
+	scheme string
+	// ref is the reference stripped of the scheme, file: references are translated relative to the config file
+	ref   string
+	value []byte
+}
+
+// String returns the secret without trailing newlines
+func (s *secret) String() string {
+	return strings.TrimRight(string(s.value), "\r\n")
+}
+
+// resolveSecrets resolves the string values of the passed configuration that reference a secret,
+// under the keys declared as secrets. The values under the other keys are never resolved.
+// The result is indexed by lower-case key, as viper does. The keys of list elements carry their index.
+func resolveSecrets(v *viper.Viper) (map[string]*secret, error) {
+	base := filepath.Dir(v.ConfigFileUsed())
+	var local SecretResolver
+	if path := v.GetString("fsc.secrets.local.file"); len(path) != 0 {
+		store, err := NewLocalStore(TranslatePath(base, path))
+		if err != nil {
+			return nil, err
+		}
+		local = store
+	}
+
+	declared := append(append([]string{}, DefaultSecretKeys...), v.GetStringSlice("fsc.secrets.keys")...)
+	res := map[string]*secret{}
+	for key, leaf := range leaves("", v.AllSettings()) {
+		value, ok := leaf.(string)
+		if !ok || !isSecretKey(key, declared) {
+			continue
+		}
+		scheme, ref, ok := splitSecretRef(value)
+		if !ok {
+			continue
+		}
+		var resolver SecretResolver
+		if scheme == LocalScheme {
+			if local == nil {
+				return nil, errors.Errorf("failed resolving [%s], fsc.secrets.local.file is not set", key)
+			}
+			resolver = local
+		} else if resolver, ok = secretResolver(scheme); !ok {
+			// not a secret reference, a URL for instance
+			continue
+		}
+		if scheme == FileScheme {
+			ref = TranslatePath(base, ref)
+		}
+		s, err := resolver.Resolve(ref)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed resolving secret [%s:...] of [%s]", scheme, key)
+		}
+		res[strings.ToLower(key)] = &secret{scheme: scheme, ref: ref, value: s}
+	}
+	return res, nil
+}
+
+// isSecretKey returns true if the passed key matches one of the passed patterns
+func isSecretKey(key string, patterns []string) bool {
+	segments := strings.Split(strings.ToLower(key), ".")
+	for _, pattern := range patterns {
+		p := strings.Split(strings.ToLower(pattern), ".")
+		if len(p) != len(segments) {
+			continue
+		}
+		match := true
+		for i := range p {
+			if p[i] != "*" && p[i] != segments[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// newSecretsDir creates a directory, accessible by the owner only, to write secrets in.
+// A memory-backed file system is used, if available, so that secrets never reach the disk.
+func newSecretsDir() (string, error) {
+	base := ""
+	if dirExists(memoryDir) {
+		base = memoryDir
+	}
+	dir, err := ioutil.TempDir(base, "fsc-secrets")
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating secrets directory")
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		os.RemoveAll(dir)
+		return "", errors.Wrap(err, "failed restricting secrets directory")
+	}
+	return dir, nil
+}
+
+// materialize writes the passed secret in a file, readable by the owner only, under the passed directory
+// and returns its path. The file is rewritten only if the secret changed, this way reloads triggered
+// by the modification of the files do not repeat endlessly.
+func materialize(dir, key string, value []byte) (string, error) {
+	path := filepath.Join(dir, strings.ReplaceAll(key, string(filepath.Separator), "_"))
+	if current, err := ioutil.ReadFile(path); err == nil && bytes.Equal(current, value) {
+		return path, nil
+	}
+	if err := ioutil.WriteFile(path, value, 0600); err != nil {
+		return "", errors.Wrapf(err, "failed writing secret of [%s]", key)
+	}
+	return path, nil
+}
+
+// leaves returns the leaves of the passed settings indexed by their fully qualified key.
+// The viper in use does not flatten the nested maps of yaml files.
+// The elements of lists are keyed by their index, as in fabric.msps.0.path.
+func leaves(prefix string, settings interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	add := func(m map[string]interface{}) {
+		for k, v := range m {
+			res[k] = v
+		}
+	}
+	switch m := settings.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			add(leaves(prefix+k+".", v))
+		}
+	case map[interface{}]interface{}:
+		for k, v := range m {
+			add(leaves(fmt.Sprintf("%s%v.", prefix, k), v))
+		}
+	case []interface{}:
+		for i, v := range m {
+			add(leaves(fmt.Sprintf("%s%d.", prefix, i), v))
+		}
+	default:
+		if len(prefix) != 0 {
+			res[strings.TrimSuffix(prefix, ".")] = settings
+		}
+	}
+	return res
+}
+
+// withSecrets returns a copy of the passed value, found under the passed key, where the references
+// to secrets are replaced by the secrets themselves
+func withSecrets(key string, value interface{}, secrets map[string]*secret) interface{} {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			res[k] = withSecrets(key+"."+k, v, secrets)
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(m))
+		for k, v := range m {
+			res[k] = withSecrets(fmt.Sprintf("%s.%v", key, k), v, secrets)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(m))
+		for i, v := range m {
+			res[i] = withSecrets(fmt.Sprintf("%s.%d", key, i), v, secrets)
+		}
+		return res
+	default:
+		if s, ok := secrets[strings.ToLower(key)]; ok {
+			return s.String()
+		}
+		return value
+	}
+}
@@ -315,6 +315,11 @@ func bccspHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, e
 // producing error when extraneous variables are introduced and supporting
 // the time.Duration type
 func EnhancedExactUnmarshal(v *viper.Viper, key string, output interface{}) error {
+	return EnhancedExactUnmarshalValue(v.Get(key), output)
+}
+
+// EnhancedExactUnmarshalValue is EnhancedExactUnmarshal for an already retrieved value
+func EnhancedExactUnmarshalValue(value interface{}, output interface{}) error {
 	oType := reflect.TypeOf(output)
 	if oType.Kind() != reflect.Ptr {
 		return errors.Errorf("supplied output argument must be a pointer to a struct but is not pointer")
@@ -343,5 +348,5 @@ func EnhancedExactUnmarshal(v *viper.Viper, key string, output interface{}) erro
 	if err != nil {
 		return err
 	}
-	return decoder.Decode(v.Get(key))
+	return decoder.Decode(value)
 }
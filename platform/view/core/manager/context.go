@@ -176,6 +176,22 @@ func (ctx *ctx) Session() view.Session {
 	return ctx.session
 }
 
+// openSessions returns the sessions of this context that are not closed yet
+func (ctx *ctx) openSessions() []view.Session {
+	var res []view.Session
+	if ctx.session != nil && !ctx.session.Info().Closed {
+		res = append(res, ctx.session)
+	}
+	ctx.sessionsLock.RLock()
+	defer ctx.sessionsLock.RUnlock()
+	for _, s := range ctx.sessions {
+		if !s.Info().Closed {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
 func (ctx *ctx) GetService(v interface{}) (interface{}, error) {
 	return ctx.sp.GetService(v)
 }
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package manager
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const (
+	drainPollInterval = 100 * time.Millisecond
+	shutdownMessage   = "node is shutting down"
+)
+
+// Drain makes the manager reject new views, either initiated locally or requested by remote parties
+// on new contexts, and waits for the running views to complete or for the passed context to be done.
+// In the latter case, the parties of the views still running are notified over the open sessions.
+func (cm *manager) Drain(ctx context.Context) error {
+	atomic.StoreInt32(&cm.draining, 1)
+	logger.Infof("draining views...")
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		running := cm.RunningViews()
+		if len(running) == 0 {
+			logger.Infof("draining views...done")
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			contextIDs := map[string]struct{}{}
+			for _, rv := range running {
+				logger.Warnf("view [%s] still running as %s in context [%s]", rv.View, rv.Role, rv.ContextID)
+				contextIDs[rv.ContextID] = struct{}{}
+			}
+			cm.notifyShutdown(contextIDs)
+			return errors.Errorf("[%d] views still running after draining", len(running))
+		}
+	}
+}
+
+func (cm *manager) isDraining() bool {
+	return atomic.LoadInt32(&cm.draining) == 1
+}
+
+func (cm *manager) existContext(contextID string) bool {
+	cm.contextsSync.RLock()
+	defer cm.contextsSync.RUnlock()
+	_, ok := cm.contexts[contextID]
+	return ok
+}
+
+// deleteContext deregisters the passed context, if it is still the one registered under the passed id
+func (cm *manager) deleteContext(contextID string, ctx view.Context) {
+	cm.contextsSync.Lock()
+	defer cm.contextsSync.Unlock()
+	if c, ok := cm.contexts[contextID]; ok && c == ctx {
+		delete(cm.contexts, contextID)
+	}
+}
+
+// notifyShutdown sends an error to the parties of the open sessions of the passed contexts
+func (cm *manager) notifyShutdown(contextIDs map[string]struct{}) {
+	var sessions []view.Session
+	cm.contextsSync.RLock()
+	for contextID := range contextIDs {
+		if c, ok := cm.contexts[contextID].(*wrappedContext); ok {
+			sessions = append(sessions, c.openSessions()...)
+		}
+	}
+	cm.contextsSync.RUnlock()
+
+	for _, s := range sessions {
+		logger.Debugf("notifying shutdown on session [%s]", s.Info().ID)
+		if err := s.SendError([]byte(shutdownMessage)); err != nil {
+			logger.Warnf("failed notifying shutdown on session [%s]: [%s]", s.Info().ID, err)
+		}
+	}
+}
@@ -9,12 +9,15 @@ import (
 	"context"
 	"reflect"
 	"runtime/debug"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
 )
 
@@ -39,16 +42,26 @@ type manager struct {
 	views      map[string][]*viewEntry
 	initiators map[string]string
 	factories  map[string]api.Factory
+
+	runningSync    sync.Mutex
+	running        map[uint64]*api.RunningView
+	nextRunningKey uint64
+	// draining is 1 when the manager does not accept new views, 0 otherwise
+	draining int32
+
+	metrics *Metrics
 }
 
 func New(serviceProvider api.ServiceProvider) *manager {
 	return &manager{
-		sp: serviceProvider,
+		sp:      serviceProvider,
+		metrics: NewMetrics(operations.GetMetricsProvider(serviceProvider)),
 
 		contexts:   map[string]view.Context{},
 		views:      map[string][]*viewEntry{},
 		initiators: map[string]string{},
 		factories:  map[string]api.Factory{},
+		running:    map[uint64]*api.RunningView{},
 	}
 }
 
@@ -119,6 +132,9 @@ func (cm *manager) InitiateView(view view.View) (interface{}, error) {
 }
 
 func (cm *manager) InitiateViewWithIdentity(view view.View, id view.Identity) (interface{}, error) {
+	if cm.isDraining() {
+		return nil, errors.Errorf("cannot initiate view [%s], %s", getIdentifier(view), shutdownMessage)
+	}
 	// Create the context
 	cm.contextsSync.Lock()
 	ctx := cm.ctx
@@ -136,7 +152,7 @@ func (cm *manager) InitiateViewWithIdentity(view view.View, id view.Identity) (i
 	cm.contextsSync.Unlock()
 
 	logger.Debugf("[%s] InitiateView [view:%s], [ContextID:%s]", id, getIdentifier(view), wrappedContext.ID())
-	res, err := wrappedContext.RunView(view)
+	res, err := cm.runView(wrappedContext, view, initiatorRole)
 	if err != nil {
 		logger.Debugf("[%s] InitiateView [view:%s], [ContextID:%s] failed [%s]", id, getIdentifier(view), wrappedContext.ID(), err)
 		return nil, err
@@ -150,6 +166,9 @@ func (cm *manager) InitiateContext(view view.View) (view.Context, error) {
 }
 
 func (cm *manager) InitiateContextWithIdentity(view view.View, id view.Identity) (view.Context, error) {
+	if cm.isDraining() {
+		return nil, errors.Errorf("cannot initiate context for view [%s], %s", getIdentifier(view), shutdownMessage)
+	}
 	// Create the context
 	cm.contextsSync.Lock()
 	ctx := cm.ctx
@@ -230,13 +249,19 @@ func (cm *manager) respond(responder view.View, id view.Identity, msg *view.Mess
 	logger.Debugf("[%s] Respond [from:%s], [sessionID:%s], [contextID:%s]\n", id, msg.FromEndpoint, msg.SessionID, msg.ContextID)
 
 	// get context
+	existing := cm.existContext(msg.ContextID)
 	ctx, err = cm.newContext(id, msg)
 	if err != nil {
 		return nil, nil, errors.WithMessagef(err, "failed getting context for [%s,%s,%v]", msg.ContextID, id, msg)
 	}
+	if !existing && cm.isDraining() {
+		// only the contexts already in-flight can go on, the new one is kept just to return the error to the caller
+		cm.deleteContext(msg.ContextID, ctx)
+		return ctx, nil, errors.Errorf("cannot respond on context [%s], %s", msg.ContextID, shutdownMessage)
+	}
 
 	// run view
-	res, err = ctx.RunView(responder)
+	res, err = cm.runView(ctx, responder, responderRole)
 	if err != nil {
 		logger.Debugf("[%s] Respond Failure [from:%s], [sessionID:%s], [contextID:%s] [%s]\n", id, msg.FromEndpoint, msg.SessionID, msg.ContextID, err)
 	}
@@ -352,3 +377,31 @@ func getIdentifier(f view.View) string {
 	}
 	return t.PkgPath() + "/" + t.Name()
 }
+
+// RunningViews returns the views currently running on this node, sorted by start time
+func (cm *manager) RunningViews() []api.RunningView {
+	cm.runningSync.Lock()
+	res := make([]api.RunningView, 0, len(cm.running))
+	for _, rv := range cm.running {
+		res = append(res, *rv)
+	}
+	cm.runningSync.Unlock()
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Since.Before(res[j].Since) })
+	return res
+}
+
+// trackRunning adds an entry to the list of running views and returns the function that removes it
+func (cm *manager) trackRunning(contextID, view, role string) func() {
+	cm.runningSync.Lock()
+	key := cm.nextRunningKey
+	cm.nextRunningKey++
+	cm.running[key] = &api.RunningView{ContextID: contextID, View: view, Role: role, Since: time.Now()}
+	cm.runningSync.Unlock()
+
+	return func() {
+		cm.runningSync.Lock()
+		delete(cm.running, key)
+		cm.runningSync.Unlock()
+	}
+}
@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package manager_test
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -82,6 +83,57 @@ func TestManagerRace(t *testing.T) {
 	wg.Wait()
 }
 
+type BlockingView struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *BlockingView) Call(context view.Context) (interface{}, error) {
+	close(b.started)
+	<-b.release
+	return nil, nil
+}
+
+func TestDrain(t *testing.T) {
+	registry := registry2.New()
+	idProvider := &mock.IdentityProvider{}
+	idProvider.DefaultIdentityReturns([]byte("alice"))
+	assert.NoError(t, registry.RegisterService(idProvider))
+	assert.NoError(t, registry.RegisterService(&mock2.CommLayer{}))
+	assert.NoError(t, registry.RegisterService(&mock.EndpointService{}))
+	assert.NoError(t, registry.RegisterService(&mock2.SessionFactory{}))
+	manager := manager.New(registry)
+
+	v := &BlockingView{started: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		_, err := manager.InitiateView(v)
+		done <- err
+	}()
+	<-v.started
+	assert.Len(t, manager.RunningViews(), 1)
+
+	// the grace period expires while the view is still running
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := manager.Drain(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "views still running")
+
+	// new views are rejected
+	_, err = manager.InitiateView(&DummyView{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "shutting down")
+	_, err = manager.InitiateContext(&DummyView{})
+	assert.Error(t, err)
+
+	// the running view completes
+	close(v.release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, manager.Drain(context.Background()))
+	assert.Len(t, manager.RunningViews(), 0)
+}
+
 func registerFactory(t *testing.T, wg *sync.WaitGroup, m Manager) {
 	err := m.RegisterFactory(manager.GenerateUUID(), &DummyFactory{})
 	wg.Done()
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package manager
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+const (
+	initiatorRole = "initiator"
+	responderRole = "responder"
+)
+
+var (
+	startedViewsCounterOpts = metrics.CounterOpts{
+		Namespace:  "fsc",
+		Subsystem:  "view",
+		Name:       "started",
+		Help:       "Views started, by view and role (initiator or responder).",
+		LabelNames: []string{"view", "role"},
+	}
+
+	failedViewsCounterOpts = metrics.CounterOpts{
+		Namespace:  "fsc",
+		Subsystem:  "view",
+		Name:       "failed",
+		Help:       "Views terminated with an error, by view and role (initiator or responder).",
+		LabelNames: []string{"view", "role"},
+	}
+
+	runningViewsGaugeOpts = metrics.GaugeOpts{
+		Namespace:  "fsc",
+		Subsystem:  "view",
+		Name:       "running",
+		Help:       "Views currently running, by view and role (initiator or responder).",
+		LabelNames: []string{"view", "role"},
+	}
+)
+
+type Metrics struct {
+	StartedViews metrics.Counter
+	FailedViews  metrics.Counter
+	RunningViews metrics.Gauge
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		StartedViews: p.NewCounter(startedViewsCounterOpts),
+		FailedViews:  p.NewCounter(failedViewsCounterOpts),
+		RunningViews: p.NewGauge(runningViewsGaugeOpts),
+	}
+}
+
+// runView runs the passed view in the passed context keeping track of it in the view metrics
+// and in the list of running views
+func (cm *manager) runView(ctx view.Context, v view.View, role string) (interface{}, error) {
+	id := getIdentifier(v)
+	defer cm.trackRunning(ctx.ID(), id, role)()
+
+	labels := []string{"view", id, "role", role}
+	cm.metrics.StartedViews.With(labels...).Add(1)
+	running := cm.metrics.RunningViews.With(labels...)
+	running.Add(1)
+	defer running.Add(-1)
+
+	res, err := ctx.RunView(v)
+	if err != nil {
+		cm.metrics.FailedViews.With(labels...).Add(1)
+	}
+	return res, err
+}
@@ -50,6 +50,11 @@ func (m *Manager) InitiateContext(view View) (*Context, error) {
 	return &Context{c: context}, nil
 }
 
+// RunningViews returns the views currently running on this node
+func (m *Manager) RunningViews() []api.RunningView {
+	return m.m.RunningViews()
+}
+
 func GetManager(sp ServiceProvider) *Manager {
 	return &Manager{m: api.GetViewManager(sp)}
 }
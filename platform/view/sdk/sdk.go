@@ -23,10 +23,12 @@ import (
 	config2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/core/config"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/manager"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/sig"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/admin"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/assert"
 	comm2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm"
 	grpc2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/kvs"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/operations"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server"
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/protos"
 )
@@ -39,11 +41,20 @@ type Registry interface {
 	RegisterService(service interface{}) error
 }
 
+// reloadableConfig is a configuration provider that can re-read its source
+// and release, on close, the resources held for secrets
+type reloadableConfig interface {
+	Reload() error
+	Close() error
+}
+
 type p struct {
 	confPath   string
 	registry   Registry
+	config     reloadableConfig
 	grpcServer *grpc2.GRPCServer
 	viewServer server.Server
+	operations *operations.System
 
 	context context.Context
 }
@@ -58,6 +69,11 @@ func (p *p) Install() error {
 	configProvider, err := config2.NewProvider(p.confPath)
 	assert.NoError(err, "failed instantiating config provider")
 	assert.NoError(p.registry.RegisterService(configProvider), "failed registering config provider")
+	p.config = configProvider
+
+	// Operations
+	p.operations = operations.NewSystem(p.getOperationsOptions())
+	assert.NoError(p.registry.RegisterService(p.operations), "failed registering operations system")
 
 	// Sig Service
 	des, err := sig.NewMultiplexDeserializer(p.registry)
@@ -74,7 +90,11 @@ func (p *p) Install() error {
 		return fmt.Errorf("error creating view service response marshaller: %s", err)
 	}
 
-	p.viewServer, err = server.NewServer(marshaller, server.YesPolicyChecker{})
+	policyChecker, err := admin.NewPolicyCheckerFromConfig(configProvider)
+	if err != nil {
+		return errors.Wrap(err, "failed creating view service policy checker")
+	}
+	p.viewServer, err = server.NewServer(marshaller, policyChecker)
 	if err != nil {
 		return fmt.Errorf("error creating view service server: %s", err)
 	}
@@ -86,6 +106,15 @@ func (p *p) Install() error {
 	if err := p.registry.RegisterService(manager.New(p.registry)); err != nil {
 		return err
 	}
+	if err := admin.RegisterViews(api2.GetRegistry(p.registry)); err != nil {
+		return errors.Wrap(err, "failed registering admin views")
+	}
+	if configProvider.GetBool("fsc.p2p.faults.enabled") {
+		logger.Warnf("fault injection enabled, do not use in production")
+		if err := admin.RegisterFaultsView(api2.GetRegistry(p.registry)); err != nil {
+			return errors.Wrap(err, "failed registering faults view")
+		}
+	}
 
 	// KVS
 	driverName := view.GetConfigService(p.registry).GetString("fsc.kvs.persistence.type")
@@ -104,6 +133,7 @@ func (p *p) Install() error {
 func (p *p) Start(ctx context.Context) error {
 	p.context = ctx
 
+	assert.NoError(p.startOperations(), "failed starting operations system")
 	assert.NoError(p.startGRPCServer(), "failed starting grpc server")
 	assert.NoError(p.startCommLayer(), "failed starting comm layer")
 	assert.NoError(p.startViewServer(), "failed starting view server")
@@ -114,6 +144,14 @@ func (p *p) Start(ctx context.Context) error {
 	return p.serve()
 }
 
+func (p *p) startOperations() error {
+	if len(p.getOperationsOptions().ListenAddress) == 0 {
+		logger.Infof("Operations system not configured, skipping")
+		return nil
+	}
+	return p.operations.Start()
+}
+
 func (p *p) startGRPCServer() error {
 	configProvider := view.GetConfigService(p.registry)
 
@@ -163,9 +201,11 @@ func (p *p) startCommLayer() error {
 		view.GetEndpointService(p.registry),
 		view.GetConfigService(p.registry),
 		view.GetIdentityProvider(p.registry).DefaultIdentity(),
+		p.operations,
 	)
 	assert.NoError(err, "failed instantiating the communication service")
 	assert.NoError(p.registry.RegisterService(commService), "failed registering communication service")
+	assert.NoError(p.operations.RegisterChecker("comm", commService), "failed registering comm health checker")
 	commService.Start(p.context)
 
 	return nil
@@ -193,18 +233,85 @@ func (p *p) serve() error {
 			logger.Errorf("grpc server stopped with err [%s]", err)
 		}
 	}()
-	go func() {
-		select {
-		case <-p.context.Done():
-			logger.Info("Server stopping...")
-			p.grpcServer.Stop()
-			logger.Info("KVS stopping...")
-			kvs.GetService(p.registry).Stop()
+	return nil
+}
+
+// Drain makes the view manager reject new views and waits for the running ones to complete,
+// or for the passed context to be done. The gRPC server keeps serving in the meantime.
+func (p *p) Drain(ctx context.Context) error {
+	return api2.GetViewManager(p.registry).Drain(ctx)
+}
+
+// Stop stops the gRPC server, the KVS, and the operations system
+func (p *p) Stop() error {
+	logger.Info("Server stopping...")
+	p.grpcServer.Stop()
+	logger.Info("KVS stopping...")
+	kvs.GetService(p.registry).Stop()
+	if len(p.operations.Addr()) != 0 {
+		logger.Info("Operations system stopping...")
+		if err := p.operations.Stop(); err != nil {
+			return errors.Wrap(err, "failed stopping operations system")
 		}
-	}()
+	}
+	if err := p.config.Close(); err != nil {
+		return errors.WithMessage(err, "failed closing configuration")
+	}
+	return nil
+}
+
+// Reload re-reads the configuration, activating the logging spec if it changed, and the TLS material
+// of the gRPC server: its certificate and the client root CAs. The connections in place are not dropped,
+// the new handshakes use the new material.
+// The p2p identity key is not reloaded, since it determines the identity of the node in the p2p network.
+func (p *p) Reload() error {
+	if err := p.config.Reload(); err != nil {
+		return errors.WithMessage(err, "failed reloading configuration")
+	}
+	if p.grpcServer == nil || !p.grpcServer.TLSEnabled() {
+		return nil
+	}
+
+	serverConfig, err := p.getServerConfig()
+	if err != nil {
+		return errors.WithMessage(err, "failed reloading grpc server tls configuration")
+	}
+	if !serverConfig.SecOpts.UseTLS {
+		logger.Warnf("TLS cannot be disabled without restarting, keeping the current TLS configuration")
+		return nil
+	}
+	cert, err := tls.X509KeyPair(serverConfig.SecOpts.Certificate, serverConfig.SecOpts.Key)
+	if err != nil {
+		return errors.Wrap(err, "failed parsing grpc server tls key pair")
+	}
+	p.grpcServer.SetServerCertificate(cert)
+	if p.grpcServer.MutualTLSRequired() && len(serverConfig.SecOpts.ClientRootCAs) != 0 {
+		if err := p.grpcServer.SetClientRootCAs(serverConfig.SecOpts.ClientRootCAs); err != nil {
+			return errors.WithMessage(err, "failed setting grpc server client root CAs")
+		}
+	}
+	logger.Infof("Reloaded grpc server TLS certificate and client root CAs")
 	return nil
 }
 
+// WatchedPaths returns the configuration file and the TLS material of the gRPC server
+func (p *p) WatchedPaths() []string {
+	configProvider := view.GetConfigService(p.registry)
+
+	paths := []string{
+		configProvider.ConfigFileUsed(),
+		configProvider.GetPath("fsc.tls.cert.file"),
+		configProvider.GetPath("fsc.tls.key.file"),
+		configProvider.GetPath("fsc.tls.clientCert.file"),
+		configProvider.GetPath("fsc.tls.clientKey.file"),
+		configProvider.GetPath("fsc.tls.rootcert.file"),
+	}
+	for _, file := range configProvider.GetStringSlice("fsc.tls.clientRootCAs.files") {
+		paths = append(paths, configProvider.TranslatePath(file))
+	}
+	return paths
+}
+
 func (p *p) getLocalAddress() (string, error) {
 	configProvider := view.GetConfigService(p.registry)
 
@@ -298,6 +405,28 @@ func (p *p) getServerConfig() (grpc2.ServerConfig, error) {
 	return serverConfig, nil
 }
 
+func (p *p) getOperationsOptions() operations.Options {
+	configProvider := view.GetConfigService(p.registry)
+
+	var clientRootCAs []string
+	for _, file := range configProvider.GetStringSlice("fsc.operations.tls.clientRootCAs.files") {
+		clientRootCAs = append(clientRootCAs, configProvider.TranslatePath(file))
+	}
+	return operations.Options{
+		ListenAddress: configProvider.GetString("fsc.operations.listenAddress"),
+		Metrics: operations.MetricsOptions{
+			Provider: configProvider.GetString("fsc.metrics.provider"),
+		},
+		TLS: operations.TLS{
+			Enabled:            configProvider.GetBool("fsc.operations.tls.enabled"),
+			CertFile:           configProvider.GetPath("fsc.operations.tls.cert.file"),
+			KeyFile:            configProvider.GetPath("fsc.operations.tls.key.file"),
+			ClientCertRequired: configProvider.GetBool("fsc.operations.tls.clientAuthRequired"),
+			ClientCACertFiles:  clientRootCAs,
+		},
+	}
+}
+
 func (p *p) getClientCertificate() (tls.Certificate, error) {
 	configProvider := view.GetConfigService(p.registry)
 
@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"strings"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.admin")
+
+// Prefix is the prefix of the identifiers of the admin views.
+// Views whose identifier starts with this prefix can be called only by admin identities.
+const Prefix = "fsc.admin."
+
+const (
+	// StatusViewID is the identifier of the view that returns the Status of the node
+	StatusViewID = Prefix + "status"
+	// PeersViewID is the identifier of the view that returns the peers known to the p2p layer of the node
+	PeersViewID = Prefix + "peers"
+	// ContextsViewID is the identifier of the view that returns the views currently running on the node
+	ContextsViewID = Prefix + "contexts"
+	// LogSpecViewID is the identifier of the view that returns, and optionally sets, the logging spec of the node
+	LogSpecViewID = Prefix + "logspec"
+)
+
+// IsAdminView returns true if the passed view identifier is reserved to the admin views
+func IsAdminView(fid string) bool {
+	return strings.HasPrefix(fid, Prefix)
+}
+
+// Registry is used to register the admin view factories
+type Registry interface {
+	RegisterFactory(id string, factory api.Factory) error
+}
+
+// RegisterViews registers the factories of the admin views of the view platform
+func RegisterViews(r Registry) error {
+	for fid, factory := range map[string]api.Factory{
+		StatusViewID:   &StatusViewFactory{},
+		PeersViewID:    &PeersViewFactory{},
+		ContextsViewID: &ContextsViewFactory{},
+		LogSpecViewID:  &LogSpecViewFactory{},
+	} {
+		if err := r.RegisterFactory(fid, factory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"hash"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/core/config"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/client"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+	hash2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/hash"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/id/ecdsa"
+)
+
+// ViewClient calls views on an FSC node
+type ViewClient interface {
+	CallView(fid string, input []byte) (interface{}, error)
+}
+
+// Client calls the views of a running FSC node, admin views included, over its View Service
+type Client struct {
+	ViewClient ViewClient
+}
+
+// NewClient returns a Client for the FSC node whose configuration, core.yaml, is in the passed folder.
+// The client connects to fsc.address and signs its requests with the node identity, which is an admin by default.
+func NewClient(confPath string) (*Client, error) {
+	cp, err := config.NewProvider(confPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed loading configuration from [%s]", confPath)
+	}
+
+	connectionConfig := &grpc.ConnectionConfig{
+		Address:           cp.GetString("fsc.address"),
+		ConnectionTimeout: 10 * time.Second,
+		TLSEnabled:        cp.GetBool("fsc.tls.enabled"),
+	}
+	if connectionConfig.TLSEnabled {
+		connectionConfig.TLSRootCertFile = cp.GetPath("fsc.tls.rootcert.file")
+	}
+	if len(connectionConfig.Address) == 0 {
+		return nil, errors.New("fsc.address isn't set")
+	}
+
+	sID, err := newSigningIdentity(cp.GetPath("fsc.identity.cert.file"), cp.GetPath("fsc.identity.key.file"))
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(
+		&client.Config{ID: cp.GetString("fsc.id"), FSCNode: connectionConfig},
+		sID,
+		&sha256Hasher{},
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed creating client for [%s]", connectionConfig.Address)
+	}
+	return &Client{ViewClient: c}, nil
+}
+
+// Call calls the passed view with the JSON encoding of the passed input, if not nil,
+// and returns the raw result
+func (c *Client) Call(fid string, input interface{}) ([]byte, error) {
+	var in []byte
+	if input != nil {
+		var err error
+		in, err = json.Marshal(input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed marshalling input of [%s]", fid)
+		}
+	}
+	res, err := c.ViewClient.CallView(fid, in)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := res.([]byte)
+	if !ok {
+		return nil, errors.Errorf("expected []byte from [%s], got [%T]", fid, res)
+	}
+	return raw, nil
+}
+
+type signingIdentity struct {
+	cert   []byte
+	signer api.Signer
+}
+
+func newSigningIdentity(certFile, keyFile string) (*signingIdentity, error) {
+	cert, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading identity certificate [%s]", certFile)
+	}
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading identity key [%s]", keyFile)
+	}
+	signer, err := ecdsa.NewSignerFromPEM(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed loading identity key [%s]", keyFile)
+	}
+	return &signingIdentity{cert: cert, signer: signer}, nil
+}
+
+func (s *signingIdentity) Serialize() ([]byte, error) {
+	return s.cert, nil
+}
+
+func (s *signingIdentity) Sign(msg []byte) ([]byte, error) {
+	return s.signer.Sign(msg)
+}
+
+type sha256Hasher struct{}
+
+func (h *sha256Hasher) GetHash() hash.Hash {
+	return sha256.New()
+}
+
+func (h *sha256Hasher) Hash(msg []byte) ([]byte, error) {
+	return hash2.SHA256(msg)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// FaultsViewID is the identifier of the view that injects faults in the communication with the other nodes.
+// The view is a test hook, it is registered only if fsc.p2p.faults.enabled is set.
+const FaultsViewID = Prefix + "faults"
+
+// Faults sets, or clears, the fault injected in the communication with a node.
+// The result of FaultsView is the map of the fault rules in place, by p2p identifier.
+type Faults struct {
+	// Node is the name, or any label the endpoint service binds to an identity, of the node.
+	// If empty, Clear clears all the rules and no rule is set.
+	Node string
+	// Rule is the fault to inject
+	Rule comm.FaultRule
+	// Clear clears the rule for the node instead of setting it
+	Clear bool
+}
+
+type FaultsView struct {
+	*Faults
+}
+
+func (f *FaultsView) Call(context view.Context) (interface{}, error) {
+	commService, err := getCommService(context)
+	if err != nil {
+		return nil, err
+	}
+	faults := commService.Faults()
+
+	var peerID string
+	if len(f.Node) != 0 {
+		es := view2.GetEndpointService(context)
+		id, err := es.GetIdentity(f.Node, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed getting identity of [%s]", f.Node)
+		}
+		_, _, pkid, err := es.Resolve(id)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed resolving [%s]", f.Node)
+		}
+		peerID = string(pkid)
+	}
+
+	switch {
+	case f.Clear:
+		faults.Clear(peerID)
+		logger.Infof("cleared faults for [%s]", f.Node)
+	case len(peerID) != 0:
+		faults.Set(peerID, f.Rule)
+		logger.Infof("injected fault [%+v] for [%s]", f.Rule, f.Node)
+	}
+	return faults.Rules(), nil
+}
+
+type FaultsViewFactory struct{}
+
+func (f *FaultsViewFactory) NewView(in []byte) (view.View, error) {
+	v := &FaultsView{Faults: &Faults{}}
+	if len(in) != 0 {
+		if err := json.Unmarshal(in, v.Faults); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling input")
+		}
+	}
+	return v, nil
+}
+
+// RegisterFaultsView registers the factory of the view injecting faults
+func RegisterFaultsView(r Registry) error {
+	return r.RegisterFactory(FaultsViewID, &FaultsViewFactory{})
+}
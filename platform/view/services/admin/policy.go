@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/id/ecdsa"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/protos"
+)
+
+type ConfigProvider interface {
+	GetPath(key string) string
+	GetStringSlice(key string) []string
+	TranslatePath(path string) string
+}
+
+// PolicyChecker lets only the admin identities call, or initiate, the admin views.
+// Any other command is accepted.
+type PolicyChecker struct {
+	admins [][]byte
+}
+
+// NewPolicyChecker returns a PolicyChecker whose admins are the passed PEM encoded x509 certificates
+func NewPolicyChecker(admins ...[]byte) (*PolicyChecker, error) {
+	p := &PolicyChecker{}
+	for _, admin := range admins {
+		cert, err := parseCertificate(admin)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed parsing admin certificate")
+		}
+		p.admins = append(p.admins, cert.Raw)
+	}
+	return p, nil
+}
+
+// NewPolicyCheckerFromConfig returns a PolicyChecker whose admins are the node identity, fsc.identity.cert.file,
+// if set, and the identities listed under fsc.admin.identities.files.
+// With no admins, the admin views are closed and any other command is accepted, as before.
+func NewPolicyCheckerFromConfig(cp ConfigProvider) (*PolicyChecker, error) {
+	var files []string
+	if file := cp.GetPath("fsc.identity.cert.file"); len(file) != 0 {
+		files = append(files, file)
+	}
+	for _, file := range cp.GetStringSlice("fsc.admin.identities.files") {
+		files = append(files, cp.TranslatePath(file))
+	}
+	var admins [][]byte
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading admin certificate [%s]", file)
+		}
+		admins = append(admins, raw)
+	}
+	return NewPolicyChecker(admins...)
+}
+
+func (p *PolicyChecker) Check(sc *protos.SignedCommand, c *protos.Command) error {
+	var fid string
+	switch payload := c.Payload.(type) {
+	case *protos.Command_CallView:
+		fid = payload.CallView.Fid
+	case *protos.Command_InitiateView:
+		fid = payload.InitiateView.Fid
+	default:
+		return nil
+	}
+	if !IsAdminView(fid) {
+		return nil
+	}
+
+	cert, err := parseCertificate(c.Header.Creator)
+	if err != nil {
+		return errors.Wrapf(err, "access to [%s] denied, invalid creator", fid)
+	}
+	if !p.isAdmin(cert) {
+		return errors.Errorf("access to [%s] denied, creator is not an admin", fid)
+	}
+	_, verifier, err := ecdsa.NewIdentityFromPEMCert(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	if err != nil {
+		return errors.Wrapf(err, "access to [%s] denied, invalid creator", fid)
+	}
+	if err := verifier.Verify(sc.Command, sc.Signature); err != nil {
+		return errors.Wrapf(err, "access to [%s] denied, invalid signature", fid)
+	}
+	return nil
+}
+
+func (p *PolicyChecker) isAdmin(cert *x509.Certificate) bool {
+	for _, admin := range p.admins {
+		if bytes.Equal(admin, cert.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCertificate parses the passed identity either as a PEM encoded x509 certificate
+// or as an MSP serialized identity carrying one
+func parseCertificate(id []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(id)
+	if block == nil {
+		si := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(id, si); err != nil {
+			return nil, errors.Wrap(err, "identity is neither a PEM certificate nor a serialized identity")
+		}
+		block, _ = pem.Decode(si.IdBytes)
+		if block == nil {
+			return nil, errors.New("serialized identity does not carry a PEM certificate")
+		}
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
+	ecdsa2 "github.com/hyperledger-labs/fabric-smart-client/platform/view/services/id/ecdsa"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/server/protos"
+)
+
+type identity struct {
+	cert   []byte
+	signer api.Signer
+}
+
+func newIdentity(t *testing.T, cn string) *identity {
+	sk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &sk.PublicKey, sk)
+	assert.NoError(t, err)
+	raw, err := x509.MarshalPKCS8PrivateKey(sk)
+	assert.NoError(t, err)
+	signer, err := ecdsa2.NewSignerFromPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw}))
+	assert.NoError(t, err)
+	return &identity{cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), signer: signer}
+}
+
+func (id *identity) signedCommand(t *testing.T, creator []byte, payload interface{}) (*protos.SignedCommand, *protos.Command) {
+	command := &protos.Command{Header: &protos.Header{Creator: creator}}
+	switch p := payload.(type) {
+	case *protos.Command_CallView:
+		command.Payload = p
+	case *protos.Command_InitiateView:
+		command.Payload = p
+	case *protos.Command_IsTxFinal:
+		command.Payload = p
+	}
+	raw, err := proto.Marshal(command)
+	assert.NoError(t, err)
+	sigma, err := id.signer.Sign(raw)
+	assert.NoError(t, err)
+	return &protos.SignedCommand{Command: raw, Signature: sigma}, command
+}
+
+func TestPolicyChecker(t *testing.T) {
+	admin := newIdentity(t, "admin")
+	other := newIdentity(t, "other")
+
+	pc, err := NewPolicyChecker(admin.cert)
+	assert.NoError(t, err)
+
+	callStatus := &protos.Command_CallView{CallView: &protos.CallView{Fid: StatusViewID}}
+
+	// admin views are open to admins only
+	assert.NoError(t, pc.Check(admin.signedCommand(t, admin.cert, callStatus)))
+	assert.NoError(t, pc.Check(admin.signedCommand(t, admin.cert, &protos.Command_InitiateView{InitiateView: &protos.InitiateView{Fid: ContextsViewID}})))
+	err = pc.Check(other.signedCommand(t, other.cert, callStatus))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "creator is not an admin")
+
+	// the signature must be valid
+	err = pc.Check(other.signedCommand(t, admin.cert, callStatus))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid signature")
+
+	// admins can be carried by MSP serialized identities
+	si, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "org", IdBytes: admin.cert})
+	assert.NoError(t, err)
+	assert.NoError(t, pc.Check(admin.signedCommand(t, si, callStatus)))
+
+	// anything else is open to everybody
+	assert.NoError(t, pc.Check(other.signedCommand(t, other.cert, &protos.Command_CallView{CallView: &protos.CallView{Fid: "transfer"}})))
+	assert.NoError(t, pc.Check(other.signedCommand(t, other.cert, &protos.Command_IsTxFinal{IsTxFinal: &protos.IsTxFinal{Txid: "tx"}})))
+}
+
+type configProvider map[string][]string
+
+func (c configProvider) GetPath(key string) string {
+	if v := c[key]; len(v) != 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c configProvider) GetStringSlice(key string) []string {
+	return c[key]
+}
+
+func (c configProvider) TranslatePath(path string) string {
+	return path
+}
+
+func TestPolicyCheckerFromConfig(t *testing.T) {
+	other := newIdentity(t, "other")
+	callStatus := &protos.Command_CallView{CallView: &protos.CallView{Fid: StatusViewID}}
+
+	// no node identity and no admins, only the admin views are closed
+	pc, err := NewPolicyCheckerFromConfig(configProvider{})
+	assert.NoError(t, err)
+	assert.Error(t, pc.Check(other.signedCommand(t, other.cert, callStatus)))
+	assert.NoError(t, pc.Check(other.signedCommand(t, other.cert, &protos.Command_CallView{CallView: &protos.CallView{Fid: "transfer"}})))
+
+	_, err = NewPolicyCheckerFromConfig(configProvider{"fsc.identity.cert.file": {"/no/such/file"}})
+	assert.Error(t, err)
+}
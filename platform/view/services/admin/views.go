@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/api"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/comm"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/view"
+)
+
+// Status describes an FSC node
+type Status struct {
+	ID                 string
+	Address            string
+	DefaultIdentity    string
+	P2PID              string
+	P2PListenAddresses []string
+	Peers              int
+	RunningViews       int
+}
+
+type StatusView struct{}
+
+func (s *StatusView) Call(context view.Context) (interface{}, error) {
+	configService := view2.GetConfigService(context)
+	status := &Status{
+		ID:              configService.GetString("fsc.id"),
+		Address:         configService.GetString("fsc.address"),
+		DefaultIdentity: view2.GetIdentityProvider(context).DefaultIdentity().String(),
+		RunningViews:    len(view2.GetManager(context).RunningViews()),
+	}
+	commService, err := getCommService(context)
+	if err != nil {
+		return nil, err
+	}
+	status.P2PID = commService.ID()
+	status.P2PListenAddresses = commService.ListenAddresses()
+	status.Peers = len(commService.Peers())
+
+	return status, nil
+}
+
+type StatusViewFactory struct{}
+
+func (s *StatusViewFactory) NewView(in []byte) (view.View, error) {
+	return &StatusView{}, nil
+}
+
+type PeersView struct{}
+
+func (p *PeersView) Call(context view.Context) (interface{}, error) {
+	commService, err := getCommService(context)
+	if err != nil {
+		return nil, err
+	}
+	return commService.Peers(), nil
+}
+
+type PeersViewFactory struct{}
+
+func (p *PeersViewFactory) NewView(in []byte) (view.View, error) {
+	return &PeersView{}, nil
+}
+
+type ContextsView struct{}
+
+func (c *ContextsView) Call(context view.Context) (interface{}, error) {
+	// do not report the view serving this request
+	var res []api.RunningView
+	for _, rv := range view2.GetManager(context).RunningViews() {
+		if rv.ContextID != context.ID() {
+			res = append(res, rv)
+		}
+	}
+	return res, nil
+}
+
+type ContextsViewFactory struct{}
+
+func (c *ContextsViewFactory) NewView(in []byte) (view.View, error) {
+	return &ContextsView{}, nil
+}
+
+// LogSpec carries a logging spec.
+// When passed to LogSpecView with a non-empty Spec, the spec is activated.
+type LogSpec struct {
+	Spec string
+}
+
+type LogSpecView struct {
+	*LogSpec
+}
+
+func (l *LogSpecView) Call(context view.Context) (interface{}, error) {
+	if len(l.Spec) != 0 {
+		if err := flogging.Global.ActivateSpec(l.Spec); err != nil {
+			return nil, errors.Wrapf(err, "failed activating logging spec [%s]", l.Spec)
+		}
+		logger.Infof("activated logging spec [%s]", l.Spec)
+	}
+	return &LogSpec{Spec: flogging.Global.Spec()}, nil
+}
+
+type LogSpecViewFactory struct{}
+
+func (l *LogSpecViewFactory) NewView(in []byte) (view.View, error) {
+	f := &LogSpecView{LogSpec: &LogSpec{}}
+	if len(in) != 0 {
+		if err := json.Unmarshal(in, f.LogSpec); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling input")
+		}
+	}
+	return f, nil
+}
+
+func getCommService(sp view2.ServiceProvider) (*comm.Service, error) {
+	s, err := sp.GetService(&comm.Service{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed getting communication service")
+	}
+	return s.(*comm.Service), nil
+}
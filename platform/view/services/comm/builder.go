@@ -12,6 +12,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -21,7 +22,7 @@ import (
 	"github.com/multiformats/go-multiaddr"
 )
 
-func newHost(ListenAddress string, keyDispenser PrivateKeyDispenser) (*P2PNode, error) {
+func newHost(ListenAddress string, keyDispenser PrivateKeyDispenser, metricsProvider metrics.Provider) (*P2PNode, error) {
 	priv, err := keyDispenser.PrivateKey()
 	if err != nil {
 		return nil, err
@@ -61,6 +62,8 @@ func newHost(ListenAddress string, keyDispenser PrivateKeyDispenser) (*P2PNode,
 		streams:          make(map[peer.ID][]*streamHandler),
 		sessions:         make(map[string]*NetworkStreamSession),
 		isStopping:       false,
+		metrics:          NewMetrics(metricsProvider),
+		faults:           NewFaults(),
 	}
 
 	return node, err
@@ -87,8 +90,8 @@ func (p *PrivateKeyFromFile) PrivateKey() (crypto.PrivKey, error) {
 	return crypto.UnmarshalECDSAPrivateKey(privBytes)
 }
 
-func NewBootstrapNode(ListenAddress string, keyDispenser PrivateKeyDispenser) (*P2PNode, error) {
-	node, err := newHost(ListenAddress, keyDispenser)
+func NewBootstrapNode(ListenAddress string, keyDispenser PrivateKeyDispenser, metricsProvider metrics.Provider) (*P2PNode, error) {
+	node, err := newHost(ListenAddress, keyDispenser, metricsProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +103,8 @@ func NewBootstrapNode(ListenAddress string, keyDispenser PrivateKeyDispenser) (*
 	return node, nil
 }
 
-func NewNode(ListenAddress, BootstrapNode string, keyDispenser PrivateKeyDispenser) (*P2PNode, error) {
-	node, err := newHost(ListenAddress, keyDispenser)
+func NewNode(ListenAddress, BootstrapNode string, keyDispenser PrivateKeyDispenser, metricsProvider metrics.Provider) (*P2PNode, error) {
+	node, err := newHost(ListenAddress, keyDispenser, metricsProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ package comm
 import (
 	"context"
 
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger-labs/fabric-smart-client/platform/view"
@@ -28,6 +29,7 @@ type Service struct {
 	EndpointService     EndpointService
 	ConfigService       ConfigService
 	DefaultIdentity     view2.Identity
+	MetricsProvider     metrics.Provider
 	Node                *P2PNode
 }
 
@@ -36,12 +38,14 @@ func NewService(
 	endpointService EndpointService,
 	configService ConfigService,
 	defaultIdentity view2.Identity,
+	metricsProvider metrics.Provider,
 ) (*Service, error) {
 	s := &Service{
 		PrivateKeyDispenser: privateKeyDispenser,
 		EndpointService:     endpointService,
 		ConfigService:       configService,
 		DefaultIdentity:     defaultIdentity,
+		MetricsProvider:     metricsProvider,
 	}
 	if err := s.init(); err != nil {
 		return nil, err
@@ -57,6 +61,31 @@ func (s *Service) Stop() {
 	s.Node.Stop()
 }
 
+// HealthCheck returns an error if the p2p node is stopping or not listening
+func (s *Service) HealthCheck(ctx context.Context) error {
+	return s.Node.HealthCheck(ctx)
+}
+
+// ID returns the p2p identifier of this node
+func (s *Service) ID() string {
+	return s.Node.ID()
+}
+
+// ListenAddresses returns the multiaddresses the p2p node listens to
+func (s *Service) ListenAddresses() []string {
+	return s.Node.ListenAddresses()
+}
+
+// Faults returns the faults injected in the communication with the other nodes
+func (s *Service) Faults() *Faults {
+	return s.Node.Faults()
+}
+
+// Peers returns the peers discovered so far by the p2p node
+func (s *Service) Peers() []PeerInfo {
+	return s.Node.Peers()
+}
+
 func (s *Service) NewSessionWithID(sessionID, contextID, endpoint string, pkid []byte, caller view2.Identity, msg *view2.Message) (view2.Session, error) {
 	return s.Node.NewSessionWithID(sessionID, contextID, endpoint, pkid, caller, msg)
 }
@@ -83,6 +112,7 @@ func (s *Service) init() error {
 		s.Node, err = NewBootstrapNode(
 			p2pListenAddress,
 			s.PrivateKeyDispenser,
+			s.MetricsProvider,
 		)
 		if err != nil {
 			return errors.Wrapf(err, "failed initializing bootstrap p2p manager [%s,%s,%s]", p2pListenAddress, endpoint, pkid)
@@ -102,6 +132,7 @@ func (s *Service) init() error {
 			p2pListenAddress,
 			AddressToEndpoint(endpoints[view.P2PPort])+"/p2p/"+string(pkID),
 			s.PrivateKeyDispenser,
+			s.MetricsProvider,
 		)
 		if err != nil {
 			return errors.Wrapf(err, "failed initializing node p2p manager [%s,%s]", p2pListenAddress, AddressToEndpoint(endpoints[view.P2PPort])+"/p2p/"+string(pkID))
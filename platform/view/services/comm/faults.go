@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package comm
+
+import (
+	"sync"
+	"time"
+)
+
+// FaultRule describes the fault injected in the communication with a peer
+type FaultRule struct {
+	// Drop drops the messages sent to and received from the peer
+	Drop bool
+	// Delay delays the messages sent to and received from the peer
+	Delay time.Duration
+}
+
+// Faults injects faults in the communication with the other nodes, it is a test hook
+// to check that views recover from partitions and slow links
+type Faults struct {
+	mutex sync.RWMutex
+	rules map[string]FaultRule
+}
+
+func NewFaults() *Faults {
+	return &Faults{rules: map[string]FaultRule{}}
+}
+
+// Set sets the fault rule for the peer with the passed p2p identifier
+func (f *Faults) Set(peerID string, rule FaultRule) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rules[peerID] = rule
+}
+
+// Clear removes the fault rule for the peer with the passed p2p identifier, all rules if empty
+func (f *Faults) Clear(peerID string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(peerID) == 0 {
+		f.rules = map[string]FaultRule{}
+		return
+	}
+	delete(f.rules, peerID)
+}
+
+// Rules returns the fault rules by p2p identifier
+func (f *Faults) Rules() map[string]FaultRule {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	res := make(map[string]FaultRule, len(f.rules))
+	for id, rule := range f.rules {
+		res[id] = rule
+	}
+	return res
+}
+
+// apply applies the fault rule for the passed peer, if any, and returns true if the message must be dropped
+func (f *Faults) apply(peerID string) bool {
+	f.mutex.RLock()
+	rule, ok := f.rules[peerID]
+	f.mutex.RUnlock()
+	if !ok {
+		return false
+	}
+	if rule.Drop {
+		return true
+	}
+	if rule.Delay > 0 {
+		time.Sleep(rule.Delay)
+	}
+	return false
+}
@@ -47,6 +47,7 @@ func (p *P2PNode) getOrCreateSession(sessionID, endpointAddress, contextID, call
 	}
 
 	p.sessions[internalSessionID] = s
+	p.metrics.OpenedSessions.Add(1)
 
 	logger.Infof("session [%s] as internal session [%s] ready", sessionID, internalSessionID)
 
@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+var (
+	openedSessionsCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "sessions_opened",
+		Help:      "Sessions opened. Opened minus closed is the number of active sessions.",
+	}
+
+	closedSessionsCounterOpts = metrics.CounterOpts{
+		Namespace: "fsc",
+		Subsystem: "comm",
+		Name:      "sessions_closed",
+		Help:      "Sessions closed. Opened minus closed is the number of active sessions.",
+	}
+)
+
+type Metrics struct {
+	OpenedSessions metrics.Counter
+	ClosedSessions metrics.Counter
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		OpenedSessions: p.NewCounter(openedSessionsCounterOpts),
+		ClosedSessions: p.NewCounter(closedSessionsCounterOpts),
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
@@ -122,7 +123,7 @@ func newBootstrapNode(port int) (*node, error) {
 	}
 	nodeEndpoint := "/ip4/127.0.0.1/tcp/" + strconv.Itoa(port)
 	nodeDHTEndpoint := nodeEndpoint + "/p2p/" + nodeID
-	p2pNode, err := NewBootstrapNode(nodeEndpoint, &PrivateKeyFromCryptoKey{Key: sk})
+	p2pNode, err := NewBootstrapNode(nodeEndpoint, &PrivateKeyFromCryptoKey{Key: sk}, &disabled.Provider{})
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +147,7 @@ func newNode(port int, bootstrapNode *node) (*node, error) {
 	}
 	nodeEndpoint := "/ip4/127.0.0.1/tcp/" + strconv.Itoa(port)
 
-	p2pNode, err := NewNode(nodeEndpoint, bootstrapNode.DHTEndpoint, &PrivateKeyFromCryptoKey{Key: sk})
+	p2pNode, err := NewNode(nodeEndpoint, bootstrapNode.DHTEndpoint, &PrivateKeyFromCryptoKey{Key: sk}, &disabled.Provider{})
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,7 @@ import (
 	"encoding/binary"
 	"errors"
 	io2 "io"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -58,6 +59,8 @@ type P2PNode struct {
 	stopFinder       int32
 	finderWg         sync.WaitGroup
 	isStopping       bool
+	metrics          *Metrics
+	faults           *Faults
 }
 
 func (p *P2PNode) Start(ctx context.Context) {
@@ -162,6 +165,11 @@ func (p *P2PNode) sendTo(IDString string, msg proto.Message) error {
 		return err
 	}
 
+	if p.faults.apply(IDString) {
+		logger.Debugf("dropping message to [%s], fault injected", IDString)
+		return nil
+	}
+
 	if err := p.sendWithCachedStreams(ID, msg); err != errStreamNotFound {
 		return err
 	}
@@ -193,6 +201,20 @@ func (p *P2PNode) handleStream() network.StreamHandler {
 	}
 }
 
+// HealthCheck returns an error if the node is stopping or its host does not listen to any address
+func (p *P2PNode) HealthCheck(ctx context.Context) error {
+	p.streamsMutex.RLock()
+	stopping := p.isStopping
+	p.streamsMutex.RUnlock()
+	if stopping {
+		return errors.New("p2p node is stopping")
+	}
+	if len(p.host.Network().ListenAddresses()) == 0 {
+		return errors.New("p2p node is not listening")
+	}
+	return nil
+}
+
 func (p *P2PNode) Lookup(peerID string) (peer.AddrInfo, bool) {
 	p.peersMutex.RLock()
 	defer p.peersMutex.RUnlock()
@@ -201,6 +223,48 @@ func (p *P2PNode) Lookup(peerID string) (peer.AddrInfo, bool) {
 	return peer, in
 }
 
+// PeerInfo describes a peer discovered by the p2p node
+type PeerInfo struct {
+	ID        string
+	Addresses []string
+}
+
+// ID returns the p2p identifier of this node
+func (p *P2PNode) ID() string {
+	return p.host.ID().String()
+}
+
+// Faults returns the faults injected in the communication with the other nodes
+func (p *P2PNode) Faults() *Faults {
+	return p.faults
+}
+
+// ListenAddresses returns the multiaddresses this node listens to
+func (p *P2PNode) ListenAddresses() []string {
+	var res []string
+	for _, addr := range p.host.Network().ListenAddresses() {
+		res = append(res, addr.String())
+	}
+	return res
+}
+
+// Peers returns the peers discovered so far, sorted by identifier
+func (p *P2PNode) Peers() []PeerInfo {
+	p.peersMutex.RLock()
+	defer p.peersMutex.RUnlock()
+
+	res := make([]PeerInfo, 0, len(p.peers))
+	for id, info := range p.peers {
+		peerInfo := PeerInfo{ID: id}
+		for _, addr := range info.Addrs {
+			peerInfo.Addresses = append(peerInfo.Addresses, addr.String())
+		}
+		res = append(res, peerInfo)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res
+}
+
 type streamHandler struct {
 	stream network.Stream
 	reader protoio.ReadCloser
@@ -240,6 +304,11 @@ func (s *streamHandler) handleIncoming() {
 		}
 		logger.Debugf("incoming message from [%s] on session [%s]", msg.Caller, msg.SessionID)
 
+		if s.node.faults.apply(s.stream.Conn().RemotePeer().String()) {
+			logger.Debugf("dropping message from [%s], fault injected", s.stream.Conn().RemotePeer())
+			continue
+		}
+
 		s.node.incomingMessages <- &messageWithStream{
 			message: &view.Message{
 				ContextID:    msg.ContextID,
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/stretchr/testify/assert"
@@ -31,7 +32,7 @@ func idForParty(t *testing.T, keyFile string) string {
 }
 
 func getBootstrapNode(t *testing.T, bootstrapNodeEndpoint string, keyDispenser PrivateKeyDispenser) *P2PNode {
-	node, err := NewBootstrapNode(bootstrapNodeEndpoint, keyDispenser)
+	node, err := NewBootstrapNode(bootstrapNodeEndpoint, keyDispenser, &disabled.Provider{})
 	assert.NoError(t, err)
 	assert.NotNil(t, node)
 
@@ -40,7 +41,7 @@ func getBootstrapNode(t *testing.T, bootstrapNodeEndpoint string, keyDispenser P
 
 func getNode(t *testing.T, bootstrapNodeID, bootstrapNodeEndpoint, nodeEndpoint string, keyDispenser PrivateKeyDispenser) *P2PNode {
 	bootstrapNodeDHTEndpoint := bootstrapNodeEndpoint + "/p2p/" + bootstrapNodeID
-	node, err := NewNode(nodeEndpoint, bootstrapNodeDHTEndpoint, keyDispenser)
+	node, err := NewNode(nodeEndpoint, bootstrapNodeDHTEndpoint, keyDispenser, &disabled.Provider{})
 	assert.NoError(t, err)
 	assert.NotNil(t, node)
 
@@ -259,3 +260,36 @@ func SessionsForMPCTestRound(t *testing.T, bootstrapNode, node *P2PNode, bootstr
 	bootstrapNode.Stop()
 	node.Stop()
 }
+
+func TestFaults(t *testing.T) {
+	bootstrapNode, node, bootstrapNodeID, nodeID := setupTwoNodesFromFiles(t)
+	defer bootstrapNode.Stop()
+	defer node.Stop()
+
+	messages := node.incomingMessages
+
+	// incoming messages are dropped
+	node.Faults().Set(bootstrapNodeID, FaultRule{Drop: true})
+	assert.NoError(t, bootstrapNode.sendTo(nodeID, &ViewPacket{Payload: []byte("msg1")}))
+	time.Sleep(500 * time.Millisecond)
+	node.Faults().Clear(bootstrapNodeID)
+
+	// outgoing messages are dropped
+	bootstrapNode.Faults().Set(nodeID, FaultRule{Drop: true})
+	assert.NoError(t, bootstrapNode.sendTo(nodeID, &ViewPacket{Payload: []byte("msg2")}))
+	bootstrapNode.Faults().Clear("")
+	assert.Empty(t, bootstrapNode.Faults().Rules())
+
+	assert.NoError(t, bootstrapNode.sendTo(nodeID, &ViewPacket{Payload: []byte("msg3")}))
+	msg := <-messages
+	assert.Equal(t, []byte("msg3"), msg.message.Payload)
+
+	// outgoing messages are delayed
+	bootstrapNode.Faults().Set(nodeID, FaultRule{Delay: 200 * time.Millisecond})
+	assert.Equal(t, map[string]FaultRule{nodeID: {Delay: 200 * time.Millisecond}}, bootstrapNode.Faults().Rules())
+	start := time.Now()
+	assert.NoError(t, bootstrapNode.sendTo(nodeID, &ViewPacket{Payload: []byte("msg4")}))
+	msg = <-messages
+	assert.Equal(t, []byte("msg4"), msg.message.Payload)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+}
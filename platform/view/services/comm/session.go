@@ -76,6 +76,7 @@ func (n *NetworkStreamSession) Close() {
 	logger.Debugf("Closing session incoming [%s]", n.sessionID)
 	close(n.incoming)
 	n.closed = true
+	n.node.metrics.ClosedSessions.Add(1)
 
 	logger.Debugf("Closing session [%s] done", n.sessionID)
 }
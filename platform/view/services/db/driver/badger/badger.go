@@ -289,3 +289,52 @@ func (db *badgerDB) GetStateRangeScanIterator(namespace string, startKey string,
 		namespace: namespace,
 	}, nil
 }
+
+type entriesIterator struct {
+	txn *badger.Txn
+	it  *badger.Iterator
+}
+
+func (r *entriesIterator) Next() (*driver.VersionedEntry, error) {
+	if !r.it.Valid() {
+		return nil, nil
+	}
+
+	item := r.it.Item()
+	dbKey := string(item.Key())
+	v, err := versionedValue(item, dbKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error iterating on entry %s", dbKey)
+	}
+	sep := strings.Index(dbKey, keys.NamespaceSeparator)
+	if sep < 0 {
+		return nil, errors.Errorf("invalid key %s, no namespace", dbKey)
+	}
+
+	r.it.Next()
+
+	return &driver.VersionedEntry{
+		Namespace: dbKey[:sep],
+		Key:       dbKey[sep+1:],
+		Raw:       v.Value,
+		Metadata:  v.Meta,
+		Block:     v.Block,
+		TxNum:     v.Txnum,
+	}, nil
+}
+
+func (r *entriesIterator) Close() {
+	r.it.Close()
+	r.txn.Discard()
+}
+
+func (db *badgerDB) GetEntriesIterator() (driver.VersionedEntriesIterator, error) {
+	txn := db.db.NewTransaction(false)
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	it.Rewind()
+
+	return &entriesIterator{
+		txn: txn,
+		it:  it,
+	}, nil
+}
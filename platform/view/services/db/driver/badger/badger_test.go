@@ -285,6 +285,36 @@ func TestSimpleReadWrite(t *testing.T) {
 	assert.Equal(t, uint64(0), tn)
 }
 
+func TestEntriesIterator(t *testing.T) {
+	dbpath := filepath.Join(tempDir, "DB-TestEntriesIterator")
+	db, err := OpenDB(dbpath)
+	defer db.Close()
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.BeginUpdate())
+	assert.NoError(t, db.SetState("ns2", "k1", []byte("v1"), 1, 0))
+	assert.NoError(t, db.SetState("ns1", "k\x00a", []byte("v2"), 2, 1))
+	assert.NoError(t, db.SetStateMetadata("ns1", "k\x00a", map[string][]byte{"m": []byte("v")}, 2, 1))
+	assert.NoError(t, db.Commit())
+
+	itr, err := db.GetEntriesIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	var entries []*driver.VersionedEntry
+	for {
+		e, err := itr.Next()
+		assert.NoError(t, err)
+		if e == nil {
+			break
+		}
+		entries = append(entries, e)
+	}
+	assert.Equal(t, []*driver.VersionedEntry{
+		{Namespace: "ns1", Key: "k\x00a", Raw: []byte("v2"), Metadata: map[string][]byte{"m": []byte("v")}, Block: 2, TxNum: 1},
+		{Namespace: "ns2", Key: "k1", Raw: []byte("v1"), Block: 1},
+	}, entries)
+}
+
 func TestMain(m *testing.M) {
 	var err error
 	tempDir, err = ioutil.TempDir("", "badger-fsc-test")
@@ -33,6 +33,23 @@ type VersionedResultsIterator interface {
 	Close()
 }
 
+// VersionedEntry is a key of a versioned persistence, with its value, metadata and version
+type VersionedEntry struct {
+	Namespace string
+	Key       string
+	Raw       []byte
+	Metadata  map[string][]byte `json:",omitempty"`
+	Block     uint64
+	TxNum     uint64
+}
+
+type VersionedEntriesIterator interface {
+	// Next returns the next entry, nil when the iterator gets exhausted
+	Next() (*VersionedEntry, error)
+	// Close releases resources occupied by the iterator
+	Close()
+}
+
 type VersionedPersistence interface {
 	SetState(namespace, key string, value []byte, block, txnum uint64) error
 	GetState(namespace, key string) ([]byte, uint64, uint64, error)
@@ -40,6 +57,8 @@ type VersionedPersistence interface {
 	GetStateMetadata(namespace, key string) (map[string][]byte, uint64, uint64, error)
 	SetStateMetadata(namespace, key string, metadata map[string][]byte, block, txnum uint64) error
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (VersionedResultsIterator, error)
+	// GetEntriesIterator returns an iterator over all the entries, ordered by namespace and key
+	GetEntriesIterator() (VersionedEntriesIterator, error)
 	Close() error
 	BeginUpdate() error
 	Commit() error
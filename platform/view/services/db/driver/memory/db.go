@@ -179,6 +179,60 @@ func (db *db) GetStateRangeScanIterator(namespace string, startKey string, endKe
 	}, nil
 }
 
+// entriesIterator iterates over a copy of the entries taken when it was created
+type entriesIterator struct {
+	entries []*driver.VersionedEntry
+}
+
+func (r *entriesIterator) Next() (*driver.VersionedEntry, error) {
+	if len(r.entries) == 0 {
+		return nil, nil
+	}
+	e := r.entries[0]
+	r.entries = r.entries[1:]
+	return e, nil
+}
+
+func (r *entriesIterator) Close() {}
+
+func (db *db) GetEntriesIterator() (driver.VersionedEntriesIterator, error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	namespaces := make([]string, 0, len(db.keys))
+	for ns := range db.keys {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var entries []*driver.VersionedEntry
+	for _, ns := range namespaces {
+		keys := make([]string, 0, len(db.keys[ns]))
+		for k := range db.keys[ns] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			vv := db.keys[ns][k]
+			e := &driver.VersionedEntry{
+				Namespace: ns,
+				Key:       k,
+				Raw:       append([]byte(nil), vv.value...),
+				Block:     vv.block,
+				TxNum:     vv.txnum,
+			}
+			if len(vv.metadata) != 0 {
+				e.Metadata = map[string][]byte{}
+				for mk, mv := range vv.metadata {
+					e.Metadata[mk] = append([]byte(nil), mv...)
+				}
+			}
+			entries = append(entries, e)
+		}
+	}
+	return &entriesIterator{entries: entries}, nil
+}
+
 func (db *db) GetState(namespace string, key string) ([]byte, uint64, uint64, error) {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
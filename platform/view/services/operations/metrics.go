@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/prometheus"
+)
+
+// prometheusProvider is shared by all the operations systems of the process, as the metrics
+// of Fabric's prometheus provider are registered with the default prometheus registry.
+var prometheusProvider = newSharedProvider(&prometheus.Provider{})
+
+// sharedProvider wraps a metrics.Provider so that a metric is created only once and then shared
+// by all the components asking for it. Fabric's prometheus provider panics if a metric is registered twice.
+type sharedProvider struct {
+	provider metrics.Provider
+
+	lock       sync.Mutex
+	counters   map[string]metrics.Counter
+	gauges     map[string]metrics.Gauge
+	histograms map[string]metrics.Histogram
+}
+
+func newSharedProvider(provider metrics.Provider) *sharedProvider {
+	return &sharedProvider{
+		provider:   provider,
+		counters:   map[string]metrics.Counter{},
+		gauges:     map[string]metrics.Gauge{},
+		histograms: map[string]metrics.Histogram{},
+	}
+}
+
+func (p *sharedProvider) NewCounter(o metrics.CounterOpts) metrics.Counter {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := fqName(o.Namespace, o.Subsystem, o.Name)
+	c, ok := p.counters[k]
+	if !ok {
+		c = p.provider.NewCounter(o)
+		p.counters[k] = c
+	}
+	return c
+}
+
+func (p *sharedProvider) NewGauge(o metrics.GaugeOpts) metrics.Gauge {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := fqName(o.Namespace, o.Subsystem, o.Name)
+	g, ok := p.gauges[k]
+	if !ok {
+		g = p.provider.NewGauge(o)
+		p.gauges[k] = g
+	}
+	return g
+}
+
+func (p *sharedProvider) NewHistogram(o metrics.HistogramOpts) metrics.Histogram {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := fqName(o.Namespace, o.Subsystem, o.Name)
+	h, ok := p.histograms[k]
+	if !ok {
+		h = p.provider.NewHistogram(o)
+		p.histograms[k] = h
+	}
+	return h
+}
+
+func fqName(namespace, subsystem, name string) string {
+	var parts []string
+	for _, s := range []string{namespace, subsystem, name} {
+		if len(s) != 0 {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "_")
+}
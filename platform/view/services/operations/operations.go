@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+
+	view2 "github.com/hyperledger-labs/fabric-smart-client/platform/view"
+)
+
+// GetSystem returns the operations system registered in the passed service provider, nil if none is registered
+func GetSystem(sp view2.ServiceProvider) *System {
+	s, err := sp.GetService(&System{})
+	if err != nil {
+		return nil
+	}
+	return s.(*System)
+}
+
+// GetMetricsProvider returns the metrics provider of the operations system registered in the passed
+// service provider. If no operations system is registered, metrics are disabled.
+func GetMetricsProvider(sp view2.ServiceProvider) metrics.Provider {
+	if s := GetSystem(sp); s != nil {
+		return s.Provider
+	}
+	return &disabled.Provider{}
+}
+
+// RegisterChecker registers the passed health checker for the passed component with the operations system
+// registered in the passed service provider. If no operations system is registered, it does nothing.
+func RegisterChecker(sp view2.ServiceProvider, component string, checker healthz.HealthChecker) error {
+	if s := GetSystem(sp); s != nil {
+		return s.RegisterChecker(component, checker)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging"
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/flogging/httpadmin"
+)
+
+var logger = flogging.MustGetLogger("view-sdk.operations")
+
+type MetricsOptions struct {
+	// Provider is the metrics provider, one of [prometheus,disabled]
+	Provider string
+}
+
+type Options struct {
+	// ListenAddress is the address the operations server listens to
+	ListenAddress string
+	Metrics       MetricsOptions
+	TLS           TLS
+}
+
+// System is the operations server of an FSC node. It serves:
+// - /healthz, the status of the registered health checkers;
+// - /logspec, to get and set the logging spec;
+// - /metrics, the metrics in the Prometheus text exposition format, if the prometheus provider is selected.
+// System is also the metrics.Provider components use to create their metrics.
+type System struct {
+	metrics.Provider
+
+	options       Options
+	healthHandler *healthz.HealthHandler
+	httpServer    *http.Server
+	mux           *http.ServeMux
+	addr          string
+}
+
+func NewSystem(o Options) *System {
+	system := &System{
+		options: o,
+	}
+
+	system.initializeServer()
+	system.initializeHealthCheckHandler()
+	system.initializeLoggingHandler()
+	system.initializeMetricsProvider()
+
+	return system
+}
+
+// Start makes the operations server listen to the configured address
+func (s *System) Start() error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	s.addr = listener.Addr().String()
+	logger.Infof("operations server listening on [%s]", s.addr)
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("operations server stopped with err [%s]", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop shuts down the operations server
+func (s *System) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.httpServer.Shutdown(ctx)
+}
+
+// RegisterChecker registers a health checker for the passed component.
+// The checker is invoked each time /healthz is queried.
+func (s *System) RegisterChecker(component string, checker healthz.HealthChecker) error {
+	return s.healthHandler.RegisterChecker(component, checker)
+}
+
+// RegisterHandler mounts the passed handler under the passed pattern.
+// When TLS is enabled and client certificates are required, the handler requires a verified client certificate.
+func (s *System) RegisterHandler(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, s.handlerChain(handler, s.secure()))
+}
+
+// Addr returns the address the operations server listens to, once started
+func (s *System) Addr() string {
+	return s.addr
+}
+
+func (s *System) initializeServer() {
+	s.mux = http.NewServeMux()
+	s.httpServer = &http.Server{
+		Addr:         s.options.ListenAddress,
+		Handler:      s.mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 2 * time.Minute,
+	}
+}
+
+// secure returns true if the handlers, but /healthz, require a verified client certificate
+func (s *System) secure() bool {
+	return s.options.TLS.Enabled && s.options.TLS.ClientCertRequired
+}
+
+func (s *System) handlerChain(h http.Handler, secure bool) http.Handler {
+	if secure {
+		return middleware.NewChain(middleware.RequireCert(), middleware.WithRequestID(util.GenerateUUID)).Handler(h)
+	}
+	return middleware.NewChain(middleware.WithRequestID(util.GenerateUUID)).Handler(h)
+}
+
+func (s *System) initializeMetricsProvider() {
+	switch providerType := s.options.Metrics.Provider; providerType {
+	case "prometheus":
+		s.Provider = prometheusProvider
+		s.mux.Handle("/metrics", s.handlerChain(promhttp.Handler(), s.secure()))
+	default:
+		if len(providerType) != 0 && providerType != "disabled" {
+			logger.Warnf("unknown metrics provider type [%s], metrics disabled", providerType)
+		}
+		s.Provider = &disabled.Provider{}
+	}
+}
+
+func (s *System) initializeLoggingHandler() {
+	s.mux.Handle("/logspec", s.handlerChain(httpadmin.NewSpecHandler(), s.secure()))
+}
+
+func (s *System) initializeHealthCheckHandler() {
+	s.healthHandler = healthz.NewHealthHandler()
+	s.mux.Handle("/healthz", s.handlerChain(s.healthHandler, false))
+}
+
+func (s *System) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", s.options.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := s.options.TLS.Config()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	return listener, nil
+}
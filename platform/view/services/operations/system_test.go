@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type checker struct {
+	err error
+}
+
+func (c *checker) HealthCheck(ctx context.Context) error {
+	return c.err
+}
+
+func TestSystem(t *testing.T) {
+	s := NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		Metrics:       MetricsOptions{Provider: "prometheus"},
+	})
+	assert.NoError(t, s.Start())
+	defer s.Stop()
+	url := "http://" + s.Addr()
+
+	// health
+	c := &checker{}
+	assert.NoError(t, s.RegisterChecker("vault", c))
+	status, _ := get(t, url+"/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	c.err = errors.New("store closed")
+	status, body := get(t, url+"/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Contains(t, body, "store closed")
+
+	// metrics
+	s.NewCounter(metrics.CounterOpts{Namespace: "fsc", Name: "test", LabelNames: []string{"channel"}}).With("channel", "testchannel").Add(2)
+	// a metric created twice is shared
+	s.NewCounter(metrics.CounterOpts{Namespace: "fsc", Name: "test", LabelNames: []string{"channel"}}).With("channel", "testchannel").Add(1)
+	status, body = get(t, url+"/metrics")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `fsc_test{channel="testchannel"} 3`)
+
+	// logspec
+	status, body = get(t, url+"/logspec")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "spec")
+	req, err := http.NewRequest(http.MethodPut, url+"/logspec", strings.NewReader(`{"spec":"debug"}`))
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	_, body = get(t, url+"/logspec")
+	assert.Contains(t, body, "debug")
+}
+
+func TestSystemMetricsDisabled(t *testing.T) {
+	s := NewSystem(Options{ListenAddress: "127.0.0.1:0"})
+	assert.NoError(t, s.Start())
+	defer s.Stop()
+
+	status, _ := get(t, "http://"+s.Addr()+"/metrics")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
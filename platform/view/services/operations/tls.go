@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/fabric-smart-client/platform/view/services/grpc"
+)
+
+// TLS contains the TLS configuration of the operations server.
+// When ClientCertRequired is set, all the endpoints but /healthz require a client certificate
+// signed by one of the client CAs. /healthz is always reachable without a client certificate.
+type TLS struct {
+	Enabled            bool
+	CertFile           string
+	KeyFile            string
+	ClientCertRequired bool
+	ClientCACertFiles  []string
+}
+
+// Config returns the tls.Config of the operations server, nil if TLS is not enabled
+func (t TLS) Config() (*tls.Config, error) {
+	if !t.Enabled {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed loading operations tls key pair [%s,%s]", t.CertFile, t.KeyFile)
+	}
+	caCertPool := x509.NewCertPool()
+	for _, caPath := range t.ClientCACertFiles {
+		caPem, err := ioutil.ReadFile(caPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed loading operations client ca [%s]", caPath)
+		}
+		caCertPool.AppendCertsFromPEM(caPem)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		CipherSuites: grpc.DefaultTLSCipherSuites,
+		ClientCAs:    caCertPool,
+	}
+	// client certificates are checked by the handlers, so that /healthz does not need one
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return tlsConfig, nil
+}
@@ -13,12 +13,9 @@ import (
 	"github.com/hyperledger-labs/fabric-smart-client/integration/fabric/iou"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/generic/pingpong"
 	"github.com/hyperledger-labs/fabric-smart-client/integration/nwo"
-	"github.com/onsi/ginkgo"
-	"github.com/onsi/gomega"
 )
 
 func main() {
-	gomega.RegisterFailHandler(ginkgo.Fail)
 	topologies := map[string][]nwo.Topology{}
 
 	topologies["fabric_atsa_chaincode.yaml"] = chaincode.Topology()